@@ -3,9 +3,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -13,7 +14,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/zorak1103/notebook/internal/auth"
+	"github.com/zorak1103/notebook/internal/config"
 	"github.com/zorak1103/notebook/internal/db"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/logging"
+	"github.com/zorak1103/notebook/internal/secrets"
 	"github.com/zorak1103/notebook/internal/tsapp"
 	"github.com/zorak1103/notebook/internal/web"
 )
@@ -26,88 +32,383 @@ var (
 )
 
 func main() {
-	// Parse command-line flags
-	var (
-		devListen = flag.String("dev-listen", "", "Development mode: listen on this address (e.g., :8080) without Tailscale")
-		hostname  = flag.String("hostname", "notebook", "Tailscale hostname for the service")
-		stateDir  = flag.String("state-dir", "tsnet-state", "Tailscale state directory")
-		dbPath    = flag.String("db", "notebook.db", "SQLite database file path")
-		verbose   = flag.Bool("verbose", false, "Enable verbose logging")
-	)
-	flag.Parse()
-
-	// Open database
-	database, err := db.Open(*dbPath)
-	if err != nil {
-		log.Fatalf("failed to open database: %v", err)
+	if runSubcommand(os.Args[1:]) {
+		return
 	}
 
-	// Run migrations
-	if err := database.Migrate(); err != nil {
-		log.Fatalf("failed to migrate database: %v", err)
+	cfg := loadConfig(os.Args[1:])
+	setupLogging(cfg.LogFormat, cfg.LogLevel)
+
+	cipher, err := loadCipher(cfg.MasterKey)
+	if err != nil {
+		fatal("invalid master key", err)
 	}
+	location := loadLocation(cfg.Timezone)
+	database := openDatabase(cfg.DB, location, cipher)
 	defer database.Close()
+	lockedConfig := seedLLMConfig(database, cipher, &cfg.LLM)
 
 	// Determine if running in dev mode
-	devMode := *devListen != ""
+	devMode := cfg.DevListen != ""
 
 	// Setup context with cancellation for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	startTrashPurge(ctx, database, cfg.TrashRetention())
 
 	// Initialize the application
-	tsApp, listener := setupListener(ctx, devMode, *devListen, *hostname, *stateDir)
+	tsApp, listener := setupListener(ctx, cfg)
 	defer closeTsApp(tsApp)
 
+	publicListener, shareBaseURL := setupPublicListener(ctx, tsApp, cfg.FunnelListen)
+
 	// Create and start HTTP server
-	httpServer := createHTTPServer(tsApp, database, devMode, *verbose)
+	separateMetrics := cfg.Metrics && cfg.MetricsListen != ""
+	webServer := web.NewServer(tsApp, database, web.Options{
+		DevMode:       devMode,
+		Version:       version,
+		Commit:        commit,
+		Date:          date,
+		Location:      location,
+		Metrics:       cfg.Metrics && !separateMetrics,
+		HTTPS:         tsApp != nil && cfg.HTTPS,
+		Admins:        cfg.Admins,
+		LockedConfig:  lockedConfig,
+		ShareBaseURL:  shareBaseURL,
+		Cipher:        cipher,
+		Auth:          setupAuth(ctx, cfg, cipher),
+		NoteRetention: repositories.RevisionRetention{MaxCount: cfg.NoteRevisions, MaxAge: cfg.NoteRevisionMaxAge()},
+
+		StrictNoteLocks: cfg.StrictNoteLocks,
+	})
+	if publicListener != nil {
+		startPublicServer(publicListener, webServer.PublicHandler())
+	}
+	if separateMetrics {
+		startMetricsServer(ctx, tsApp, cfg.MetricsListen, webServer.MetricsHandler())
+	}
+	httpServer := createHTTPServer(webServer.Handler())
+	httpServer.RegisterOnShutdown(webServer.Close)
 	startServer(httpServer, listener)
 }
 
-func setupListener(ctx context.Context, devMode bool, devListen, hostname, stateDir string) (*tsapp.App, net.Listener) {
-	if devMode {
-		fmt.Printf("Starting in development mode on %s\n", devListen)
+// runSubcommand runs the subcommand named by args[0], if any, and reports
+// whether it did. Failing subcommands exit the process.
+func runSubcommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	run, ok := subcommands[args[0]]
+	if !ok {
+		return false
+	}
+	if err := run(args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	return true
+}
+
+// loadConfig layers defaults, the config file, NOTEBOOK_* environment
+// variables and flags, exiting on invalid settings or -h
+func loadConfig(args []string) *config.Config {
+	cfg, err := config.Load(args, os.LookupEnv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration: %v\n", err)
+		os.Exit(2)
+	}
+	return cfg
+}
+
+// setupLogging installs the default slog logger
+func setupLogging(format, level string) {
+	logger, err := logging.New(os.Stderr, format, level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid logging flags: %v\n", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+}
+
+// fatal logs an error and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// openDatabase opens the database and runs migrations; naive times of
+// existing meetings are read in loc and plaintext secrets encrypted with c
+func openDatabase(path string, loc *time.Location, c *secrets.Cipher) *db.DB {
+	database, err := db.Open(path)
+	if err != nil {
+		fatal("failed to open database", err)
+	}
+
+	database.SetLocation(loc)
+	database.SetCipher(c)
+	if err = database.Migrate(); err != nil {
+		fatal("failed to migrate database", err)
+	}
+
+	// Fail early if stored secrets cannot be read with the configured key
+	if _, err = repositories.NewConfigRepository(database.DB).WithCipher(c).GetAll(); err != nil {
+		fatal("failed to read stored secrets", err)
+	}
+	if c == nil {
+		slog.Warn("no master key configured; storing an LLM API key is disabled")
+	}
+	return database
+}
+
+// seedLLMConfig stores the LLM settings from the configuration in the
+// config table and returns the keys locked against changes from the UI
+func seedLLMConfig(database *db.DB, c *secrets.Cipher, llmConfig *config.LLM) []string {
+	values := llmConfig.Settings()
+	repo := repositories.NewConfigRepository(database.DB).WithCipher(c)
+	if err := repo.Seed(values, llmConfig.Lock); err != nil {
+		fatal("failed to seed LLM configuration", err)
+	}
+
+	if !llmConfig.Lock {
+		return nil
+	}
+	locked := make([]string, 0, len(values))
+	for key := range values {
+		locked = append(locked, key)
+	}
+	return locked
+}
+
+// startTrashPurge permanently deletes items that have been in the trash for
+// longer than retention, on startup and then hourly. A zero retention keeps
+// the trash until it is purged by hand.
+func startTrashPurge(ctx context.Context, database *db.DB, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	repo := repositories.NewTrashRepository(database.DB).WithContext(ctx)
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			purged, err := repo.PurgeDeletedBefore(time.Now().Add(-retention))
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to purge trash", "error", err)
+			} else if purged > 0 {
+				slog.InfoContext(ctx, "purged trash", "items", purged)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// loadLocation resolves the --timezone flag, defaulting to the system zone
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		fatal("invalid time zone", err)
+	}
+	return loc
+}
+
+func setupListener(ctx context.Context, cfg *config.Config) (*tsapp.App, net.Listener) {
+	if cfg.DevListen != "" {
+		if cfg.HTTPS {
+			slog.WarnContext(ctx, "--https is ignored in development mode")
+		}
+		slog.InfoContext(ctx, "starting in development mode", "addr", cfg.DevListen)
 		lc := &net.ListenConfig{}
-		listener, err := lc.Listen(ctx, "tcp", devListen)
+		listener, err := lc.Listen(ctx, "tcp", cfg.DevListen)
 		if err != nil {
-			log.Fatalf("failed to listen: %v", err)
+			fatal("failed to listen", err)
 		}
 		return nil, listener
 	}
 
-	return setupTailscale(ctx, hostname, stateDir)
+	if cfg.Listen != "" {
+		if cfg.HTTPS {
+			slog.WarnContext(ctx, "--https is ignored in standalone mode; terminate TLS at the reverse proxy")
+		}
+		slog.InfoContext(ctx, "starting in standalone mode", "addr", cfg.Listen, "auth", cfg.Auth.Mode)
+		lc := &net.ListenConfig{}
+		listener, err := lc.Listen(ctx, "tcp", cfg.Listen)
+		if err != nil {
+			fatal("failed to listen", err)
+		}
+		return nil, listener
+	}
+
+	return setupTailscale(ctx, cfg.Hostname, cfg.StateDir, cfg.HTTPS)
 }
 
-func setupTailscale(ctx context.Context, hostname, stateDir string) (*tsapp.App, net.Listener) {
-	fmt.Printf("Starting Tailscale service as '%s'\n", hostname)
+// setupAuth returns the authenticator of standalone mode, or nil when
+// Tailscale or dev mode identify users
+func setupAuth(ctx context.Context, cfg *config.Config, c *secrets.Cipher) web.Authenticator {
+	if cfg.Listen == "" {
+		return nil
+	}
+
+	if cfg.Auth.Mode == config.AuthHeader {
+		headerAuth, err := auth.NewHeaderAuth(cfg.Auth.Header, cfg.Auth.NameHeader, cfg.Auth.TrustedProxies)
+		if err != nil {
+			fatal("invalid header authentication settings", err)
+		}
+		return headerAuth
+	}
+
+	oidc, err := auth.NewOIDC(ctx, auth.OIDCConfig{
+		IssuerURL:    cfg.Auth.OIDC.Issuer,
+		ClientID:     cfg.Auth.OIDC.ClientID,
+		ClientSecret: cfg.Auth.OIDC.ClientSecret,
+		RedirectURL:  cfg.Auth.OIDC.RedirectURL,
+		Scopes:       cfg.Auth.OIDC.Scopes,
+	}, c)
+	if err != nil {
+		fatal("failed to set up OIDC login", err)
+	}
+	return oidc
+}
+
+func setupTailscale(ctx context.Context, hostname, stateDir string, https bool) (*tsapp.App, net.Listener) {
+	slog.InfoContext(ctx, "starting tailscale service", "hostname", hostname)
 	tsApp := tsapp.New(hostname, stateDir)
 
 	if err := tsApp.Up(ctx); err != nil {
-		log.Fatalf("failed to start Tailscale: %v", err)
+		fatal("failed to start tailscale", err)
+	}
+
+	if https {
+		return tsApp, setupHTTPS(tsApp)
 	}
 
 	listener, err := tsApp.Listen("tcp", ":80")
 	if err != nil {
-		log.Fatalf("failed to create Tailscale listener: %v", err)
+		fatal("failed to create tailscale listener", err)
 	}
 
 	return tsApp, listener
 }
 
-func createHTTPServer(tsApp *tsapp.App, database *db.DB, devMode, verbose bool) *http.Server {
-	webServer := web.NewServer(tsApp, database, devMode, verbose, version, commit, date)
+// setupHTTPS listens on :443 with Tailscale certificates and redirects
+// plain HTTP on :80 to it
+func setupHTTPS(tsApp *tsapp.App) net.Listener {
+	domain, err := tsApp.CertDomain()
+	if err != nil {
+		fatal("failed to enable https", err)
+	}
+
+	listener, err := tsApp.ListenTLS("tcp", ":443")
+	if err != nil {
+		fatal("failed to create tailscale tls listener", err)
+	}
+
+	redirectListener, err := tsApp.Listen("tcp", ":80")
+	if err != nil {
+		fatal("failed to create tailscale listener", err)
+	}
+	redirectServer := createHTTPServer(web.RedirectToHTTPS(domain))
+	go func() {
+		slog.Info("redirecting http to https", "domain", domain)
+		if err := redirectServer.Serve(redirectListener); err != nil {
+			slog.Error("redirect server error", "error", err)
+		}
+	}()
+
+	return listener
+}
+
+func createHTTPServer(handler http.Handler) *http.Server {
 	return &http.Server{
-		Handler:      webServer.Handler(),
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 }
 
+// startMetricsServer serves the metrics handler on its own address: on the
+// host in dev mode, on the tailnet otherwise
+func startMetricsServer(ctx context.Context, tsApp *tsapp.App, addr string, handler http.Handler) {
+	var listener net.Listener
+	var err error
+	if tsApp != nil {
+		listener, err = tsApp.Listen("tcp", addr)
+	} else {
+		lc := &net.ListenConfig{}
+		listener, err = lc.Listen(ctx, "tcp", addr)
+	}
+	if err != nil {
+		fatal("failed to create metrics listener", err)
+	}
+
+	metricsServer := createHTTPServer(handler)
+	go func() {
+		slog.Info("metrics listening", "addr", listener.Addr().String())
+		if err := metricsServer.Serve(listener); err != nil {
+			slog.Error("metrics server error", "error", err)
+		}
+	}()
+}
+
+// setupPublicListener listens for public share links on addr: through
+// Tailscale Funnel, or on the host in dev mode. It returns the base URL of
+// share links, or nil and "" if addr is empty.
+func setupPublicListener(ctx context.Context, tsApp *tsapp.App, addr string) (net.Listener, string) {
+	if addr == "" {
+		return nil, ""
+	}
+
+	if tsApp == nil {
+		lc := &net.ListenConfig{}
+		listener, err := lc.Listen(ctx, "tcp", addr)
+		if err != nil {
+			fatal("failed to create public listener", err)
+		}
+		return listener, "http://" + listener.Addr().String()
+	}
+
+	domain, err := tsApp.CertDomain()
+	if err != nil {
+		fatal("failed to enable funnel", err)
+	}
+	listener, err := tsApp.ListenFunnel("tcp", addr)
+	if err != nil {
+		fatal("failed to create funnel listener", err)
+	}
+	baseURL := "https://" + domain
+	if _, port, _ := net.SplitHostPort(addr); port != "443" {
+		baseURL += ":" + port
+	}
+	return listener, baseURL
+}
+
+// startPublicServer serves share links on the public listener
+func startPublicServer(listener net.Listener, handler http.Handler) {
+	publicServer := createHTTPServer(handler)
+	go func() {
+		slog.Info("public share links listening", "addr", listener.Addr().String())
+		if err := publicServer.Serve(listener); err != nil {
+			slog.Error("public server error", "error", err)
+		}
+	}()
+}
+
 func startServer(httpServer *http.Server, listener net.Listener) {
 	serverErrors := make(chan error, 1)
 	go func() {
-		fmt.Printf("HTTP server listening on %s\n", listener.Addr())
+		slog.Info("http server listening", "addr", listener.Addr().String())
 		serverErrors <- httpServer.Serve(listener)
 	}()
 
@@ -120,18 +421,18 @@ func handleShutdown(httpServer *http.Server, serverErrors chan error) {
 
 	select {
 	case err := <-serverErrors:
-		log.Fatalf("server error: %v", err)
+		fatal("server error", err)
 	case sig := <-sigChan:
-		fmt.Printf("\nReceived signal %v, shutting down gracefully...\n", sig)
+		slog.Info("shutting down gracefully", "signal", sig.String())
 
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer shutdownCancel()
 
 		if err := httpServer.Shutdown(shutdownCtx); err != nil {
-			log.Printf("error during shutdown: %v", err)
+			slog.Error("error during shutdown", "error", err)
 		}
 
-		fmt.Println("Shutdown complete")
+		slog.Info("shutdown complete")
 	}
 }
 
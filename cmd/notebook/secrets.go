@@ -0,0 +1,102 @@
+// coverage-exempt: CLI wiring, rotation is tested in the repositories package
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/zorak1103/notebook/internal/config"
+	"github.com/zorak1103/notebook/internal/db"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/secrets"
+)
+
+// subcommands run instead of the server when named as the first argument
+var subcommands = map[string]func(args []string, stdout, stderr io.Writer) error{
+	"generate-master-key": runGenerateMasterKey,
+	"rotate-master-key":   runRotateMasterKey,
+}
+
+// loadCipher creates the cipher for stored secrets, or returns nil if no
+// master key is configured
+func loadCipher(masterKey string) (*secrets.Cipher, error) {
+	if masterKey == "" {
+		return nil, nil //nolint:nilnil // Intentional: no master key means no cipher
+	}
+	return secrets.New([]byte(masterKey))
+}
+
+// runGenerateMasterKey prints a new random master key
+func runGenerateMasterKey(_ []string, stdout, _ io.Writer) error {
+	key, err := secrets.GenerateKey()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, key)
+	return err
+}
+
+// runRotateMasterKey re-encrypts the stored secrets with a new master key.
+// The database and current key come from the regular configuration unless
+// given as flags. The server must be stopped while rotating.
+func runRotateMasterKey(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load(nil, os.LookupEnv, stderr)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	fs := flag.NewFlagSet("rotate-master-key", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dbPath := fs.String("db", cfg.DB, "SQLite database file path")
+	oldKeyFile := fs.String("old-key-file", "", "File containing the current master key (default: the configured master key)")
+	newKeyFile := fs.String("new-key-file", "", "File containing the new master key (required)")
+	if err = fs.Parse(args); err != nil {
+		return err
+	}
+	if *newKeyFile == "" {
+		return errors.New("--new-key-file is required")
+	}
+
+	oldKey := []byte(cfg.MasterKey)
+	if *oldKeyFile != "" {
+		if oldKey, err = secrets.ReadKeyFile(*oldKeyFile); err != nil {
+			return err
+		}
+	}
+	if len(oldKey) == 0 {
+		return secrets.ErrNoMasterKey
+	}
+	oldCipher, err := secrets.New(oldKey)
+	if err != nil {
+		return fmt.Errorf("current master key: %w", err)
+	}
+
+	newKey, err := secrets.ReadKeyFile(*newKeyFile)
+	if err != nil {
+		return err
+	}
+	newCipher, err := secrets.New(newKey)
+	if err != nil {
+		return fmt.Errorf("new master key: %w", err)
+	}
+
+	database, err := db.Open(*dbPath)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer database.Close()
+	database.SetCipher(oldCipher)
+	if err = database.Migrate(); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+
+	rotated, err := repositories.NewConfigRepository(database.DB).WithCipher(oldCipher).RotateSecrets(newCipher)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "re-encrypted %d secret(s); start notebook with the new master key\n", rotated)
+	return err
+}
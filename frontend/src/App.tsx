@@ -6,16 +6,24 @@ import { MeetingDetail } from './components/MeetingDetail';
 import { SearchPanel } from './components/SearchPanel';
 import ConfigPanel from './components/ConfigPanel';
 import UserInfoPanel from './components/UserInfoPanel';
+import { TrashPanel } from './components/TrashPanel';
 import { getConfig } from './api/client';
 import i18n from './i18n';
 import './App.css';
 
-type View = 'list' | 'create' | 'edit' | 'detail' | 'search' | 'config' | 'info';
+type View = 'list' | 'create' | 'edit' | 'detail' | 'search' | 'trash' | 'config' | 'info';
+
+// Deep links such as /meetings/42 (used by the calendar feed) open the meeting detail
+function deepLinkedMeetingId(): number | undefined {
+  const match = window.location.pathname.match(/^\/meetings\/(\d+)$/);
+  return match ? Number(match[1]) : undefined;
+}
 
 function App() {
   const { t } = useTranslation();
-  const [view, setView] = useState<View>('list');
-  const [selectedId, setSelectedId] = useState<number | undefined>();
+  const [initialMeetingId] = useState(deepLinkedMeetingId);
+  const [view, setView] = useState<View>(initialMeetingId ? 'detail' : 'list');
+  const [selectedId, setSelectedId] = useState<number | undefined>(initialMeetingId);
 
   useEffect(() => {
     getConfig().then((cfg) => {
@@ -38,6 +46,10 @@ function App() {
     setView('search');
   };
 
+  const handleTrash = () => {
+    setView('trash');
+  };
+
   const handleConfig = () => {
     setView('config');
   };
@@ -103,6 +115,12 @@ function App() {
           >
             {t('navigation.search')}
           </button>
+          <button
+            className={`nav-item ${view === 'trash' ? 'nav-item--active' : ''}`}
+            onClick={handleTrash}
+          >
+            {t('navigation.trash')}
+          </button>
         </nav>
 
         <div className="sidebar-footer">
@@ -145,6 +163,7 @@ function App() {
           />
         )}
         {view === 'search' && <SearchPanel onSelectMeeting={handleSearchSelect} />}
+        {view === 'trash' && <TrashPanel />}
         {view === 'config' && <ConfigPanel />}
         {view === 'info' && <UserInfoPanel />}
       </main>
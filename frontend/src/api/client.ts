@@ -1,4 +1,4 @@
-import type { UserInfo, VersionInfo, Meeting, CreateMeetingRequest, Note, CreateNoteRequest, UpdateNoteRequest, ReorderNoteRequest, Config, ConfigUpdateRequest, EnhanceNoteRequest, EnhanceNoteResponse } from './types';
+import type { UserInfo, VersionInfo, Meeting, CreateMeetingRequest, MeetingPatch, Note, NotePatch, CreateNoteRequest, UpdateNoteRequest, NoteRevision, NoteRevisionDiff, MeetingSummary, ReorderNoteRequest, NoteOrderRequest, MoveNotesRequest, SplitMeetingRequest, Config, ConfigUpdateRequest, EnhanceNoteRequest, EnhanceNoteResponse, MeetingShare, CreateShareRequest, CreateShareResponse, APIToken, CreateAPITokenRequest, CreateAPITokenResponse, TrashItem, ServerEvent, ServerEventType, MeetingPresence, NoteLock } from './types';
 
 export async function fetchVersion(): Promise<VersionInfo> {
   return apiGet<VersionInfo>('/api/version');
@@ -21,6 +21,40 @@ export async function fetchWhoAmI(): Promise<UserInfo> {
 
 // Generic API helpers
 
+// ConflictError is thrown when a conditional write finds the resource changed;
+// current holds the server's state so the caller can merge
+export class ConflictError<T> extends Error {
+  current: T;
+
+  constructor(current: T) {
+    super('precondition failed');
+    this.current = current;
+  }
+}
+
+// LockedError is thrown when another user holds the edit lock of a note
+export class LockedError extends Error {
+  lock: NoteLock;
+
+  constructor(message: string, lock: NoteLock) {
+    super(message);
+    this.lock = lock;
+  }
+}
+
+// throwIfLocked throws a LockedError for lock conflicts
+async function throwIfLocked(response: Response, statuses: number[]): Promise<void> {
+  if (statuses.includes(response.status)) {
+    const data = await response.json();
+    throw new LockedError(data.error, data.lock);
+  }
+}
+
+// ifMatch returns the If-Match header for a write conditional on version
+function ifMatch(id: number, version?: number): Record<string, string> {
+  return version === undefined ? {} : { 'If-Match': `"${id}-${version}"` };
+}
+
 async function parseErrorMessage(response: Response): Promise<string> {
   try {
     const data = await response.json();
@@ -52,12 +86,33 @@ async function apiPost<T>(url: string, data: unknown): Promise<T> {
   return response.json();
 }
 
-async function apiPut<T>(url: string, data: unknown): Promise<T> {
+async function apiPut<T>(url: string, data: unknown, headers: Record<string, string> = {}): Promise<T> {
   const response = await fetch(url, {
     method: 'PUT',
-    headers: { 'Content-Type': 'application/json' },
+    headers: { 'Content-Type': 'application/json', ...headers },
     body: JSON.stringify(data),
   });
+  if (response.status === 412) {
+    throw new ConflictError<T>(await response.json());
+  }
+  await throwIfLocked(response, [423]);
+  if (!response.ok) {
+    const message = await parseErrorMessage(response);
+    throw new Error(message);
+  }
+  return response.json();
+}
+
+async function apiPatch<T>(url: string, patch: unknown, headers: Record<string, string> = {}): Promise<T> {
+  const response = await fetch(url, {
+    method: 'PATCH',
+    headers: { 'Content-Type': 'application/merge-patch+json', ...headers },
+    body: JSON.stringify(patch),
+  });
+  if (response.status === 412) {
+    throw new ConflictError<T>(await response.json());
+  }
+  await throwIfLocked(response, [423]);
   if (!response.ok) {
     const message = await parseErrorMessage(response);
     throw new Error(message);
@@ -65,8 +120,11 @@ async function apiPut<T>(url: string, data: unknown): Promise<T> {
   return response.json();
 }
 
-async function apiDelete(url: string): Promise<void> {
-  const response = await fetch(url, { method: 'DELETE' });
+async function apiDelete(url: string, headers: Record<string, string> = {}): Promise<void> {
+  const response = await fetch(url, { method: 'DELETE', headers });
+  if (response.status === 412) {
+    throw new ConflictError<unknown>(await response.json());
+  }
   if (!response.ok) {
     const message = await parseErrorMessage(response);
     throw new Error(message);
@@ -91,8 +149,15 @@ export async function createMeeting(data: CreateMeetingRequest): Promise<Meeting
   return apiPost<Meeting>('/api/meetings', data);
 }
 
-export async function updateMeeting(id: number, data: CreateMeetingRequest): Promise<Meeting> {
-  return apiPut<Meeting>(`/api/meetings/${id}`, data);
+// updateMeeting saves a meeting; with version it fails with a ConflictError
+// if the meeting has changed since that version was loaded
+export async function updateMeeting(id: number, data: CreateMeetingRequest, version?: number): Promise<Meeting> {
+  return apiPut<Meeting>(`/api/meetings/${id}`, data, ifMatch(id, version));
+}
+
+// patchMeeting changes only the given fields; null clears an optional field
+export async function patchMeeting(id: number, patch: MeetingPatch, version?: number): Promise<Meeting> {
+  return apiPatch<Meeting>(`/api/meetings/${id}`, patch, ifMatch(id, version));
 }
 
 export async function deleteMeeting(id: number): Promise<void> {
@@ -108,6 +173,56 @@ export async function summarizeMeeting(id: number): Promise<Meeting> {
   return apiPost<Meeting>(`/api/meetings/${id}/summarize`, {});
 }
 
+export async function fetchSummaries(meetingId: number): Promise<MeetingSummary[]> {
+  return apiGet<MeetingSummary[]>(`/api/meetings/${meetingId}/summaries`);
+}
+
+export async function fetchSummaryDiff(meetingId: number, from: number, to: number): Promise<NoteRevisionDiff> {
+  return apiGet<NoteRevisionDiff>(`/api/meetings/${meetingId}/summaries/diff?from=${from}&to=${to}`);
+}
+
+export async function promoteSummary(meetingId: number, version: number): Promise<Meeting> {
+  return apiPost<Meeting>(`/api/meetings/${meetingId}/summaries/${version}/promote`, {});
+}
+
+// mergeMeeting merges meeting otherId into meetingId and returns the merged meeting
+export async function mergeMeeting(meetingId: number, otherId: number): Promise<Meeting> {
+  return apiPost<Meeting>(`/api/meetings/${meetingId}/merge`, { meeting_id: otherId });
+}
+
+// splitMeeting moves a range of notes into a new meeting and returns it
+export async function splitMeeting(meetingId: number, req: SplitMeetingRequest): Promise<Meeting> {
+  return apiPost<Meeting>(`/api/meetings/${meetingId}/split`, req);
+}
+
+// Share API functions
+
+export async function fetchShares(meetingId: number): Promise<MeetingShare[]> {
+  return apiGet<MeetingShare[]>(`/api/meetings/${meetingId}/shares`);
+}
+
+export async function createShare(meetingId: number, data: CreateShareRequest): Promise<CreateShareResponse> {
+  return apiPost<CreateShareResponse>(`/api/meetings/${meetingId}/shares`, data);
+}
+
+export async function revokeShare(id: number): Promise<void> {
+  return apiDelete(`/api/shares/${id}`);
+}
+
+// API token functions
+
+export async function fetchAPITokens(): Promise<APIToken[]> {
+  return apiGet<APIToken[]>('/api/tokens');
+}
+
+export async function createAPIToken(data: CreateAPITokenRequest): Promise<CreateAPITokenResponse> {
+  return apiPost<CreateAPITokenResponse>('/api/tokens', data);
+}
+
+export async function revokeAPIToken(id: number): Promise<void> {
+  return apiDelete(`/api/tokens/${id}`);
+}
+
 // Note API functions
 
 export async function fetchNotes(meetingId: number): Promise<Note[]> {
@@ -122,8 +237,14 @@ export async function createNote(data: CreateNoteRequest): Promise<Note> {
   return apiPost<Note>('/api/notes', data);
 }
 
-export async function updateNote(id: number, data: UpdateNoteRequest): Promise<Note> {
-  return apiPut<Note>(`/api/notes/${id}`, data);
+// updateNote saves a note; with version it fails with a ConflictError if the
+// note has changed since that version was loaded
+export async function updateNote(id: number, data: UpdateNoteRequest, version?: number): Promise<Note> {
+  return apiPut<Note>(`/api/notes/${id}`, data, ifMatch(id, version));
+}
+
+export async function patchNote(id: number, patch: NotePatch, version?: number): Promise<Note> {
+  return apiPatch<Note>(`/api/notes/${id}`, patch, ifMatch(id, version));
 }
 
 export async function deleteNote(id: number): Promise<void> {
@@ -140,6 +261,135 @@ export async function reorderNote(id: number, direction: 'up' | 'down'): Promise
   return apiPut<Note[]>(`/api/notes/${id}/reorder`, req);
 }
 
+export async function setNoteOrder(meetingId: number, noteIds: number[]): Promise<Note[]> {
+  const req: NoteOrderRequest = { note_ids: noteIds };
+  return apiPut<Note[]>(`/api/meetings/${meetingId}/notes/order`, req);
+}
+
+// moveNotes appends notes to another meeting and returns the notes of that meeting
+export async function moveNotes(noteIds: number[], meetingId: number): Promise<Note[]> {
+  const req: MoveNotesRequest = { note_ids: noteIds, meeting_id: meetingId };
+  return apiPost<Note[]>('/api/notes/move', req);
+}
+
+export async function fetchNoteRevisions(id: number): Promise<NoteRevision[]> {
+  return apiGet<NoteRevision[]>(`/api/notes/${id}/revisions`);
+}
+
+export async function fetchNoteRevisionDiff(id: number, from: number, to: number): Promise<NoteRevisionDiff> {
+  return apiGet<NoteRevisionDiff>(`/api/notes/${id}/revisions/diff?from=${from}&to=${to}`);
+}
+
+export async function restoreNoteRevision(id: number, revision: number): Promise<Note> {
+  return apiPost<Note>(`/api/notes/${id}/revisions/${revision}/restore`, {});
+}
+
+// Trash API functions
+
+export async function fetchTrash(): Promise<TrashItem[]> {
+  return apiGet<TrashItem[]>('/api/trash');
+}
+
+export async function restoreTrashedMeeting(id: number): Promise<Meeting> {
+  return apiPost<Meeting>(`/api/trash/meetings/${id}/restore`, {});
+}
+
+export async function restoreTrashedNote(id: number): Promise<Note> {
+  return apiPost<Note>(`/api/trash/notes/${id}/restore`, {});
+}
+
+export async function purgeTrashedMeeting(id: number): Promise<void> {
+  return apiDelete(`/api/trash/meetings/${id}`);
+}
+
+export async function purgeTrashedNote(id: number): Promise<void> {
+  return apiDelete(`/api/trash/notes/${id}`);
+}
+
+// Event stream
+
+const serverEventTypes: ServerEventType[] = [
+  'note.created',
+  'note.updated',
+  'note.reordered',
+  'note.deleted',
+  'meeting.created',
+  'meeting.updated',
+  'meeting.deleted',
+  'summary.generated',
+  'presence.updated',
+  'note.locked',
+  'note.unlocked',
+];
+
+// ServerEventListener receives server events, or null when events were
+// missed and the listener has to reload its data
+export type ServerEventListener = (event: ServerEvent | null) => void;
+
+const eventListeners = new Set<ServerEventListener>();
+let eventSource: EventSource | null = null;
+
+// subscribeServerEvents shares one EventSource among all listeners. The
+// browser reconnects on its own and resumes with Last-Event-ID.
+export function subscribeServerEvents(listener: ServerEventListener): () => void {
+  eventListeners.add(listener);
+  if (!eventSource) {
+    eventSource = new EventSource('/api/events');
+    for (const type of serverEventTypes) {
+      eventSource.addEventListener(type, (e) => {
+        const event = JSON.parse((e as MessageEvent<string>).data) as ServerEvent;
+        eventListeners.forEach((l) => l(event));
+      });
+    }
+    eventSource.addEventListener('reset', () => {
+      eventListeners.forEach((l) => l(null));
+    });
+  }
+
+  return () => {
+    eventListeners.delete(listener);
+    if (eventListeners.size === 0 && eventSource) {
+      eventSource.close();
+      eventSource = null;
+    }
+  };
+}
+
+// Presence and note lock API functions
+
+export async function fetchPresence(meetingId: number): Promise<MeetingPresence> {
+  return apiGet<MeetingPresence>(`/api/meetings/${meetingId}/presence`);
+}
+
+// touchPresence is the heartbeat marking the user as viewing the meeting,
+// or as editing noteId
+export async function touchPresence(meetingId: number, noteId?: number): Promise<MeetingPresence> {
+  return apiPut<MeetingPresence>(`/api/meetings/${meetingId}/presence`, {
+    state: noteId ? 'editing' : 'viewing',
+    note_id: noteId,
+  });
+}
+
+export async function leavePresence(meetingId: number): Promise<void> {
+  // keepalive lets the request finish while the page unloads
+  await fetch(`/api/meetings/${meetingId}/presence`, { method: 'DELETE', keepalive: true });
+}
+
+// lockNote acquires or renews the user's edit lock; force takes it over
+// from another user
+export async function lockNote(noteId: number, force = false): Promise<NoteLock> {
+  const response = await fetch(`/api/notes/${noteId}/lock${force ? '?force=true' : ''}`, { method: 'POST' });
+  await throwIfLocked(response, [403, 409]);
+  if (!response.ok) {
+    throw new Error(await parseErrorMessage(response));
+  }
+  return response.json();
+}
+
+export async function unlockNote(noteId: number): Promise<void> {
+  await fetch(`/api/notes/${noteId}/lock`, { method: 'DELETE', keepalive: true });
+}
+
 // Config API functions
 
 export async function getConfig(): Promise<Config> {
@@ -25,8 +25,15 @@ export interface Meeting {
   participants: string | null;
   summary: string | null;
   keywords: string | null;
+  ical_uid?: string;
+  ical_recurrence_id?: string;
+  timezone: string;
+  start_utc: string | null;
+  end_utc: string | null;
   created_at: string;
   updated_at: string;
+  deleted_at?: string;
+  version: number;
 }
 
 // CreateMeetingRequest represents the request body for creating a meeting
@@ -38,8 +45,13 @@ export interface CreateMeetingRequest {
   participants?: string | null;
   summary?: string | null;
   keywords?: string | null;
+  timezone?: string;
 }
 
+// MeetingPatch is a JSON merge patch of a meeting: absent fields stay
+// unchanged, null clears an optional field
+export type MeetingPatch = Partial<CreateMeetingRequest>;
+
 // UpdateMeetingRequest represents the request body for updating a meeting
 export interface UpdateMeetingRequest extends CreateMeetingRequest {
   id: number;
@@ -53,6 +65,13 @@ export interface Note {
   content: string;
   created_at: string;
   updated_at: string;
+  deleted_at?: string;
+  version: number;
+}
+
+// NotePatch is a JSON merge patch of a note
+export interface NotePatch {
+  content?: string;
 }
 
 // CreateNoteRequest represents the request body for creating a note
@@ -61,9 +80,115 @@ export interface CreateNoteRequest {
   content: string;
 }
 
+// NoteRevisionSource tells how a note revision was created
+export type NoteRevisionSource = 'manual' | 'llm-enhance' | 'import';
+
 // UpdateNoteRequest represents the request body for updating a note
 export interface UpdateNoteRequest {
   content: string;
+  source?: 'manual' | 'llm-enhance';
+}
+
+// NoteRevision is one stored version of a note's content
+export interface NoteRevision {
+  id: number;
+  note_id: number;
+  revision: number;
+  content: string;
+  author: string;
+  source: NoteRevisionSource;
+  created_at: string;
+}
+
+// NoteRevisionDiff is a unified diff between two note revisions or summary versions
+export interface NoteRevisionDiff {
+  from: number;
+  to: number;
+  diff: string;
+}
+
+// MeetingSummary is one stored version of a meeting's summary with its provenance
+export interface MeetingSummary {
+  id: number;
+  meeting_id: number;
+  version: number;
+  summary: string;
+  author: string;
+  source: 'manual' | 'llm';
+  model: string | null;
+  prompt_hash: string | null;
+  input_tokens: number | null;
+  output_tokens: number | null;
+  promoted_from: number | null;
+  created_at: string;
+}
+
+// TrashItem is a deleted meeting or note that can still be restored
+export interface TrashItem {
+  type: 'meeting' | 'note';
+  id: number;
+  meeting_id: number;
+  subject: string;
+  meeting_date: string;
+  note_number?: number;
+  content?: string;
+  notes: number;
+  deleted_at: string;
+}
+
+// ServerEventType names the changes streamed by GET /api/events
+export type ServerEventType =
+  | 'note.created'
+  | 'note.updated'
+  | 'note.reordered'
+  | 'note.deleted'
+  | 'meeting.created'
+  | 'meeting.updated'
+  | 'meeting.deleted'
+  | 'summary.generated'
+  | 'presence.updated'
+  | 'note.locked'
+  | 'note.unlocked';
+
+// ServerEvent is a change made by any client. data holds the changed
+// meeting or note, the reordered notes, the meeting's presence, a note
+// lock, or { id } for deletions and released locks.
+export interface ServerEvent {
+  id: number;
+  type: ServerEventType;
+  meeting_id: number;
+  actor: string;
+  time: string;
+  data: Meeting | Note | Note[] | MeetingPresence | NoteLock | { id: number };
+}
+
+// PresenceUser identifies a user by login name
+export interface PresenceUser {
+  login: string;
+  name: string;
+}
+
+// PresenceEntry is a user currently viewing a meeting or editing one of its notes
+export interface PresenceEntry extends PresenceUser {
+  meeting_id: number;
+  note_id?: number;
+  state: 'viewing' | 'editing';
+  last_seen: string;
+}
+
+// NoteLock is an advisory edit lock on a note
+export interface NoteLock {
+  note_id: number;
+  meeting_id: number;
+  holder: PresenceUser;
+  acquired_at: string;
+  expires_at: string;
+}
+
+// MeetingPresence lists who is in a meeting and which of its notes are locked
+export interface MeetingPresence {
+  users: PresenceEntry[];
+  locks: NoteLock[];
 }
 
 // ReorderNoteRequest represents the request body for reordering a note
@@ -71,6 +196,25 @@ export interface ReorderNoteRequest {
   direction: 'up' | 'down';
 }
 
+// NoteOrderRequest lists all notes of a meeting in their new order
+export interface NoteOrderRequest {
+  note_ids: number[];
+}
+
+// SplitMeetingRequest represents the request body for splitting notes off a meeting
+export interface SplitMeetingRequest {
+  from_note: number;
+  to_note: number;
+  // subject of the new meeting; defaults to the original's
+  subject?: string;
+}
+
+// MoveNotesRequest represents the request body for moving notes to another meeting
+export interface MoveNotesRequest {
+  note_ids: number[];
+  meeting_id: number;
+}
+
 // Config represents the application configuration
 export interface Config {
   llm_provider_url: string;
@@ -79,6 +223,8 @@ export interface Config {
   language: string;
   llm_prompt_summary: string;
   llm_prompt_enhance: string;
+  // Keys set by the operator (file, environment or flags) that cannot be changed here
+  locked: string[];
 }
 
 // EnhanceNoteRequest is the body sent to the note enhancement endpoint
@@ -100,3 +246,55 @@ export interface ConfigUpdateRequest {
   llm_prompt_summary: string;
   llm_prompt_enhance: string;
 }
+
+// MeetingShare is a public read-only link to a meeting
+export interface MeetingShare {
+  id: number;
+  meeting_id: number;
+  include_notes: boolean;
+  created_by: string;
+  created_at: string;
+  expires_at: string;
+  revoked_at: string | null;
+  last_accessed_at: string | null;
+  access_count: number;
+}
+
+// CreateShareRequest represents the request body for creating a share
+export interface CreateShareRequest {
+  expires_in_hours: number;
+  include_notes: boolean;
+}
+
+// CreateShareResponse carries the share URL, which is only returned once
+export interface CreateShareResponse extends MeetingShare {
+  token: string;
+  url: string;
+}
+
+// APITokenScope limits what an API token may do
+export type APITokenScope = 'read' | 'notes-write' | 'admin';
+
+// APIToken is a personal token for scripts, sent as "Authorization: Bearer"
+export interface APIToken {
+  id: number;
+  name: string;
+  owner: string;
+  scope: APITokenScope;
+  created_at: string;
+  expires_at: string;
+  last_used_at: string | null;
+  revoked_at: string | null;
+}
+
+// CreateAPITokenRequest represents the request body for creating a token
+export interface CreateAPITokenRequest {
+  name: string;
+  scope: APITokenScope;
+  expires_in_days: number;
+}
+
+// CreateAPITokenResponse carries the token, which is only returned once
+export interface CreateAPITokenResponse extends APIToken {
+  token: string;
+}
@@ -14,6 +14,7 @@ function ConfigPanel(): React.JSX.Element {
   const [error, setError] = useState<string | null>(null);
   const [success, setSuccess] = useState(false);
   const [originalKey, setOriginalKey] = useState<string>('');
+  const [locked, setLocked] = useState<string[]>([]);
   const [formData, setFormData] = useState<ConfigUpdateRequest>({
     llm_provider_url: '',
     llm_api_key: '',
@@ -35,6 +36,7 @@ function ConfigPanel(): React.JSX.Element {
             llm_prompt_enhance: config.llm_prompt_enhance || '',
           });
           setOriginalKey(config.llm_api_key || '');
+          setLocked(config.locked ?? []);
           setError(null);
         }
       })
@@ -71,6 +73,7 @@ function ConfigPanel(): React.JSX.Element {
         llm_prompt_enhance: result.llm_prompt_enhance || '',
       });
       setOriginalKey(result.llm_api_key || '');
+      setLocked(result.locked ?? []);
       setSuccess(true);
 
       // Auto-dismiss success message after 3 seconds
@@ -88,6 +91,8 @@ function ConfigPanel(): React.JSX.Element {
     setFormData(prev => ({ ...prev, [field]: value }));
   };
 
+  const isLocked = (field: keyof ConfigUpdateRequest) => locked.includes(field);
+
   if (loading) {
     return <LoadingSpinner />;
   }
@@ -125,8 +130,11 @@ function ConfigPanel(): React.JSX.Element {
               value={formData.llm_provider_url}
               onChange={(e) => handleChange('llm_provider_url', e.target.value)}
               placeholder={t('config.providerUrlPlaceholder')}
+              disabled={isLocked('llm_provider_url')}
             />
-            <small className="hint">{t('config.providerUrlHint')}</small>
+            <small className="hint">
+              {isLocked('llm_provider_url') ? t('config.lockedHint') : t('config.providerUrlHint')}
+            </small>
           </div>
 
           <div className="form-group">
@@ -137,8 +145,11 @@ function ConfigPanel(): React.JSX.Element {
               value={formData.llm_api_key}
               onChange={(e) => handleChange('llm_api_key', e.target.value)}
               placeholder={t('config.apiKeyPlaceholder')}
+              disabled={isLocked('llm_api_key')}
             />
-            <small className="hint">{t('config.apiKeyHint')}</small>
+            <small className="hint">
+              {isLocked('llm_api_key') ? t('config.lockedHint') : t('config.apiKeyHint')}
+            </small>
           </div>
 
           <div className="form-group">
@@ -149,8 +160,11 @@ function ConfigPanel(): React.JSX.Element {
               value={formData.llm_model}
               onChange={(e) => handleChange('llm_model', e.target.value)}
               placeholder={t('config.modelPlaceholder')}
+              disabled={isLocked('llm_model')}
             />
-            <small className="hint">{t('config.modelHint')}</small>
+            <small className="hint">
+              {isLocked('llm_model') ? t('config.lockedHint') : t('config.modelHint')}
+            </small>
           </div>
         </section>
 
@@ -0,0 +1,30 @@
+import type { ReactNode } from 'react';
+import { useTranslation } from 'react-i18next';
+import './ConflictNotice.css';
+
+interface ConflictNoticeProps {
+  message: string;
+  onOverwrite: () => void;
+  onReload: () => void;
+  // children show the current server state to merge from
+  children?: ReactNode;
+}
+
+export function ConflictNotice({ message, onOverwrite, onReload, children }: ConflictNoticeProps) {
+  const { t } = useTranslation();
+
+  return (
+    <div className="conflict-notice" role="alert">
+      <p className="conflict-text">{message}</p>
+      {children}
+      <div className="conflict-actions">
+        <button type="button" className="btn conflict-reload" onClick={onReload}>
+          {t('conflict.reload')}
+        </button>
+        <button type="button" className="btn conflict-overwrite" onClick={onOverwrite}>
+          {t('conflict.overwrite')}
+        </button>
+      </div>
+    </div>
+  );
+}
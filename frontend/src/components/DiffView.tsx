@@ -0,0 +1,23 @@
+import './DiffView.css';
+
+interface DiffViewProps {
+  diff: string;
+}
+
+function lineClass(line: string): string {
+  if (line.startsWith('@@')) return 'diff-hunk';
+  if (line.startsWith('+') && !line.startsWith('+++')) return 'diff-added';
+  if (line.startsWith('-') && !line.startsWith('---')) return 'diff-removed';
+  return '';
+}
+
+// DiffView renders a unified diff with added and removed lines highlighted
+export function DiffView({ diff }: DiffViewProps) {
+  return (
+    <pre className="diff-view">
+      {diff.split('\n').map((line, i) => (
+        <div key={i} className={lineClass(line)}>{line || ' '}</div>
+      ))}
+    </pre>
+  );
+}
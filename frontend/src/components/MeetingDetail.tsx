@@ -1,11 +1,17 @@
-import { useState, useEffect } from 'react';
+import { useState, useEffect, useCallback } from 'react';
 import { useTranslation } from 'react-i18next';
-import { fetchMeeting, summarizeMeeting, updateMeeting } from '../api/client';
-import type { Meeting } from '../api/types';
+import { fetchMeeting, summarizeMeeting, patchMeeting } from '../api/client';
+import type { Meeting, ServerEvent } from '../api/types';
+import { useServerEvents } from '../hooks/useServerEvents';
+import { usePresence } from '../hooks/usePresence';
 import { LoadingSpinner } from './LoadingSpinner';
 import { ErrorMessage } from './ErrorMessage';
 import { NoteList } from './NoteList';
 import { NoteForm } from './NoteForm';
+import { SharePanel } from './SharePanel';
+import { SummaryHistory } from './SummaryHistory';
+import { PresenceBar } from './PresenceBar';
+import { MergeSplitPanel } from './MergeSplitPanel';
 import './MeetingDetail.css';
 
 interface MeetingDetailProps {
@@ -26,6 +32,9 @@ export function MeetingDetail({ meetingId, onBack, onEdit }: MeetingDetailProps)
   const [summarizing, setSummarizing] = useState(false);
   const [summaryError, setSummaryError] = useState<string | null>(null);
   const [previousSummary, setPreviousSummary] = useState<string | null>(null);
+  const [showShare, setShowShare] = useState(false);
+  const [showMergeSplit, setShowMergeSplit] = useState(false);
+  const [showSummaryHistory, setShowSummaryHistory] = useState(false);
 
   useEffect(() => {
     let cancelled = false;
@@ -42,6 +51,22 @@ export function MeetingDetail({ meetingId, onBack, onEdit }: MeetingDetailProps)
     return () => { cancelled = true; };
   }, [meetingId]);
 
+  // Show meeting and summary changes made by other clients
+  const handleServerEvent = useCallback((event: ServerEvent | null) => {
+    if (!event) {
+      fetchMeeting(meetingId).then(setMeeting).catch(() => { /* keep showing the loaded meeting */ });
+      return;
+    }
+    if (event.meeting_id !== meetingId) return;
+    if (event.type === 'meeting.updated' || event.type === 'summary.generated') {
+      setMeeting(event.data as Meeting);
+    }
+  }, [meetingId]);
+
+  useServerEvents(handleServerEvent);
+
+  const presence = usePresence(meetingId, noteView === 'edit' ? editingNoteId : undefined);
+
   const handleNoteSuccess = () => {
     setNoteView('list');
     setEditingNoteId(undefined);
@@ -87,15 +112,7 @@ export function MeetingDetail({ meetingId, onBack, onEdit }: MeetingDetailProps)
       setSummarizing(true);
       setSummaryError(null);
 
-      const updatedMeeting = await updateMeeting(meetingId, {
-        subject: meeting.subject,
-        meeting_date: meeting.meeting_date,
-        start_time: meeting.start_time,
-        end_time: meeting.end_time,
-        participants: meeting.participants,
-        summary: previousSummary,
-        keywords: meeting.keywords,
-      });
+      const updatedMeeting = await patchMeeting(meetingId, { summary: previousSummary });
 
       setMeeting(updatedMeeting);
       setPreviousSummary(null);
@@ -135,6 +152,27 @@ export function MeetingDetail({ meetingId, onBack, onEdit }: MeetingDetailProps)
               ↶
             </button>
           )}
+          <button
+            onClick={() => setShowSummaryHistory(!showSummaryHistory)}
+            className="btn btn-icon btn-history"
+            title={t('summaryHistory.title')}
+          >
+            🕘
+          </button>
+          <button
+            onClick={() => setShowShare(!showShare)}
+            className="btn btn-icon btn-share"
+            title={t('meetingDetail.share')}
+          >
+            🔗
+          </button>
+          <button
+            onClick={() => setShowMergeSplit(!showMergeSplit)}
+            className="btn btn-icon btn-merge"
+            title={t('mergeSplit.title')}
+          >
+            ⇄
+          </button>
           <button onClick={onEdit} className="btn btn-icon btn-edit" title={t('meetingDetail.editMeeting')}>
             ✏
           </button>
@@ -155,6 +193,7 @@ export function MeetingDetail({ meetingId, onBack, onEdit }: MeetingDetailProps)
             <span className="metadata-value">
               {meeting.start_time}
               {meeting.end_time && ` - ${meeting.end_time}`}
+              {meeting.timezone && ` (${meeting.timezone})`}
             </span>
           </div>
           {meeting.participants && (
@@ -184,10 +223,19 @@ export function MeetingDetail({ meetingId, onBack, onEdit }: MeetingDetailProps)
         </div>
       </div>
 
+      {showSummaryHistory && <SummaryHistory meeting={meeting} onPromoted={setMeeting} />}
+
+      {showShare && <SharePanel meetingId={meetingId} />}
+
+      {showMergeSplit && <MergeSplitPanel meeting={meeting} onMerged={setMeeting} />}
+
+      <PresenceBar users={presence.users} />
+
       <div className="notes-section">
         {noteView === 'list' && (
           <NoteList
             meetingId={meetingId}
+            locks={presence.locks}
             onEdit={handleEditNote}
             onAdd={handleAddNote}
           />
@@ -1,9 +1,10 @@
 import { useState, useEffect, FormEvent, useRef } from 'react';
 import { useTranslation } from 'react-i18next';
-import { fetchMeeting, createMeeting, updateMeeting } from '../api/client';
-import type { CreateMeetingRequest } from '../api/types';
+import { fetchMeeting, createMeeting, updateMeeting, ConflictError } from '../api/client';
+import type { CreateMeetingRequest, Meeting } from '../api/types';
 import { LoadingSpinner } from './LoadingSpinner';
 import { ErrorMessage } from './ErrorMessage';
+import { ConflictNotice } from './ConflictNotice';
 import {
   MaxSubjectLength,
   MaxParticipantsLength,
@@ -12,6 +13,20 @@ import {
 } from '../generated/validationRules';
 import './MeetingForm.css';
 
+// toFormData returns the editable fields of a meeting
+function toFormData(meeting: Meeting): CreateMeetingRequest {
+  return {
+    subject: meeting.subject,
+    meeting_date: meeting.meeting_date,
+    start_time: meeting.start_time,
+    end_time: meeting.end_time,
+    participants: meeting.participants,
+    summary: meeting.summary,
+    keywords: meeting.keywords,
+    timezone: meeting.timezone || undefined,
+  };
+}
+
 interface MeetingFormProps {
   meetingId?: number;
   onSuccess: () => void;
@@ -22,6 +37,9 @@ export function MeetingForm({ meetingId, onSuccess, onCancel }: MeetingFormProps
   const { t } = useTranslation();
   const [loading, setLoading] = useState(!!meetingId);
   const [error, setError] = useState<string | null>(null);
+  // version is the one the form was loaded at; saving fails if it has changed since
+  const [version, setVersion] = useState<number | undefined>(undefined);
+  const [conflict, setConflict] = useState<Meeting | null>(null);
   const subjectInputRef = useRef<HTMLInputElement>(null);
 
   // Get current date and time for default values
@@ -37,6 +55,7 @@ export function MeetingForm({ meetingId, onSuccess, onCancel }: MeetingFormProps
     participants: null,
     summary: null,
     keywords: null,
+    timezone: meetingId ? undefined : Intl.DateTimeFormat().resolvedOptions().timeZone,
   });
 
   // Load meeting data if editing
@@ -46,15 +65,8 @@ export function MeetingForm({ meetingId, onSuccess, onCancel }: MeetingFormProps
       fetchMeeting(meetingId)
         .then((meeting) => {
           if (!cancelled) {
-            setFormData({
-              subject: meeting.subject,
-              meeting_date: meeting.meeting_date,
-              start_time: meeting.start_time,
-              end_time: meeting.end_time,
-              participants: meeting.participants,
-              summary: meeting.summary,
-              keywords: meeting.keywords,
-            });
+            setFormData(toFormData(meeting));
+            setVersion(meeting.version);
           }
         })
         .catch((err) => {
@@ -70,25 +82,41 @@ export function MeetingForm({ meetingId, onSuccess, onCancel }: MeetingFormProps
     }
   }, [meetingId]);
 
-  const handleSubmit = async (e: FormEvent) => {
-    e.preventDefault();
+  const save = async (expectedVersion: number | undefined) => {
     setLoading(true);
     setError(null);
+    setConflict(null);
 
     try {
       if (meetingId) {
-        await updateMeeting(meetingId, formData);
+        await updateMeeting(meetingId, formData, expectedVersion);
       } else {
         await createMeeting(formData);
       }
       onSuccess();
     } catch (err) {
-      setError(err instanceof Error ? err.message : 'Failed to save meeting');
+      if (err instanceof ConflictError) {
+        setConflict(err.current as Meeting);
+      } else {
+        setError(err instanceof Error ? err.message : 'Failed to save meeting');
+      }
     } finally {
       setLoading(false);
     }
   };
 
+  const handleSubmit = (e: FormEvent) => {
+    e.preventDefault();
+    save(version);
+  };
+
+  const handleReload = () => {
+    if (!conflict) return;
+    setFormData(toFormData(conflict));
+    setVersion(conflict.version);
+    setConflict(null);
+  };
+
   const handleChange = (field: keyof CreateMeetingRequest, value: string) => {
     setFormData((prev) => ({
       ...prev,
@@ -104,6 +132,14 @@ export function MeetingForm({ meetingId, onSuccess, onCancel }: MeetingFormProps
 
       {error && <ErrorMessage message={error} />}
 
+      {conflict && (
+        <ConflictNotice
+          message={t('conflict.meeting')}
+          onOverwrite={() => save(conflict.version)}
+          onReload={handleReload}
+        />
+      )}
+
       <form onSubmit={handleSubmit}>
         <div className="form-group">
           <label htmlFor="subject">
@@ -0,0 +1,119 @@
+import { useState, useEffect } from 'react';
+import { useTranslation } from 'react-i18next';
+import { fetchMeetings, mergeMeeting, splitMeeting } from '../api/client';
+import type { Meeting } from '../api/types';
+import { ErrorMessage } from './ErrorMessage';
+import './MergeSplitPanel.css';
+
+interface MergeSplitPanelProps {
+  meeting: Meeting;
+  onMerged: (meeting: Meeting) => void;
+}
+
+export function MergeSplitPanel({ meeting, onMerged }: MergeSplitPanelProps) {
+  const { t } = useTranslation();
+  const [others, setOthers] = useState<Meeting[]>([]);
+  const [mergeId, setMergeId] = useState<number | null>(null);
+  const [fromNote, setFromNote] = useState(1);
+  const [toNote, setToNote] = useState(1);
+  const [subject, setSubject] = useState('');
+  const [splitOff, setSplitOff] = useState<Meeting | null>(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    fetchMeetings('meeting_date', 'desc')
+      .then((data) => {
+        if (!cancelled) setOthers(data.filter((m) => m.id !== meeting.id));
+      })
+      .catch((err) => {
+        if (!cancelled) setError(err instanceof Error ? err.message : t('mergeSplit.loadError'));
+      });
+    return () => { cancelled = true; };
+  }, [meeting.id, t]);
+
+  const handleMerge = async () => {
+    const other = others.find((m) => m.id === mergeId);
+    if (!other || !window.confirm(t('mergeSplit.confirmMerge', { subject: other.subject }))) return;
+    try {
+      setBusy(true);
+      setError(null);
+      onMerged(await mergeMeeting(meeting.id, other.id));
+      setOthers((prev) => prev.filter((m) => m.id !== other.id));
+      setMergeId(null);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('mergeSplit.mergeError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleSplit = async () => {
+    try {
+      setBusy(true);
+      setError(null);
+      setSplitOff(await splitMeeting(meeting.id, { from_note: fromNote, to_note: toNote, subject: subject.trim() }));
+      setSubject('');
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('mergeSplit.splitError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <div className="merge-split-panel card-section">
+      <h2 className="section-heading">{t('mergeSplit.title')}</h2>
+
+      {error && <ErrorMessage message={error} />}
+
+      <p className="merge-split-hint">{t('mergeSplit.mergeHint')}</p>
+      <div className="merge-split-row">
+        <select
+          value={mergeId ?? ''}
+          onChange={(e) => setMergeId(e.target.value ? Number(e.target.value) : null)}
+          disabled={busy}
+          aria-label={t('mergeSplit.chooseMeeting')}
+        >
+          <option value="">{t('mergeSplit.chooseMeeting')}</option>
+          {others.map((m) => (
+            <option key={m.id} value={m.id}>{m.meeting_date} · {m.subject}</option>
+          ))}
+        </select>
+        <button onClick={handleMerge} className="btn btn-submit" disabled={busy || mergeId === null}>
+          {t('mergeSplit.merge')}
+        </button>
+      </div>
+
+      <p className="merge-split-hint">{t('mergeSplit.splitHint')}</p>
+      <div className="merge-split-row">
+        <label>
+          {t('mergeSplit.fromNote')}{' '}
+          <input type="number" min={1} value={fromNote} onChange={(e) => setFromNote(Number(e.target.value))} disabled={busy} />
+        </label>
+        <label>
+          {t('mergeSplit.toNote')}{' '}
+          <input type="number" min={fromNote} value={toNote} onChange={(e) => setToNote(Number(e.target.value))} disabled={busy} />
+        </label>
+        <input
+          type="text"
+          value={subject}
+          onChange={(e) => setSubject(e.target.value)}
+          placeholder={meeting.subject}
+          aria-label={t('mergeSplit.subject')}
+          disabled={busy}
+        />
+        <button onClick={handleSplit} className="btn btn-submit" disabled={busy || toNote < fromNote}>
+          {t('mergeSplit.split')}
+        </button>
+      </div>
+
+      {splitOff && (
+        <p className="merge-split-hint">
+          {t('mergeSplit.splitDone', { subject: splitOff.subject, date: splitOff.meeting_date })}
+        </p>
+      )}
+    </div>
+  );
+}
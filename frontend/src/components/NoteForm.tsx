@@ -1,9 +1,11 @@
 import { useState, useEffect, FormEvent, useRef } from 'react';
 import { useTranslation } from 'react-i18next';
-import { fetchNote, createNote, updateNote, enhanceNote } from '../api/client';
-import type { CreateNoteRequest, UpdateNoteRequest } from '../api/types';
+import { fetchNote, createNote, updateNote, enhanceNote, ConflictError, LockedError } from '../api/client';
+import type { CreateNoteRequest, Note, UpdateNoteRequest } from '../api/types';
 import { LoadingSpinner } from './LoadingSpinner';
 import { ErrorMessage } from './ErrorMessage';
+import { ConflictNotice } from './ConflictNotice';
+import { useNoteLock } from '../hooks/useNoteLock';
 import { MaxNoteContentLength } from '../generated/validationRules';
 import './NoteForm.css';
 
@@ -19,10 +21,14 @@ export function NoteForm({ meetingId, noteId, onSuccess, onCancel }: NoteFormPro
   const [loading, setLoading] = useState(!!noteId);
   const [error, setError] = useState<string | null>(null);
   const [content, setContent] = useState('');
+  // version is the one the form was loaded at; saving fails if it has changed since
+  const [version, setVersion] = useState<number | undefined>(undefined);
+  const [conflict, setConflict] = useState<Note | null>(null);
   const contentTextareaRef = useRef<HTMLTextAreaElement>(null);
   const [enhancing, setEnhancing] = useState(false);
   const [enhanceError, setEnhanceError] = useState<string | null>(null);
   const [previousContent, setPreviousContent] = useState<string | null>(null);
+  const { heldBy, takeOver } = useNoteLock(noteId);
 
   // Load note data if editing
   useEffect(() => {
@@ -30,7 +36,10 @@ export function NoteForm({ meetingId, noteId, onSuccess, onCancel }: NoteFormPro
       let cancelled = false;
       fetchNote(noteId)
         .then((note) => {
-          if (!cancelled) setContent(note.content);
+          if (!cancelled) {
+            setContent(note.content);
+            setVersion(note.version);
+          }
         })
         .catch((err) => {
           if (!cancelled) setError(err instanceof Error ? err.message : 'Failed to load note');
@@ -45,15 +54,18 @@ export function NoteForm({ meetingId, noteId, onSuccess, onCancel }: NoteFormPro
     }
   }, [noteId]);
 
-  const handleSubmit = async (e: FormEvent) => {
-    e.preventDefault();
+  const save = async (expectedVersion: number | undefined) => {
     setLoading(true);
     setError(null);
+    setConflict(null);
 
     try {
       if (noteId) {
-        const updateData: UpdateNoteRequest = { content };
-        await updateNote(noteId, updateData);
+        const updateData: UpdateNoteRequest = {
+          content,
+          source: previousContent !== null ? 'llm-enhance' : 'manual',
+        };
+        await updateNote(noteId, updateData, expectedVersion);
       } else {
         const createData: CreateNoteRequest = {
           meeting_id: meetingId,
@@ -63,12 +75,31 @@ export function NoteForm({ meetingId, noteId, onSuccess, onCancel }: NoteFormPro
       }
       onSuccess();
     } catch (err) {
-      setError(err instanceof Error ? err.message : 'Failed to save note');
+      if (err instanceof ConflictError) {
+        setConflict(err.current as Note);
+      } else if (err instanceof LockedError) {
+        setError(t('locks.saveBlocked', { name: err.lock.holder.name }));
+      } else {
+        setError(err instanceof Error ? err.message : 'Failed to save note');
+      }
     } finally {
       setLoading(false);
     }
   };
 
+  const handleSubmit = (e: FormEvent) => {
+    e.preventDefault();
+    save(version);
+  };
+
+  const handleReload = () => {
+    if (!conflict) return;
+    setContent(conflict.content);
+    setVersion(conflict.version);
+    setPreviousContent(null);
+    setConflict(null);
+  };
+
   const handleEnhance = async () => {
     if (!noteId) return; // Only available in edit mode
 
@@ -126,9 +157,29 @@ export function NoteForm({ meetingId, noteId, onSuccess, onCancel }: NoteFormPro
         )}
       </div>
 
+      {heldBy && (
+        <div className="note-lock-notice" role="status">
+          <span>{t('locks.heldBy', { name: heldBy.holder.name })}</span>
+          <button type="button" className="btn note-lock-takeover" onClick={takeOver}>
+            {t('locks.takeOver')}
+          </button>
+        </div>
+      )}
+
       {error && <ErrorMessage message={error} />}
       {enhanceError && <ErrorMessage message={enhanceError} />}
 
+      {conflict && (
+        <ConflictNotice
+          message={t('conflict.note')}
+          onOverwrite={() => save(conflict.version)}
+          onReload={handleReload}
+        >
+          <small>{t('conflict.current')}</small>
+          <pre className="conflict-current">{conflict.content}</pre>
+        </ConflictNotice>
+      )}
+
       <form onSubmit={handleSubmit}>
         <div className="form-group">
           <label htmlFor="content">
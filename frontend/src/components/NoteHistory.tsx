@@ -0,0 +1,112 @@
+import { useState, useEffect } from 'react';
+import { useTranslation } from 'react-i18next';
+import { fetchNoteRevisions, fetchNoteRevisionDiff, restoreNoteRevision } from '../api/client';
+import type { NoteRevision, NoteRevisionDiff } from '../api/types';
+import { ErrorMessage } from './ErrorMessage';
+import { DiffView } from './DiffView';
+import './NoteHistory.css';
+
+interface NoteHistoryProps {
+  noteId: number;
+  // updatedAt reloads the revisions whenever the note changes
+  updatedAt: string;
+  onRestored: () => Promise<void>;
+}
+
+export function NoteHistory({ noteId, updatedAt, onRestored }: NoteHistoryProps) {
+  const { t } = useTranslation();
+  const [revisions, setRevisions] = useState<NoteRevision[]>([]);
+  const [diff, setDiff] = useState<NoteRevisionDiff | null>(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    setDiff(null);
+    fetchNoteRevisions(noteId)
+      .then((data) => {
+        if (!cancelled) setRevisions(data);
+      })
+      .catch((err) => {
+        if (!cancelled) setError(err instanceof Error ? err.message : t('history.loadError'));
+      });
+    return () => { cancelled = true; };
+  }, [noteId, updatedAt, t]);
+
+  const latest = revisions.length > 0 ? revisions[0].revision : 0;
+
+  const handleDiff = async (revision: number) => {
+    try {
+      setBusy(true);
+      setError(null);
+      setDiff(await fetchNoteRevisionDiff(noteId, revision, latest));
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('history.diffError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleRestore = async (revision: number) => {
+    if (!window.confirm(t('history.confirmRestore', { revision }))) return;
+    try {
+      setBusy(true);
+      setError(null);
+      await restoreNoteRevision(noteId, revision);
+      await onRestored();
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('history.restoreError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <div className="note-history">
+      <h4>{t('history.title')}</h4>
+
+      {error && <ErrorMessage message={error} />}
+
+      <ul className="history-list">
+        {revisions.map((rev) => (
+          <li key={rev.id} className="history-item">
+            <span>
+              #{rev.revision} · {new Date(rev.created_at).toLocaleString()} · {rev.author} · {t(`history.source.${rev.source}`)}
+            </span>
+            {rev.revision !== latest && (
+              <span className="history-actions">
+                <button
+                  onClick={() => handleDiff(rev.revision)}
+                  className="btn btn-icon btn-diff"
+                  title={t('history.diff')}
+                  disabled={busy}
+                >
+                  ⇄
+                </button>
+                <button
+                  onClick={() => handleRestore(rev.revision)}
+                  className="btn btn-icon btn-restore"
+                  title={t('history.restore')}
+                  disabled={busy}
+                >
+                  ↺
+                </button>
+              </span>
+            )}
+          </li>
+        ))}
+      </ul>
+
+      {diff && (
+        <div className="history-diff">
+          <small>{t('history.diffTitle', { from: diff.from, to: diff.to })}</small>
+          {diff.diff === '' ? (
+            <p className="history-hint">{t('history.noChanges')}</p>
+          ) : (
+            <DiffView diff={diff.diff} />
+          )}
+        </div>
+      )}
+    </div>
+  );
+}
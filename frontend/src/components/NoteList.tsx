@@ -1,24 +1,33 @@
 import { useState } from 'react';
 import { useTranslation } from 'react-i18next';
 import { useNotes } from '../hooks/useNotes';
-import { enhanceNote, updateNote } from '../api/client';
+import { enhanceNote, updateNote, fetchMeetings } from '../api/client';
 import { LoadingSpinner } from './LoadingSpinner';
 import { ErrorMessage } from './ErrorMessage';
+import { NoteHistory } from './NoteHistory';
+import type { Meeting, NoteLock } from '../api/types';
 import './NoteList.css';
 
 interface NoteListProps {
   meetingId: number;
+  // locks are the edit locks other users hold on notes of the meeting
+  locks?: NoteLock[];
   onEdit: (noteId: number) => void;
   onAdd: () => void;
 }
 
-export function NoteList({ meetingId, onEdit, onAdd }: NoteListProps) {
+export function NoteList({ meetingId, locks = [], onEdit, onAdd }: NoteListProps) {
   const { t } = useTranslation();
-  const { notes, loading, error, handleDelete, handleReorder, refresh } = useNotes(meetingId);
+  const { notes, loading, error, handleDelete, handleReorder, handleMove, refresh } = useNotes(meetingId);
   const [enhancingId, setEnhancingId] = useState<number | null>(null);
   const [enhanceError, setEnhanceError] = useState<string | null>(null);
   const [previousContent, setPreviousContent] = useState<{ noteId: number; content: string } | null>(null);
   const [reorderingId, setReorderingId] = useState<number | null>(null);
+  const [historyId, setHistoryId] = useState<number | null>(null);
+  const [movingId, setMovingId] = useState<number | null>(null);
+  const [targets, setTargets] = useState<Meeting[]>([]);
+
+  const lockHolder = (noteId: number) => locks.find((l) => l.note_id === noteId)?.holder.name;
 
   const handleReorderNote = async (id: number, direction: 'up' | 'down') => {
     try {
@@ -31,6 +40,26 @@ export function NoteList({ meetingId, onEdit, onAdd }: NoteListProps) {
     }
   };
 
+  const toggleMove = (id: number) => {
+    if (movingId === id) {
+      setMovingId(null);
+      return;
+    }
+    setMovingId(id);
+    fetchMeetings('meeting_date', 'desc')
+      .then((meetings) => setTargets(meetings.filter((m) => m.id !== meetingId)))
+      .catch(() => setTargets([]));
+  };
+
+  const handleMoveNote = async (id: number, targetMeetingId: number) => {
+    try {
+      await handleMove([id], targetMeetingId);
+      setMovingId(null);
+    } catch {
+      alert(t('notes.moveFailed'));
+    }
+  };
+
   const confirmDelete = async (id: number, noteNumber: number) => {
     if (window.confirm(t('notes.confirmDelete', { number: noteNumber }))) {
       try {
@@ -51,7 +80,7 @@ export function NoteList({ meetingId, onEdit, onAdd }: NoteListProps) {
       setPreviousContent({ noteId, content: note.content });
 
       const result = await enhanceNote(noteId, note.content);
-      await updateNote(noteId, { content: result.content });
+      await updateNote(noteId, { content: result.content, source: 'llm-enhance' });
       await refresh();
     } catch (err) {
       setEnhanceError(err instanceof Error ? err.message : t('notes.enhanceError'));
@@ -124,6 +153,11 @@ export function NoteList({ meetingId, onEdit, onAdd }: NoteListProps) {
                   </div>
                 )}
                 <span className="note-number">#{note.note_number}</span>
+                {lockHolder(note.id) && (
+                  <span className="note-lock" title={t('locks.lockedBy', { name: lockHolder(note.id) })}>
+                    🔒 {lockHolder(note.id)}
+                  </span>
+                )}
                 <div className="note-actions">
                   <button
                     onClick={() => handleEnhance(note.id)}
@@ -143,6 +177,20 @@ export function NoteList({ meetingId, onEdit, onAdd }: NoteListProps) {
                       ↶
                     </button>
                   )}
+                  <button
+                    onClick={() => setHistoryId(historyId === note.id ? null : note.id)}
+                    className="btn btn-icon btn-history"
+                    title={t('history.title')}
+                  >
+                    🕘
+                  </button>
+                  <button
+                    onClick={() => toggleMove(note.id)}
+                    className="btn btn-icon btn-move"
+                    title={t('notes.moveToMeeting')}
+                  >
+                    ↪
+                  </button>
                   <button
                     onClick={() => onEdit(note.id)}
                     className="btn btn-icon btn-edit"
@@ -159,12 +207,29 @@ export function NoteList({ meetingId, onEdit, onAdd }: NoteListProps) {
                   </button>
                 </div>
               </div>
+              {movingId === note.id && (
+                <div className="note-move">
+                  <select
+                    defaultValue=""
+                    onChange={(e) => handleMoveNote(note.id, Number(e.target.value))}
+                    aria-label={t('notes.moveToMeeting')}
+                  >
+                    <option value="" disabled>{t('notes.chooseMeeting')}</option>
+                    {targets.map((m) => (
+                      <option key={m.id} value={m.id}>{m.meeting_date} · {m.subject}</option>
+                    ))}
+                  </select>
+                </div>
+              )}
               <div className="note-content">
                 {note.content}
               </div>
               <div className="note-footer">
                 <small>{t('notes.updated', { date: new Date(note.updated_at).toLocaleString() })}</small>
               </div>
+              {historyId === note.id && (
+                <NoteHistory noteId={note.id} updatedAt={note.updated_at} onRestored={refresh} />
+              )}
             </div>
           ))}
         </div>
@@ -0,0 +1,31 @@
+import { useTranslation } from 'react-i18next';
+import type { PresenceEntry } from '../api/types';
+import './PresenceBar.css';
+
+interface PresenceBarProps {
+  users: PresenceEntry[];
+}
+
+export function PresenceBar({ users }: PresenceBarProps) {
+  const { t } = useTranslation();
+
+  if (users.length === 0) return null;
+
+  return (
+    <div className="presence-bar" aria-label={t('presence.title')}>
+      <span className="presence-label">{t('presence.title')}</span>
+      <ul className="presence-users">
+        {users.map((user) => (
+          <li
+            key={user.login}
+            className={`presence-user presence-${user.state}`}
+            title={user.login}
+          >
+            {user.name}
+            {user.state === 'editing' && <small> · {t('presence.editing')}</small>}
+          </li>
+        ))}
+      </ul>
+    </div>
+  );
+}
@@ -0,0 +1,149 @@
+import { useState, useEffect } from 'react';
+import { useTranslation } from 'react-i18next';
+import { createShare, fetchShares, revokeShare } from '../api/client';
+import type { CreateShareResponse, MeetingShare } from '../api/types';
+import { ErrorMessage } from './ErrorMessage';
+import './SharePanel.css';
+
+interface SharePanelProps {
+  meetingId: number;
+}
+
+// Lifetimes offered for new share links, in hours
+const EXPIRY_OPTIONS = [24, 168, 720];
+
+function isActive(share: MeetingShare): boolean {
+  return share.revoked_at === null && new Date(share.expires_at) > new Date();
+}
+
+export function SharePanel({ meetingId }: SharePanelProps) {
+  const { t } = useTranslation();
+  const [shares, setShares] = useState<MeetingShare[]>([]);
+  const [expiresInHours, setExpiresInHours] = useState(168);
+  const [includeNotes, setIncludeNotes] = useState(false);
+  const [created, setCreated] = useState<CreateShareResponse | null>(null);
+  const [copied, setCopied] = useState(false);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    fetchShares(meetingId)
+      .then((data) => {
+        if (!cancelled) setShares(data);
+      })
+      .catch((err) => {
+        if (!cancelled) setError(err instanceof Error ? err.message : t('share.loadError'));
+      });
+    return () => { cancelled = true; };
+  }, [meetingId, t]);
+
+  const handleCreate = async () => {
+    try {
+      setBusy(true);
+      setError(null);
+      setCopied(false);
+      const share = await createShare(meetingId, { expires_in_hours: expiresInHours, include_notes: includeNotes });
+      setCreated(share);
+      setShares(await fetchShares(meetingId));
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('share.createError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleCopy = async () => {
+    if (!created) return;
+    try {
+      await navigator.clipboard.writeText(created.url);
+      setCopied(true);
+    } catch {
+      // Clipboard access needs a secure context; the link stays selectable
+      setCopied(false);
+    }
+  };
+
+  const handleRevoke = async (id: number) => {
+    if (!window.confirm(t('share.confirmRevoke'))) return;
+    try {
+      setBusy(true);
+      setError(null);
+      await revokeShare(id);
+      if (created?.id === id) setCreated(null);
+      setShares(await fetchShares(meetingId));
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('share.revokeError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <div className="share-panel card-section">
+      <h2 className="section-heading">{t('share.title')}</h2>
+      <p className="share-hint">{t('share.hint')}</p>
+
+      {error && <ErrorMessage message={error} />}
+
+      <div className="share-create">
+        <label>
+          {t('share.expiresIn')}{' '}
+          <select value={expiresInHours} onChange={(e) => setExpiresInHours(Number(e.target.value))} disabled={busy}>
+            {EXPIRY_OPTIONS.map((hours) => (
+              <option key={hours} value={hours}>
+                {t('share.days', { count: hours / 24 })}
+              </option>
+            ))}
+          </select>
+        </label>
+        <label>
+          <input type="checkbox" checked={includeNotes} onChange={(e) => setIncludeNotes(e.target.checked)} disabled={busy} />{' '}
+          {t('share.includeNotes')}
+        </label>
+        <button onClick={handleCreate} className="btn btn-submit" disabled={busy}>
+          {t('share.create')}
+        </button>
+      </div>
+
+      {created && (
+        <div className="share-created">
+          <span className="data-label">{t('share.link')}:</span>
+          <input type="text" readOnly value={created.url} onFocus={(e) => e.target.select()} />
+          <button onClick={handleCopy} className="btn btn-icon" title={t('share.copy')}>
+            {copied ? '✓' : '📋'}
+          </button>
+          <p className="share-hint">{t('share.onceHint')}</p>
+        </div>
+      )}
+
+      {shares.length > 0 && (
+        <ul className="share-list">
+          {shares.map((share) => (
+            <li key={share.id} className={isActive(share) ? 'share-item' : 'share-item share-inactive'}>
+              <span>
+                {share.revoked_at
+                  ? t('share.revoked')
+                  : isActive(share)
+                    ? t('share.expires', { date: new Date(share.expires_at).toLocaleString() })
+                    : t('share.expired')}
+                {share.include_notes && ` · ${t('share.withNotes')}`}
+                {` · ${t('share.accessCount', { count: share.access_count })}`}
+              </span>
+              {isActive(share) && (
+                <button
+                  onClick={() => handleRevoke(share.id)}
+                  className="btn btn-icon btn-delete"
+                  title={t('share.revoke')}
+                  disabled={busy}
+                >
+                  ⊘
+                </button>
+              )}
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  );
+}
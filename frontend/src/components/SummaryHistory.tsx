@@ -0,0 +1,125 @@
+import { useState, useEffect } from 'react';
+import { useTranslation } from 'react-i18next';
+import { fetchSummaries, fetchSummaryDiff, promoteSummary } from '../api/client';
+import type { Meeting, MeetingSummary, NoteRevisionDiff } from '../api/types';
+import { ErrorMessage } from './ErrorMessage';
+import { DiffView } from './DiffView';
+import './NoteHistory.css';
+import './SummaryHistory.css';
+
+interface SummaryHistoryProps {
+  meeting: Meeting;
+  onPromoted: (meeting: Meeting) => void;
+}
+
+export function SummaryHistory({ meeting, onPromoted }: SummaryHistoryProps) {
+  const { t } = useTranslation();
+  const [summaries, setSummaries] = useState<MeetingSummary[]>([]);
+  const [diff, setDiff] = useState<NoteRevisionDiff | null>(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  // Reload whenever the summary changes, e.g. after summarizing or promoting
+  useEffect(() => {
+    let cancelled = false;
+    setDiff(null);
+    fetchSummaries(meeting.id)
+      .then((data) => {
+        if (!cancelled) setSummaries(data);
+      })
+      .catch((err) => {
+        if (!cancelled) setError(err instanceof Error ? err.message : t('summaryHistory.loadError'));
+      });
+    return () => { cancelled = true; };
+  }, [meeting.id, meeting.summary, t]);
+
+  const latest = summaries.length > 0 ? summaries[0].version : 0;
+
+  const handleDiff = async (version: number) => {
+    try {
+      setBusy(true);
+      setError(null);
+      setDiff(await fetchSummaryDiff(meeting.id, version, latest));
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('summaryHistory.diffError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handlePromote = async (version: number) => {
+    if (!window.confirm(t('summaryHistory.confirmPromote', { version }))) return;
+    try {
+      setBusy(true);
+      setError(null);
+      onPromoted(await promoteSummary(meeting.id, version));
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('summaryHistory.promoteError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <div className="summary-history card-section">
+      <h2 className="section-heading">{t('summaryHistory.title')}</h2>
+
+      {error && <ErrorMessage message={error} />}
+
+      {summaries.length === 0 ? (
+        <p className="history-hint">{t('summaryHistory.empty')}</p>
+      ) : (
+        <ul className="history-list">
+          {summaries.map((s) => (
+            <li key={s.id} className="history-item">
+              <span>
+                #{s.version} · {new Date(s.created_at).toLocaleString()} · {s.author}
+                {s.source === 'llm'
+                  ? ` · ${t('summaryHistory.llm', { model: s.model ?? '?' })}`
+                  : ` · ${t('summaryHistory.manual')}`}
+                {s.input_tokens !== null && ` · ${t('summaryHistory.tokens', { input: s.input_tokens, output: s.output_tokens ?? 0 })}`}
+                {s.prompt_hash && (
+                  <span className="history-provenance" title={s.prompt_hash}>
+                    {' · '}{t('summaryHistory.prompt', { hash: s.prompt_hash.slice(0, 8) })}
+                  </span>
+                )}
+                {s.promoted_from !== null && ` · ${t('summaryHistory.promotedFrom', { version: s.promoted_from })}`}
+              </span>
+              {s.version !== latest && (
+                <span className="history-actions">
+                  <button
+                    onClick={() => handleDiff(s.version)}
+                    className="btn btn-icon btn-diff"
+                    title={t('summaryHistory.diff')}
+                    disabled={busy}
+                  >
+                    ⇄
+                  </button>
+                  <button
+                    onClick={() => handlePromote(s.version)}
+                    className="btn btn-icon btn-restore"
+                    title={t('summaryHistory.promote')}
+                    disabled={busy}
+                  >
+                    ↺
+                  </button>
+                </span>
+              )}
+            </li>
+          ))}
+        </ul>
+      )}
+
+      {diff && (
+        <div className="history-diff">
+          <small>{t('summaryHistory.diffTitle', { from: diff.from, to: diff.to })}</small>
+          {diff.diff === '' ? (
+            <p className="history-hint">{t('summaryHistory.noChanges')}</p>
+          ) : (
+            <DiffView diff={diff.diff} />
+          )}
+        </div>
+      )}
+    </div>
+  );
+}
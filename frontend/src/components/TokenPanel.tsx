@@ -0,0 +1,146 @@
+import { useState, useEffect } from 'react';
+import { useTranslation } from 'react-i18next';
+import { createAPIToken, fetchAPITokens, revokeAPIToken } from '../api/client';
+import type { APIToken, APITokenScope, CreateAPITokenResponse } from '../api/types';
+import { ErrorMessage } from './ErrorMessage';
+import './TokenPanel.css';
+
+const SCOPES: APITokenScope[] = ['read', 'notes-write', 'admin'];
+
+// Lifetimes offered for new tokens, in days
+const EXPIRY_OPTIONS = [30, 90, 365];
+
+function isActive(token: APIToken): boolean {
+  return token.revoked_at === null && new Date(token.expires_at) > new Date();
+}
+
+export function TokenPanel() {
+  const { t } = useTranslation();
+  const [tokens, setTokens] = useState<APIToken[]>([]);
+  const [name, setName] = useState('');
+  const [scope, setScope] = useState<APITokenScope>('read');
+  const [expiresInDays, setExpiresInDays] = useState(90);
+  const [created, setCreated] = useState<CreateAPITokenResponse | null>(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    fetchAPITokens()
+      .then((data) => {
+        if (!cancelled) setTokens(data);
+      })
+      .catch((err) => {
+        if (!cancelled) setError(err instanceof Error ? err.message : t('tokens.loadError'));
+      });
+    return () => { cancelled = true; };
+  }, [t]);
+
+  const handleCreate = async (e: React.FormEvent) => {
+    e.preventDefault();
+    try {
+      setBusy(true);
+      setError(null);
+      const token = await createAPIToken({ name, scope, expires_in_days: expiresInDays });
+      setCreated(token);
+      setName('');
+      setTokens(await fetchAPITokens());
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('tokens.createError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handleRevoke = async (token: APIToken) => {
+    if (!window.confirm(t('tokens.confirmRevoke', { name: token.name }))) return;
+    try {
+      setBusy(true);
+      setError(null);
+      await revokeAPIToken(token.id);
+      if (created?.id === token.id) setCreated(null);
+      setTokens(await fetchAPITokens());
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('tokens.revokeError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <section className="token-panel card-section">
+      <h2 className="section-heading">{t('tokens.title')}</h2>
+      <p className="token-hint">{t('tokens.hint')}</p>
+
+      {error && <ErrorMessage message={error} />}
+
+      <form className="token-create" onSubmit={handleCreate}>
+        <input
+          type="text"
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+          placeholder={t('tokens.namePlaceholder')}
+          maxLength={100}
+          required
+          disabled={busy}
+        />
+        <select value={scope} onChange={(e) => setScope(e.target.value as APITokenScope)} disabled={busy}>
+          {SCOPES.map((s) => (
+            <option key={s} value={s}>
+              {t(`tokens.scopes.${s}`)}
+            </option>
+          ))}
+        </select>
+        <select value={expiresInDays} onChange={(e) => setExpiresInDays(Number(e.target.value))} disabled={busy}>
+          {EXPIRY_OPTIONS.map((days) => (
+            <option key={days} value={days}>
+              {t('tokens.days', { count: days })}
+            </option>
+          ))}
+        </select>
+        <button type="submit" className="btn btn-submit" disabled={busy || name.trim() === ''}>
+          {t('tokens.create')}
+        </button>
+      </form>
+
+      {created && (
+        <div className="token-created">
+          <input type="text" readOnly value={created.token} onFocus={(e) => e.target.select()} />
+          <p className="token-hint">{t('tokens.onceHint')}</p>
+        </div>
+      )}
+
+      {tokens.length > 0 && (
+        <ul className="token-list">
+          {tokens.map((token) => (
+            <li key={token.id} className={isActive(token) ? 'token-item' : 'token-item token-inactive'}>
+              <span>
+                <strong>{token.name}</strong>
+                {` · ${t(`tokens.scopes.${token.scope}`)} · `}
+                {token.revoked_at
+                  ? t('tokens.revoked')
+                  : isActive(token)
+                    ? t('tokens.expires', { date: new Date(token.expires_at).toLocaleDateString() })
+                    : t('tokens.expired')}
+                {' · '}
+                {token.last_used_at
+                  ? t('tokens.lastUsed', { date: new Date(token.last_used_at).toLocaleString() })
+                  : t('tokens.neverUsed')}
+              </span>
+              {isActive(token) && (
+                <button
+                  onClick={() => handleRevoke(token)}
+                  className="btn btn-icon btn-delete"
+                  title={t('tokens.revoke')}
+                  disabled={busy}
+                >
+                  ⊘
+                </button>
+              )}
+            </li>
+          ))}
+        </ul>
+      )}
+    </section>
+  );
+}
@@ -0,0 +1,130 @@
+import { useState, useEffect } from 'react';
+import { useTranslation } from 'react-i18next';
+import {
+  fetchTrash,
+  restoreTrashedMeeting,
+  restoreTrashedNote,
+  purgeTrashedMeeting,
+  purgeTrashedNote,
+} from '../api/client';
+import type { TrashItem } from '../api/types';
+import { ErrorMessage } from './ErrorMessage';
+import './TrashPanel.css';
+
+function truncate(text: string | undefined, maxLength: number): string {
+  if (!text) return '';
+  if (text.length <= maxLength) return text;
+  return text.slice(0, maxLength) + '...';
+}
+
+export function TrashPanel() {
+  const { t } = useTranslation();
+  const [items, setItems] = useState<TrashItem[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    fetchTrash()
+      .then((data) => {
+        if (!cancelled) setItems(data);
+      })
+      .catch((err) => {
+        if (!cancelled) setError(err instanceof Error ? err.message : t('trash.loadError'));
+      })
+      .finally(() => {
+        if (!cancelled) setLoading(false);
+      });
+    return () => { cancelled = true; };
+  }, [t]);
+
+  const handleRestore = async (item: TrashItem) => {
+    try {
+      setBusy(true);
+      setError(null);
+      if (item.type === 'meeting') {
+        await restoreTrashedMeeting(item.id);
+      } else {
+        await restoreTrashedNote(item.id);
+      }
+      setItems(await fetchTrash());
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('trash.restoreError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  const handlePurge = async (item: TrashItem) => {
+    if (!window.confirm(t('trash.confirmPurge'))) return;
+    try {
+      setBusy(true);
+      setError(null);
+      if (item.type === 'meeting') {
+        await purgeTrashedMeeting(item.id);
+      } else {
+        await purgeTrashedNote(item.id);
+      }
+      setItems(await fetchTrash());
+    } catch (err) {
+      setError(err instanceof Error ? err.message : t('trash.purgeError'));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <div className="trash-panel page-panel">
+      <h2 className="page-heading">{t('trash.title')}</h2>
+      <p className="trash-hint">{t('trash.hint')}</p>
+
+      {error && <ErrorMessage message={error} />}
+
+      {!loading && items.length === 0 && <div className="trash-empty">{t('trash.empty')}</div>}
+
+      {items.length > 0 && (
+        <ul className="trash-list">
+          {items.map((item) => (
+            <li key={`${item.type}-${item.id}`} className="trash-item">
+              <div className="trash-item-text">
+                {item.type === 'meeting' ? (
+                  <strong>{item.subject}</strong>
+                ) : (
+                  <>
+                    <strong>{t('trash.note', { number: item.note_number })}</strong>
+                    {` · ${item.subject}`}
+                  </>
+                )}
+                <div className="trash-item-meta">
+                  {item.meeting_date}
+                  {item.type === 'meeting' && ` · ${t('trash.notes', { count: item.notes })}`}
+                  {` · ${t('trash.deletedAt', { date: new Date(item.deleted_at).toLocaleString() })}`}
+                </div>
+                {item.content && <div className="trash-item-content">{truncate(item.content, 150)}</div>}
+              </div>
+              <span className="trash-actions">
+                <button
+                  onClick={() => handleRestore(item)}
+                  className="btn btn-icon btn-restore"
+                  title={t('trash.restore')}
+                  disabled={busy}
+                >
+                  ↺
+                </button>
+                <button
+                  onClick={() => handlePurge(item)}
+                  className="btn btn-icon btn-delete"
+                  title={t('trash.purge')}
+                  disabled={busy}
+                >
+                  ✕
+                </button>
+              </span>
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  );
+}
@@ -4,6 +4,7 @@ import { fetchWhoAmI, fetchVersion } from '../api/client';
 import type { UserInfo, VersionInfo } from '../api/types';
 import { LoadingSpinner } from './LoadingSpinner';
 import { ErrorMessage } from './ErrorMessage';
+import { TokenPanel } from './TokenPanel';
 import './UserInfoPanel.css';
 
 function UserInfoPanel(): React.JSX.Element {
@@ -66,18 +67,24 @@ function UserInfoPanel(): React.JSX.Element {
             <span className="info-value">{userInfo.loginName}</span>
           </div>
 
-          <div className="info-row">
-            <span className="data-label">{t('info.nodeName')}:</span>
-            <span className="info-value">{userInfo.nodeName}</span>
-          </div>
+          {userInfo.nodeName && (
+            <div className="info-row">
+              <span className="data-label">{t('info.nodeName')}:</span>
+              <span className="info-value">{userInfo.nodeName}</span>
+            </div>
+          )}
 
-          <div className="info-row">
-            <span className="data-label">{t('info.nodeID')}:</span>
-            <span className="info-value">{userInfo.nodeID}</span>
-          </div>
+          {userInfo.nodeID && (
+            <div className="info-row">
+              <span className="data-label">{t('info.nodeID')}:</span>
+              <span className="info-value">{userInfo.nodeID}</span>
+            </div>
+          )}
         </section>
       )}
 
+      {userInfo && <TokenPanel />}
+
       {versionInfo && (
         <section className="card-section">
           <h2 className="section-heading">{t('info.sectionApplication')}</h2>
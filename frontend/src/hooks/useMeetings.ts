@@ -1,6 +1,7 @@
 import { useState, useEffect, useCallback } from 'react';
-import type { Meeting } from '../api/types';
+import type { Meeting, ServerEvent } from '../api/types';
 import { fetchMeetings, deleteMeeting } from '../api/client';
+import { useServerEvents } from './useServerEvents';
 
 interface UseMeetingsResult {
   meetings: Meeting[];
@@ -63,6 +64,14 @@ export function useMeetings(): UseMeetingsResult {
     }
   }, [sortColumn, sortOrder]);
 
+  // Reload the sorted list when another client changes a meeting
+  const handleServerEvent = useCallback((event: ServerEvent | null) => {
+    if (event && !event.type.startsWith('meeting.')) return;
+    fetchMeetings(sortColumn, sortOrder).then(setMeetings).catch(() => { /* keep the current list */ });
+  }, [sortColumn, sortOrder]);
+
+  useServerEvents(handleServerEvent);
+
   const handleDelete = useCallback(async (id: number) => {
     try {
       await deleteMeeting(id);
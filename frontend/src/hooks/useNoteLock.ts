@@ -0,0 +1,53 @@
+import { useState, useEffect, useCallback } from 'react';
+import type { NoteLock, ServerEvent } from '../api/types';
+import { lockNote, unlockNote, LockedError } from '../api/client';
+import { useServerEvents } from './useServerEvents';
+
+// Locks expire on the server two minutes after they were last renewed
+const lockRenewal = 30_000;
+
+interface UseNoteLockResult {
+  // heldBy is the lock of another user, or null while the user holds it
+  heldBy: NoteLock | null;
+  takeOver: () => Promise<void>;
+}
+
+/**
+ * Holds the edit lock of a note while the component is mounted. When
+ * another user holds it, renewals keep trying until it is free.
+ * @param noteId - The note being edited; no lock is taken without one
+ */
+export function useNoteLock(noteId?: number): UseNoteLockResult {
+  const [heldBy, setHeldBy] = useState<NoteLock | null>(null);
+
+  const acquire = useCallback(async (force: boolean) => {
+    if (!noteId) return;
+    try {
+      await lockNote(noteId, force);
+      setHeldBy(null);
+    } catch (err) {
+      if (err instanceof LockedError) setHeldBy(err.lock);
+    }
+  }, [noteId]);
+
+  useEffect(() => {
+    if (!noteId) return;
+    acquire(false);
+    const timer = setInterval(() => acquire(false), lockRenewal);
+    return () => {
+      clearInterval(timer);
+      unlockNote(noteId).catch(() => { /* expires on its own */ });
+    };
+  }, [noteId, acquire]);
+
+  // Notice a takeover right away instead of at the next renewal
+  const handleServerEvent = useCallback((event: ServerEvent | null) => {
+    if (event?.type === 'note.locked' && (event.data as NoteLock).note_id === noteId) {
+      acquire(false);
+    }
+  }, [noteId, acquire]);
+
+  useServerEvents(handleServerEvent);
+
+  return { heldBy, takeOver: () => acquire(true) };
+}
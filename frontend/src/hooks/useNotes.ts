@@ -1,6 +1,7 @@
 import { useState, useEffect, useCallback } from 'react';
-import type { Note } from '../api/types';
-import { fetchNotes, deleteNote, reorderNote } from '../api/client';
+import type { Note, ServerEvent } from '../api/types';
+import { fetchNotes, deleteNote, reorderNote, moveNotes } from '../api/client';
+import { useServerEvents } from './useServerEvents';
 
 interface UseNotesResult {
   notes: Note[];
@@ -8,6 +9,7 @@ interface UseNotesResult {
   error: string | null;
   handleDelete: (id: number) => Promise<void>;
   handleReorder: (id: number, direction: 'up' | 'down') => Promise<void>;
+  handleMove: (ids: number[], targetMeetingId: number) => Promise<void>;
   refresh: () => Promise<void>;
 }
 
@@ -47,6 +49,33 @@ export function useNotes(meetingId: number): UseNotesResult {
     }
   }, [meetingId]);
 
+  // Apply changes made by other clients; created notes are fetched again
+  // to get their place in the list
+  const handleServerEvent = useCallback((event: ServerEvent | null) => {
+    if (event && event.meeting_id !== meetingId) return;
+    switch (event?.type) {
+      case 'note.updated': {
+        const updated = event.data as Note;
+        setNotes((prev) => prev.map((n) => (n.id === updated.id ? updated : n)));
+        return;
+      }
+      case 'note.deleted': {
+        const { id } = event.data as { id: number };
+        setNotes((prev) => prev.filter((n) => n.id !== id));
+        return;
+      }
+      case 'note.reordered':
+        setNotes(event.data as Note[]);
+        return;
+      case 'note.created':
+      case undefined:
+        fetchNotes(meetingId).then(setNotes).catch(() => { /* keep the current list */ });
+        return;
+    }
+  }, [meetingId]);
+
+  useServerEvents(handleServerEvent);
+
   const handleDelete = useCallback(async (id: number) => {
     try {
       await deleteNote(id);
@@ -65,12 +94,22 @@ export function useNotes(meetingId: number): UseNotesResult {
     }
   }, []);
 
+  const handleMove = useCallback(async (ids: number[], targetMeetingId: number) => {
+    try {
+      await moveNotes(ids, targetMeetingId);
+      await refresh();
+    } catch (err) {
+      throw new Error(err instanceof Error ? err.message : 'Failed to move notes', { cause: err });
+    }
+  }, [refresh]);
+
   return {
     notes,
     loading,
     error,
     handleDelete,
     handleReorder,
+    handleMove,
     refresh,
   };
 }
@@ -0,0 +1,52 @@
+import { useState, useEffect, useCallback } from 'react';
+import type { MeetingPresence, ServerEvent } from '../api/types';
+import { fetchPresence, touchPresence, leavePresence } from '../api/client';
+import { useServerEvents } from './useServerEvents';
+
+// Presence expires on the server after a minute without a heartbeat
+const presenceHeartbeat = 30_000;
+
+/**
+ * Marks the user as present in a meeting while the component is mounted
+ * and keeps track of who else is
+ * @param meetingId - The meeting being viewed
+ * @param editingNoteId - The note being edited, if any
+ * @returns The users present and the locked notes of the meeting
+ */
+export function usePresence(meetingId: number, editingNoteId?: number): MeetingPresence {
+  const [presence, setPresence] = useState<MeetingPresence>({ users: [], locks: [] });
+
+  useEffect(() => {
+    let cancelled = false;
+    const touch = () => {
+      touchPresence(meetingId, editingNoteId)
+        .then((data) => {
+          if (!cancelled) setPresence(data);
+        })
+        .catch(() => { /* retried with the next heartbeat */ });
+    };
+    touch();
+    const timer = setInterval(touch, presenceHeartbeat);
+    return () => {
+      cancelled = true;
+      clearInterval(timer);
+    };
+  }, [meetingId, editingNoteId]);
+
+  useEffect(() => () => {
+    leavePresence(meetingId).catch(() => { /* expires on its own */ });
+  }, [meetingId]);
+
+  const handleServerEvent = useCallback((event: ServerEvent | null) => {
+    if (event && event.meeting_id !== meetingId) return;
+    if (event?.type === 'presence.updated') {
+      setPresence(event.data as MeetingPresence);
+    } else if (!event || event.type === 'note.locked' || event.type === 'note.unlocked') {
+      fetchPresence(meetingId).then(setPresence).catch(() => { /* keep the last known presence */ });
+    }
+  }, [meetingId]);
+
+  useServerEvents(handleServerEvent);
+
+  return presence;
+}
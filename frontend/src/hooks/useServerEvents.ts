@@ -0,0 +1,19 @@
+import { useEffect, useRef } from 'react';
+import type { ServerEvent } from '../api/types';
+import { subscribeServerEvents } from '../api/client';
+
+/**
+ * Calls onEvent for every change streamed from the server while the
+ * component is mounted
+ * @param onEvent - Receives each event, or null when events were missed
+ *   and the caller should reload its data
+ */
+export function useServerEvents(onEvent: (event: ServerEvent | null) => void): void {
+  const handler = useRef(onEvent);
+
+  useEffect(() => {
+    handler.current = onEvent;
+  }, [onEvent]);
+
+  useEffect(() => subscribeServerEvents((event) => handler.current(event)), []);
+}
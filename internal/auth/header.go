@@ -0,0 +1,113 @@
+// Package auth identifies users when notebook runs outside Tailscale: behind
+// a reverse proxy that passes the user in a request header, or with an
+// OpenID Connect login of its own. Identities are mapped onto the same
+// tsapp.UserInfo that Tailscale's WhoIs returns.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+// ErrUnauthenticated is returned when a request carries no usable identity
+var ErrUnauthenticated = errors.New("not authenticated")
+
+// HeaderAuth trusts the user named in a request header, but only on
+// requests whose peer is one of the trusted reverse proxies
+type HeaderAuth struct {
+	header     string
+	nameHeader string
+	trusted    []netip.Prefix
+}
+
+// NewHeaderAuth reads the login name from header and, if nameHeader is set,
+// the display name from nameHeader. trustedProxies holds CIDRs or single
+// addresses.
+func NewHeaderAuth(header, nameHeader string, trustedProxies []string) (*HeaderAuth, error) {
+	if header == "" {
+		return nil, errors.New("no identity header configured")
+	}
+	if len(trustedProxies) == 0 {
+		return nil, errors.New("no trusted proxies configured")
+	}
+
+	h := &HeaderAuth{header: header, nameHeader: nameHeader}
+	for _, entry := range trustedProxies {
+		prefix, err := parsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		h.trusted = append(h.trusted, prefix)
+	}
+	return h, nil
+}
+
+// parsePrefix parses a CIDR, or a single address as a one-address prefix
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+// trustedPeer reports whether the request comes directly from a trusted proxy
+func (h *HeaderAuth) trustedPeer(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range h.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticate returns the user named in the identity header
+func (h *HeaderAuth) Authenticate(r *http.Request) (*tsapp.UserInfo, error) {
+	if !h.trustedPeer(r) {
+		return nil, fmt.Errorf("%w: request from untrusted address %s", ErrUnauthenticated, r.RemoteAddr)
+	}
+	login := strings.TrimSpace(r.Header.Get(h.header))
+	if login == "" {
+		return nil, fmt.Errorf("%w: missing %s header", ErrUnauthenticated, h.header)
+	}
+
+	user := &tsapp.UserInfo{LoginName: login, DisplayName: login}
+	if h.nameHeader != "" {
+		if name := strings.TrimSpace(r.Header.Get(h.nameHeader)); name != "" {
+			user.DisplayName = name
+		}
+	}
+	return user, nil
+}
+
+// LoginURL returns "": the reverse proxy handles logins
+func (h *HeaderAuth) LoginURL(*http.Request) string {
+	return ""
+}
+
+// Handler returns nil: header authentication has no endpoints of its own
+func (h *HeaderAuth) Handler() http.Handler {
+	return nil
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderAuth_Authenticate(t *testing.T) {
+	h, err := NewHeaderAuth("X-Forwarded-User", "X-Forwarded-Name", []string{"10.0.0.0/8", "::1"})
+	if err != nil {
+		t.Fatalf("NewHeaderAuth() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		wantLogin  string
+		wantName   string
+	}{
+		{name: "trusted proxy", remoteAddr: "10.1.2.3:4000", headers: map[string]string{"X-Forwarded-User": "alice"}, wantLogin: "alice", wantName: "alice"},
+		{name: "display name", remoteAddr: "10.1.2.3:4000", headers: map[string]string{"X-Forwarded-User": "alice", "X-Forwarded-Name": "Alice Example"}, wantLogin: "alice", wantName: "Alice Example"},
+		{name: "single address", remoteAddr: "[::1]:4000", headers: map[string]string{"X-Forwarded-User": "bob"}, wantLogin: "bob", wantName: "bob"},
+		{name: "ipv4-mapped address", remoteAddr: "[::ffff:10.0.0.1]:4000", headers: map[string]string{"X-Forwarded-User": "carol"}, wantLogin: "carol", wantName: "carol"},
+		{name: "untrusted peer", remoteAddr: "192.0.2.1:4000", headers: map[string]string{"X-Forwarded-User": "mallory"}},
+		{name: "missing header", remoteAddr: "10.1.2.3:4000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			user, err := h.Authenticate(req)
+			if tt.wantLogin == "" {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Errorf("expected ErrUnauthenticated, got %v, %v", user, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if user.LoginName != tt.wantLogin || user.DisplayName != tt.wantName {
+				t.Errorf("got %+v, want login %q name %q", user, tt.wantLogin, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestNewHeaderAuth_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		proxies []string
+	}{
+		{name: "no header", proxies: []string{"127.0.0.1"}},
+		{name: "no proxies", header: "X-Forwarded-User"},
+		{name: "invalid CIDR", header: "X-Forwarded-User", proxies: []string{"10.0.0.0/33"}},
+		{name: "invalid address", header: "X-Forwarded-User", proxies: []string{"proxy.local"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHeaderAuth(tt.header, "", tt.proxies); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
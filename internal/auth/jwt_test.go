@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+// es256Token signs payload with key in the compact JWT format
+func es256Token(t *testing.T, key *ecdsa.PrivateKey, alg, payload string) string {
+	t.Helper()
+	input := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"`+alg+`","kid":"ec"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(payload))
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifyJWT_ES256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	keys := &keySet{keys: map[string]crypto.PublicKey{"ec": &key.PublicKey}, fetched: time.Now()}
+
+	payload, err := verifyJWT(context.Background(), es256Token(t, key, "ES256", `{"sub":"x"}`), keys)
+	if err != nil || string(payload) != `{"sub":"x"}` {
+		t.Fatalf("verifyJWT() = %s, %v", payload, err)
+	}
+
+	// Algorithm confusion and unsigned tokens are rejected
+	for _, alg := range []string{"RS256", "HS256", "none"} {
+		if _, err := verifyJWT(context.Background(), es256Token(t, key, alg, `{}`), keys); err == nil {
+			t.Errorf("expected alg %s to be rejected", alg)
+		}
+	}
+
+	// The key registered under the token's key ID must have signed it
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	keys.keys = map[string]crypto.PublicKey{"ec": &other.PublicKey}
+	if _, err := verifyJWT(context.Background(), es256Token(t, key, "ES256", `{}`), keys); err == nil {
+		t.Error("expected signature by another key to be rejected")
+	}
+}
+
+func TestAudience_UnmarshalJSON(t *testing.T) {
+	var a audience
+	if err := a.UnmarshalJSON([]byte(`"notebook"`)); err != nil || !a.contains("notebook") {
+		t.Errorf("single audience: %v, %v", a, err)
+	}
+	if err := a.UnmarshalJSON([]byte(`["other","notebook"]`)); err != nil || !a.contains("notebook") || a.contains("x") {
+		t.Errorf("audience list: %v, %v", a, err)
+	}
+	if err := a.UnmarshalJSON([]byte(`42`)); err == nil {
+		t.Error("expected invalid audience to fail")
+	}
+}
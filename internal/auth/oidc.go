@@ -0,0 +1,417 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/secrets"
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+const (
+	// SessionCookie holds the signed-in user
+	SessionCookie = "notebook_session"
+	// loginCookie carries state, nonce and PKCE verifier through the login
+	loginCookie = "notebook_login"
+
+	sessionTTL = 12 * time.Hour
+	loginTTL   = 10 * time.Minute
+	// clockSkew is the leeway granted when checking token expiry
+	clockSkew = time.Minute
+
+	loginPath    = "/auth/login"
+	callbackPath = "/auth/callback"
+	logoutPath   = "/auth/logout"
+)
+
+// OIDCConfig holds the settings of an OpenID Connect client
+type OIDCConfig struct {
+	// IssuerURL is the issuer identifier; discovery is read from
+	// IssuerURL/.well-known/openid-configuration
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the public URL of /auth/callback
+	RedirectURL string
+	Scopes      []string
+}
+
+// providerMetadata is the part of the discovery document notebook uses
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDC signs users in with the authorization code flow and PKCE and keeps
+// them signed in with an encrypted session cookie
+type OIDC struct {
+	cfg      OIDCConfig
+	provider providerMetadata
+	keys     *keySet
+	client   *http.Client
+	cipher   *secrets.Cipher
+	// secure marks cookies Secure when notebook is reached over HTTPS
+	secure bool
+	now    func() time.Time
+}
+
+// NewOIDC reads the provider's discovery document. Cookies are encrypted
+// with c; without one, a random key is used and sessions end on restart.
+func NewOIDC(ctx context.Context, cfg OIDCConfig, c *secrets.Cipher) (*OIDC, error) {
+	redirect, err := url.Parse(cfg.RedirectURL)
+	if err != nil || !redirect.IsAbs() {
+		return nil, fmt.Errorf("invalid redirect URL %q", cfg.RedirectURL)
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+
+	if c == nil {
+		c, err = ephemeralCipher()
+		if err != nil {
+			return nil, err
+		}
+		slog.WarnContext(ctx, "no master key configured; login sessions end when notebook restarts")
+	}
+
+	o := &OIDC{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		cipher: c,
+		secure: redirect.Scheme == "https",
+		now:    time.Now,
+	}
+
+	issuer := strings.TrimSuffix(cfg.IssuerURL, "/")
+	if err := getJSON(ctx, o.client, issuer+"/.well-known/openid-configuration", &o.provider); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimSuffix(o.provider.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", o.provider.Issuer, cfg.IssuerURL)
+	}
+	if o.provider.AuthorizationEndpoint == "" || o.provider.TokenEndpoint == "" || o.provider.JWKSURI == "" {
+		return nil, errors.New("oidc discovery: incomplete provider metadata")
+	}
+	o.keys = &keySet{uri: o.provider.JWKSURI, client: o.client}
+	return o, nil
+}
+
+// ephemeralCipher returns a cipher with a random key
+func ephemeralCipher() (*secrets.Cipher, error) {
+	key := make([]byte, secrets.MinMasterKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate session key: %w", err)
+	}
+	return secrets.New(key)
+}
+
+// session is the content of the session cookie
+type session struct {
+	User    tsapp.UserInfo `json:"user"`
+	Expires int64          `json:"exp"`
+}
+
+// loginState is the content of the login cookie
+type loginState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Redirect string `json:"redirect"`
+	Expires  int64  `json:"exp"`
+}
+
+// Authenticate returns the user of a valid session cookie
+func (o *OIDC) Authenticate(r *http.Request) (*tsapp.UserInfo, error) {
+	var s session
+	if err := o.readCookie(r, SessionCookie, &s); err != nil {
+		return nil, err
+	}
+	if o.now().Unix() >= s.Expires {
+		return nil, fmt.Errorf("%w: session expired", ErrUnauthenticated)
+	}
+	return &s.User, nil
+}
+
+// LoginURL returns the login page, returning to the current page afterwards
+func (o *OIDC) LoginURL(r *http.Request) string {
+	return loginPath + "?redirect=" + url.QueryEscape(r.URL.RequestURI())
+}
+
+// Handler serves /auth/login, /auth/callback and /auth/logout
+func (o *OIDC) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+loginPath, o.handleLogin)
+	mux.HandleFunc("GET "+callbackPath, o.handleCallback)
+	mux.HandleFunc(logoutPath, o.handleLogout)
+	return mux
+}
+
+// handleLogin redirects to the provider's authorization endpoint
+func (o *OIDC) handleLogin(w http.ResponseWriter, r *http.Request) {
+	state := loginState{
+		State:    randomString(),
+		Nonce:    randomString(),
+		Verifier: randomString(),
+		Redirect: localRedirect(r.URL.Query().Get("redirect")),
+		Expires:  o.now().Add(loginTTL).Unix(),
+	}
+	if err := o.writeCookie(w, loginCookie, state, loginTTL); err != nil {
+		slog.ErrorContext(r.Context(), "failed to start login", "error", err)
+		http.Error(w, "failed to start login", http.StatusInternalServerError)
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(state.Verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {o.cfg.ClientID},
+		"redirect_uri":          {o.cfg.RedirectURL},
+		"scope":                 {strings.Join(o.cfg.Scopes, " ")},
+		"state":                 {state.State},
+		"nonce":                 {state.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	target := o.provider.AuthorizationEndpoint
+	if strings.Contains(target, "?") {
+		target += "&" + query.Encode()
+	} else {
+		target += "?" + query.Encode()
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// handleCallback exchanges the authorization code for an ID token and
+// starts a session
+func (o *OIDC) handleCallback(w http.ResponseWriter, r *http.Request) {
+	var state loginState
+	err := o.readCookie(r, loginCookie, &state)
+	o.clearCookie(w, loginCookie)
+	query := r.URL.Query()
+	if err != nil || o.now().Unix() >= state.Expires ||
+		subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state.State)) != 1 {
+		http.Error(w, "login expired or invalid, please try again", http.StatusBadRequest)
+		return
+	}
+	if errCode := query.Get("error"); errCode != "" {
+		slog.WarnContext(r.Context(), "oidc login failed", "error", errCode, "description", query.Get("error_description"))
+		http.Error(w, "login failed: "+errCode, http.StatusUnauthorized)
+		return
+	}
+
+	user, err := o.exchange(r.Context(), query.Get("code"), &state)
+	if err != nil {
+		slog.WarnContext(r.Context(), "oidc login failed", "error", err)
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+
+	s := session{User: *user, Expires: o.now().Add(sessionTTL).Unix()}
+	if err := o.writeCookie(w, SessionCookie, s, sessionTTL); err != nil {
+		slog.ErrorContext(r.Context(), "failed to start session", "error", err)
+		http.Error(w, "failed to start session", http.StatusInternalServerError)
+		return
+	}
+	slog.InfoContext(r.Context(), "user signed in", "user", user.LoginName)
+	http.Redirect(w, r, state.Redirect, http.StatusFound)
+}
+
+// handleLogout ends the session
+func (o *OIDC) handleLogout(w http.ResponseWriter, _ *http.Request) {
+	o.clearCookie(w, SessionCookie)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("Signed out.\n"))
+}
+
+// tokenResponse is the part of the token endpoint response notebook uses
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+// exchange redeems an authorization code and verifies the ID token
+func (o *OIDC) exchange(ctx context.Context, code string, state *loginState) (*tsapp.UserInfo, error) {
+	if code == "" {
+		return nil, errors.New("missing authorization code")
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {o.cfg.RedirectURL},
+		"code_verifier": {state.Verifier},
+	}
+	if o.cfg.ClientSecret == "" {
+		form.Set("client_id", o.cfg.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if o.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(o.cfg.ClientID), url.QueryEscape(o.cfg.ClientSecret))
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request: %s", resp.Status)
+	}
+	var tokens tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("decode token response: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	return o.verifyIDToken(ctx, tokens.IDToken, state.Nonce)
+}
+
+// idTokenClaims are the ID token claims notebook checks or maps
+type idTokenClaims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	Expiry            int64    `json:"exp"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Picture           string   `json:"picture"`
+}
+
+// verifyIDToken checks signature, issuer, audience, expiry and nonce of an
+// ID token and maps its claims onto a user
+func (o *OIDC) verifyIDToken(ctx context.Context, token, nonce string) (*tsapp.UserInfo, error) {
+	payload, err := verifyJWT(ctx, token, o.keys)
+	if err != nil {
+		return nil, fmt.Errorf("verify id token: %w", err)
+	}
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("decode id token: %w", err)
+	}
+
+	switch {
+	case claims.Issuer != o.provider.Issuer:
+		return nil, fmt.Errorf("id token issued by %q", claims.Issuer)
+	case !claims.Audience.contains(o.cfg.ClientID):
+		return nil, errors.New("id token not issued for this client")
+	case o.now().Add(-clockSkew).Unix() >= claims.Expiry:
+		return nil, errors.New("id token expired")
+	case subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1:
+		return nil, errors.New("id token nonce mismatch")
+	case claims.Subject == "":
+		return nil, errors.New("id token has no subject")
+	}
+
+	return claims.userInfo(), nil
+}
+
+// userInfo maps the claims onto a user. The login name is the e-mail
+// address if present, so --admin works the same as with Tailscale.
+func (c *idTokenClaims) userInfo() *tsapp.UserInfo {
+	login := c.Email
+	if login == "" {
+		login = c.PreferredUsername
+	}
+	if login == "" {
+		login = c.Subject
+	}
+	name := c.Name
+	if name == "" {
+		name = login
+	}
+	return &tsapp.UserInfo{
+		DisplayName:   name,
+		LoginName:     login,
+		ProfilePicURL: c.Picture,
+	}
+}
+
+// writeCookie stores v encrypted in an HttpOnly cookie
+func (o *OIDC) writeCookie(w http.ResponseWriter, name string, v any, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	value, err := o.cipher.Encrypt(name, string(data))
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   o.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// readCookie decrypts the cookie name into v
+func (o *OIDC) readCookie(r *http.Request, name string, v any) error {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return fmt.Errorf("%w: no %s cookie", ErrUnauthenticated, name)
+	}
+	if !secrets.IsEncrypted(cookie.Value) {
+		return fmt.Errorf("%w: invalid %s cookie", ErrUnauthenticated, name)
+	}
+	data, err := o.cipher.Decrypt(name, cookie.Value)
+	if err != nil {
+		return fmt.Errorf("%w: invalid %s cookie", ErrUnauthenticated, name)
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("%w: invalid %s cookie", ErrUnauthenticated, name)
+	}
+	return nil
+}
+
+// clearCookie deletes the cookie name
+func (o *OIDC) clearCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   o.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// randomString returns 32 random bytes, base64url-encoded
+func randomString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// localRedirect accepts only paths on this server, so the login cannot be
+// abused as an open redirect
+func localRedirect(target string) string {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") || strings.HasPrefix(target, "/\\") {
+		return "/"
+	}
+	return target
+}
@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testClientID     = "notebook"
+	testClientSecret = "s3cret"
+	testRedirectURL  = "https://notebook.example.com/auth/callback"
+)
+
+// mockIssuer is a minimal OpenID provider: discovery, JWKS, an
+// authorization endpoint that approves immediately and a token endpoint
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu sync.Mutex
+	// codes maps issued authorization codes to their login request
+	codes map[string]url.Values
+	// claims overrides ID token claims of the next token response
+	claims map[string]any
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	m := &mockIssuer{key: key, codes: map[string]url.Values{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(w, map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("GET /authorize", m.handleAuthorize)
+	mux.HandleFunc("POST /token", m.handleToken)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func writeTestJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// handleAuthorize approves every request and redirects back with a code
+func (m *mockIssuer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	code := randomString()
+	m.mu.Lock()
+	m.codes[code] = query
+	m.mu.Unlock()
+	http.Redirect(w, r, query.Get("redirect_uri")+"?code="+code+"&state="+query.Get("state"), http.StatusFound)
+}
+
+// handleToken redeems a code after checking client credentials and PKCE
+func (m *mockIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != testClientID || secret != testClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	login, found := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !found || login.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(challenge[:]) ||
+		r.PostForm.Get("redirect_uri") != login.Get("redirect_uri") {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims := map[string]any{
+		"iss":   m.server.URL,
+		"sub":   "user-42",
+		"aud":   testClientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": login.Get("nonce"),
+		"email": "alice@example.com",
+		"name":  "Alice Example",
+	}
+	m.mu.Lock()
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	m.mu.Unlock()
+	writeTestJSON(w, map[string]string{"access_token": "unused", "token_type": "Bearer", "id_token": m.sign(claims)})
+}
+
+// sign returns an RS256 JWT of claims
+func (m *mockIssuer) sign(claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test-key", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, digest[:])
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestOIDC(t *testing.T, issuer *mockIssuer) *OIDC {
+	t.Helper()
+	o, err := NewOIDC(context.Background(), OIDCConfig{
+		IssuerURL:    issuer.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewOIDC() error = %v", err)
+	}
+	return o
+}
+
+// login runs the browser side of the login flow and returns the callback
+// response
+func login(t *testing.T, o *OIDC, redirect string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := o.Handler()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/auth/login?redirect="+url.QueryEscape(redirect), nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusFound {
+		t.Fatalf("login: expected status 302, got %d", w.Code)
+	}
+	loginCookies := w.Result().Cookies()
+
+	// The provider approves and sends the browser back to the callback
+	resp, err := noRedirectClient().Get(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || callback.Path != "/auth/callback" {
+		t.Fatalf("authorize: unexpected redirect %q", resp.Header.Get("Location"))
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodGet, callback.RequestURI(), nil)
+	for _, c := range loginCookies {
+		req.AddCookie(c)
+	}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func noRedirectClient() *http.Client {
+	return &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+}
+
+// sessionCookie returns the session cookie set by a callback response
+func sessionCookie(t *testing.T, w *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, c := range w.Result().Cookies() {
+		if c.Name == SessionCookie {
+			return c
+		}
+	}
+	t.Fatalf("no session cookie in %v", w.Result().Cookies())
+	return nil
+}
+
+func TestOIDC_Login(t *testing.T) {
+	issuer := newMockIssuer(t)
+	o := newTestOIDC(t, issuer)
+
+	w := login(t, o, "/meetings/3?tab=notes")
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/meetings/3?tab=notes" {
+		t.Fatalf("callback: got %d to %q: %s", w.Code, w.Header().Get("Location"), w.Body.String())
+	}
+	cookie := sessionCookie(t, w)
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("session cookie attributes: %+v", cookie)
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/whoami", nil)
+	req.AddCookie(cookie)
+	user, err := o.Authenticate(req)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if user.LoginName != "alice@example.com" || user.DisplayName != "Alice Example" {
+		t.Errorf("unexpected user %+v", user)
+	}
+
+	// Sessions expire
+	o.now = func() time.Time { return time.Now().Add(sessionTTL + time.Minute) }
+	if _, err := o.Authenticate(req); err == nil {
+		t.Error("expected expired session to be rejected")
+	}
+}
+
+func TestOIDC_RejectsInvalidTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]any
+	}{
+		{name: "wrong audience", claims: map[string]any{"aud": "someone-else"}},
+		{name: "wrong issuer", claims: map[string]any{"iss": "https://evil.example.com"}},
+		{name: "expired", claims: map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}},
+		{name: "wrong nonce", claims: map[string]any{"nonce": "replayed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newMockIssuer(t)
+			issuer.claims = tt.claims
+			o := newTestOIDC(t, issuer)
+
+			w := login(t, o, "/")
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("expected status 401, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestOIDC_CallbackChecksState(t *testing.T) {
+	issuer := newMockIssuer(t)
+	o := newTestOIDC(t, issuer)
+
+	// A callback without the login cookie, e.g. a forged link
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/auth/callback?code=abc&state=xyz", nil)
+	w := httptest.NewRecorder()
+	o.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestOIDC_RejectsForgedCookie(t *testing.T) {
+	issuer := newMockIssuer(t)
+	o := newTestOIDC(t, issuer)
+
+	for _, value := range []string{"", "alice@example.com", "enc:v1:AAAA"} {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: SessionCookie, Value: value})
+		if _, err := o.Authenticate(req); err == nil {
+			t.Errorf("Authenticate(%q) succeeded", value)
+		}
+	}
+
+	// A session from another instance (another key) is not accepted
+	other := newTestOIDC(t, issuer)
+	cookie := sessionCookie(t, login(t, other, "/"))
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	if _, err := o.Authenticate(req); err == nil {
+		t.Error("expected cookie encrypted with another key to be rejected")
+	}
+}
+
+func TestNewOIDC_IssuerMismatch(t *testing.T) {
+	issuer := newMockIssuer(t)
+	_, err := NewOIDC(context.Background(), OIDCConfig{
+		IssuerURL:   issuer.server.URL + "/other",
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+	}, nil)
+	if err == nil {
+		t.Error("expected discovery to fail")
+	}
+}
+
+func TestLocalRedirect(t *testing.T) {
+	tests := map[string]string{
+		"/meetings/1":          "/meetings/1",
+		"":                     "/",
+		"https://evil.example": "/",
+		"//evil.example/":      "/",
+		"/\\evil.example":      "/",
+	}
+	for in, want := range tests {
+		if got := localRedirect(in); got != want {
+			t.Errorf("localRedirect(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOIDC_LoginURL(t *testing.T) {
+	o := &OIDC{}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/meetings/3?x=1", nil)
+	if got := o.LoginURL(req); !strings.HasPrefix(got, "/auth/login?redirect=") || !strings.Contains(got, url.QueryEscape("/meetings/3?x=1")) {
+		t.Errorf("LoginURL() = %q", got)
+	}
+}
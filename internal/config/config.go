@@ -0,0 +1,413 @@
+// Package config loads the runtime settings of notebook. Each layer overrides
+// the previous one: built-in defaults, an optional YAML file, NOTEBOOK_*
+// environment variables and finally command-line flags.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variable of every setting
+const EnvPrefix = "NOTEBOOK_"
+
+// envConfig names the configuration file when --config is not given
+const envConfig = EnvPrefix + "CONFIG"
+
+// Config keys of the LLM settings in the config table
+const (
+	KeyLLMProviderURL = "llm_provider_url"
+	KeyLLMModel       = "llm_model"
+	KeyLLMAPIKey      = "llm_api_key" // #nosec G101 - config key name, not credential
+)
+
+// Authentication modes of standalone mode
+const (
+	AuthHeader = "header"
+	AuthOIDC   = "oidc"
+)
+
+// Config holds all runtime settings
+type Config struct {
+	DevListen string `yaml:"dev_listen"`
+	// Listen runs notebook without Tailscale on this address, behind a
+	// reverse proxy, authenticating users as configured in Auth
+	Listen        string   `yaml:"listen"`
+	Hostname      string   `yaml:"hostname"`
+	StateDir      string   `yaml:"state_dir"`
+	HTTPS         bool     `yaml:"https"`
+	DB            string   `yaml:"db"`
+	Timezone      string   `yaml:"timezone"`
+	LogFormat     string   `yaml:"log_format"`
+	LogLevel      string   `yaml:"log_level"`
+	Verbose       bool     `yaml:"verbose"`
+	Metrics       bool     `yaml:"metrics"`
+	MetricsListen string   `yaml:"metrics_listen"`
+	FunnelListen  string   `yaml:"funnel_listen"`
+	Admins        []string `yaml:"admins"`
+	// MasterKey encrypts secrets stored in the config table
+	MasterKey string `yaml:"master_key"`
+	// MasterKeyFile names a file holding the master key. It takes
+	// precedence over MasterKey.
+	MasterKeyFile string `yaml:"master_key_file"`
+	// NoteRevisions is the number of revisions kept per note; 0 keeps all
+	NoteRevisions int `yaml:"note_revisions"`
+	// NoteRevisionDays is how long older revisions are kept; 0 keeps them forever
+	NoteRevisionDays int `yaml:"note_revision_days"`
+	// TrashDays is how long deleted meetings and notes stay in the trash;
+	// 0 keeps them until they are purged by hand
+	TrashDays int `yaml:"trash_days"`
+	// StrictNoteLocks rejects note updates while another user holds the
+	// note's edit lock, instead of only warning
+	StrictNoteLocks bool `yaml:"strict_note_locks"`
+	Auth            Auth `yaml:"auth"`
+	LLM             LLM  `yaml:"llm"`
+}
+
+// Auth holds how users are authenticated in standalone mode
+type Auth struct {
+	// Mode is AuthHeader or AuthOIDC
+	Mode string `yaml:"mode"`
+	// Header carries the login name set by the reverse proxy
+	Header string `yaml:"header"`
+	// NameHeader optionally carries the display name
+	NameHeader string `yaml:"name_header"`
+	// TrustedProxies lists the CIDRs whose identity headers are trusted
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	OIDC           OIDC     `yaml:"oidc"`
+}
+
+// OIDC holds the OpenID Connect client settings
+type OIDC struct {
+	Issuer       string `yaml:"issuer"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	// ClientSecretFile names a file holding the client secret. It takes
+	// precedence over ClientSecret.
+	ClientSecretFile string   `yaml:"client_secret_file"`
+	RedirectURL      string   `yaml:"redirect_url"`
+	Scopes           []string `yaml:"scopes"`
+}
+
+// LLM holds LLM settings that are seeded into, or locked in, the config table
+type LLM struct {
+	ProviderURL string `yaml:"provider_url"`
+	Model       string `yaml:"model"`
+	APIKey      string `yaml:"api_key"`
+	// APIKeyFile names a file holding the API key, e.g. a Docker secret.
+	// It takes precedence over APIKey.
+	APIKeyFile string `yaml:"api_key_file"`
+	// Lock makes the configured LLM settings read-only in the UI.
+	// Without it they only seed empty values.
+	Lock bool `yaml:"lock"`
+}
+
+// Defaults returns the built-in settings
+func Defaults() *Config {
+	return &Config{
+		Hostname:      "notebook",
+		StateDir:      "tsnet-state",
+		DB:            "notebook.db",
+		LogFormat:     "text",
+		LogLevel:      "info",
+		NoteRevisions: 100,
+		TrashDays:     30,
+		Auth: Auth{
+			Header: "X-Forwarded-User",
+			OIDC:   OIDC{Scopes: []string{"openid", "profile", "email"}},
+		},
+	}
+}
+
+// setting is one configurable value. Its flag is --name and its environment
+// variable NOTEBOOK_NAME, upper-cased with '-' replaced by '_'.
+type setting struct {
+	name    string
+	usage   string
+	envOnly bool // secrets must not show up in the process list
+	str     func(c *Config) *string
+	boolean func(c *Config) *bool
+	integer func(c *Config) *int
+	list    func(c *Config) *[]string
+}
+
+var settings = []setting{
+	{name: "dev-listen", usage: "Development mode: listen on this address (e.g., :8080) without Tailscale", str: func(c *Config) *string { return &c.DevListen }},
+	{name: "listen", usage: "Standalone mode: listen on this address (e.g., :8080) without Tailscale, behind a reverse proxy; requires --auth", str: func(c *Config) *string { return &c.Listen }},
+	{name: "hostname", usage: "Tailscale hostname for the service", str: func(c *Config) *string { return &c.Hostname }},
+	{name: "state-dir", usage: "Tailscale state directory", str: func(c *Config) *string { return &c.StateDir }},
+	{name: "https", usage: "Serve HTTPS on :443 with Tailscale certificates and redirect :80 to it", boolean: func(c *Config) *bool { return &c.HTTPS }},
+	{name: "db", usage: "SQLite database file path", str: func(c *Config) *string { return &c.DB }},
+	{name: "timezone", usage: "IANA time zone for meeting dates and times (default: system local zone)", str: func(c *Config) *string { return &c.Timezone }},
+	{name: "log-format", usage: "Log output format: text or json", str: func(c *Config) *string { return &c.LogFormat }},
+	{name: "log-level", usage: "Minimum log level: debug, info, warn or error", str: func(c *Config) *string { return &c.LogLevel }},
+	{name: "verbose", usage: "Shorthand for --log-level debug", boolean: func(c *Config) *bool { return &c.Verbose }},
+	{name: "metrics", usage: "Expose Prometheus metrics at /metrics", boolean: func(c *Config) *bool { return &c.Metrics }},
+	{name: "metrics-listen", usage: "Serve metrics on this separate address (e.g., :9090) instead of the main listener", str: func(c *Config) *string { return &c.MetricsListen }},
+	{name: "funnel-listen", usage: "Serve public meeting share links through Tailscale Funnel on this address (e.g., :8443)", str: func(c *Config) *string { return &c.FunnelListen }},
+	{name: "admin", usage: "Comma-separated Tailscale login names allowed to use admin endpoints", list: func(c *Config) *[]string { return &c.Admins }},
+	{name: "master-key", envOnly: true, str: func(c *Config) *string { return &c.MasterKey }},
+	{name: "master-key-file", usage: "File containing the master key that encrypts stored secrets", str: func(c *Config) *string { return &c.MasterKeyFile }},
+	{name: "note-revisions", usage: "Number of revisions kept per note (0 keeps all)", integer: func(c *Config) *int { return &c.NoteRevisions }},
+	{name: "note-revision-days", usage: "Days older note revisions are kept; the latest revision is always kept (0 keeps them forever)", integer: func(c *Config) *int { return &c.NoteRevisionDays }},
+	{name: "trash-days", usage: "Days deleted meetings and notes stay in the trash before they are purged (0 keeps them)", integer: func(c *Config) *int { return &c.TrashDays }},
+	{name: "strict-note-locks", usage: "Reject note updates while another user holds the note's edit lock instead of only warning", boolean: func(c *Config) *bool { return &c.StrictNoteLocks }},
+	{name: "auth", usage: "Authentication in standalone mode: header or oidc", str: func(c *Config) *string { return &c.Auth.Mode }},
+	{name: "auth-header", usage: "Request header carrying the login name set by the reverse proxy", str: func(c *Config) *string { return &c.Auth.Header }},
+	{name: "auth-name-header", usage: "Request header carrying the display name set by the reverse proxy", str: func(c *Config) *string { return &c.Auth.NameHeader }},
+	{name: "trusted-proxies", usage: "Comma-separated CIDRs of reverse proxies whose identity headers are trusted", list: func(c *Config) *[]string { return &c.Auth.TrustedProxies }},
+	{name: "oidc-issuer", usage: "OpenID Connect issuer URL", str: func(c *Config) *string { return &c.Auth.OIDC.Issuer }},
+	{name: "oidc-client-id", usage: "OpenID Connect client ID", str: func(c *Config) *string { return &c.Auth.OIDC.ClientID }},
+	{name: "oidc-client-secret", envOnly: true, str: func(c *Config) *string { return &c.Auth.OIDC.ClientSecret }},
+	{name: "oidc-client-secret-file", usage: "File containing the OpenID Connect client secret", str: func(c *Config) *string { return &c.Auth.OIDC.ClientSecretFile }},
+	{name: "oidc-redirect-url", usage: "Public URL of /auth/callback registered with the OpenID provider", str: func(c *Config) *string { return &c.Auth.OIDC.RedirectURL }},
+	{name: "oidc-scopes", usage: "Comma-separated OpenID Connect scopes", list: func(c *Config) *[]string { return &c.Auth.OIDC.Scopes }},
+	{name: "llm-provider-url", usage: "LLM provider URL to seed or lock", str: func(c *Config) *string { return &c.LLM.ProviderURL }},
+	{name: "llm-model", usage: "LLM model to seed or lock", str: func(c *Config) *string { return &c.LLM.Model }},
+	{name: "llm-api-key", envOnly: true, str: func(c *Config) *string { return &c.LLM.APIKey }},
+	{name: "llm-api-key-file", usage: "File containing the LLM API key to seed or lock", str: func(c *Config) *string { return &c.LLM.APIKeyFile }},
+	{name: "llm-lock", usage: "Lock the configured LLM settings against changes from the UI", boolean: func(c *Config) *bool { return &c.LLM.Lock }},
+}
+
+// envName returns the environment variable of a setting
+func (s *setting) envName() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(s.name, "-", "_"))
+}
+
+// apply parses value into the setting's field
+func (s *setting) apply(c *Config, value string) error {
+	switch {
+	case s.boolean != nil:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		*s.boolean(c) = b
+	case s.integer != nil:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		*s.integer(c) = n
+	case s.list != nil:
+		*s.list(c) = splitList(value)
+	default:
+		*s.str(c) = value
+	}
+	return nil
+}
+
+// register adds the setting's flag, showing its default from c
+func (s *setting) register(fs *flag.FlagSet, c *Config) {
+	switch {
+	case s.boolean != nil:
+		fs.Bool(s.name, *s.boolean(c), s.usage)
+	case s.integer != nil:
+		fs.Int(s.name, *s.integer(c), s.usage)
+	case s.list != nil:
+		fs.String(s.name, strings.Join(*s.list(c), ","), s.usage)
+	default:
+		fs.String(s.name, *s.str(c), s.usage)
+	}
+}
+
+// Load builds the configuration from args (without the program name) and
+// the environment. It returns flag.ErrHelp if -h was given.
+func Load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (*Config, error) {
+	c := Defaults()
+
+	fs := flag.NewFlagSet("notebook", flag.ContinueOnError)
+	fs.SetOutput(output)
+	configPath := fs.String("config", "", "YAML configuration file (env "+envConfig+")")
+	byName := make(map[string]*setting, len(settings))
+	for i := range settings {
+		s := &settings[i]
+		byName[s.name] = s
+		if !s.envOnly {
+			s.register(fs, c)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configPath == "" {
+		*configPath, _ = lookupEnv(envConfig)
+	}
+	if *configPath != "" {
+		if err := loadFile(*configPath, c); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range settings {
+		s := &settings[i]
+		if value, ok := lookupEnv(s.envName()); ok {
+			if err := s.apply(c, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", s.envName(), err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		if s, ok := byName[f.Name]; ok && flagErr == nil {
+			flagErr = s.apply(c, f.Value.String())
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	return c, c.resolve()
+}
+
+// loadFile overlays the settings present in a YAML file onto c
+func loadFile(path string, c *Config) error {
+	data, err := os.ReadFile(path) // #nosec G304 - path is chosen by the operator
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolve derives and validates settings after all layers are applied
+func (c *Config) resolve() error {
+	if c.Verbose {
+		c.LogLevel = "debug"
+	}
+
+	if c.MasterKeyFile != "" {
+		key, err := os.ReadFile(c.MasterKeyFile)
+		if err != nil {
+			return fmt.Errorf("read master key file: %w", err)
+		}
+		c.MasterKey = strings.TrimSpace(string(key))
+	}
+
+	if c.LLM.APIKeyFile != "" {
+		key, err := os.ReadFile(c.LLM.APIKeyFile)
+		if err != nil {
+			return fmt.Errorf("read LLM API key file: %w", err)
+		}
+		c.LLM.APIKey = strings.TrimSpace(string(key))
+	}
+
+	if c.NoteRevisions < 0 || c.NoteRevisionDays < 0 {
+		return errors.New("--note-revisions and --note-revision-days must not be negative")
+	}
+	if c.TrashDays < 0 {
+		return errors.New("--trash-days must not be negative")
+	}
+
+	if c.LLM.ProviderURL != "" {
+		if _, err := url.ParseRequestURI(c.LLM.ProviderURL); err != nil {
+			return fmt.Errorf("invalid LLM provider URL: %w", err)
+		}
+	}
+
+	return c.resolveAuth()
+}
+
+// resolveAuth validates the standalone mode settings
+func (c *Config) resolveAuth() error {
+	if c.Listen == "" {
+		if c.Auth.Mode != "" {
+			return errors.New("--auth requires --listen")
+		}
+		return nil
+	}
+	if c.DevListen != "" {
+		return errors.New("--listen and --dev-listen are mutually exclusive")
+	}
+
+	switch c.Auth.Mode {
+	case AuthHeader:
+		if c.Auth.Header == "" {
+			return errors.New("--auth header requires --auth-header")
+		}
+		if len(c.Auth.TrustedProxies) == 0 {
+			return errors.New("--auth header requires --trusted-proxies")
+		}
+	case AuthOIDC:
+		return c.Auth.OIDC.resolve()
+	case "":
+		return errors.New("--listen requires --auth header or --auth oidc")
+	default:
+		return fmt.Errorf("invalid --auth %q: must be header or oidc", c.Auth.Mode)
+	}
+	return nil
+}
+
+// resolve reads the client secret file and checks the required settings
+func (o *OIDC) resolve() error {
+	if o.ClientSecretFile != "" {
+		secret, err := os.ReadFile(o.ClientSecretFile)
+		if err != nil {
+			return fmt.Errorf("read OIDC client secret file: %w", err)
+		}
+		o.ClientSecret = strings.TrimSpace(string(secret))
+	}
+
+	if o.Issuer == "" || o.ClientID == "" || o.RedirectURL == "" {
+		return errors.New("--auth oidc requires --oidc-issuer, --oidc-client-id and --oidc-redirect-url")
+	}
+	for name, value := range map[string]string{"OIDC issuer": o.Issuer, "OIDC redirect": o.RedirectURL} {
+		if u, err := url.ParseRequestURI(value); err != nil || u.Host == "" {
+			return fmt.Errorf("invalid %s URL %q", name, value)
+		}
+	}
+	return nil
+}
+
+// NoteRevisionMaxAge returns how long older note revisions are kept, or 0
+func (c *Config) NoteRevisionMaxAge() time.Duration {
+	return time.Duration(c.NoteRevisionDays) * 24 * time.Hour
+}
+
+// TrashRetention returns how long trashed items are kept, or 0
+func (c *Config) TrashRetention() time.Duration {
+	return time.Duration(c.TrashDays) * 24 * time.Hour
+}
+
+// Settings returns the configured LLM values keyed by config table key
+func (l *LLM) Settings() map[string]string {
+	values := map[string]string{}
+	for key, value := range map[string]string{
+		KeyLLMProviderURL: l.ProviderURL,
+		KeyLLMModel:       l.Model,
+		KeyLLMAPIKey:      l.APIKey,
+	} {
+		if value != "" {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
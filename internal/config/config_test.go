@@ -0,0 +1,254 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func envFunc(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	c, err := Load(nil, envFunc(nil), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(c, Defaults()) {
+		t.Errorf("Load() = %+v, want defaults %+v", c, Defaults())
+	}
+}
+
+func TestLoad_Layering(t *testing.T) {
+	file := writeFile(t, "notebook.yaml", `
+hostname: from-file
+db: /data/file.db
+log_level: warn
+note_revision_days: 30
+admins: [alice@example.com]
+llm:
+  provider_url: https://api.openai.com/v1
+  model: gpt-4o
+`)
+
+	env := map[string]string{
+		"NOTEBOOK_CONFIG":    file,
+		"NOTEBOOK_DB":        "/data/env.db",
+		"NOTEBOOK_METRICS":   "true",
+		"NOTEBOOK_ADMIN":     "bob@example.com, carol@example.com",
+		"NOTEBOOK_LLM_MODEL": "gpt-4o-mini",
+	}
+	args := []string{"--log-level", "error", "--llm-model", "gpt-5", "--note-revisions", "20"}
+
+	c, err := Load(args, envFunc(env), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if c.Hostname != "from-file" {
+		t.Errorf("Hostname = %q, want value from file", c.Hostname)
+	}
+	if c.DB != "/data/env.db" {
+		t.Errorf("DB = %q, want value from environment", c.DB)
+	}
+	if c.LogLevel != "error" {
+		t.Errorf("LogLevel = %q, want value from flag", c.LogLevel)
+	}
+	if c.StateDir != "tsnet-state" {
+		t.Errorf("StateDir = %q, want default", c.StateDir)
+	}
+	if !c.Metrics {
+		t.Error("Metrics should be enabled from environment")
+	}
+	if c.NoteRevisions != 20 || c.NoteRevisionDays != 30 {
+		t.Errorf("NoteRevisions = %d, NoteRevisionDays = %d", c.NoteRevisions, c.NoteRevisionDays)
+	}
+	if want := []string{"bob@example.com", "carol@example.com"}; !reflect.DeepEqual(c.Admins, want) {
+		t.Errorf("Admins = %v, want %v", c.Admins, want)
+	}
+	if c.LLM.ProviderURL != "https://api.openai.com/v1" || c.LLM.Model != "gpt-5" {
+		t.Errorf("LLM = %+v", c.LLM)
+	}
+}
+
+func TestLoad_ConfigFlagOverridesEnv(t *testing.T) {
+	flagFile := writeFile(t, "flag.yaml", "hostname: from-flag-file\n")
+	env := map[string]string{"NOTEBOOK_CONFIG": "/does/not/exist.yaml"}
+
+	c, err := Load([]string{"--config", flagFile}, envFunc(env), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.Hostname != "from-flag-file" {
+		t.Errorf("Hostname = %q", c.Hostname)
+	}
+}
+
+func TestLoad_APIKey(t *testing.T) {
+	keyFile := writeFile(t, "key", "sk-from-file\n")
+
+	c, err := Load(nil, envFunc(map[string]string{"NOTEBOOK_LLM_API_KEY": "sk-from-env"}), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.LLM.APIKey != "sk-from-env" {
+		t.Errorf("APIKey = %q, want value from environment", c.LLM.APIKey)
+	}
+
+	env := map[string]string{"NOTEBOOK_LLM_API_KEY": "sk-from-env", "NOTEBOOK_LLM_API_KEY_FILE": keyFile}
+	c, err = Load([]string{"--llm-lock"}, envFunc(env), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.LLM.APIKey != "sk-from-file" || !c.LLM.Lock {
+		t.Errorf("LLM = %+v, want key from file and lock", c.LLM)
+	}
+
+	if _, err := Load([]string{"--llm-api-key", "sk-secret"}, envFunc(nil), io.Discard); err == nil {
+		t.Error("expected the API key to be rejected as a flag")
+	}
+}
+
+func TestLoad_MasterKey(t *testing.T) {
+	keyFile := writeFile(t, "master.key", "file-master-key\n")
+
+	c, err := Load(nil, envFunc(map[string]string{"NOTEBOOK_MASTER_KEY": "env-master-key"}), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.MasterKey != "env-master-key" {
+		t.Errorf("MasterKey = %q, want value from environment", c.MasterKey)
+	}
+
+	c, err = Load([]string{"--master-key-file", keyFile}, envFunc(map[string]string{"NOTEBOOK_MASTER_KEY": "env-master-key"}), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.MasterKey != "file-master-key" {
+		t.Errorf("MasterKey = %q, want value from file", c.MasterKey)
+	}
+
+	if _, err := Load([]string{"--master-key", "secret"}, envFunc(nil), io.Discard); err == nil {
+		t.Error("expected the master key to be rejected as a flag")
+	}
+}
+
+func TestLoad_Standalone(t *testing.T) {
+	file := writeFile(t, "notebook.yaml", `
+listen: :8080
+auth:
+  mode: header
+  header: Remote-User
+  name_header: Remote-Name
+  trusted_proxies: [10.0.0.0/8]
+`)
+	c, err := Load([]string{"--config", file}, envFunc(nil), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := Auth{
+		Mode:           AuthHeader,
+		Header:         "Remote-User",
+		NameHeader:     "Remote-Name",
+		TrustedProxies: []string{"10.0.0.0/8"},
+		OIDC:           OIDC{Scopes: []string{"openid", "profile", "email"}},
+	}
+	if c.Listen != ":8080" || !reflect.DeepEqual(c.Auth, want) {
+		t.Errorf("Load() = %q %+v, want %+v", c.Listen, c.Auth, want)
+	}
+
+	secretFile := writeFile(t, "client.secret", "file-secret\n")
+	c, err = Load([]string{"--listen", ":8080", "--auth", "oidc", "--oidc-client-secret-file", secretFile}, envFunc(map[string]string{
+		"NOTEBOOK_OIDC_ISSUER":        "https://id.example.com/realms/main",
+		"NOTEBOOK_OIDC_CLIENT_ID":     "notebook",
+		"NOTEBOOK_OIDC_CLIENT_SECRET": "env-secret",
+		"NOTEBOOK_OIDC_REDIRECT_URL":  "https://notebook.example.com/auth/callback",
+	}), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.Auth.OIDC.ClientSecret != "file-secret" || c.Auth.OIDC.ClientID != "notebook" {
+		t.Errorf("unexpected OIDC settings %+v", c.Auth.OIDC)
+	}
+
+	if _, err := Load([]string{"--oidc-client-secret", "secret"}, envFunc(nil), io.Discard); err == nil {
+		t.Error("expected the client secret to be rejected as a flag")
+	}
+}
+
+func TestLoad_Verbose(t *testing.T) {
+	c, err := Load([]string{"--verbose"}, envFunc(nil), io.Discard)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.LogLevel != "debug" {
+		t.Errorf("LogLevel = %q, want debug", c.LogLevel)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	unknownKey := writeFile(t, "bad.yaml", "hostnme: typo\n")
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+	}{
+		{name: "unknown flag", args: []string{"--nope"}},
+		{name: "missing file", args: []string{"--config", "/does/not/exist.yaml"}},
+		{name: "unknown file key", args: []string{"--config", unknownKey}},
+		{name: "invalid env boolean", env: map[string]string{"NOTEBOOK_METRICS": "maybe"}},
+		{name: "invalid env integer", env: map[string]string{"NOTEBOOK_NOTE_REVISIONS": "many"}},
+		{name: "negative revisions", args: []string{"--note-revisions", "-1"}},
+		{name: "negative trash days", args: []string{"--trash-days", "-1"}},
+		{name: "invalid provider URL", env: map[string]string{"NOTEBOOK_LLM_PROVIDER_URL": "not a url"}},
+		{name: "missing key file", env: map[string]string{"NOTEBOOK_LLM_API_KEY_FILE": "/does/not/exist"}},
+		{name: "missing master key file", env: map[string]string{"NOTEBOOK_MASTER_KEY_FILE": "/does/not/exist"}},
+		{name: "listen without auth", args: []string{"--listen", ":8080"}},
+		{name: "auth without listen", args: []string{"--auth", "header", "--trusted-proxies", "127.0.0.1"}},
+		{name: "listen and dev-listen", args: []string{"--listen", ":8080", "--dev-listen", ":8081", "--auth", "header", "--trusted-proxies", "127.0.0.1"}},
+		{name: "unknown auth mode", args: []string{"--listen", ":8080", "--auth", "basic"}},
+		{name: "header without proxies", args: []string{"--listen", ":8080", "--auth", "header"}},
+		{name: "oidc without issuer", args: []string{"--listen", ":8080", "--auth", "oidc", "--oidc-client-id", "nb", "--oidc-redirect-url", "https://nb/auth/callback"}},
+		{name: "oidc with relative redirect", args: []string{"--listen", ":8080", "--auth", "oidc", "--oidc-issuer", "https://id", "--oidc-client-id", "nb", "--oidc-redirect-url", "/auth/callback"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.args, envFunc(tt.env), io.Discard); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	if _, err := Load([]string{"-h"}, envFunc(nil), io.Discard); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("expected flag.ErrHelp, got %v", err)
+	}
+}
+
+func TestLLM_Settings(t *testing.T) {
+	l := LLM{ProviderURL: "https://api.anthropic.com/v1", APIKey: "sk-ant"}
+	want := map[string]string{KeyLLMProviderURL: "https://api.anthropic.com/v1", KeyLLMAPIKey: "sk-ant"}
+	if got := l.Settings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Settings() = %v, want %v", got, want)
+	}
+}
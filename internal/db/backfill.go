@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/secrets"
+)
+
+// meetingsTimestampTrigger is the updated_at trigger from migration 001. It is
+// dropped while backfilling so derived columns do not count as edits, which
+// would change every meeting's updated_at and the ETags derived from it.
+const meetingsTimestampTrigger = `
+CREATE TRIGGER update_meetings_timestamp
+AFTER UPDATE ON meetings
+FOR EACH ROW
+BEGIN
+    UPDATE meetings SET updated_at = CURRENT_TIMESTAMP WHERE id = OLD.id;
+END`
+
+// backfillMeetingTimes sets timezone, start_utc and end_utc of existing
+// meetings, reading their naive dates and times in env.location
+func backfillMeetingTimes(ctx context.Context, tx *sql.Tx, env backfillEnv) error {
+	loc := env.location
+	rows, err := tx.QueryContext(ctx, `SELECT id, meeting_date, start_time, end_time FROM meetings WHERE start_utc IS NULL`)
+	if err != nil {
+		return fmt.Errorf("list meetings: %w", err)
+	}
+
+	var meetings []*models.Meeting
+	for rows.Next() {
+		m := &models.Meeting{}
+		if err := rows.Scan(&m.ID, &m.MeetingDate, &m.StartTime, &m.EndTime); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan meeting: %w", err)
+		}
+		meetings = append(meetings, m)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("list meetings: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DROP TRIGGER update_meetings_timestamp`); err != nil {
+		return fmt.Errorf("drop updated_at trigger: %w", err)
+	}
+
+	for _, m := range meetings {
+		if err := m.ResolveUTC(loc); err != nil {
+			// Leave rows with malformed dates alone; readers fall back to the naive fields
+			slog.WarnContext(ctx, "skipping time zone backfill", "meeting_id", m.ID, "error", err)
+			continue
+		}
+
+		var endUTC any
+		if m.EndUTC != nil {
+			endUTC = m.EndUTC.Format(time.RFC3339)
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE meetings SET timezone = ?, start_utc = ?, end_utc = ? WHERE id = ?`,
+			loc.String(), m.StartUTC.Format(time.RFC3339), endUTC, m.ID)
+		if err != nil {
+			return fmt.Errorf("backfill meeting %d: %w", m.ID, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, meetingsTimestampTrigger); err != nil {
+		return fmt.Errorf("restore updated_at trigger: %w", err)
+	}
+
+	return nil
+}
+
+// backfillEncryptSecrets encrypts secret config values stored in plaintext
+// with env.cipher
+func backfillEncryptSecrets(ctx context.Context, tx *sql.Tx, env backfillEnv) error {
+	for _, key := range models.SecretConfigKeys {
+		var value string
+		err := tx.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, key).Scan(&value)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && (value == "" || secrets.IsEncrypted(value))) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("get config %s: %w", key, err)
+		}
+
+		encrypted, err := env.cipher.Encrypt(key, value)
+		if err != nil {
+			return fmt.Errorf("encrypt %s stored in plaintext: %w", key, err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE config SET value = ? WHERE key = ?`, encrypted, key); err != nil {
+			return fmt.Errorf("update config %s: %w", key, err)
+		}
+		slog.InfoContext(ctx, "encrypted config secret", "key", key)
+	}
+
+	return nil
+}
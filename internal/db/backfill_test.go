@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/secrets"
+)
+
+// runBackfill runs a migration's data backfill in a transaction
+func (db *DB) runBackfill(ctx context.Context, backfill func(context.Context, *sql.Tx, backfillEnv) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := backfill(ctx, tx, db.backfillEnv()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func TestBackfillMeetingTimes(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	database, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer database.Close()
+	database.SetLocation(berlin)
+
+	if err := database.Migrate(); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	// Rows written before migration 6 have no zone or UTC instants
+	ctx := context.Background()
+	_, err = database.ExecContext(ctx, `
+		INSERT INTO meetings (created_by, subject, meeting_date, start_time, end_time, updated_at)
+		VALUES ('a@example.com', 'Late', '2026-03-10', '23:30', '00:30', '2026-01-01 00:00:00')`)
+	if err != nil {
+		t.Fatalf("failed to insert meeting: %v", err)
+	}
+
+	if err := database.runBackfill(ctx, backfillMeetingTimes); err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+
+	var zone, startUTC, endUTC, updatedAt string
+	err = database.QueryRowContext(ctx, `SELECT timezone, start_utc, end_utc, updated_at FROM meetings`).Scan(&zone, &startUTC, &endUTC, &updatedAt)
+	if err != nil {
+		t.Fatalf("failed to read meeting: %v", err)
+	}
+
+	if zone != "Europe/Berlin" || startUTC != "2026-03-10T22:30:00Z" || endUTC != "2026-03-10T23:30:00Z" {
+		t.Errorf("unexpected backfill %s %s %s", zone, startUTC, endUTC)
+	}
+	if !strings.HasPrefix(updatedAt, "2026-01-01") {
+		t.Errorf("expected updated_at untouched, got %s", updatedAt)
+	}
+
+	// The updated_at trigger is back in place
+	if _, err := database.ExecContext(ctx, `UPDATE meetings SET subject = 'Later'`); err != nil {
+		t.Fatalf("failed to update meeting: %v", err)
+	}
+	if err := database.QueryRowContext(ctx, `SELECT updated_at FROM meetings`).Scan(&updatedAt); err != nil {
+		t.Fatalf("failed to read meeting: %v", err)
+	}
+	if strings.HasPrefix(updatedAt, "2026-01-01") {
+		t.Error("expected updated_at trigger to be restored")
+	}
+}
+
+func TestBackfillEncryptSecrets(t *testing.T) {
+	database, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.Migrate(); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	// Keys written before migration 7 are plaintext
+	ctx := context.Background()
+	if _, err := database.ExecContext(ctx, `UPDATE config SET value = 'sk-plain' WHERE key = 'llm_api_key'`); err != nil {
+		t.Fatalf("failed to store key: %v", err)
+	}
+
+	if err := database.runBackfill(ctx, backfillEncryptSecrets); !errors.Is(err, secrets.ErrNoMasterKey) {
+		t.Fatalf("expected ErrNoMasterKey without a cipher, got %v", err)
+	}
+
+	c, err := secrets.New([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+	database.SetCipher(c)
+	if err := database.runBackfill(ctx, backfillEncryptSecrets); err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+
+	var stored string
+	if err := database.QueryRowContext(ctx, `SELECT value FROM config WHERE key = 'llm_api_key'`).Scan(&stored); err != nil {
+		t.Fatalf("failed to read key: %v", err)
+	}
+	if plain, err := c.Decrypt("llm_api_key", stored); err != nil || plain != "sk-plain" {
+		t.Errorf("stored value %q decrypts to %q, %v", stored, plain, err)
+	}
+
+	// Encrypted values are left alone
+	if err := database.runBackfill(ctx, backfillEncryptSecrets); err != nil {
+		t.Fatalf("second backfill failed: %v", err)
+	}
+	var again string
+	if err := database.QueryRowContext(ctx, `SELECT value FROM config WHERE key = 'llm_api_key'`).Scan(&again); err != nil {
+		t.Fatalf("failed to read key: %v", err)
+	}
+	if again != stored {
+		t.Error("expected an encrypted value not to be encrypted again")
+	}
+}
@@ -6,9 +6,14 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
-	_ "modernc.org/sqlite" // SQLite driver for database/sql
+	"modernc.org/sqlite"
+
+	"github.com/zorak1103/notebook/internal/secrets"
 )
 
 //go:embed migrations/*.sql
@@ -17,14 +22,52 @@ var migrationsFS embed.FS
 // DB wraps sql.DB for our database operations
 type DB struct {
 	*sql.DB
+	location *time.Location
+	cipher   *secrets.Cipher
+	path     string
+}
+
+// backfillEnv holds what data backfills need beyond the transaction
+type backfillEnv struct {
+	location *time.Location
+	cipher   *secrets.Cipher
+}
+
+// migration is one embedded schema change with an optional data backfill
+type migration struct {
+	version  int
+	file     string
+	backfill func(ctx context.Context, tx *sql.Tx, env backfillEnv) error
+}
+
+// migrations lists all schema changes in the order they are applied
+var migrations = []migration{
+	{1, "migrations/001_initial_schema.sql", nil},
+	{2, "migrations/002_add_language_config.sql", nil},
+	{3, "migrations/003_add_llm_prompts.sql", nil},
+	{4, "migrations/004_add_ical_uid.sql", nil},
+	{5, "migrations/005_add_caldav_name.sql", nil},
+	{6, "migrations/006_add_meeting_timezone.sql", backfillMeetingTimes},
+	{7, "migrations/007_encrypt_config_secrets.sql", backfillEncryptSecrets},
+	{8, "migrations/008_add_meeting_shares.sql", nil},
+	{9, "migrations/009_add_api_tokens.sql", nil},
+	{10, "migrations/010_add_audit_log.sql", nil},
+	{11, "migrations/011_add_note_revisions.sql", nil},
+	{12, "migrations/012_add_meeting_summaries.sql", nil},
+	{13, "migrations/013_add_trash.sql", nil},
+	{14, "migrations/014_add_row_versions.sql", nil},
+	{15, "migrations/015_add_note_hierarchy.sql", nil},
+}
+
+// LatestSchemaVersion returns the version Migrate brings the schema to
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
 }
 
 // Open opens or creates the SQLite database
 func Open(dataSourceName string) (*DB, error) {
-	db, err := sql.Open("sqlite", dataSourceName)
-	if err != nil {
-		return nil, fmt.Errorf("open database: %w", err)
-	}
+	// Statements are timed for the notebook_db_query_duration_seconds metric
+	db := sql.OpenDB(&instrumentedConnector{dsn: dataSourceName, driver: &sqlite.Driver{}})
 
 	// Pragmas for better performance and consistency
 	pragmas := []string{
@@ -40,7 +83,59 @@ func Open(dataSourceName string) (*DB, error) {
 		}
 	}
 
-	return &DB{db}, nil
+	return &DB{DB: db, path: databaseFile(dataSourceName)}, nil
+}
+
+// databaseFile extracts the file path from a data source name, or returns
+// "" for in-memory databases
+func databaseFile(dataSourceName string) string {
+	path := strings.TrimPrefix(dataSourceName, "file:")
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	if path == "" || path == ":memory:" {
+		return ""
+	}
+	return path
+}
+
+// SchemaVersion returns the latest applied migration
+func (db *DB) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("get schema version: %w", err)
+	}
+	return version, nil
+}
+
+// FileSizes returns the size in bytes of the database file and its
+// write-ahead log. Missing files, e.g. for in-memory databases, count as 0.
+func (db *DB) FileSizes() (dbSize, walSize int64) {
+	if db.path == "" {
+		return 0, 0
+	}
+	return fileSize(db.path), fileSize(db.path + "-wal")
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// SetLocation sets the zone migrations interpret existing naive meeting
+// dates and times in. Defaults to time.Local.
+func (db *DB) SetLocation(loc *time.Location) {
+	db.location = loc
+}
+
+// SetCipher sets the cipher migrations encrypt plaintext secrets with.
+// Without one, migrating a database that holds a plaintext secret fails.
+func (db *DB) SetCipher(c *secrets.Cipher) {
+	db.cipher = c
 }
 
 // Migrate runs all embedded migrations
@@ -59,20 +154,9 @@ func (db *DB) Migrate() error {
 	}
 
 	// Get current version
-	var currentVersion int
-	err = db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&currentVersion)
+	currentVersion, err := db.SchemaVersion(ctx)
 	if err != nil {
-		return fmt.Errorf("get current version: %w", err)
-	}
-
-	// Define migrations
-	migrations := []struct {
-		version int
-		file    string
-	}{
-		{1, "migrations/001_initial_schema.sql"},
-		{2, "migrations/002_add_language_config.sql"},
-		{3, "migrations/003_add_llm_prompts.sql"},
+		return err
 	}
 
 	// Apply migrations
@@ -81,21 +165,56 @@ func (db *DB) Migrate() error {
 			continue
 		}
 
-		log.Printf("Applying migration %d: %s", m.version, m.file)
+		slog.InfoContext(ctx, "applying migration", "version", m.version, "file", m.file)
 
-		migrationSQL, err := migrationsFS.ReadFile(m.file)
-		if err != nil {
-			return fmt.Errorf("read migration %d: %w", m.version, err)
+		if err := db.applyMigration(ctx, m); err != nil {
+			return err
 		}
+	}
 
-		if _, err := db.ExecContext(ctx, string(migrationSQL)); err != nil {
-			return fmt.Errorf("apply migration %d: %w", m.version, err)
-		}
+	return nil
+}
 
-		if _, err := db.ExecContext(ctx, "INSERT INTO schema_version (version) VALUES (?)", m.version); err != nil {
-			return fmt.Errorf("record migration %d: %w", m.version, err)
+// applyMigration runs the SQL of a migration, its backfill and the
+// schema_version record in one transaction, so a failed backfill leaves
+// the schema as it was and the migration is retried on the next start
+func (db *DB) applyMigration(ctx context.Context, m migration) error {
+	migrationSQL, err := migrationsFS.ReadFile(m.file)
+	if err != nil {
+		return fmt.Errorf("read migration %d: %w", m.version, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %d: %w", m.version, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, string(migrationSQL)); err != nil {
+		return fmt.Errorf("apply migration %d: %w", m.version, err)
+	}
+
+	if m.backfill != nil {
+		if err := m.backfill(ctx, tx, db.backfillEnv()); err != nil {
+			return fmt.Errorf("backfill migration %d: %w", m.version, err)
 		}
 	}
 
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_version (version) VALUES (?)", m.version); err != nil {
+		return fmt.Errorf("record migration %d: %w", m.version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %d: %w", m.version, err)
+	}
 	return nil
 }
+
+// backfillEnv returns the environment backfills run with
+func (db *DB) backfillEnv() backfillEnv {
+	env := backfillEnv{location: db.location, cipher: db.cipher}
+	if env.location == nil {
+		env.location = time.Local
+	}
+	return env
+}
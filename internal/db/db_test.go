@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrate_SchemaVersion(t *testing.T) {
+	database, err := Open(filepath.Join(t.TempDir(), "notebook.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.Migrate(); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	version, err := database.SchemaVersion(context.Background())
+	if err != nil {
+		t.Fatalf("SchemaVersion failed: %v", err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("schema version = %d, want %d", version, LatestSchemaVersion())
+	}
+
+	dbSize, _ := database.FileSizes()
+	if dbSize == 0 {
+		t.Error("expected non-zero database file size")
+	}
+}
+
+func TestMigrate_FailedBackfillIsRetried(t *testing.T) {
+	database, err := Open(filepath.Join(t.TempDir(), "notebook.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer database.Close()
+
+	all := migrations
+	defer func() { migrations = all }()
+
+	// Migration 6 adds a column and fails its backfill
+	failing := append([]migration(nil), all[:6]...)
+	failing[5].backfill = func(context.Context, *sql.Tx, backfillEnv) error {
+		return errors.New("backfill failed")
+	}
+	migrations = failing
+	if err := database.Migrate(); err == nil {
+		t.Fatal("expected the failed backfill to fail the migration")
+	}
+	version, err := database.SchemaVersion(context.Background())
+	if err != nil || version != 5 {
+		t.Fatalf("schema version = %d (err %v), want 5", version, err)
+	}
+
+	migrations = all
+	if err := database.Migrate(); err != nil {
+		t.Fatalf("retrying the migration failed: %v", err)
+	}
+	if version, _ := database.SchemaVersion(context.Background()); version != LatestSchemaVersion() {
+		t.Errorf("schema version = %d, want %d", version, LatestSchemaVersion())
+	}
+}
+
+func TestDatabaseFile(t *testing.T) {
+	tests := map[string]string{
+		"notebook.db":                 "notebook.db",
+		"file:/data/notebook.db?_x=1": "/data/notebook.db",
+		":memory:":                    "",
+		"file::memory:?cache=shared":  "",
+	}
+	for dsn, want := range tests {
+		if got := databaseFile(dsn); got != want {
+			t.Errorf("databaseFile(%q) = %q, want %q", dsn, got, want)
+		}
+	}
+}
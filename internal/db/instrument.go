@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"log/slog"
+	"time"
+
+	"modernc.org/sqlite"
+
+	"github.com/zorak1103/notebook/internal/metrics"
+)
+
+var queryDuration = metrics.Default.NewHistogramVec(
+	"notebook_db_query_duration_seconds",
+	"Duration of SQLite statements in seconds.",
+	metrics.DefaultBuckets,
+	"operation",
+)
+
+// instrumentedConnector opens SQLite connections that time every statement
+type instrumentedConnector struct {
+	dsn    string
+	driver *sqlite.Driver
+}
+
+func (c *instrumentedConnector) Connect(_ context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn}, nil
+}
+
+func (c *instrumentedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// instrumentedConn records the duration of statements run directly on the connection
+type instrumentedConn struct {
+	driver.Conn
+}
+
+// observeQuery records a statement's duration and logs it at debug level.
+// Arguments are never logged.
+func observeQuery(ctx context.Context, operation, query string, start time.Time) {
+	duration := time.Since(start)
+	queryDuration.Observe(duration.Seconds(), operation)
+	slog.DebugContext(ctx, "db query", "operation", operation, "query", query, "duration", duration)
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	defer observeQuery(ctx, "exec", query, time.Now())
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	defer observeQuery(ctx, "query", query, time.Now())
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Prepare(query)
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Begin() //nolint:staticcheck // fallback for drivers without BeginTx
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
@@ -0,0 +1,8 @@
+-- Track the iCalendar UID of imported meetings so re-imports update instead of duplicating.
+-- Overridden instances of recurring events share the UID and are told apart by RECURRENCE-ID.
+ALTER TABLE meetings ADD COLUMN ical_uid TEXT;
+ALTER TABLE meetings ADD COLUMN ical_recurrence_id TEXT;
+
+CREATE UNIQUE INDEX idx_meetings_ical_uid
+    ON meetings(ical_uid, COALESCE(ical_recurrence_id, ''))
+    WHERE ical_uid IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Resource name (last path segment) a CalDAV client chose when it created a meeting.
+-- Clients such as Apple Calendar pick names unrelated to the event UID and expect them to stay stable.
+ALTER TABLE meetings ADD COLUMN caldav_name TEXT;
+
+CREATE UNIQUE INDEX idx_meetings_caldav_name ON meetings(caldav_name) WHERE caldav_name IS NOT NULL;
@@ -0,0 +1,7 @@
+-- IANA time zone meeting_date/start_time/end_time are expressed in, and the UTC instants derived from them
+-- (RFC 3339, e.g. 2026-03-10T09:00:00Z). Existing rows are backfilled by backfillMeetingTimes.
+ALTER TABLE meetings ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE meetings ADD COLUMN start_utc TEXT;
+ALTER TABLE meetings ADD COLUMN end_utc TEXT;
+
+CREATE INDEX idx_meetings_start_utc ON meetings(start_utc);
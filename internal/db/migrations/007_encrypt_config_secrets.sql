@@ -0,0 +1,3 @@
+-- Secret config values (llm_api_key) are encrypted with the master key from now on.
+-- The backfill of this migration encrypts values stored in plaintext before.
+SELECT 1;
@@ -0,0 +1,31 @@
+-- Public read-only links to single meetings, served through Tailscale Funnel.
+-- Only the SHA-256 hash of a share token is stored; the token itself is shown once on creation.
+CREATE TABLE meeting_shares (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    meeting_id INTEGER NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    include_notes BOOLEAN NOT NULL DEFAULT 0,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TEXT NOT NULL,            -- RFC 3339, UTC
+    revoked_at TEXT,                     -- RFC 3339, UTC
+    last_accessed_at TEXT,               -- RFC 3339, UTC
+    access_count INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_meeting_shares_meeting ON meeting_shares(meeting_id);
+
+-- Audit trail of share creation, revocation and every public access.
+-- No foreign keys, so the trail outlives deleted meetings.
+CREATE TABLE share_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    share_id INTEGER NOT NULL,
+    meeting_id INTEGER NOT NULL,
+    event TEXT NOT NULL,                 -- created, revoked or accessed
+    actor TEXT NOT NULL,                 -- login name, or the remote address for accesses
+    user_agent TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_share_events_share ON share_events(share_id);
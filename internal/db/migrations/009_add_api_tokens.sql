@@ -0,0 +1,15 @@
+-- Personal API tokens for scripts and integrations, sent as "Authorization: Bearer".
+-- Only the SHA-256 hash of a token is stored; the token itself is shown once on creation.
+CREATE TABLE api_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    owner TEXT NOT NULL,                 -- login name the token acts as
+    token_hash TEXT NOT NULL UNIQUE,
+    scope TEXT NOT NULL CHECK (scope IN ('read', 'notes-write', 'admin')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TEXT NOT NULL,            -- RFC 3339, UTC
+    last_used_at TEXT,                   -- RFC 3339, UTC
+    revoked_at TEXT                      -- RFC 3339, UTC
+);
+
+CREATE INDEX idx_api_tokens_owner ON api_tokens(owner);
@@ -0,0 +1,25 @@
+-- Append-only trail of data changes: who created, changed, deleted or reordered
+-- meetings, notes and configuration. Entries are written in the same transaction
+-- as the change; no foreign keys, so the trail outlives deleted entities.
+CREATE TABLE audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor TEXT NOT NULL,                 -- login name, or "system" for startup seeding
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'reorder')),
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('meeting', 'note', 'config')),
+    entity_id TEXT NOT NULL,             -- row ID, or the key of config entries
+    changes TEXT NOT NULL,               -- JSON object of {"field": {"before": ..., "after": ...}}
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id);
+CREATE INDEX idx_audit_log_created ON audit_log(created_at);
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
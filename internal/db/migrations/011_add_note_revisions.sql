@@ -0,0 +1,20 @@
+-- Every version of a note's content, numbered per note. Revisions are written
+-- in the transaction that creates or changes the note and pruned to the
+-- configured retention; the latest revision always matches notes.content.
+CREATE TABLE note_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    note_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,           -- 1 for the first version of a note
+    content TEXT NOT NULL,
+    author TEXT NOT NULL,                -- login name, or "system"
+    source TEXT NOT NULL CHECK (source IN ('manual', 'llm-enhance', 'import')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (note_id) REFERENCES notes(id) ON DELETE CASCADE,
+    UNIQUE(note_id, revision)
+);
+
+-- Existing notes start with their current content, attributed to the
+-- meeting's creator
+INSERT INTO note_revisions (note_id, revision, content, author, source, created_at)
+SELECT notes.id, 1, notes.content, meetings.created_by, 'manual', notes.updated_at
+FROM notes JOIN meetings ON meetings.id = notes.meeting_id;
@@ -0,0 +1,25 @@
+-- Every version of a meeting's summary with its provenance, numbered per
+-- meeting. Versions are written in the transaction that changes
+-- meetings.summary; LLM versions record the model, the SHA-256 of the prompt
+-- template and the token usage reported by the provider.
+CREATE TABLE meeting_summaries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    meeting_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,            -- 1 for the first summary of a meeting
+    summary TEXT NOT NULL,
+    author TEXT NOT NULL,                -- login name, or "system"
+    source TEXT NOT NULL CHECK (source IN ('manual', 'llm')),
+    model TEXT,
+    prompt_hash TEXT,
+    input_tokens INTEGER,
+    output_tokens INTEGER,
+    promoted_from INTEGER,               -- version this one was promoted from
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (meeting_id) REFERENCES meetings(id) ON DELETE CASCADE,
+    UNIQUE(meeting_id, version)
+);
+
+-- Existing summaries become version 1 of unknown provenance
+INSERT INTO meeting_summaries (meeting_id, version, summary, author, source, created_at)
+SELECT id, 1, summary, created_by, 'manual', updated_at
+FROM meetings WHERE summary IS NOT NULL AND summary != '';
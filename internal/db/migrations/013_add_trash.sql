@@ -0,0 +1,54 @@
+-- Soft deletion: deleted meetings and notes stay in the trash until they are
+-- restored or purged. deleted_at is the RFC 3339 UTC time of deletion; notes
+-- trashed together with their meeting share its deleted_at, so restoring the
+-- meeting brings back exactly those notes.
+ALTER TABLE meetings ADD COLUMN deleted_at TEXT;
+ALTER TABLE notes ADD COLUMN deleted_at TEXT;
+
+CREATE INDEX idx_meetings_deleted_at ON meetings(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_notes_deleted_at ON notes(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Calendar identities only need to be unique among live meetings, so a
+-- trashed meeting does not block re-importing or re-creating its event
+DROP INDEX idx_meetings_ical_uid;
+CREATE UNIQUE INDEX idx_meetings_ical_uid
+    ON meetings(ical_uid, COALESCE(ical_recurrence_id, ''))
+    WHERE ical_uid IS NOT NULL AND deleted_at IS NULL;
+
+DROP INDEX idx_meetings_caldav_name;
+CREATE UNIQUE INDEX idx_meetings_caldav_name ON meetings(caldav_name)
+    WHERE caldav_name IS NOT NULL AND deleted_at IS NULL;
+
+-- The audit log records restores and purges; SQLite cannot alter a CHECK
+-- constraint, so the table is rebuilt with its entries
+DROP TRIGGER audit_log_no_update;
+DROP TRIGGER audit_log_no_delete;
+
+CREATE TABLE audit_log_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor TEXT NOT NULL,                 -- login name, or "system" for startup seeding and trash purges
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'reorder', 'restore', 'purge')),
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('meeting', 'note', 'config')),
+    entity_id TEXT NOT NULL,             -- row ID, or the key of config entries
+    changes TEXT NOT NULL,               -- JSON object of {"field": {"before": ..., "after": ...}}
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO audit_log_new (id, actor, action, entity_type, entity_id, changes, created_at)
+SELECT id, actor, action, entity_type, entity_id, changes, created_at FROM audit_log;
+
+DROP TABLE audit_log;
+ALTER TABLE audit_log_new RENAME TO audit_log;
+
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id);
+CREATE INDEX idx_audit_log_created ON audit_log(created_at);
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
@@ -0,0 +1,21 @@
+-- Row versions for optimistic concurrency: every update of a meeting or note
+-- increments its version, which the API exposes as the ETag. updated_at only
+-- has second resolution, so two edits within one second would share a tag.
+ALTER TABLE meetings ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE notes ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+DROP TRIGGER update_meetings_timestamp;
+CREATE TRIGGER update_meetings_timestamp
+AFTER UPDATE ON meetings
+FOR EACH ROW
+BEGIN
+    UPDATE meetings SET updated_at = CURRENT_TIMESTAMP, version = OLD.version + 1 WHERE id = OLD.id;
+END;
+
+DROP TRIGGER update_notes_timestamp;
+CREATE TRIGGER update_notes_timestamp
+AFTER UPDATE ON notes
+FOR EACH ROW
+BEGIN
+    UPDATE notes SET updated_at = CURRENT_TIMESTAMP, version = OLD.version + 1 WHERE id = OLD.id;
+END;
@@ -0,0 +1,8 @@
+-- Nested notes and note types: a note may sit under a parent note of the same
+-- meeting, and its type tells the summary prompt what kind of entry it is.
+-- Existing notes stay top-level plain notes.
+ALTER TABLE notes ADD COLUMN parent_id INTEGER REFERENCES notes(id) ON DELETE SET NULL;
+ALTER TABLE notes ADD COLUMN note_type TEXT NOT NULL DEFAULT 'plain'
+    CHECK (note_type IN ('plain', 'decision', 'question', 'action', 'info'));
+
+CREATE INDEX idx_notes_parent_id ON notes(parent_id) WHERE parent_id IS NOT NULL;
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// API token scopes, from least to most privileged
+const (
+	// TokenScopeRead allows reading everything the owner can read
+	TokenScopeRead = "read"
+	// TokenScopeNotesWrite additionally allows changing meetings and notes
+	TokenScopeNotesWrite = "notes-write"
+	// TokenScopeAdmin allows everything the owner can do
+	TokenScopeAdmin = "admin"
+)
+
+// TokenScopes lists the valid API token scopes
+var TokenScopes = []string{TokenScopeRead, TokenScopeNotesWrite, TokenScopeAdmin}
+
+// APIToken is a personal API token. The secret is only known when the
+// token is created; the database keeps its hash.
+type APIToken struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`
+	Scope      string     `json:"scope"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// Active reports whether the token can still be used at now
+func (t *APIToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
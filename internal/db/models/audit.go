@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit actions recorded in audit_log
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionReorder = "reorder"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+)
+
+// Audited entity types
+const (
+	AuditEntityMeeting = "meeting"
+	AuditEntityNote    = "note"
+	AuditEntityConfig  = "config"
+)
+
+// AuditActions and AuditEntityTypes list the valid audit filter values
+var (
+	AuditActions     = []string{AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionReorder, AuditActionRestore, AuditActionPurge}
+	AuditEntityTypes = []string{AuditEntityMeeting, AuditEntityNote, AuditEntityConfig}
+)
+
+// AuditRedacted replaces secret values in audit entries
+const AuditRedacted = "[redacted]"
+
+// AuditSystemActor is recorded for changes made without a user, e.g. when
+// configuration is seeded on startup or the trash is purged
+const AuditSystemActor = "system"
+
+// AuditChange is the value of one field before and after a change. Before
+// is null for created entities, After for deleted ones.
+type AuditChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditEntry is one entry of the audit log
+type AuditEntry struct {
+	ID         int                    `json:"id"`
+	Actor      string                 `json:"actor"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Changes    map[string]AuditChange `json:"changes"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
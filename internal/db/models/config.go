@@ -8,3 +8,16 @@ type Config struct {
 	Value     string    `json:"value"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// SecretConfigKeys lists the config keys whose values are encrypted at rest
+var SecretConfigKeys = []string{"llm_api_key"}
+
+// IsSecretConfigKey reports whether key holds a secret
+func IsSecretConfigKey(key string) bool {
+	for _, secret := range SecretConfigKeys {
+		if key == secret {
+			return true
+		}
+	}
+	return false
+}
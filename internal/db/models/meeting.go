@@ -1,18 +1,63 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+)
 
 // Meeting represents a meeting record
 type Meeting struct {
-	ID           int       `json:"id"`
-	CreatedBy    string    `json:"created_by"`
-	Subject      string    `json:"subject"`
-	MeetingDate  string    `json:"meeting_date"` // YYYY-MM-DD
-	StartTime    string    `json:"start_time"`   // HH:MM
-	EndTime      *string   `json:"end_time"`     // optional
-	Participants *string   `json:"participants"` // optional
-	Summary      *string   `json:"summary"`      // optional
-	Keywords     *string   `json:"keywords"`     // optional
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID               int        `json:"id"`
+	CreatedBy        string     `json:"created_by"`
+	Subject          string     `json:"subject"`
+	MeetingDate      string     `json:"meeting_date"`                 // YYYY-MM-DD
+	StartTime        string     `json:"start_time"`                   // HH:MM
+	EndTime          *string    `json:"end_time"`                     // optional
+	Participants     *string    `json:"participants"`                 // optional
+	Summary          *string    `json:"summary"`                      // optional
+	Keywords         *string    `json:"keywords"`                     // optional
+	ICalUID          *string    `json:"ical_uid,omitempty"`           // set for meetings imported from .ics
+	ICalRecurrenceID *string    `json:"ical_recurrence_id,omitempty"` // overridden instance of a recurring event
+	CalDAVName       *string    `json:"-"`                            // resource name chosen by a CalDAV client
+	Timezone         string     `json:"timezone"`                     // IANA zone of meeting_date, start_time and end_time
+	StartUTC         *time.Time `json:"start_utc"`                    // derived from the local start
+	EndUTC           *time.Time `json:"end_utc"`                      // derived from the local end; nil without end_time
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`         // set while the meeting is in the trash
+	Version          int        `json:"version"`                      // incremented by every update; the API's ETag
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// ResolveUTC derives StartUTC and EndUTC from the local date and times,
+// interpreted in loc. An end time before the start time means the meeting
+// runs past midnight.
+func (m *Meeting) ResolveUTC(loc *time.Location) error {
+	start, err := time.ParseInLocation(dateLayout+" "+timeLayout, m.MeetingDate+" "+m.StartTime, loc)
+	if err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	start = start.UTC()
+	m.StartUTC = &start
+	m.EndUTC = nil
+
+	if m.EndTime == nil || *m.EndTime == "" {
+		return nil
+	}
+
+	end, err := time.ParseInLocation(dateLayout+" "+timeLayout, m.MeetingDate+" "+*m.EndTime, loc)
+	if err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	if end.Before(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	end = end.UTC()
+	m.EndUTC = &end
+
+	return nil
 }
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Sources of meeting summary versions
+const (
+	SummarySourceManual = "manual"
+	SummarySourceLLM    = "llm"
+)
+
+// SummaryProvenance describes how a summary version was produced. The LLM
+// fields are nil for manual summaries.
+type SummaryProvenance struct {
+	Source string `json:"source"`
+	// Model is the model that generated the summary
+	Model *string `json:"model"`
+	// PromptHash is the hex SHA-256 of the prompt template
+	PromptHash   *string `json:"prompt_hash"`
+	InputTokens  *int    `json:"input_tokens"`
+	OutputTokens *int    `json:"output_tokens"`
+	// PromotedFrom is the version a promoted summary was copied from
+	PromotedFrom *int `json:"promoted_from"`
+}
+
+// MeetingSummary is one stored version of a meeting's summary
+type MeetingSummary struct {
+	ID        int    `json:"id"`
+	MeetingID int    `json:"meeting_id"`
+	Version   int    `json:"version"`
+	Summary   string `json:"summary"`
+	Author    string `json:"author"`
+	SummaryProvenance
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -2,12 +2,42 @@ package models
 
 import "time"
 
+// Note types
+const (
+	NoteTypePlain    = "plain"
+	NoteTypeDecision = "decision"
+	NoteTypeQuestion = "question"
+	NoteTypeAction   = "action"
+	NoteTypeInfo     = "info"
+)
+
+// NoteTypes lists the valid note types
+var NoteTypes = []string{NoteTypePlain, NoteTypeDecision, NoteTypeQuestion, NoteTypeAction, NoteTypeInfo}
+
 // Note represents a note within a meeting
 type Note struct {
-	ID         int       `json:"id"`
-	MeetingID  int       `json:"meeting_id"`
-	NoteNumber int       `json:"note_number"`
-	Content    string    `json:"content"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         int    `json:"id"`
+	MeetingID  int    `json:"meeting_id"`
+	NoteNumber int    `json:"note_number"`
+	Content    string `json:"content"`
+	// ParentID is the note of the same meeting this one is nested under; nil
+	// for top-level notes
+	ParentID *int `json:"parent_id"`
+	// NoteType is one of NoteTypes
+	NoteType  string    `json:"note_type"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt is set while the note is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version is incremented by every update; the API derives its ETag from it
+	Version int `json:"version"`
+}
+
+// SameParent reports whether n and other are nested under the same note or
+// are both top-level
+func (n *Note) SameParent(other *Note) bool {
+	if n.ParentID == nil || other.ParentID == nil {
+		return n.ParentID == other.ParentID
+	}
+	return *n.ParentID == *other.ParentID
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Sources of note revisions
+const (
+	RevisionSourceManual     = "manual"
+	RevisionSourceLLMEnhance = "llm-enhance"
+	RevisionSourceImport     = "import"
+)
+
+// NoteRevision is one stored version of a note's content
+type NoteRevision struct {
+	ID        int       `json:"id"`
+	NoteID    int       `json:"note_id"`
+	Revision  int       `json:"revision"`
+	Content   string    `json:"content"`
+	Author    string    `json:"author"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// ReportPeriod aggregates the meetings of one week or month, or of the whole
+// report range when Period is empty
+type ReportPeriod struct {
+	Period             string  `json:"period,omitempty"` // first day (YYYY-MM-DD) of the week (Monday) or month
+	Meetings           int     `json:"meetings"`
+	Hours              float64 `json:"hours"`
+	Notes              int     `json:"notes"`
+	NoteChars          int     `json:"note_chars"`
+	AvgNotesPerMeeting float64 `json:"avg_notes_per_meeting"`
+	Summarized         int     `json:"summarized"`
+	SummaryShare       float64 `json:"summary_share"` // 0..1
+}
+
+// ReportBreakdown aggregates the meetings of one period that share a keyword or participant
+type ReportBreakdown struct {
+	Period   string  `json:"period"`
+	Name     string  `json:"name"`
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+}
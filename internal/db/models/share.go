@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Share event types recorded in share_events
+const (
+	ShareEventCreated  = "created"
+	ShareEventRevoked  = "revoked"
+	ShareEventAccessed = "accessed"
+)
+
+// MeetingShare is a public read-only link to a meeting. The token is only
+// known when the share is created; the database keeps its hash.
+type MeetingShare struct {
+	ID             int        `json:"id"`
+	MeetingID      int        `json:"meeting_id"`
+	IncludeNotes   bool       `json:"include_notes"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+	AccessCount    int        `json:"access_count"`
+}
+
+// Active reports whether the share can still be used at now
+func (s *MeetingShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ShareEvent is one entry of the share audit trail
+type ShareEvent struct {
+	ID        int       `json:"id"`
+	ShareID   int       `json:"share_id"`
+	MeetingID int       `json:"meeting_id"`
+	Event     string    `json:"event"`
+	Actor     string    `json:"actor"`
+	UserAgent *string   `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Types of trash items
+const (
+	TrashTypeMeeting = "meeting"
+	TrashTypeNote    = "note"
+)
+
+// TrashItem is a meeting or note in the trash
+type TrashItem struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+	// MeetingID is the meeting of a note, or the meeting itself
+	MeetingID   int    `json:"meeting_id"`
+	Subject     string `json:"subject"`
+	MeetingDate string `json:"meeting_date"`
+	// NoteNumber and Content are set for notes
+	NoteNumber int    `json:"note_number,omitempty"`
+	Content    string `json:"content,omitempty"`
+	// Notes is the number of notes trashed with a meeting
+	Notes     int       `json:"notes"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// APITokenPrefix starts every API token, so leaked tokens are easy to
+// recognize and a Bearer value is not mistaken for another kind of token
+const APITokenPrefix = "nbt_"
+
+// apiTokenBytes is the entropy of an API token
+const apiTokenBytes = 32
+
+// apiTokenTouchInterval limits how often last_used_at is written
+const apiTokenTouchInterval = time.Minute
+
+// apiTokenColumns is the column list shared by all token SELECTs, in scanAPIToken order
+const apiTokenColumns = `id, name, owner, scope, created_at, expires_at, last_used_at, revoked_at`
+
+// APITokenRepository handles personal API tokens
+type APITokenRepository struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
+// NewAPITokenRepository creates a new API token repository
+func NewAPITokenRepository(db *sql.DB) *APITokenRepository {
+	return &APITokenRepository{db: db}
+}
+
+// WithContext returns a copy of the repository that runs its queries with ctx
+func (r *APITokenRepository) WithContext(ctx context.Context) *APITokenRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// NewAPIToken returns a random API token with APITokenPrefix
+func NewAPIToken() (string, error) {
+	b := make([]byte, apiTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate API token: %w", err)
+	}
+	return APITokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// scanAPIToken scans a row selected with apiTokenColumns
+func scanAPIToken(row rowScanner) (*models.APIToken, error) {
+	t := &models.APIToken{}
+	var expiresAt, lastUsedAt, revokedAt sql.NullString
+	if err := row.Scan(&t.ID, &t.Name, &t.Owner, &t.Scope, &t.CreatedAt, &expiresAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	expires, err := parseUTCColumn(expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("api token %d expires_at: %w", t.ID, err)
+	}
+	if expires == nil {
+		return nil, fmt.Errorf("api token %d has no expires_at", t.ID)
+	}
+	t.ExpiresAt = *expires
+	if t.LastUsedAt, err = parseUTCColumn(lastUsedAt); err != nil {
+		return nil, fmt.Errorf("api token %d last_used_at: %w", t.ID, err)
+	}
+	if t.RevokedAt, err = parseUTCColumn(revokedAt); err != nil {
+		return nil, fmt.Errorf("api token %d revoked_at: %w", t.ID, err)
+	}
+	return t, nil
+}
+
+// Create stores a token for secret
+func (r *APITokenRepository) Create(t *models.APIToken, secret string) error {
+	ctx := queryContext(r.ctx)
+	expiresAt := t.ExpiresAt.UTC()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO api_tokens (name, owner, token_hash, scope, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, t.Name, t.Owner, hashToken(secret), t.Scope, utcColumn(&expiresAt))
+	if err != nil {
+		return fmt.Errorf("create api token: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	created, err := r.GetByID(int(id))
+	if err != nil {
+		return err
+	}
+	if created == nil {
+		return fmt.Errorf("api token %d not found after insert", id)
+	}
+	*t = *created
+	return nil
+}
+
+// GetByID retrieves a token by ID
+func (r *APITokenRepository) GetByID(id int) (*models.APIToken, error) {
+	ctx := queryContext(r.ctx)
+	row := r.db.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE id = ?`, id)
+	t, err := scanAPIToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get api token: %w", err)
+	}
+	return t, nil
+}
+
+// GetBySecret retrieves the token a secret belongs to, whether active or not
+func (r *APITokenRepository) GetBySecret(secret string) (*models.APIToken, error) {
+	if !strings.HasPrefix(secret, APITokenPrefix) {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+
+	ctx := queryContext(r.ctx)
+	row := r.db.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = ?`, hashToken(secret))
+	t, err := scanAPIToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get api token: %w", err)
+	}
+	return t, nil
+}
+
+// ListByOwner lists the tokens of a user, newest first
+func (r *APITokenRepository) ListByOwner(owner string) ([]*models.APIToken, error) {
+	ctx := queryContext(r.ctx)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+apiTokenColumns+` FROM api_tokens
+		WHERE owner = ?
+		ORDER BY id DESC
+	`, owner)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*models.APIToken{}
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api token: %w", err)
+		}
+		tokens = append(tokens, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Revoke disables a token. Revoking a token twice keeps the first
+// revocation time.
+func (r *APITokenRepository) Revoke(t *models.APIToken) error {
+	ctx := queryContext(r.ctx)
+	now := time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE api_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL
+	`, utcColumn(&now), t.ID)
+	if err != nil {
+		return fmt.Errorf("revoke api token: %w", err)
+	}
+	if t.RevokedAt == nil {
+		t.RevokedAt = &now
+	}
+	return nil
+}
+
+// Touch records that a token was used at now. To spare a write per
+// request, last_used_at is only updated once per minute.
+func (r *APITokenRepository) Touch(t *models.APIToken, now time.Time) error {
+	if t.LastUsedAt != nil && now.Sub(*t.LastUsedAt) < apiTokenTouchInterval {
+		return nil
+	}
+
+	ctx := queryContext(r.ctx)
+	now = now.UTC()
+	_, err := r.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, utcColumn(&now), t.ID)
+	if err != nil {
+		return fmt.Errorf("touch api token: %w", err)
+	}
+	t.LastUsedAt = &now
+	return nil
+}
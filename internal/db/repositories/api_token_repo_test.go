@@ -0,0 +1,89 @@
+package repositories_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func TestAPITokenRepository_Lifecycle(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	secret, err := repositories.NewAPIToken()
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	if !strings.HasPrefix(secret, repositories.APITokenPrefix) {
+		t.Errorf("token %q lacks prefix", secret)
+	}
+
+	repo := repositories.NewAPITokenRepository(database.DB)
+	token := &models.APIToken{
+		Name:      "CI",
+		Owner:     "alice@example.com",
+		Scope:     models.TokenScopeNotesWrite,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if err := repo.Create(token, secret); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if token.ID == 0 || !token.Active(time.Now()) || token.LastUsedAt != nil {
+		t.Fatalf("unexpected token %+v", token)
+	}
+
+	found, err := repo.GetBySecret(secret)
+	if err != nil || found == nil || found.ID != token.ID {
+		t.Fatalf("GetBySecret() = %v, %v", found, err)
+	}
+	for _, wrong := range []string{"", "nbt_unknown", strings.TrimPrefix(secret, repositories.APITokenPrefix)} {
+		if missing, err := repo.GetBySecret(wrong); err != nil || missing != nil {
+			t.Errorf("GetBySecret(%q) = %v, %v", wrong, missing, err)
+		}
+	}
+
+	// Touch writes at most once per interval
+	now := time.Now()
+	if err := repo.Touch(found, now); err != nil {
+		t.Fatalf("touch failed: %v", err)
+	}
+	if err := repo.Touch(found, now.Add(10*time.Second)); err != nil {
+		t.Fatalf("second touch failed: %v", err)
+	}
+	reloaded, err := repo.GetByID(token.ID)
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if reloaded.LastUsedAt == nil || reloaded.LastUsedAt.Unix() != now.Unix() {
+		t.Errorf("LastUsedAt = %v, want %v", reloaded.LastUsedAt, now)
+	}
+
+	if err := repo.Revoke(reloaded); err != nil {
+		t.Fatalf("revoke failed: %v", err)
+	}
+
+	if err := repo.Create(&models.APIToken{Name: "Other", Owner: "bob@example.com", Scope: models.TokenScopeRead, ExpiresAt: time.Now().Add(time.Hour)}, "nbt_other"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	tokens, err := repo.ListByOwner("alice@example.com")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].Active(time.Now()) {
+		t.Errorf("unexpected tokens %+v", tokens)
+	}
+}
+
+func TestAPITokenRepository_InvalidScope(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	repo := repositories.NewAPITokenRepository(database.DB)
+	err := repo.Create(&models.APIToken{Name: "x", Owner: "alice@example.com", Scope: "root", ExpiresAt: time.Now().Add(time.Hour)}, "nbt_x")
+	if err == nil {
+		t.Error("expected invalid scope to be rejected")
+	}
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// DefaultAuditLimit is the number of entries List returns without a limit
+const DefaultAuditLimit = 500
+
+// auditIgnoredFields are maintained by the database and left out of diffs
+var auditIgnoredFields = map[string]bool{"id": true, "created_at": true, "updated_at": true, "version": true}
+
+// AuditFilter selects audit log entries. Empty fields match everything;
+// From and To are inclusive dates (YYYY-MM-DD, UTC).
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       string
+	To         string
+	Limit      int
+}
+
+// AuditRepository reads the audit log. Entries are written by the other
+// repositories in the transaction of the change they record.
+type AuditRepository struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// WithContext returns a copy of the repository that runs its queries with ctx
+func (r *AuditRepository) WithContext(ctx context.Context) *AuditRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// auditFields returns the JSON fields of an entity, or nil for a nil pointer
+func auditFields(entity any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// auditDiff returns the fields that differ between two states of an entity.
+// before is nil for created entities, after for deleted ones.
+func auditDiff(before, after any) (map[string]models.AuditChange, error) {
+	old, err := auditFields(before)
+	if err != nil {
+		return nil, fmt.Errorf("encode audit state: %w", err)
+	}
+	updated, err := auditFields(after)
+	if err != nil {
+		return nil, fmt.Errorf("encode audit state: %w", err)
+	}
+
+	changes := map[string]models.AuditChange{}
+	for field, value := range updated {
+		if !auditIgnoredFields[field] && !auditEqual(old[field], value) {
+			changes[field] = models.AuditChange{Before: old[field], After: value}
+		}
+	}
+	for field, value := range old {
+		if _, ok := updated[field]; !ok && !auditIgnoredFields[field] && !auditEqual(value, nil) {
+			changes[field] = models.AuditChange{Before: value}
+		}
+	}
+	return changes, nil
+}
+
+// auditEqual reports whether two JSON values are equal, treating a missing
+// value as null
+func auditEqual(a, b json.RawMessage) bool {
+	if len(a) == 0 {
+		a = json.RawMessage("null")
+	}
+	if len(b) == 0 {
+		b = json.RawMessage("null")
+	}
+	return bytes.Equal(a, b)
+}
+
+// insertAuditEntry appends an entry by the actor of ctx to the audit log.
+// Updates without changes are not recorded.
+func insertAuditEntry(ctx context.Context, tx *sql.Tx, action, entityType, entityID string, changes map[string]models.AuditChange) error {
+	if action == models.AuditActionUpdate && len(changes) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("encode audit changes: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_log (actor, action, entity_type, entity_id, changes)
+		VALUES (?, ?, ?, ?, ?)
+	`, actorFromContext(ctx), action, entityType, entityID, string(data))
+	if err != nil {
+		return fmt.Errorf("record audit entry: %w", err)
+	}
+	return nil
+}
+
+// List returns the entries matching filter, newest first
+func (r *AuditRepository) List(filter AuditFilter) ([]*models.AuditEntry, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	for _, f := range []struct{ column, value string }{
+		{"actor", filter.Actor},
+		{"action", filter.Action},
+		{"entity_type", filter.EntityType},
+		{"entity_id", filter.EntityID},
+	} {
+		if f.value != "" {
+			conditions = append(conditions, f.column+" = ?")
+			args = append(args, f.value)
+		}
+	}
+	if filter.From != "" {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		conditions = append(conditions, "created_at < date(?, '+1 day')")
+		args = append(args, filter.To)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultAuditLimit
+	}
+	args = append(args, limit)
+
+	ctx := queryContext(r.ctx)
+	//nolint:gosec // only fixed column names are interpolated
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, actor, action, entity_type, entity_id, changes, created_at
+		FROM audit_log
+		`+where+`
+		ORDER BY id DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*models.AuditEntry{}
+	for rows.Next() {
+		e := &models.AuditEntry{}
+		var changes string
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityID, &changes, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, fmt.Errorf("audit entry %d changes: %w", e.ID, err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return entries, nil
+}
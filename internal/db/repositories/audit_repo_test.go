@@ -0,0 +1,148 @@
+package repositories_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func TestAuditRepository_RecordsMutations(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	ctx := repositories.ContextWithActor(context.Background(), "alice@example.com")
+	meetings := repositories.NewMeetingRepository(database.DB).WithContext(ctx)
+	notes := repositories.NewNoteRepository(database.DB).WithContext(ctx)
+	audit := repositories.NewAuditRepository(database.DB)
+
+	m := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Standup", MeetingDate: "2026-03-01", StartTime: "09:00"}
+	if err := meetings.Create(m); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	m.Subject = "Daily"
+	if err := meetings.Update(m); err != nil {
+		t.Fatalf("update meeting: %v", err)
+	}
+	// Unchanged updates are not recorded
+	if err := meetings.Update(m); err != nil {
+		t.Fatalf("update meeting: %v", err)
+	}
+
+	n1 := &models.Note{MeetingID: m.ID, Content: "first"}
+	n2 := &models.Note{MeetingID: m.ID, Content: "second"}
+	for _, n := range []*models.Note{n1, n2} {
+		if err := notes.Create(n); err != nil {
+			t.Fatalf("create note: %v", err)
+		}
+	}
+	if err := notes.SwapNoteOrder(n1.ID, n2.ID); err != nil {
+		t.Fatalf("swap notes: %v", err)
+	}
+	if err := meetings.Delete(m.ID); err != nil {
+		t.Fatalf("delete meeting: %v", err)
+	}
+
+	entries, err := audit.List(repositories.AuditFilter{})
+	if err != nil {
+		t.Fatalf("list audit log: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		if e.Actor != "alice@example.com" {
+			t.Errorf("entry %d actor = %q", e.ID, e.Actor)
+		}
+		got = append(got, e.Action+" "+e.EntityType)
+	}
+	want := "delete meeting,reorder note,reorder note,create note,create note,update meeting,create meeting"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("entries = %s, want %s", strings.Join(got, ","), want)
+	}
+
+	update := entries[5].Changes
+	if len(update) != 1 || string(update["subject"].Before) != `"Standup"` || string(update["subject"].After) != `"Daily"` {
+		t.Errorf("update changes = %+v", update)
+	}
+	reorder := entries[1].Changes
+	if len(reorder) != 1 || string(reorder["note_number"].Before) != "2" || string(reorder["note_number"].After) != "1" {
+		t.Errorf("reorder changes = %+v", reorder)
+	}
+	deleted := entries[0].Changes
+	if len(deleted) != 1 || string(deleted["deleted_at"].Before) != "null" || string(deleted["deleted_at"].After) == "null" {
+		t.Errorf("delete changes = %+v", deleted)
+	}
+
+	// Filters
+	filtered, err := audit.List(repositories.AuditFilter{EntityType: models.AuditEntityMeeting, EntityID: strconv.Itoa(m.ID), Action: models.AuditActionUpdate})
+	if err != nil || len(filtered) != 1 {
+		t.Errorf("filtered entries = %d, %v", len(filtered), err)
+	}
+	if filtered, _ = audit.List(repositories.AuditFilter{Actor: "bob@example.com"}); len(filtered) != 0 {
+		t.Errorf("expected no entries for another actor, got %d", len(filtered))
+	}
+	if filtered, _ = audit.List(repositories.AuditFilter{From: "2000-01-01", To: "2000-12-31"}); len(filtered) != 0 {
+		t.Errorf("expected no entries outside the date range, got %d", len(filtered))
+	}
+	if filtered, _ = audit.List(repositories.AuditFilter{Limit: 2}); len(filtered) != 2 {
+		t.Errorf("expected 2 entries with limit, got %d", len(filtered))
+	}
+}
+
+func TestAuditRepository_ConfigSecretsRedacted(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	repo := repositories.NewConfigRepository(database.DB).WithCipher(newTestCipher(t, "0123456789abcdef0123456789abcdef"))
+	if err := repo.Set("llm_api_key", "sk-secret"); err != nil {
+		t.Fatalf("set api key: %v", err)
+	}
+	if err := repo.Set("llm_model", "gpt-4"); err != nil {
+		t.Fatalf("set model: %v", err)
+	}
+	if err := repo.Set("llm_model", "gpt-4"); err != nil {
+		t.Fatalf("set model: %v", err)
+	}
+
+	entries, err := repositories.NewAuditRepository(database.DB).List(repositories.AuditFilter{EntityType: models.AuditEntityConfig})
+	if err != nil {
+		t.Fatalf("list audit log: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].EntityID != "llm_model" || entries[0].Actor != models.AuditSystemActor {
+		t.Errorf("unexpected entry %+v", entries[0])
+	}
+	secret := entries[1]
+	if secret.EntityID != "llm_api_key" || string(secret.Changes["value"].After) != `"`+models.AuditRedacted+`"` {
+		t.Errorf("unexpected secret entry %+v", secret)
+	}
+
+	var raw string
+	if err := database.QueryRowContext(context.Background(), `SELECT group_concat(changes) FROM audit_log`).Scan(&raw); err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	if strings.Contains(raw, "sk-secret") || strings.Contains(raw, "enc:") {
+		t.Errorf("audit log leaks the API key: %s", raw)
+	}
+}
+
+func TestAuditRepository_AppendOnly(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	if err := repositories.NewConfigRepository(database.DB).Set("language", "de"); err != nil {
+		t.Fatalf("set language: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := database.ExecContext(ctx, `UPDATE audit_log SET actor = 'mallory'`); err == nil {
+		t.Error("expected audit log update to fail")
+	}
+	if _, err := database.ExecContext(ctx, `DELETE FROM audit_log`); err == nil {
+		t.Error("expected audit log delete to fail")
+	}
+}
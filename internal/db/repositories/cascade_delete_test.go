@@ -51,10 +51,13 @@ func TestCascadeDelete_MeetingDeletesNotes(t *testing.T) {
 		t.Errorf("expected 2 notes before delete, got %d", len(notes))
 	}
 
-	// Delete the meeting
+	// Delete the meeting and purge it from the trash
 	if err := meetingRepo.Delete(meeting.ID); err != nil {
 		t.Fatalf("failed to delete meeting: %v", err)
 	}
+	if err := meetingRepo.Purge(meeting.ID); err != nil {
+		t.Fatalf("failed to purge meeting: %v", err)
+	}
 
 	// Verify notes were cascade deleted
 	notesAfterDelete, err := noteRepo.ListByMeeting(meeting.ID)
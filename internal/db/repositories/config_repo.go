@@ -3,14 +3,20 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/secrets"
 )
 
-// ConfigRepository handles config CRUD operations
+// ConfigRepository handles config CRUD operations. Values of
+// models.SecretConfigKeys are encrypted with the repository's cipher.
 type ConfigRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	ctx    context.Context
+	cipher *secrets.Cipher
 }
 
 // NewConfigRepository creates a new config repository
@@ -18,9 +24,50 @@ func NewConfigRepository(db *sql.DB) *ConfigRepository {
 	return &ConfigRepository{db: db}
 }
 
+// WithContext returns a copy of the repository that runs its queries with ctx
+func (r *ConfigRepository) WithContext(ctx context.Context) *ConfigRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// WithCipher returns a copy of the repository that encrypts secrets with c.
+// Without a cipher, reading or writing a non-empty secret fails with
+// secrets.ErrNoMasterKey.
+func (r *ConfigRepository) WithCipher(c *secrets.Cipher) *ConfigRepository {
+	cp := *r
+	cp.cipher = c
+	return &cp
+}
+
+// encrypt returns the value to store for key
+func (r *ConfigRepository) encrypt(key, value string) (string, error) {
+	if !models.IsSecretConfigKey(key) {
+		return value, nil
+	}
+	encrypted, err := r.cipher.Encrypt(key, value)
+	if err != nil {
+		return "", fmt.Errorf("encrypt %s: %w", key, err)
+	}
+	return encrypted, nil
+}
+
+// decrypt replaces the stored value of a secret with its plaintext
+func (r *ConfigRepository) decrypt(c *models.Config) error {
+	if !models.IsSecretConfigKey(c.Key) {
+		return nil
+	}
+	value, err := r.cipher.Decrypt(c.Key, c.Value)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: %w", c.Key, err)
+	}
+	c.Value = value
+	return nil
+}
+
 // Get retrieves a config value by key
 func (r *ConfigRepository) Get(key string) (*models.Config, error) {
-	ctx := context.Background()
+	ctx := queryContext(r.ctx)
 	c := &models.Config{}
 	err := r.db.QueryRowContext(ctx, `
 		SELECT key, value, updated_at FROM config WHERE key = ?
@@ -33,13 +80,16 @@ func (r *ConfigRepository) Get(key string) (*models.Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("get config: %w", err)
 	}
+	if err := r.decrypt(c); err != nil {
+		return nil, err
+	}
 
 	return c, nil
 }
 
 // GetAll retrieves all config entries
 func (r *ConfigRepository) GetAll() ([]*models.Config, error) {
-	ctx := context.Background()
+	ctx := queryContext(r.ctx)
 	rows, err := r.db.QueryContext(ctx, `SELECT key, value, updated_at FROM config`)
 	if err != nil {
 		return nil, fmt.Errorf("get all config: %w", err)
@@ -52,6 +102,9 @@ func (r *ConfigRepository) GetAll() ([]*models.Config, error) {
 		if err := rows.Scan(&c.Key, &c.Value, &c.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan config: %w", err)
 		}
+		if err := r.decrypt(c); err != nil {
+			return nil, err
+		}
 		configs = append(configs, c)
 	}
 
@@ -62,17 +115,151 @@ func (r *ConfigRepository) GetAll() ([]*models.Config, error) {
 	return configs, nil
 }
 
-// Set sets a config value (upsert)
+// Set sets a config value (upsert) and records the change in the audit log
 func (r *ConfigRepository) Set(key, value string) error {
-	ctx := context.Background()
-	_, err := r.db.ExecContext(ctx, `
+	return r.upsert(`
 		INSERT INTO config (key, value) VALUES (?, ?)
 		ON CONFLICT(key) DO UPDATE SET value = excluded.value
 	`, key, value)
+}
+
+// Seed stores values whose key is missing or empty. With overwrite set,
+// existing values are replaced as well.
+func (r *ConfigRepository) Seed(values map[string]string, overwrite bool) error {
+	query := `
+		INSERT INTO config (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value WHERE config.value = ''
+	`
+	if overwrite {
+		query = `
+			INSERT INTO config (key, value) VALUES (?, ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value WHERE config.value != excluded.value
+		`
+	}
+
+	for key, value := range values {
+		if overwrite && models.IsSecretConfigKey(key) {
+			// Ciphertexts never compare equal, so compare the plaintext to
+			// keep updated_at stable across restarts
+			current, err := r.Get(key)
+			if err != nil {
+				return err
+			}
+			if current != nil && current.Value == value {
+				continue
+			}
+		}
+
+		if err := r.upsert(query, key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upsert runs the INSERT query for key and value and records an actual
+// change in the audit log in the same transaction
+func (r *ConfigRepository) upsert(query, key, value string) error {
+	ctx := queryContext(r.ctx)
+	stored, err := r.encrypt(key, value)
+	if err != nil {
+		return err
+	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("set config: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
+	var before sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, key).Scan(&before)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("get config %s: %w", key, err)
+	}
+
+	result, err := tx.ExecContext(ctx, query, key, stored)
+	if err != nil {
+		return fmt.Errorf("set config %s: %w", key, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+
+	// Ciphertexts never compare equal, so every non-empty write of a secret
+	// is recorded, without its value
+	if rows > 0 && (!before.Valid || before.String != stored) {
+		action := models.AuditActionUpdate
+		if !before.Valid {
+			action = models.AuditActionCreate
+		}
+		change := models.AuditChange{
+			Before: configAuditValue(key, before),
+			After:  configAuditValue(key, sql.NullString{String: stored, Valid: true}),
+		}
+		if err := insertAuditEntry(ctx, tx, action, models.AuditEntityConfig, key, map[string]models.AuditChange{"value": change}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
 	return nil
 }
+
+// configAuditValue returns the audit form of a stored config value: null
+// for missing entries and models.AuditRedacted for non-empty secrets
+func configAuditValue(key string, stored sql.NullString) json.RawMessage {
+	if !stored.Valid {
+		return nil
+	}
+	value := stored.String
+	if value != "" && models.IsSecretConfigKey(key) {
+		value = models.AuditRedacted
+	}
+	data, _ := json.Marshal(value)
+	return data
+}
+
+// RotateSecrets re-encrypts all stored secrets with next in a single
+// transaction and returns the number of values rewritten
+func (r *ConfigRepository) RotateSecrets(next *secrets.Cipher) (int, error) {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rotated := 0
+	for _, key := range models.SecretConfigKeys {
+		c := &models.Config{Key: key}
+		err := tx.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, key).Scan(&c.Value)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && c.Value == "") {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("get config %s: %w", key, err)
+		}
+
+		if err := r.decrypt(c); err != nil {
+			return 0, err
+		}
+		encrypted, err := next.Encrypt(key, c.Value)
+		if err != nil {
+			return 0, fmt.Errorf("encrypt %s: %w", key, err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE config SET value = ? WHERE key = ?`, encrypted, key); err != nil {
+			return 0, fmt.Errorf("update config %s: %w", key, err)
+		}
+		rotated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return rotated, nil
+}
@@ -1,11 +1,23 @@
 package repositories_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/secrets"
 )
 
+func newTestCipher(t *testing.T, masterKey string) *secrets.Cipher {
+	t.Helper()
+	c, err := secrets.New([]byte(masterKey))
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+	return c
+}
+
 func TestConfigRepository_Get(t *testing.T) {
 	database := setupTestDB(t)
 	defer database.Close()
@@ -107,3 +119,152 @@ func TestConfigRepository_Set_Insert(t *testing.T) {
 		t.Errorf("expected value %q, got %q", "custom_value", cfg.Value)
 	}
 }
+
+func TestConfigRepository_WithContext(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	repo := repositories.NewConfigRepository(database.DB)
+	if _, err := repo.WithContext(ctx).Get("llm_provider_url"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// The original repository keeps running with the background context
+	if _, err := repo.Get("llm_provider_url"); err != nil {
+		t.Errorf("get failed: %v", err)
+	}
+}
+
+func TestConfigRepository_Seed(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	repo := repositories.NewConfigRepository(database.DB).WithCipher(newTestCipher(t, "0123456789abcdef0123456789abcdef"))
+	if err := repo.Set("llm_model", "user-choice"); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	// Migration seeds llm_provider_url with empty string
+	seed := map[string]string{
+		"llm_provider_url": "https://api.openai.com/v1",
+		"llm_model":        "gpt-4o",
+		"llm_api_key":      "sk-seeded",
+	}
+	if err := repo.Seed(seed, false); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	want := map[string]string{
+		"llm_provider_url": "https://api.openai.com/v1",
+		"llm_model":        "user-choice",
+		"llm_api_key":      "sk-seeded",
+	}
+	for key, value := range want {
+		cfg, err := repo.Get(key)
+		if err != nil || cfg == nil {
+			t.Fatalf("get %s failed: %v", key, err)
+		}
+		if cfg.Value != value {
+			t.Errorf("%s = %q, want %q", key, cfg.Value, value)
+		}
+	}
+
+	if err := repo.Seed(map[string]string{"llm_model": "gpt-4o"}, true); err != nil {
+		t.Fatalf("seed with overwrite failed: %v", err)
+	}
+	cfg, err := repo.Get("llm_model")
+	if err != nil || cfg == nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if cfg.Value != "gpt-4o" {
+		t.Errorf("llm_model = %q, want overwritten value", cfg.Value)
+	}
+}
+
+func TestConfigRepository_SecretsEncrypted(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	plain := repositories.NewConfigRepository(database.DB)
+	if err := plain.Set("llm_api_key", "sk-secret"); !errors.Is(err, secrets.ErrNoMasterKey) {
+		t.Fatalf("expected ErrNoMasterKey without a cipher, got %v", err)
+	}
+
+	repo := plain.WithCipher(newTestCipher(t, "0123456789abcdef0123456789abcdef"))
+	if err := repo.Set("llm_api_key", "sk-secret"); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	var stored string
+	err := database.QueryRowContext(context.Background(), `SELECT value FROM config WHERE key = 'llm_api_key'`).Scan(&stored)
+	if err != nil {
+		t.Fatalf("failed to read stored value: %v", err)
+	}
+	if !secrets.IsEncrypted(stored) {
+		t.Errorf("expected encrypted value, got %q", stored)
+	}
+
+	cfg, err := repo.Get("llm_api_key")
+	if err != nil || cfg == nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if cfg.Value != "sk-secret" {
+		t.Errorf("Get() = %q, want decrypted value", cfg.Value)
+	}
+
+	if _, err := plain.GetAll(); !errors.Is(err, secrets.ErrNoMasterKey) {
+		t.Errorf("expected ErrNoMasterKey reading without a cipher, got %v", err)
+	}
+
+	// Unchanged secrets are not rewritten when seeding with overwrite
+	if err := repo.Seed(map[string]string{"llm_api_key": "sk-secret"}, true); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+	var reseeded string
+	err = database.QueryRowContext(context.Background(), `SELECT value FROM config WHERE key = 'llm_api_key'`).Scan(&reseeded)
+	if err != nil {
+		t.Fatalf("failed to read stored value: %v", err)
+	}
+	if reseeded != stored {
+		t.Error("expected unchanged secret to keep its ciphertext")
+	}
+}
+
+func TestConfigRepository_RotateSecrets(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	oldCipher := newTestCipher(t, "0123456789abcdef0123456789abcdef")
+	newCipher := newTestCipher(t, "fedcba9876543210fedcba9876543210")
+	repo := repositories.NewConfigRepository(database.DB).WithCipher(oldCipher)
+	if err := repo.Set("llm_api_key", "sk-secret"); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	rotated, err := repo.RotateSecrets(newCipher)
+	if err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	if rotated != 1 {
+		t.Errorf("rotated %d values, want 1", rotated)
+	}
+
+	cfg, err := repo.WithCipher(newCipher).Get("llm_api_key")
+	if err != nil || cfg == nil {
+		t.Fatalf("get with new key failed: %v", err)
+	}
+	if cfg.Value != "sk-secret" {
+		t.Errorf("Get() = %q after rotation", cfg.Value)
+	}
+	if _, err := repo.Get("llm_api_key"); !errors.Is(err, secrets.ErrDecrypt) {
+		t.Errorf("expected ErrDecrypt with the old key, got %v", err)
+	}
+
+	// Rotating with the wrong current key changes nothing
+	if _, err := repo.RotateSecrets(oldCipher); !errors.Is(err, secrets.ErrDecrypt) {
+		t.Errorf("expected ErrDecrypt, got %v", err)
+	}
+}
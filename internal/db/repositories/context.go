@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// actorKey is the context key of the login name recorded in the audit log
+type actorKey struct{}
+
+// queryContext returns the context set with WithContext, or
+// context.Background() for repositories created without one
+func queryContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+// ContextWithActor returns a context whose changes are recorded in the audit
+// log as made by actor
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFromContext returns the actor stored in ctx, or models.AuditSystemActor
+func actorFromContext(ctx context.Context) string {
+	if actor, _ := ctx.Value(actorKey{}).(string); actor != "" {
+		return actor
+	}
+	return models.AuditSystemActor
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// MeetingImport is a meeting read from a calendar event
+type MeetingImport struct {
+	Meeting *models.Meeting
+	// Description becomes the first note of a new meeting unless empty
+	Description string
+	// Created is set by Import when the meeting is new rather than updated
+	Created bool
+}
+
+// Import stores the meetings of a calendar in one transaction, so a failing
+// event leaves none of them stored. A meeting imported before from the same
+// event (same UID and RECURRENCE-ID) takes the scheduling fields of the event
+// and keeps its summary and keywords; other meetings are created, with their
+// description as first note created through notes. Each meeting of imports
+// is replaced by the stored one.
+func (r *MeetingRepository) Import(imports []*MeetingImport, notes *NoteRepository) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, imp := range imports {
+		if err := r.importMeeting(ctx, tx, imp, notes); err != nil {
+			return fmt.Errorf("import event %s: %w", stringValue(imp.Meeting.ICalUID), err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// importMeeting stores one meeting of Import within tx
+func (r *MeetingRepository) importMeeting(ctx context.Context, tx *sql.Tx, imp *MeetingImport, notes *NoteRepository) error {
+	m := imp.Meeting
+	existing, err := getByICalUID(ctx, tx, stringValue(m.ICalUID), stringValue(m.ICalRecurrenceID))
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		m.ID = existing.ID
+		m.Summary = existing.Summary
+		m.Keywords = existing.Keywords
+		if err := r.update(ctx, tx, m); err != nil {
+			return err
+		}
+	} else {
+		if err := r.create(ctx, tx, m); err != nil {
+			return err
+		}
+		if imp.Description != "" {
+			if err := notes.create(ctx, tx, &models.Note{MeetingID: m.ID, Content: imp.Description}); err != nil {
+				return err
+			}
+		}
+		imp.Created = true
+	}
+
+	stored, err := getLiveMeeting(ctx, tx, m.ID)
+	if err != nil {
+		return err
+	}
+	imp.Meeting = stored
+	return nil
+}
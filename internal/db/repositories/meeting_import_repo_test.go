@@ -0,0 +1,66 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func importTestMeeting(uid, subject string) *models.Meeting {
+	return &models.Meeting{CreatedBy: "test@example.com", Subject: subject, MeetingDate: "2026-02-14", StartTime: "10:00", ICalUID: &uid}
+}
+
+func TestMeetingRepository_Import(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+	meetingRepo := repositories.NewMeetingRepository(database.DB)
+	noteRepo := repositories.NewNoteRepository(database.DB)
+
+	imports := []*repositories.MeetingImport{{Meeting: importTestMeeting("a", "Planning"), Description: "Agenda"}}
+	if err := meetingRepo.Import(imports, noteRepo); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if !imports[0].Created || imports[0].Meeting.ID == 0 {
+		t.Fatalf("expected a created meeting, got %+v", imports[0])
+	}
+	notes, err := noteRepo.ListByMeeting(imports[0].Meeting.ID)
+	if err != nil || len(notes) != 1 || notes[0].Content != "Agenda" {
+		t.Fatalf("expected the description as first note, got %v (err %v)", notes, err)
+	}
+
+	again := []*repositories.MeetingImport{{Meeting: importTestMeeting("a", "Planning (moved)"), Description: "Agenda"}}
+	if err := meetingRepo.Import(again, noteRepo); err != nil {
+		t.Fatalf("re-import failed: %v", err)
+	}
+	if again[0].Created || again[0].Meeting.ID != imports[0].Meeting.ID || again[0].Meeting.Subject != "Planning (moved)" {
+		t.Errorf("expected the meeting to be updated, got %+v", again[0].Meeting)
+	}
+}
+
+func TestMeetingRepository_Import_AllOrNothing(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+	meetingRepo := repositories.NewMeetingRepository(database.DB)
+	noteRepo := repositories.NewNoteRepository(database.DB)
+
+	// The second event clashes with the resource name of a CalDAV meeting
+	name := "standup.ics"
+	caldav := &models.Meeting{CreatedBy: "test@example.com", Subject: "Standup", MeetingDate: "2026-02-14", StartTime: "09:00", CalDAVName: &name}
+	if err := meetingRepo.Create(caldav); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+	clashing := importTestMeeting("b", "Review")
+	clashing.CalDAVName = &name
+
+	imports := []*repositories.MeetingImport{
+		{Meeting: importTestMeeting("a", "Planning"), Description: "Agenda"},
+		{Meeting: clashing},
+	}
+	if err := meetingRepo.Import(imports, noteRepo); err == nil {
+		t.Fatal("expected the import to fail")
+	}
+	if m, err := meetingRepo.GetByICalUID("a", ""); err != nil || m != nil {
+		t.Errorf("expected no meeting from the failed import, got %+v (err %v)", m, err)
+	}
+}
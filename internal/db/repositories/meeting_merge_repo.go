@@ -0,0 +1,287 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+var (
+	// ErrMergeSelf is returned when merging a meeting into itself
+	ErrMergeSelf = errors.New("cannot merge a meeting into itself")
+	// ErrEmptySplit is returned when the range to split off holds no notes
+	ErrEmptySplit = errors.New("no notes in the range to split off")
+)
+
+// getLiveMeeting retrieves a meeting outside the trash by ID within tx
+func getLiveMeeting(ctx context.Context, tx *sql.Tx, id int) (*models.Meeting, error) {
+	m, err := scanMeeting(tx.QueryRowContext(ctx, `SELECT `+meetingColumns+` FROM meetings WHERE id = ? AND deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("meeting not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get meeting: %w", err)
+	}
+	return m, nil
+}
+
+// listItemKey reduces an entry of a comma-separated participants or keywords
+// column such as "Alice <alice@example.com>" to its lower-cased address, and
+// other entries to their lower-cased text
+func listItemKey(item string) string {
+	if i, j := strings.Index(item, "<"), strings.LastIndex(item, ">"); i >= 0 && j > i {
+		item = item[i+1 : j]
+	}
+	return strings.ToLower(item)
+}
+
+// unionList joins two comma-separated columns, dropping entries of b that
+// are already in a
+func unionList(a, b *string) *string {
+	seen := map[string]bool{}
+	var items []string
+	for _, list := range []*string{a, b} {
+		for _, item := range strings.Split(stringValue(list), ",") {
+			item = strings.TrimSpace(item)
+			if item == "" || seen[listItemKey(item)] {
+				continue
+			}
+			seen[listItemKey(item)] = true
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	joined := strings.Join(items, ", ")
+	return &joined
+}
+
+// mergeNotes moves all notes of meeting sourceID to meetingID within tx and
+// numbers them together: the notes outside the trash in the order they were
+// created, then the trashed ones. The changes are recorded in the audit log.
+func mergeNotes(ctx context.Context, tx *sql.Tx, meetingID, sourceID int) error {
+	_, before, err := meetingNotes(ctx, tx, []int{meetingID, sourceID})
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE notes SET meeting_id = ?, note_number = -id WHERE meeting_id = ?", meetingID, sourceID); err != nil {
+		return fmt.Errorf("move notes: %w", err)
+	}
+
+	notes, err := listMeetingNotes(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := notes[i], notes[j]
+		if (a.DeletedAt == nil) != (b.DeletedAt == nil) {
+			return a.DeletedAt == nil
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	numbers := map[int]int{}
+	for i, n := range notes {
+		if n.NoteNumber != i+1 {
+			numbers[n.ID] = i + 1
+		}
+	}
+	if err := renumberNotes(ctx, tx, numbers); err != nil {
+		return err
+	}
+
+	after, err := listMeetingNotes(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	return auditRenumbered(ctx, tx, before, after)
+}
+
+// mergeSummaries appends the summary versions of meeting source to the
+// history of meeting m within tx and returns the summary of the merged
+// meeting: the one of m, recorded again as the latest version, or the one of
+// source if m has none
+func (r *MeetingRepository) mergeSummaries(ctx context.Context, tx *sql.Tx, m, source *models.Meeting) (*string, error) {
+	var latest int
+	err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM meeting_summaries WHERE meeting_id = ?`, m.ID).Scan(&latest)
+	if err != nil {
+		return nil, fmt.Errorf("get latest summary version: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO meeting_summaries (meeting_id, version, summary, author, source, model, prompt_hash,
+			input_tokens, output_tokens, promoted_from, created_at)
+		SELECT ?, version + ?, summary, author, source, model, prompt_hash,
+			input_tokens, output_tokens, promoted_from + ?, created_at
+		FROM meeting_summaries WHERE meeting_id = ?
+	`, m.ID, latest, latest, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("copy summary versions: %w", err)
+	}
+	appended, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	if stringValue(m.Summary) == "" {
+		return source.Summary, nil
+	}
+	// The appended versions end with the summary of source
+	if appended > 0 {
+		promoted := r.WithSummaryProvenance(models.SummaryProvenance{PromotedFrom: &latest})
+		if err := promoted.recordSummary(ctx, tx, m.ID, source.Summary, m.Summary); err != nil {
+			return nil, err
+		}
+	}
+	return m.Summary, nil
+}
+
+// Merge combines meeting sourceID into meetingID and deletes sourceID for
+// good: its notes, trashed ones included, and its summary versions live on in
+// meetingID, so restoring it from the trash would only give back an empty
+// meeting. The notes of both are numbered in the order they were created, the
+// participants and keywords are joined without duplicates, and the summary
+// versions of sourceID are appended to the history of meetingID. meetingID
+// keeps its summary, which is recorded again as the latest version, and takes
+// the one of sourceID only if it has none. All changes are recorded in the
+// audit log.
+func (r *MeetingRepository) Merge(meetingID, sourceID int) error {
+	if meetingID == sourceID {
+		return ErrMergeSelf
+	}
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	before, err := getLiveMeeting(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(r.version, before.Version); err != nil {
+		return err
+	}
+	source, err := getLiveMeeting(ctx, tx, sourceID)
+	if err != nil {
+		return err
+	}
+
+	if err := mergeNotes(ctx, tx, meetingID, sourceID); err != nil {
+		return err
+	}
+	summary, err := r.mergeSummaries(ctx, tx, before, source)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE meetings SET participants = ?, keywords = ?, summary = ? WHERE id = ?",
+		unionList(before.Participants, source.Participants), unionList(before.Keywords, source.Keywords), summary, meetingID)
+	if err != nil {
+		return fmt.Errorf("update meeting: %w", err)
+	}
+	if err := r.audit(ctx, tx, models.AuditActionUpdate, before, meetingID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM meetings WHERE id = ?", sourceID); err != nil {
+		return fmt.Errorf("purge meeting: %w", err)
+	}
+	if err := r.audit(ctx, tx, models.AuditActionPurge, source, sourceID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// Split moves the notes of a meeting numbered first to last into a new
+// meeting, which takes the date, times, participants and keywords of the
+// meeting and the given subject, and returns it. Both meetings are numbered
+// without gaps, and all changes are recorded in the audit log.
+func (r *MeetingRepository) Split(meetingID, first, last int, subject string) (*models.Meeting, error) {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	original, err := getLiveMeeting(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(r.version, original.Version); err != nil {
+		return nil, err
+	}
+	ids, err := noteIDsInRange(ctx, tx, meetingID, first, last)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, ErrEmptySplit
+	}
+
+	m := &models.Meeting{
+		CreatedBy:    actorFromContext(ctx),
+		Subject:      subject,
+		MeetingDate:  original.MeetingDate,
+		StartTime:    original.StartTime,
+		EndTime:      original.EndTime,
+		Participants: original.Participants,
+		Keywords:     original.Keywords,
+		Timezone:     original.Timezone,
+		StartUTC:     original.StartUTC,
+		EndUTC:       original.EndUTC,
+	}
+	if err := r.create(ctx, tx, m); err != nil {
+		return nil, err
+	}
+	if _, err := moveNotes(ctx, tx, ids, m.ID); err != nil {
+		return nil, err
+	}
+
+	created, err := getLiveMeeting(ctx, tx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return created, nil
+}
+
+// noteIDsInRange lists the notes of a meeting outside the trash numbered
+// first to last within tx, in order
+func noteIDsInRange(ctx context.Context, tx *sql.Tx, meetingID, first, last int) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM notes
+		WHERE meeting_id = ? AND deleted_at IS NULL AND note_number BETWEEN ? AND ?
+		ORDER BY note_number ASC
+	`, meetingID, first, last)
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,151 @@
+package repositories_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestMeetingRepository_Merge(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	target, targetNotes := createOrderTestMeeting(t, database.DB, "Standup", "t1", "t2")
+	source, sourceNotes := createOrderTestMeeting(t, database.DB, "Standup (copy)", "s1", "trashed")
+	meetingRepo := repositories.NewMeetingRepository(database.DB)
+	noteRepo := repositories.NewNoteRepository(database.DB)
+
+	// s1 was written between t1 and t2
+	created := map[int]string{
+		targetNotes[0].ID: "2026-02-14 10:00:00", sourceNotes[0].ID: "2026-02-14 10:05:00",
+		targetNotes[1].ID: "2026-02-14 10:10:00", sourceNotes[1].ID: "2026-02-14 10:01:00",
+	}
+	for id, at := range created {
+		if _, err := database.DB.Exec("UPDATE notes SET created_at = ? WHERE id = ?", at, id); err != nil {
+			t.Fatalf("failed to set created_at: %v", err)
+		}
+	}
+	if err := noteRepo.Delete(sourceNotes[1].ID); err != nil {
+		t.Fatalf("failed to delete note: %v", err)
+	}
+
+	target.Participants = strPtr("Alice <alice@example.com>, Bob")
+	target.Keywords = strPtr("planning")
+	target.Summary = strPtr("target summary")
+	source.Participants = strPtr("alice <ALICE@example.com>, Carol")
+	source.Keywords = strPtr("Planning, budget")
+	source.Summary = strPtr("source summary")
+	for _, m := range []*models.Meeting{target, source} {
+		if err := meetingRepo.Update(m); err != nil {
+			t.Fatalf("failed to update meeting: %v", err)
+		}
+	}
+
+	if err := meetingRepo.Merge(target.ID, source.ID); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	contents, numbers := noteOrder(t, database.DB, target.ID)
+	if !slices.Equal(contents, []string{"t1", "s1", "t2"}) || !slices.Equal(numbers, []int{1, 2, 3}) {
+		t.Errorf("notes = %v %v, want [t1 s1 t2] [1 2 3]", contents, numbers)
+	}
+	if err := noteRepo.Restore(sourceNotes[1].ID); err != nil {
+		t.Fatalf("failed to restore the trashed note of the merged meeting: %v", err)
+	}
+	if contents, _ := noteOrder(t, database.DB, target.ID); !slices.Equal(contents, []string{"t1", "s1", "t2", "trashed"}) {
+		t.Errorf("notes after restore = %v", contents)
+	}
+
+	merged, err := meetingRepo.GetByID(target.ID)
+	if err != nil {
+		t.Fatalf("failed to get meeting: %v", err)
+	}
+	if *merged.Participants != "Alice <alice@example.com>, Bob, Carol" || *merged.Keywords != "planning, budget" {
+		t.Errorf("participants = %q, keywords = %q", *merged.Participants, *merged.Keywords)
+	}
+	if *merged.Summary != "target summary" {
+		t.Errorf("summary = %q, want the target's", *merged.Summary)
+	}
+
+	summaries, err := meetingRepo.ListSummaries(target.ID)
+	if err != nil {
+		t.Fatalf("failed to list summaries: %v", err)
+	}
+	var history []string
+	for _, s := range summaries {
+		history = append(history, s.Summary)
+	}
+	if !slices.Equal(history, []string{"target summary", "source summary", "target summary"}) {
+		t.Errorf("summary history = %v", history)
+	}
+	if p := summaries[0].PromotedFrom; p == nil || *p != 1 {
+		t.Errorf("latest version promoted from %v, want 1", p)
+	}
+
+	if m, _ := meetingRepo.GetByID(source.ID); m != nil {
+		t.Error("merged meeting must be gone")
+	}
+	items, err := repositories.NewTrashRepository(database.DB).List()
+	if err != nil {
+		t.Fatalf("failed to list trash: %v", err)
+	}
+	for _, item := range items {
+		if item.Type == models.TrashTypeMeeting && item.ID == source.ID {
+			t.Error("merged meeting must not be restorable from the trash")
+		}
+	}
+}
+
+func TestMeetingRepository_Merge_Invalid(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meeting, _ := createOrderTestMeeting(t, database.DB, "Standup", "a")
+	repo := repositories.NewMeetingRepository(database.DB)
+
+	if err := repo.Merge(meeting.ID, meeting.ID); !errors.Is(err, repositories.ErrMergeSelf) {
+		t.Errorf("expected ErrMergeSelf, got %v", err)
+	}
+	if err := repo.Merge(meeting.ID, 999); err == nil {
+		t.Error("expected an error merging an unknown meeting")
+	}
+	if contents, _ := noteOrder(t, database.DB, meeting.ID); !slices.Equal(contents, []string{"a"}) {
+		t.Errorf("notes changed to %v", contents)
+	}
+}
+
+func TestMeetingRepository_Split(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meeting, _ := createOrderTestMeeting(t, database.DB, "Standup", "a", "b", "c", "d")
+	meeting.Participants = strPtr("Alice")
+	repo := repositories.NewMeetingRepository(database.DB)
+	if err := repo.Update(meeting); err != nil {
+		t.Fatalf("failed to update meeting: %v", err)
+	}
+
+	split, err := repo.Split(meeting.ID, 2, 3, "Retro")
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if split.Subject != "Retro" || split.MeetingDate != meeting.MeetingDate || split.Participants == nil || *split.Participants != "Alice" {
+		t.Errorf("split meeting = %+v", split)
+	}
+
+	if contents, numbers := noteOrder(t, database.DB, split.ID); !slices.Equal(contents, []string{"b", "c"}) || !slices.Equal(numbers, []int{1, 2}) {
+		t.Errorf("split notes = %v %v, want [b c] [1 2]", contents, numbers)
+	}
+	if contents, numbers := noteOrder(t, database.DB, meeting.ID); !slices.Equal(contents, []string{"a", "d"}) || !slices.Equal(numbers, []int{1, 2}) {
+		t.Errorf("remaining notes = %v %v, want [a d] [1 2]", contents, numbers)
+	}
+
+	if _, err := repo.Split(meeting.ID, 5, 9, "Empty"); !errors.Is(err, repositories.ErrEmptySplit) {
+		t.Errorf("expected ErrEmptySplit, got %v", err)
+	}
+}
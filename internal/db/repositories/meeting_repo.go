@@ -4,15 +4,83 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zorak1103/notebook/internal/db/models"
 )
 
+// ErrMeetingNotFound is returned when a meeting to add notes to does not
+// exist or is in the trash
+var ErrMeetingNotFound = errors.New("meeting not found")
+
+// meetingColumns is the column list shared by all meeting SELECTs, in scanMeeting order
+const meetingColumns = `id, created_by, subject, meeting_date, start_time, end_time, participants, summary, keywords,
+	ical_uid, ical_recurrence_id, caldav_name, timezone, start_utc, end_utc, created_at, updated_at, deleted_at, version`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryRower runs a single-row query; both *sql.DB and *sql.Tx satisfy it
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// scanMeeting scans a row selected with meetingColumns
+func scanMeeting(row rowScanner) (*models.Meeting, error) {
+	m := &models.Meeting{}
+	var startUTC, endUTC, deletedAt sql.NullString
+	err := row.Scan(&m.ID, &m.CreatedBy, &m.Subject, &m.MeetingDate, &m.StartTime, &m.EndTime, &m.Participants, &m.Summary, &m.Keywords,
+		&m.ICalUID, &m.ICalRecurrenceID, &m.CalDAVName, &m.Timezone, &startUTC, &endUTC, &m.CreatedAt, &m.UpdatedAt, &deletedAt, &m.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.StartUTC, err = parseUTCColumn(startUTC); err != nil {
+		return nil, fmt.Errorf("meeting %d start_utc: %w", m.ID, err)
+	}
+	if m.EndUTC, err = parseUTCColumn(endUTC); err != nil {
+		return nil, fmt.Errorf("meeting %d end_utc: %w", m.ID, err)
+	}
+	if m.DeletedAt, err = parseUTCColumn(deletedAt); err != nil {
+		return nil, fmt.Errorf("meeting %d deleted_at: %w", m.ID, err)
+	}
+	return m, nil
+}
+
 // MeetingRepository handles meeting CRUD operations
 type MeetingRepository struct {
-	db *sql.DB
+	db         *sql.DB
+	ctx        context.Context
+	provenance models.SummaryProvenance
+	version    int
+}
+
+// utcColumn formats an instant for the start_utc and end_utc columns
+func utcColumn(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseUTCColumn reads a start_utc or end_utc value
+func parseUTCColumn(v sql.NullString) (*time.Time, error) {
+	if !v.Valid || v.String == "" {
+		//nolint:nilnil // Intentional: NULL means the instant was never derived
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
 }
 
 // NewMeetingRepository creates a new meeting repository
@@ -20,13 +88,40 @@ func NewMeetingRepository(db *sql.DB) *MeetingRepository {
 	return &MeetingRepository{db: db}
 }
 
-// Create creates a new meeting
+// WithContext returns a copy of the repository that runs its queries with ctx
+func (r *MeetingRepository) WithContext(ctx context.Context) *MeetingRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// Create creates a new meeting and records it in the audit log
 func (r *MeetingRepository) Create(m *models.Meeting) error {
-	ctx := context.Background()
-	result, err := r.db.ExecContext(ctx, `
-		INSERT INTO meetings (created_by, subject, meeting_date, start_time, end_time, participants, summary, keywords)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, m.CreatedBy, m.Subject, m.MeetingDate, m.StartTime, m.EndTime, m.Participants, m.Summary, m.Keywords)
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.create(ctx, tx, m); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// create inserts a meeting within tx, records it in the audit log and stores
+// its summary as version 1
+func (r *MeetingRepository) create(ctx context.Context, tx *sql.Tx, m *models.Meeting) error {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO meetings (created_by, subject, meeting_date, start_time, end_time, participants, summary, keywords, ical_uid, ical_recurrence_id, caldav_name,
+			timezone, start_utc, end_utc)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, m.CreatedBy, m.Subject, m.MeetingDate, m.StartTime, m.EndTime, m.Participants, m.Summary, m.Keywords, m.ICalUID, m.ICalRecurrenceID, m.CalDAVName,
+		m.Timezone, utcColumn(m.StartUTC), utcColumn(m.EndUTC))
 
 	if err != nil {
 		return fmt.Errorf("create meeting: %w", err)
@@ -38,17 +133,33 @@ func (r *MeetingRepository) Create(m *models.Meeting) error {
 	}
 
 	m.ID = int(id)
-	return nil
+	if err := r.audit(ctx, tx, models.AuditActionCreate, nil, m.ID); err != nil {
+		return err
+	}
+	return r.recordSummary(ctx, tx, m.ID, nil, m.Summary)
 }
 
-// GetByID retrieves a meeting by ID
+// audit records the change of meeting id from before to its current state in tx
+func (r *MeetingRepository) audit(ctx context.Context, tx *sql.Tx, action string, before *models.Meeting, id int) error {
+	var after *models.Meeting
+	if action != models.AuditActionPurge {
+		var err error
+		if after, err = scanMeeting(tx.QueryRowContext(ctx, `SELECT `+meetingColumns+` FROM meetings WHERE id = ?`, id)); err != nil {
+			return fmt.Errorf("get meeting: %w", err)
+		}
+	}
+
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+	return insertAuditEntry(ctx, tx, action, models.AuditEntityMeeting, strconv.Itoa(id), changes)
+}
+
+// GetByID retrieves a meeting by ID; meetings in the trash are not found
 func (r *MeetingRepository) GetByID(id int) (*models.Meeting, error) {
-	ctx := context.Background()
-	m := &models.Meeting{}
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, created_by, subject, meeting_date, start_time, end_time, participants, summary, keywords, created_at, updated_at
-		FROM meetings WHERE id = ?
-	`, id).Scan(&m.ID, &m.CreatedBy, &m.Subject, &m.MeetingDate, &m.StartTime, &m.EndTime, &m.Participants, &m.Summary, &m.Keywords, &m.CreatedAt, &m.UpdatedAt)
+	ctx := queryContext(r.ctx)
+	m, err := scanMeeting(r.db.QueryRowContext(ctx, `SELECT `+meetingColumns+` FROM meetings WHERE id = ? AND deleted_at IS NULL`, id))
 
 	if err == sql.ErrNoRows {
 		//nolint:nilnil // Intentional: not found is not an error
@@ -61,7 +172,7 @@ func (r *MeetingRepository) GetByID(id int) (*models.Meeting, error) {
 	return m, nil
 }
 
-// List lists all meetings with optional sorting
+// List lists all meetings outside the trash with optional sorting
 func (r *MeetingRepository) List(orderBy string, ascending bool) ([]*models.Meeting, error) {
 	// Whitelist for ORDER BY (SQL injection protection)
 	validColumns := map[string]bool{
@@ -83,77 +194,175 @@ func (r *MeetingRepository) List(orderBy string, ascending bool) ([]*models.Meet
 
 	//nolint:gosec // SQL injection protected by whitelist validation above
 	query := fmt.Sprintf(`
-		SELECT id, created_by, subject, meeting_date, start_time, end_time, participants, summary, keywords, created_at, updated_at
+		SELECT %s
 		FROM meetings
+		WHERE deleted_at IS NULL
 		ORDER BY %s COLLATE NOCASE %s
-	`, orderBy, direction)
+	`, meetingColumns, orderBy, direction)
 
-	ctx := context.Background()
+	ctx := queryContext(r.ctx)
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("list meetings: %w", err)
 	}
 	defer rows.Close()
 
-	var meetings []*models.Meeting
-	for rows.Next() {
-		m := &models.Meeting{}
-		err := rows.Scan(&m.ID, &m.CreatedBy, &m.Subject, &m.MeetingDate, &m.StartTime, &m.EndTime, &m.Participants, &m.Summary, &m.Keywords, &m.CreatedAt, &m.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("scan meeting: %w", err)
-		}
-		meetings = append(meetings, m)
-	}
+	return scanMeetings(rows)
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate rows: %w", err)
+// Update updates an existing meeting and records the change in the audit log
+func (r *MeetingRepository) Update(m *models.Meeting) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	return meetings, nil
+	if err := r.update(ctx, tx, m); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
 }
 
-// Update updates an existing meeting
-func (r *MeetingRepository) Update(m *models.Meeting) error {
-	ctx := context.Background()
-	result, err := r.db.ExecContext(ctx, `
+// update is Update within tx
+func (r *MeetingRepository) update(ctx context.Context, tx *sql.Tx, m *models.Meeting) error {
+	before, err := scanMeeting(tx.QueryRowContext(ctx, `SELECT `+meetingColumns+` FROM meetings WHERE id = ? AND deleted_at IS NULL`, m.ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("meeting not found")
+	}
+	if err != nil {
+		return fmt.Errorf("get meeting: %w", err)
+	}
+	if err := checkVersion(r.version, before.Version); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE meetings
-		SET subject = ?, meeting_date = ?, start_time = ?, end_time = ?, participants = ?, summary = ?, keywords = ?
+		SET subject = ?, meeting_date = ?, start_time = ?, end_time = ?, participants = ?, summary = ?, keywords = ?,
+			timezone = ?, start_utc = ?, end_utc = ?
 		WHERE id = ?
-	`, m.Subject, m.MeetingDate, m.StartTime, m.EndTime, m.Participants, m.Summary, m.Keywords, m.ID)
+	`, m.Subject, m.MeetingDate, m.StartTime, m.EndTime, m.Participants, m.Summary, m.Keywords,
+		m.Timezone, utcColumn(m.StartUTC), utcColumn(m.EndUTC), m.ID)
 
 	if err != nil {
 		return fmt.Errorf("update meeting: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
+	if err := r.audit(ctx, tx, models.AuditActionUpdate, before, m.ID); err != nil {
+		return err
+	}
+	return r.recordSummary(ctx, tx, m.ID, before.Summary, m.Summary)
+}
+
+// meetingPatchColumns are the columns Patch compares and writes, with their
+// values in a meeting
+var meetingPatchColumns = []struct {
+	name  string
+	value func(m *models.Meeting) any
+}{
+	{"subject", func(m *models.Meeting) any { return m.Subject }},
+	{"meeting_date", func(m *models.Meeting) any { return m.MeetingDate }},
+	{"start_time", func(m *models.Meeting) any { return m.StartTime }},
+	{"end_time", func(m *models.Meeting) any { return m.EndTime }},
+	{"participants", func(m *models.Meeting) any { return m.Participants }},
+	{"summary", func(m *models.Meeting) any { return m.Summary }},
+	{"keywords", func(m *models.Meeting) any { return m.Keywords }},
+	{"timezone", func(m *models.Meeting) any { return m.Timezone }},
+	{"start_utc", func(m *models.Meeting) any { return utcColumn(m.StartUTC) }},
+	{"end_utc", func(m *models.Meeting) any { return utcColumn(m.EndUTC) }},
+}
+
+// Patch writes only the columns of m that differ from the stored meeting and
+// records the change in the audit log. Nothing is written when none differ.
+func (r *MeetingRepository) Patch(m *models.Meeting) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("get rows affected: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	if rows == 0 {
+	before, err := scanMeeting(tx.QueryRowContext(ctx, `SELECT `+meetingColumns+` FROM meetings WHERE id = ? AND deleted_at IS NULL`, m.ID))
+	if errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("meeting not found")
 	}
+	if err != nil {
+		return fmt.Errorf("get meeting: %w", err)
+	}
+	if err := checkVersion(r.version, before.Version); err != nil {
+		return err
+	}
+
+	var sets []string
+	var args []any
+	for _, column := range meetingPatchColumns {
+		if value := column.value(m); !reflect.DeepEqual(column.value(before), value) {
+			sets = append(sets, column.name+" = ?")
+			args = append(args, value)
+		}
+	}
+	if len(sets) == 0 {
+		return nil
+	}
 
+	//nolint:gosec // column names come from meetingPatchColumns
+	query := "UPDATE meetings SET " + strings.Join(sets, ", ") + " WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, query, append(args, m.ID)...); err != nil {
+		return fmt.Errorf("patch meeting: %w", err)
+	}
+
+	if err := r.audit(ctx, tx, models.AuditActionUpdate, before, m.ID); err != nil {
+		return err
+	}
+	if err := r.recordSummary(ctx, tx, m.ID, before.Summary, m.Summary); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
 	return nil
 }
 
-// Delete deletes a meeting (and all associated notes via CASCADE)
+// Delete moves a meeting and its notes to the trash and records it in the
+// audit log
 func (r *MeetingRepository) Delete(id int) error {
-	ctx := context.Background()
-	result, err := r.db.ExecContext(ctx, "DELETE FROM meetings WHERE id = ?", id)
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("delete meeting: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	rows, err := result.RowsAffected()
+	before, err := scanMeeting(tx.QueryRowContext(ctx, `SELECT `+meetingColumns+` FROM meetings WHERE id = ? AND deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("meeting not found")
+	}
 	if err != nil {
-		return fmt.Errorf("get rows affected: %w", err)
+		return fmt.Errorf("get meeting: %w", err)
+	}
+	if err := checkVersion(r.version, before.Version); err != nil {
+		return err
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("meeting not found")
+	deletedAt := trashTime()
+	if _, err := tx.ExecContext(ctx, "UPDATE meetings SET deleted_at = ? WHERE id = ?", deletedAt, id); err != nil {
+		return fmt.Errorf("delete meeting: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE notes SET deleted_at = ? WHERE meeting_id = ? AND deleted_at IS NULL", deletedAt, id); err != nil {
+		return fmt.Errorf("delete notes: %w", err)
 	}
 
+	if err := r.audit(ctx, tx, models.AuditActionDelete, before, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
 	return nil
 }
 
@@ -170,16 +379,17 @@ func escapeLikePattern(s string) string {
 
 // Search searches meetings by subject, summary, participants, and keywords
 func (r *MeetingRepository) Search(query string) ([]*models.Meeting, error) {
-	ctx := context.Background()
+	ctx := queryContext(r.ctx)
 	pattern := escapeLikePattern(query)
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, created_by, subject, meeting_date, start_time, end_time, participants, summary, keywords, created_at, updated_at
+		SELECT `+meetingColumns+`
 		FROM meetings
-		WHERE subject LIKE ? ESCAPE '\'
+		WHERE deleted_at IS NULL
+		  AND (subject LIKE ? ESCAPE '\'
 		   OR summary LIKE ? ESCAPE '\'
 		   OR participants LIKE ? ESCAPE '\'
-		   OR keywords LIKE ? ESCAPE '\'
+		   OR keywords LIKE ? ESCAPE '\')
 		ORDER BY meeting_date DESC, start_time DESC
 	`, pattern, pattern, pattern, pattern)
 
@@ -188,10 +398,14 @@ func (r *MeetingRepository) Search(query string) ([]*models.Meeting, error) {
 	}
 	defer rows.Close()
 
+	return scanMeetings(rows)
+}
+
+// scanMeetings collects all rows selected with meetingColumns
+func scanMeetings(rows *sql.Rows) ([]*models.Meeting, error) {
 	var meetings []*models.Meeting
 	for rows.Next() {
-		m := &models.Meeting{}
-		err := rows.Scan(&m.ID, &m.CreatedBy, &m.Subject, &m.MeetingDate, &m.StartTime, &m.EndTime, &m.Participants, &m.Summary, &m.Keywords, &m.CreatedAt, &m.UpdatedAt)
+		m, err := scanMeeting(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan meeting: %w", err)
 		}
@@ -204,3 +418,101 @@ func (r *MeetingRepository) Search(query string) ([]*models.Meeting, error) {
 
 	return meetings, nil
 }
+
+// GetByICalUID retrieves the meeting imported from the given calendar event.
+// recurrenceID is empty for regular events and the master of a recurring series.
+func (r *MeetingRepository) GetByICalUID(uid, recurrenceID string) (*models.Meeting, error) {
+	return getByICalUID(queryContext(r.ctx), r.db, uid, recurrenceID)
+}
+
+// getByICalUID is GetByICalUID on q, the database or a transaction
+func getByICalUID(ctx context.Context, q queryRower, uid, recurrenceID string) (*models.Meeting, error) {
+	m, err := scanMeeting(q.QueryRowContext(ctx, `
+		SELECT `+meetingColumns+`
+		FROM meetings
+		WHERE ical_uid = ? AND COALESCE(ical_recurrence_id, '') = ? AND deleted_at IS NULL
+	`, uid, recurrenceID))
+	if errors.Is(err, sql.ErrNoRows) {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get meeting by ical uid: %w", err)
+	}
+	return m, nil
+}
+
+// ListForUser lists meetings created by the given user or listing them as a
+// participant, oldest first
+func (r *MeetingRepository) ListForUser(login string) ([]*models.Meeting, error) {
+	ctx := queryContext(r.ctx)
+	// The LIKE narrows the candidates; listsParticipant matches whole entries
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+meetingColumns+`
+		FROM meetings
+		WHERE deleted_at IS NULL AND (created_by = ? OR participants LIKE ? ESCAPE '\')
+		ORDER BY meeting_date ASC, start_time ASC
+	`, login, escapeLikePattern(login))
+	if err != nil {
+		return nil, fmt.Errorf("list meetings for user: %w", err)
+	}
+	defer rows.Close()
+
+	candidates, err := scanMeetings(rows)
+	if err != nil {
+		return nil, err
+	}
+	var meetings []*models.Meeting
+	for _, m := range candidates {
+		if m.CreatedBy == login || listsParticipant(m.Participants, login) {
+			meetings = append(meetings, m)
+		}
+	}
+	return meetings, nil
+}
+
+// listsParticipant reports whether an entry of a comma-separated
+// participants column is login, or has login as its address
+func listsParticipant(participants *string, login string) bool {
+	key := strings.ToLower(strings.TrimSpace(login))
+	for _, item := range strings.Split(stringValue(participants), ",") {
+		item = strings.TrimSpace(item)
+		if strings.ToLower(item) == key || listItemKey(item) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// GetByCalDAVName retrieves the meeting a CalDAV client created under the given resource name
+func (r *MeetingRepository) GetByCalDAVName(name string) (*models.Meeting, error) {
+	ctx := queryContext(r.ctx)
+	m, err := scanMeeting(r.db.QueryRowContext(ctx, `SELECT `+meetingColumns+` FROM meetings WHERE caldav_name = ? AND deleted_at IS NULL`, name))
+
+	if err == sql.ErrNoRows {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get meeting by caldav name: %w", err)
+	}
+
+	return m, nil
+}
+
+// ChangeTag returns a value that changes whenever a meeting is created, updated
+// or deleted; CalDAV clients use it to skip re-syncing unchanged collections
+func (r *MeetingRepository) ChangeTag() (string, error) {
+	ctx := queryContext(r.ctx)
+	var (
+		count       int
+		lastUpdated sql.NullString
+		maxID       sql.NullInt64
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*), MAX(updated_at), MAX(id) FROM meetings WHERE deleted_at IS NULL`).Scan(&count, &lastUpdated, &maxID)
+	if err != nil {
+		return "", fmt.Errorf("get change tag: %w", err)
+	}
+
+	return fmt.Sprintf("%d-%d-%s", count, maxID.Int64, lastUpdated.String), nil
+}
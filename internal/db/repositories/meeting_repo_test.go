@@ -1,6 +1,8 @@
 package repositories_test
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/zorak1103/notebook/internal/db"
@@ -439,3 +441,176 @@ func TestMeetingRepository_Search_EscapesUnderscore(t *testing.T) {
 		t.Errorf("expected 'A_B Test', got '%s'", results[0].Subject)
 	}
 }
+
+func TestMeetingRepository_GetByICalUID(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	repo := repositories.NewMeetingRepository(database.DB)
+
+	uid := "series@example.com"
+	rid := "20260310T090000Z"
+	master := &models.Meeting{CreatedBy: "test@example.com", Subject: "Series", MeetingDate: "2026-03-03", StartTime: "09:00", ICalUID: &uid}
+	override := &models.Meeting{CreatedBy: "test@example.com", Subject: "Moved", MeetingDate: "2026-03-11", StartTime: "09:00", ICalUID: &uid, ICalRecurrenceID: &rid}
+	if err := repo.Create(master); err != nil {
+		t.Fatalf("create master failed: %v", err)
+	}
+	if err := repo.Create(override); err != nil {
+		t.Fatalf("create override failed: %v", err)
+	}
+
+	got, err := repo.GetByICalUID(uid, "")
+	if err != nil || got == nil || got.ID != master.ID {
+		t.Errorf("expected master meeting, got %+v (err %v)", got, err)
+	}
+
+	got, err = repo.GetByICalUID(uid, rid)
+	if err != nil || got == nil || got.ID != override.ID {
+		t.Errorf("expected override meeting, got %+v (err %v)", got, err)
+	}
+
+	got, err = repo.GetByICalUID("unknown@example.com", "")
+	if err != nil || got != nil {
+		t.Errorf("expected nil for unknown UID, got %+v (err %v)", got, err)
+	}
+
+	// The same UID and recurrence ID cannot be imported twice
+	dup := &models.Meeting{CreatedBy: "test@example.com", Subject: "Dup", MeetingDate: "2026-03-03", StartTime: "09:00", ICalUID: &uid}
+	if err := repo.Create(dup); err == nil {
+		t.Error("expected unique constraint violation for duplicate UID")
+	}
+}
+
+func TestMeetingRepository_GetByCalDAVName(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	repo := repositories.NewMeetingRepository(database.DB)
+
+	uid := "3D1B1E52@example.com"
+	name := "3D1B1E52-7C4B-4F0A-8A6F-0E9C2D5B7A41.ics"
+	m := &models.Meeting{CreatedBy: "test@example.com", Subject: "Synced", MeetingDate: "2026-03-03", StartTime: "09:00", ICalUID: &uid, CalDAVName: &name}
+	if err := repo.Create(m); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	got, err := repo.GetByCalDAVName(name)
+	if err != nil || got == nil || got.ID != m.ID {
+		t.Errorf("expected meeting by resource name, got %+v (err %v)", got, err)
+	}
+
+	got, err = repo.GetByCalDAVName("unknown.ics")
+	if err != nil || got != nil {
+		t.Errorf("expected nil for unknown name, got %+v (err %v)", got, err)
+	}
+}
+
+func TestMeetingRepository_ChangeTag(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	repo := repositories.NewMeetingRepository(database.DB)
+
+	empty, err := repo.ChangeTag()
+	if err != nil {
+		t.Fatalf("change tag of empty table failed: %v", err)
+	}
+
+	m := &models.Meeting{CreatedBy: "test@example.com", Subject: "One", MeetingDate: "2026-03-03", StartTime: "09:00"}
+	if err := repo.Create(m); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	created, err := repo.ChangeTag()
+	if err != nil || created == empty {
+		t.Errorf("expected change tag to change on create, got %q (err %v)", created, err)
+	}
+
+	if err := repo.Delete(m.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	deleted, err := repo.ChangeTag()
+	if err != nil || deleted == created {
+		t.Errorf("expected change tag to change on delete, got %q (err %v)", deleted, err)
+	}
+}
+
+func TestMeetingRepository_Patch(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	repo := repositories.NewMeetingRepository(database.DB)
+	endTime := "10:00"
+	m := &models.Meeting{CreatedBy: "test@example.com", Subject: "Planning", MeetingDate: "2026-03-03", StartTime: "09:00", EndTime: &endTime, Timezone: "UTC"}
+	if err := repo.Create(m); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	stored, err := repo.GetByID(m.ID)
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+
+	// An unchanged meeting writes nothing, so the version stays
+	if err := repo.Patch(stored); err != nil {
+		t.Fatalf("patch without changes failed: %v", err)
+	}
+	if got, _ := repo.GetByID(m.ID); got.Version != stored.Version {
+		t.Errorf("expected version %d after an empty patch, got %d", stored.Version, got.Version)
+	}
+
+	keywords := "roadmap"
+	patched := *stored
+	patched.Keywords = &keywords
+	patched.EndTime = nil
+	if err := repo.Patch(&patched); err != nil {
+		t.Fatalf("patch failed: %v", err)
+	}
+	got, err := repo.GetByID(m.ID)
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if got.Keywords == nil || *got.Keywords != "roadmap" || got.EndTime != nil || got.Subject != "Planning" || got.Version != stored.Version+1 {
+		t.Errorf("unexpected patched meeting %+v", got)
+	}
+
+	entries, err := repositories.NewAuditRepository(database.DB).List(repositories.AuditFilter{EntityType: models.AuditEntityMeeting})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected create and patch audit entries, got %d (err %v)", len(entries), err)
+	}
+	if _, ok := entries[0].Changes["keywords"]; !ok || len(entries[0].Changes) != 2 {
+		t.Errorf("expected keywords and end_time changes, got %v", entries[0].Changes)
+	}
+}
+
+func TestMeetingRepository_ListForUser(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+	repo := repositories.NewMeetingRepository(database.DB)
+
+	participants := []string{"Ann <ann@example.com>, Bob", "joanna@example.com", "ann@example.com", "Anne"}
+	for i, p := range participants {
+		m := &models.Meeting{CreatedBy: "carol@example.com", Subject: "Meeting " + strconv.Itoa(i), MeetingDate: "2026-03-0" + strconv.Itoa(i+1), StartTime: "09:00", Participants: &p}
+		if err := repo.Create(m); err != nil {
+			t.Fatalf("create failed: %v", err)
+		}
+	}
+	own := &models.Meeting{CreatedBy: "ann@example.com", Subject: "Own", MeetingDate: "2026-03-09", StartTime: "09:00"}
+	if err := repo.Create(own); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	meetings, err := repo.ListForUser("ann@example.com")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var subjects []string
+	for _, m := range meetings {
+		subjects = append(subjects, m.Subject)
+	}
+	if strings.Join(subjects, ",") != "Meeting 0,Meeting 2,Own" {
+		t.Errorf("expected whole participant entries to match, got %v", subjects)
+	}
+
+	if meetings, _ := repo.ListForUser("ann"); len(meetings) != 0 {
+		t.Errorf("expected no match for a part of an entry, got %d meetings", len(meetings))
+	}
+}
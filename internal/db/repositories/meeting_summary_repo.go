@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// meetingSummaryColumns is the column list of meeting_summaries, in scanMeetingSummary order
+const meetingSummaryColumns = `id, meeting_id, version, summary, author, source, model, prompt_hash,
+	input_tokens, output_tokens, promoted_from, created_at`
+
+// scanMeetingSummary scans a row selected with meetingSummaryColumns
+func scanMeetingSummary(row rowScanner) (*models.MeetingSummary, error) {
+	s := &models.MeetingSummary{}
+	err := row.Scan(&s.ID, &s.MeetingID, &s.Version, &s.Summary, &s.Author, &s.Source, &s.Model, &s.PromptHash,
+		&s.InputTokens, &s.OutputTokens, &s.PromotedFrom, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// WithSummaryProvenance returns a copy of the repository that records a
+// changed summary as produced by provenance. Without it, new summaries are
+// recorded as manual.
+func (r *MeetingRepository) WithSummaryProvenance(provenance models.SummaryProvenance) *MeetingRepository {
+	c := *r
+	c.provenance = provenance
+	return &c
+}
+
+// recordSummary stores summary as the next version of a meeting's summary
+// if it differs from before
+func (r *MeetingRepository) recordSummary(ctx context.Context, tx *sql.Tx, meetingID int, before, summary *string) error {
+	if stringValue(before) == stringValue(summary) {
+		return nil
+	}
+	p := r.provenance
+	if p.Source == "" {
+		p.Source = models.SummarySourceManual
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO meeting_summaries (meeting_id, version, summary, author, source, model, prompt_hash,
+			input_tokens, output_tokens, promoted_from)
+		SELECT ?, COALESCE(MAX(version), 0) + 1, ?, ?, ?, ?, ?, ?, ?, ? FROM meeting_summaries WHERE meeting_id = ?
+	`, meetingID, stringValue(summary), actorFromContext(ctx), p.Source, p.Model, p.PromptHash,
+		p.InputTokens, p.OutputTokens, p.PromotedFrom, meetingID)
+	if err != nil {
+		return fmt.Errorf("create summary version: %w", err)
+	}
+	return nil
+}
+
+// stringValue dereferences an optional string column
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ListSummaries lists the stored summary versions of a meeting, newest first
+func (r *MeetingRepository) ListSummaries(meetingID int) ([]*models.MeetingSummary, error) {
+	ctx := queryContext(r.ctx)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+meetingSummaryColumns+`
+		FROM meeting_summaries
+		WHERE meeting_id = ?
+		ORDER BY version DESC
+	`, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("list summary versions: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []*models.MeetingSummary{}
+	for rows.Next() {
+		s, err := scanMeetingSummary(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan summary version: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// GetSummary retrieves one summary version of a meeting
+func (r *MeetingRepository) GetSummary(meetingID, version int) (*models.MeetingSummary, error) {
+	ctx := queryContext(r.ctx)
+	s, err := scanMeetingSummary(r.db.QueryRowContext(ctx, `
+		SELECT `+meetingSummaryColumns+`
+		FROM meeting_summaries
+		WHERE meeting_id = ? AND version = ?
+	`, meetingID, version))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get summary version: %w", err)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,69 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func TestMeetingRepository_SummaryVersions(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	ctx := repositories.ContextWithActor(context.Background(), "alice@example.com")
+	repo := repositories.NewMeetingRepository(database.DB).WithContext(ctx)
+
+	first := "First draft"
+	m := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Standup", MeetingDate: "2026-03-01", StartTime: "09:00", Summary: &first}
+	if err := repo.Create(m); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	// Changes to other fields do not add a version
+	m.Subject = "Daily"
+	if err := repo.Update(m); err != nil {
+		t.Fatalf("update meeting: %v", err)
+	}
+
+	model, hash, tokens := "gpt-4o", "abc123", 10
+	generated := "Generated summary"
+	m.Summary = &generated
+	provenance := models.SummaryProvenance{Source: models.SummarySourceLLM, Model: &model, PromptHash: &hash, InputTokens: &tokens}
+	if err := repo.WithSummaryProvenance(provenance).Update(m); err != nil {
+		t.Fatalf("update summary: %v", err)
+	}
+
+	summaries, err := repo.ListSummaries(m.ID)
+	if err != nil {
+		t.Fatalf("list summaries: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(summaries))
+	}
+	if s := summaries[1]; s.Version != 1 || s.Summary != first || s.Source != models.SummarySourceManual || s.Author != "alice@example.com" {
+		t.Errorf("unexpected first version %+v", s)
+	}
+	s := summaries[0]
+	if s.Version != 2 || s.Source != models.SummarySourceLLM || *s.Model != model || *s.PromptHash != hash || *s.InputTokens != tokens || s.OutputTokens != nil {
+		t.Errorf("unexpected generated version %+v", s)
+	}
+
+	got, err := repo.GetSummary(m.ID, 1)
+	if err != nil || got == nil || got.Summary != first {
+		t.Errorf("get summary = %+v, %v", got, err)
+	}
+	if got, err = repo.GetSummary(m.ID, 3); err != nil || got != nil {
+		t.Errorf("expected missing version, got %+v, %v", got, err)
+	}
+
+	if err := repo.Delete(m.ID); err != nil {
+		t.Fatalf("delete meeting: %v", err)
+	}
+	if err := repo.Purge(m.ID); err != nil {
+		t.Fatalf("purge meeting: %v", err)
+	}
+	if summaries, _ = repo.ListSummaries(m.ID); len(summaries) != 0 {
+		t.Errorf("expected versions to be deleted with the meeting, got %d", len(summaries))
+	}
+}
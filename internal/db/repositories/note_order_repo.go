@@ -0,0 +1,295 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+var (
+	// ErrInvalidOrder is returned when a new order does not list every note
+	// of the meeting exactly once
+	ErrInvalidOrder = errors.New("order must list every note of the meeting exactly once")
+	// ErrInvalidMove is returned when the notes to move are not distinct
+	// notes outside the trash of other meetings than the target
+	ErrInvalidMove = errors.New("notes to move must be distinct notes of other meetings")
+)
+
+// listMeetingNotes lists all notes of a meeting, including trashed ones, by
+// number within tx
+func listMeetingNotes(ctx context.Context, tx *sql.Tx, meetingID int) ([]*models.Note, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT `+noteColumns+` FROM notes WHERE meeting_id = ? ORDER BY note_number ASC`, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*models.Note
+	for rows.Next() {
+		n, err := scanNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		notes = append(notes, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return notes, nil
+}
+
+// renumberNotes sets the numbers of notes, mapped by note ID, within tx. The
+// notes first move to the negative of their ID to work around the
+// UNIQUE(meeting_id, note_number) constraint, so the new numbers may be any
+// permutation of the old ones.
+func renumberNotes(ctx context.Context, tx *sql.Tx, numbers map[int]int) error {
+	ids := make([]int, 0, len(numbers))
+	for id := range numbers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, "UPDATE notes SET note_number = ? WHERE id = ?", -id, id); err != nil {
+			return fmt.Errorf("set temp value: %w", err)
+		}
+	}
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, "UPDATE notes SET note_number = ? WHERE id = ?", numbers[id], id); err != nil {
+			return fmt.Errorf("set note number: %w", err)
+		}
+	}
+	return nil
+}
+
+// compactNotes numbers the notes of a meeting from 1 without gaps, keeping
+// their order. Trashed notes are numbered along so they return to their
+// place when restored.
+func compactNotes(ctx context.Context, tx *sql.Tx, meetingID int) error {
+	notes, err := listMeetingNotes(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	numbers := map[int]int{}
+	for i, n := range notes {
+		if n.NoteNumber != i+1 {
+			numbers[n.ID] = i + 1
+		}
+	}
+	return renumberNotes(ctx, tx, numbers)
+}
+
+// detachNotes makes the notes of a meeting whose parent belongs to another
+// meeting top-level notes within tx
+func detachNotes(ctx context.Context, tx *sql.Tx, meetingID int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE notes SET parent_id = NULL
+		WHERE meeting_id = ? AND parent_id IN (SELECT id FROM notes WHERE meeting_id != ?)
+	`, meetingID, meetingID)
+	if err != nil {
+		return fmt.Errorf("detach notes: %w", err)
+	}
+	return nil
+}
+
+// auditRenumbered records the notes whose meeting, parent or number differs
+// between before, mapped by note ID, and after: a move to another meeting or
+// parent as an update, a new number as a reorder
+func auditRenumbered(ctx context.Context, tx *sql.Tx, before map[int]*models.Note, after []*models.Note) error {
+	for _, a := range after {
+		b := before[a.ID]
+		if b == nil || (b.MeetingID == a.MeetingID && b.NoteNumber == a.NoteNumber && b.SameParent(a)) {
+			continue
+		}
+		action := models.AuditActionReorder
+		if b.MeetingID != a.MeetingID || !b.SameParent(a) {
+			action = models.AuditActionUpdate
+		}
+		if err := auditNote(ctx, tx, action, b, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReorderNotes puts the notes of a meeting outside the trash in the order of
+// ids, which must list each of them exactly once, and records the notes that
+// moved in the audit log. The notes keep the set of numbers they had.
+func (r *NoteRepository) ReorderNotes(meetingID int, ids []int) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	notes, err := listMeetingNotes(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	before := map[int]*models.Note{}
+	var slots []int
+	for _, n := range notes {
+		if n.DeletedAt == nil {
+			before[n.ID] = n
+			slots = append(slots, n.NoteNumber)
+		}
+	}
+	if len(ids) != len(before) {
+		return ErrInvalidOrder
+	}
+
+	seen := map[int]bool{}
+	numbers := map[int]int{}
+	for i, id := range ids {
+		n, ok := before[id]
+		if !ok || seen[id] {
+			return ErrInvalidOrder
+		}
+		seen[id] = true
+		if n.NoteNumber != slots[i] {
+			numbers[id] = slots[i]
+		}
+	}
+	if err := renumberNotes(ctx, tx, numbers); err != nil {
+		return err
+	}
+
+	after, err := listMeetingNotes(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	if err := auditRenumbered(ctx, tx, before, after); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// meetingNotes lists the notes of the meetings, including trashed ones,
+// within tx, and maps them by note ID
+func meetingNotes(ctx context.Context, tx *sql.Tx, meetingIDs []int) ([]*models.Note, map[int]*models.Note, error) {
+	var notes []*models.Note
+	for _, id := range meetingIDs {
+		list, err := listMeetingNotes(ctx, tx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		notes = append(notes, list...)
+	}
+	byID := map[int]*models.Note{}
+	for _, n := range notes {
+		byID[n.ID] = n
+	}
+	return notes, byID, nil
+}
+
+// moveSources checks the notes to move to a meeting and returns the
+// meetings they belong to, sorted
+func moveSources(ctx context.Context, tx *sql.Tx, ids []int, meetingID int) ([]int, error) {
+	if len(ids) == 0 {
+		return nil, ErrInvalidMove
+	}
+	var sources []int
+	seen := map[int]bool{}
+	for _, id := range ids {
+		n, err := getNote(ctx, tx, id)
+		if err != nil || seen[id] || n.MeetingID == meetingID {
+			return nil, ErrInvalidMove
+		}
+		seen[id] = true
+		if !slices.Contains(sources, n.MeetingID) {
+			sources = append(sources, n.MeetingID)
+		}
+	}
+	sort.Ints(sources)
+	return sources, nil
+}
+
+// checkMeetingLive returns ErrMeetingNotFound unless the meeting exists
+// outside the trash within tx
+func checkMeetingLive(ctx context.Context, tx *sql.Tx, meetingID int) error {
+	var live bool
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) > 0 FROM meetings WHERE id = ? AND deleted_at IS NULL`, meetingID).Scan(&live)
+	if err != nil {
+		return fmt.Errorf("get meeting: %w", err)
+	}
+	if !live {
+		return ErrMeetingNotFound
+	}
+	return nil
+}
+
+// MoveNotes moves notes outside the trash to the end of another meeting, in
+// the order of ids, and numbers both the target and the meetings the notes
+// came from without gaps. Notes left with a parent in another meeting, moved
+// or not, become top-level notes. It records the moved, detached and
+// renumbered notes in the audit log and returns the meetings the notes came
+// from.
+func (r *NoteRepository) MoveNotes(ids []int, meetingID int) (sources []int, err error) {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := checkMeetingLive(ctx, tx, meetingID); err != nil {
+		return nil, err
+	}
+
+	if sources, err = moveNotes(ctx, tx, ids, meetingID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return sources, nil
+}
+
+// moveNotes is MoveNotes within tx
+func moveNotes(ctx context.Context, tx *sql.Tx, ids []int, meetingID int) ([]int, error) {
+	sources, err := moveSources(ctx, tx, ids, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	affected := append([]int{meetingID}, sources...)
+	_, before, err := meetingNotes(ctx, tx, affected)
+	if err != nil {
+		return nil, err
+	}
+
+	var maxNumber int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(note_number), 0) FROM notes WHERE meeting_id = ?`, meetingID).Scan(&maxNumber); err != nil {
+		return nil, fmt.Errorf("get max note number: %w", err)
+	}
+	for i, id := range ids {
+		if _, err := tx.ExecContext(ctx, "UPDATE notes SET meeting_id = ?, note_number = ? WHERE id = ?", meetingID, maxNumber+i+1, id); err != nil {
+			return nil, fmt.Errorf("move note: %w", err)
+		}
+	}
+	for _, m := range affected {
+		if err := detachNotes(ctx, tx, m); err != nil {
+			return nil, err
+		}
+		if err := compactNotes(ctx, tx, m); err != nil {
+			return nil, err
+		}
+	}
+
+	after, _, err := meetingNotes(ctx, tx, affected)
+	if err != nil {
+		return nil, err
+	}
+	if err := auditRenumbered(ctx, tx, before, after); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
@@ -0,0 +1,181 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+// createOrderTestMeeting creates a meeting with a note per content
+func createOrderTestMeeting(t *testing.T, db *sql.DB, subject string, contents ...string) (*models.Meeting, []*models.Note) {
+	t.Helper()
+	meeting := &models.Meeting{CreatedBy: "test@example.com", Subject: subject, MeetingDate: "2026-02-14", StartTime: "10:00"}
+	if err := repositories.NewMeetingRepository(db).Create(meeting); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+	notes := make([]*models.Note, 0, len(contents))
+	for _, content := range contents {
+		n := &models.Note{MeetingID: meeting.ID, Content: content}
+		if err := repositories.NewNoteRepository(db).Create(n); err != nil {
+			t.Fatalf("failed to create note: %v", err)
+		}
+		notes = append(notes, n)
+	}
+	return meeting, notes
+}
+
+// noteOrder returns the contents and numbers of the live notes of a meeting
+func noteOrder(t *testing.T, db *sql.DB, meetingID int) ([]string, []int) {
+	t.Helper()
+	notes, err := repositories.NewNoteRepository(db).ListByMeeting(meetingID)
+	if err != nil {
+		t.Fatalf("failed to list notes: %v", err)
+	}
+	var contents []string
+	var numbers []int
+	for _, n := range notes {
+		contents = append(contents, n.Content)
+		numbers = append(numbers, n.NoteNumber)
+	}
+	return contents, numbers
+}
+
+func TestNoteRepository_ReorderNotes(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meeting, notes := createOrderTestMeeting(t, database.DB, "Standup", "a", "b", "trashed", "c")
+	repo := repositories.NewNoteRepository(database.DB)
+	if err := repo.Delete(notes[2].ID); err != nil {
+		t.Fatalf("failed to delete note: %v", err)
+	}
+
+	if err := repo.ReorderNotes(meeting.ID, []int{notes[3].ID, notes[0].ID, notes[1].ID}); err != nil {
+		t.Fatalf("ReorderNotes failed: %v", err)
+	}
+
+	// The notes take over the numbers of the live notes; the trashed note keeps its own
+	contents, numbers := noteOrder(t, database.DB, meeting.ID)
+	if !slices.Equal(contents, []string{"c", "a", "b"}) || !slices.Equal(numbers, []int{1, 2, 4}) {
+		t.Errorf("order = %v %v, want [c a b] [1 2 4]", contents, numbers)
+	}
+
+	entries, err := repositories.NewAuditRepository(database.DB).List(repositories.AuditFilter{Action: models.AuditActionReorder})
+	if err != nil {
+		t.Fatalf("failed to list audit log: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected 3 reorder entries, got %d", len(entries))
+	}
+}
+
+func TestNoteRepository_ReorderNotes_Invalid(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meeting, notes := createOrderTestMeeting(t, database.DB, "Standup", "a", "b")
+	_, others := createOrderTestMeeting(t, database.DB, "Retro", "x")
+
+	tests := []struct {
+		name string
+		ids  []int
+	}{
+		{"missing note", []int{notes[0].ID}},
+		{"duplicate note", []int{notes[0].ID, notes[0].ID}},
+		{"note of another meeting", []int{notes[0].ID, others[0].ID}},
+		{"extra note", []int{notes[0].ID, notes[1].ID, others[0].ID}},
+	}
+	repo := repositories.NewNoteRepository(database.DB)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.ReorderNotes(meeting.ID, tt.ids); !errors.Is(err, repositories.ErrInvalidOrder) {
+				t.Errorf("expected ErrInvalidOrder, got %v", err)
+			}
+		})
+	}
+
+	if contents, _ := noteOrder(t, database.DB, meeting.ID); !slices.Equal(contents, []string{"a", "b"}) {
+		t.Errorf("order changed to %v", contents)
+	}
+}
+
+func TestNoteRepository_MoveNotes(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	source, notes := createOrderTestMeeting(t, database.DB, "Standup", "a", "b", "c", "d")
+	other, otherNotes := createOrderTestMeeting(t, database.DB, "Planning", "p")
+	target, _ := createOrderTestMeeting(t, database.DB, "Retro", "x", "y")
+	repo := repositories.NewNoteRepository(database.DB)
+	if err := repo.Delete(notes[0].ID); err != nil {
+		t.Fatalf("failed to delete note: %v", err)
+	}
+
+	sources, err := repo.MoveNotes([]int{notes[2].ID, otherNotes[0].ID, notes[1].ID}, target.ID)
+	if err != nil {
+		t.Fatalf("MoveNotes failed: %v", err)
+	}
+	if !slices.Equal(sources, []int{source.ID, other.ID}) {
+		t.Errorf("sources = %v, want [%d %d]", sources, source.ID, other.ID)
+	}
+
+	contents, numbers := noteOrder(t, database.DB, target.ID)
+	if !slices.Equal(contents, []string{"x", "y", "c", "p", "b"}) || !slices.Equal(numbers, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("target order = %v %v", contents, numbers)
+	}
+	// The trashed note keeps its place ahead of d
+	if contents, numbers := noteOrder(t, database.DB, source.ID); !slices.Equal(contents, []string{"d"}) || !slices.Equal(numbers, []int{2}) {
+		t.Errorf("source order = %v %v, want [d] [2]", contents, numbers)
+	}
+	if err := repo.Restore(notes[0].ID); err != nil {
+		t.Fatalf("failed to restore note: %v", err)
+	}
+	if contents, numbers := noteOrder(t, database.DB, source.ID); !slices.Equal(contents, []string{"a", "d"}) || !slices.Equal(numbers, []int{1, 2}) {
+		t.Errorf("source order after restore = %v %v, want [a d] [1 2]", contents, numbers)
+	}
+
+	entries, err := repositories.NewAuditRepository(database.DB).List(repositories.AuditFilter{Action: models.AuditActionUpdate})
+	if err != nil {
+		t.Fatalf("failed to list audit log: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected an update entry per moved note, got %d", len(entries))
+	}
+}
+
+func TestNoteRepository_MoveNotes_Invalid(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	source, notes := createOrderTestMeeting(t, database.DB, "Standup", "a", "b")
+	target, targetNotes := createOrderTestMeeting(t, database.DB, "Retro", "x")
+
+	tests := []struct {
+		name string
+		ids  []int
+	}{
+		{"no notes", nil},
+		{"duplicate note", []int{notes[0].ID, notes[0].ID}},
+		{"note of the target", []int{notes[0].ID, targetNotes[0].ID}},
+		{"unknown note", []int{999}},
+	}
+	repo := repositories.NewNoteRepository(database.DB)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repo.MoveNotes(tt.ids, target.ID); !errors.Is(err, repositories.ErrInvalidMove) {
+				t.Errorf("expected ErrInvalidMove, got %v", err)
+			}
+		})
+	}
+
+	if _, err := repo.MoveNotes([]int{notes[0].ID}, 999); err == nil {
+		t.Error("expected an error moving to an unknown meeting")
+	}
+	if contents, _ := noteOrder(t, database.DB, source.ID); !slices.Equal(contents, []string{"a", "b"}) {
+		t.Errorf("source changed to %v", contents)
+	}
+}
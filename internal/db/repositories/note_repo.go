@@ -4,14 +4,48 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/zorak1103/notebook/internal/db/models"
 )
 
-// NoteRepository handles note CRUD operations
+// ErrInvalidParent is returned when the parent of a note is not another note
+// of the same meeting outside the trash, or is nested under the note itself
+var ErrInvalidParent = errors.New("parent must be another note of the same meeting, not nested under it")
+
+// noteColumns is the column list shared by all note SELECTs, in scanNote order
+const noteColumns = `id, meeting_id, note_number, content, parent_id, note_type, created_at, updated_at, deleted_at, version`
+
+// scanNote scans a row selected with noteColumns
+func scanNote(row rowScanner) (*models.Note, error) {
+	n := &models.Note{}
+	var parentID sql.NullInt64
+	var deletedAt sql.NullString
+	if err := row.Scan(&n.ID, &n.MeetingID, &n.NoteNumber, &n.Content, &parentID, &n.NoteType,
+		&n.CreatedAt, &n.UpdatedAt, &deletedAt, &n.Version); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		n.ParentID = &id
+	}
+	var err error
+	if n.DeletedAt, err = parseUTCColumn(deletedAt); err != nil {
+		return nil, fmt.Errorf("note %d deleted_at: %w", n.ID, err)
+	}
+	return n, nil
+}
+
+// NoteRepository handles note CRUD operations. Every new content of a note
+// is stored as a revision.
 type NoteRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	ctx       context.Context
+	source    string
+	retention RevisionRetention
+	version   int
 }
 
 // NewNoteRepository creates a new note repository
@@ -19,13 +53,101 @@ func NewNoteRepository(db *sql.DB) *NoteRepository {
 	return &NoteRepository{db: db}
 }
 
-// Create creates a new note with automatic number assignment
+// WithContext returns a copy of the repository that runs its queries with ctx
+func (r *NoteRepository) WithContext(ctx context.Context) *NoteRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// getNote retrieves a note outside the trash by ID within tx
+func getNote(ctx context.Context, tx *sql.Tx, id int) (*models.Note, error) {
+	n, err := scanNote(tx.QueryRowContext(ctx, `SELECT `+noteColumns+` FROM notes WHERE id = ? AND deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("note not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get note: %w", err)
+	}
+	return n, nil
+}
+
+// checkParent checks the parent of n within tx. Following the parents up from
+// it must not lead back to n.
+func checkParent(ctx context.Context, tx *sql.Tx, n *models.Note) error {
+	if n.ParentID == nil {
+		return nil
+	}
+	parent, err := getNote(ctx, tx, *n.ParentID)
+	if err != nil || parent.MeetingID != n.MeetingID {
+		return ErrInvalidParent
+	}
+	seen := map[int]bool{}
+	for id := parent.ID; !seen[id]; {
+		if id == n.ID {
+			return ErrInvalidParent
+		}
+		seen[id] = true
+		var next sql.NullInt64
+		if err := tx.QueryRowContext(ctx, "SELECT parent_id FROM notes WHERE id = ?", id).Scan(&next); err != nil {
+			return fmt.Errorf("get parent note: %w", err)
+		}
+		if !next.Valid {
+			break
+		}
+		id = int(next.Int64)
+	}
+	return nil
+}
+
+// auditNote records the change of a note from before to after in tx
+func auditNote(ctx context.Context, tx *sql.Tx, action string, before, after *models.Note) error {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+	note := before
+	if note == nil {
+		note = after
+	}
+	return insertAuditEntry(ctx, tx, action, models.AuditEntityNote, strconv.Itoa(note.ID), changes)
+}
+
+// Create creates a new note with automatic number assignment, stores its
+// content as revision 1 and records it in the audit log. A note without a
+// type is a plain note. The meeting must be outside the trash.
 func (r *NoteRepository) Create(n *models.Note) error {
-	ctx := context.Background()
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.create(ctx, tx, n); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// create is Create within tx
+func (r *NoteRepository) create(ctx context.Context, tx *sql.Tx, n *models.Note) error {
+	if n.NoteType == "" {
+		n.NoteType = models.NoteTypePlain
+	}
+	if err := checkMeetingLive(ctx, tx, n.MeetingID); err != nil {
+		return err
+	}
+	if err := checkParent(ctx, tx, n); err != nil {
+		return err
+	}
 
-	// Get next number for this meeting
+	// Get next number for this meeting; trashed notes keep their numbers
 	var maxNumber int
-	err := r.db.QueryRowContext(ctx, `
+	err := tx.QueryRowContext(ctx, `
 		SELECT COALESCE(MAX(note_number), 0) FROM notes WHERE meeting_id = ?
 	`, n.MeetingID).Scan(&maxNumber)
 
@@ -35,10 +157,10 @@ func (r *NoteRepository) Create(n *models.Note) error {
 
 	n.NoteNumber = maxNumber + 1
 
-	result, err := r.db.ExecContext(ctx, `
-		INSERT INTO notes (meeting_id, note_number, content)
-		VALUES (?, ?, ?)
-	`, n.MeetingID, n.NoteNumber, n.Content)
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO notes (meeting_id, note_number, content, parent_id, note_type)
+		VALUES (?, ?, ?, ?, ?)
+	`, n.MeetingID, n.NoteNumber, n.Content, n.ParentID, n.NoteType)
 
 	if err != nil {
 		return fmt.Errorf("create note: %w", err)
@@ -50,17 +172,16 @@ func (r *NoteRepository) Create(n *models.Note) error {
 	}
 
 	n.ID = int(id)
-	return nil
+	if err := r.insertRevision(ctx, tx, n.ID, n.Content); err != nil {
+		return err
+	}
+	return auditNote(ctx, tx, models.AuditActionCreate, nil, n)
 }
 
-// GetByID retrieves a note by ID
+// GetByID retrieves a note by ID; notes in the trash are not found
 func (r *NoteRepository) GetByID(id int) (*models.Note, error) {
-	ctx := context.Background()
-	n := &models.Note{}
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, meeting_id, note_number, content, created_at, updated_at
-		FROM notes WHERE id = ?
-	`, id).Scan(&n.ID, &n.MeetingID, &n.NoteNumber, &n.Content, &n.CreatedAt, &n.UpdatedAt)
+	ctx := queryContext(r.ctx)
+	n, err := scanNote(r.db.QueryRowContext(ctx, `SELECT `+noteColumns+` FROM notes WHERE id = ? AND deleted_at IS NULL`, id))
 
 	if err == sql.ErrNoRows {
 		//nolint:nilnil // Intentional: not found is not an error
@@ -73,13 +194,13 @@ func (r *NoteRepository) GetByID(id int) (*models.Note, error) {
 	return n, nil
 }
 
-// ListByMeeting lists all notes for a meeting
+// ListByMeeting lists the notes of a meeting outside the trash
 func (r *NoteRepository) ListByMeeting(meetingID int) ([]*models.Note, error) {
-	ctx := context.Background()
+	ctx := queryContext(r.ctx)
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, meeting_id, note_number, content, created_at, updated_at
+		SELECT `+noteColumns+`
 		FROM notes
-		WHERE meeting_id = ?
+		WHERE meeting_id = ? AND deleted_at IS NULL
 		ORDER BY note_number ASC
 	`, meetingID)
 
@@ -90,8 +211,7 @@ func (r *NoteRepository) ListByMeeting(meetingID int) ([]*models.Note, error) {
 
 	var notes []*models.Note
 	for rows.Next() {
-		n := &models.Note{}
-		err := rows.Scan(&n.ID, &n.MeetingID, &n.NoteNumber, &n.Content, &n.CreatedAt, &n.UpdatedAt)
+		n, err := scanNote(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan note: %w", err)
 		}
@@ -105,37 +225,69 @@ func (r *NoteRepository) ListByMeeting(meetingID int) ([]*models.Note, error) {
 	return notes, nil
 }
 
-// Update updates an existing note
+// Update sets the content, parent and type of an existing note, stores
+// changed content as a new revision and records the change in the audit log.
+// An empty type keeps the type of the note.
 func (r *NoteRepository) Update(n *models.Note) error {
-	ctx := context.Background()
-	result, err := r.db.ExecContext(ctx, `
-		UPDATE notes SET content = ? WHERE id = ?
-	`, n.Content, n.ID)
-
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("update note: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	rows, err := result.RowsAffected()
+	before, err := getNote(ctx, tx, n.ID)
 	if err != nil {
-		return fmt.Errorf("get rows affected: %w", err)
+		return err
+	}
+	if err := checkVersion(r.version, before.Version); err != nil {
+		return err
+	}
+
+	if n.NoteType == "" {
+		n.NoteType = before.NoteType
+	}
+	// A parent that is already set may have moved to the trash since
+	n.MeetingID = before.MeetingID
+	if !n.SameParent(before) {
+		if err := checkParent(ctx, tx, n); err != nil {
+			return err
+		}
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("note not found")
+	_, err = tx.ExecContext(ctx, `UPDATE notes SET content = ?, parent_id = ?, note_type = ? WHERE id = ?`,
+		n.Content, n.ParentID, n.NoteType, n.ID)
+	if err != nil {
+		return fmt.Errorf("update note: %w", err)
 	}
 
+	after, err := getNote(ctx, tx, n.ID)
+	if err != nil {
+		return err
+	}
+	if after.Content != before.Content {
+		if err := r.insertRevision(ctx, tx, n.ID, n.Content); err != nil {
+			return err
+		}
+	}
+	if err := auditNote(ctx, tx, models.AuditActionUpdate, before, after); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
 	return nil
 }
 
-// SwapNoteOrder swaps the note_number values of two notes within the same meeting.
+// SwapNoteOrder swaps the note_number values of two notes within the same meeting
+// and records both moves in the audit log.
 // Uses a transaction with sentinel value 0 to work around the UNIQUE(meeting_id, note_number) constraint.
 func (r *NoteRepository) SwapNoteOrder(noteID1, noteID2 int) error {
 	if noteID1 == noteID2 {
 		return fmt.Errorf("cannot swap note with itself")
 	}
 
-	ctx := context.Background()
+	ctx := queryContext(r.ctx)
 
 	note1, err := r.GetByID(noteID1)
 	if err != nil {
@@ -181,6 +333,16 @@ func (r *NoteRepository) SwapNoteOrder(noteID1, noteID2 int) error {
 		return fmt.Errorf("set note1 number: %w", err)
 	}
 
+	for _, before := range []*models.Note{note1, note2} {
+		after, err := getNote(ctx, tx, before.ID)
+		if err != nil {
+			return err
+		}
+		if err := auditNote(ctx, tx, models.AuditActionReorder, before, after); err != nil {
+			return err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
@@ -188,22 +350,37 @@ func (r *NoteRepository) SwapNoteOrder(noteID1, noteID2 int) error {
 	return nil
 }
 
-// Delete deletes a note
+// Delete moves a note to the trash and records it in the audit log
 func (r *NoteRepository) Delete(id int) error {
-	ctx := context.Background()
-	result, err := r.db.ExecContext(ctx, "DELETE FROM notes WHERE id = ?", id)
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("delete note: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	rows, err := result.RowsAffected()
+	before, err := getNote(ctx, tx, id)
 	if err != nil {
-		return fmt.Errorf("get rows affected: %w", err)
+		return err
+	}
+	if err := checkVersion(r.version, before.Version); err != nil {
+		return err
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("note not found")
+	deletedAt := trashTime()
+	if _, err := tx.ExecContext(ctx, "UPDATE notes SET deleted_at = ? WHERE id = ?", deletedAt, id); err != nil {
+		return fmt.Errorf("delete note: %w", err)
 	}
 
+	after, err := scanNote(tx.QueryRowContext(ctx, `SELECT `+noteColumns+` FROM notes WHERE id = ?`, id))
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+	if err := auditNote(ctx, tx, models.AuditActionDelete, before, after); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
 	return nil
 }
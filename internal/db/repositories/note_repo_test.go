@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/zorak1103/notebook/internal/db/models"
@@ -447,3 +448,49 @@ func TestNoteRepository_DeleteCascade(t *testing.T) {
 		t.Error("expected note to be deleted via CASCADE")
 	}
 }
+
+func TestNoteRepository_Hierarchy(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meetingRepo := repositories.NewMeetingRepository(database.DB)
+	meeting := &models.Meeting{CreatedBy: "test@example.com", Subject: "Planning", MeetingDate: "2026-02-14", StartTime: "10:00"}
+	other := &models.Meeting{CreatedBy: "test@example.com", Subject: "Other", MeetingDate: "2026-02-15", StartTime: "10:00"}
+	for _, m := range []*models.Meeting{meeting, other} {
+		if err := meetingRepo.Create(m); err != nil {
+			t.Fatalf("failed to create meeting: %v", err)
+		}
+	}
+
+	noteRepo := repositories.NewNoteRepository(database.DB)
+	parent := &models.Note{MeetingID: meeting.ID, Content: "Budget"}
+	if err := noteRepo.Create(parent); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if parent.NoteType != models.NoteTypePlain || parent.ParentID != nil {
+		t.Errorf("expected a top-level plain note, got %+v", parent)
+	}
+	child := &models.Note{MeetingID: meeting.ID, Content: "Approved", ParentID: &parent.ID, NoteType: models.NoteTypeDecision}
+	if err := noteRepo.Create(child); err != nil {
+		t.Fatalf("create child failed: %v", err)
+	}
+
+	retrieved, err := noteRepo.GetByID(child.ID)
+	if err != nil {
+		t.Fatalf("getByID failed: %v", err)
+	}
+	if retrieved.ParentID == nil || *retrieved.ParentID != parent.ID || retrieved.NoteType != models.NoteTypeDecision {
+		t.Errorf("unexpected child %+v", retrieved)
+	}
+
+	foreign := &models.Note{MeetingID: other.ID, Content: "Elsewhere", ParentID: &parent.ID}
+	if err := noteRepo.Create(foreign); !errors.Is(err, repositories.ErrInvalidParent) {
+		t.Errorf("expected ErrInvalidParent for a parent of another meeting, got %v", err)
+	}
+
+	// Nesting the parent under its own child would form a cycle
+	parent.ParentID = &child.ID
+	if err := noteRepo.Update(parent); !errors.Is(err, repositories.ErrInvalidParent) {
+		t.Errorf("expected ErrInvalidParent for a cycle, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// revisionTimeFormat matches CURRENT_TIMESTAMP, which created_at is set from
+const revisionTimeFormat = "2006-01-02 15:04:05"
+
+// RevisionRetention limits the revisions kept per note. The latest revision
+// is always kept; zero values keep everything.
+type RevisionRetention struct {
+	// MaxCount is the number of revisions kept per note
+	MaxCount int
+	// MaxAge is how long older revisions are kept
+	MaxAge time.Duration
+}
+
+// WithRevisionSource returns a copy of the repository that records new
+// content as coming from source, models.RevisionSourceManual by default
+func (r *NoteRepository) WithRevisionSource(source string) *NoteRepository {
+	c := *r
+	c.source = source
+	return &c
+}
+
+// WithRetention returns a copy of the repository that prunes revisions of
+// changed notes to retention
+func (r *NoteRepository) WithRetention(retention RevisionRetention) *NoteRepository {
+	c := *r
+	c.retention = retention
+	return &c
+}
+
+// insertRevision stores content as the next revision of a note and prunes
+// the note's revisions
+func (r *NoteRepository) insertRevision(ctx context.Context, tx *sql.Tx, noteID int, content string) error {
+	source := r.source
+	if source == "" {
+		source = models.RevisionSourceManual
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO note_revisions (note_id, revision, content, author, source)
+		SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ? FROM note_revisions WHERE note_id = ?
+	`, noteID, content, actorFromContext(ctx), source, noteID)
+	if err != nil {
+		return fmt.Errorf("create note revision: %w", err)
+	}
+
+	if r.retention.MaxCount > 0 {
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM note_revisions WHERE note_id = ? AND id NOT IN (
+				SELECT id FROM note_revisions WHERE note_id = ? ORDER BY revision DESC LIMIT ?
+			)
+		`, noteID, noteID, r.retention.MaxCount)
+		if err != nil {
+			return fmt.Errorf("prune note revisions: %w", err)
+		}
+	}
+	if r.retention.MaxAge > 0 {
+		cutoff := time.Now().UTC().Add(-r.retention.MaxAge).Format(revisionTimeFormat)
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM note_revisions WHERE note_id = ? AND created_at < ?
+				AND revision < (SELECT MAX(revision) FROM note_revisions WHERE note_id = ?)
+		`, noteID, cutoff, noteID)
+		if err != nil {
+			return fmt.Errorf("prune note revisions: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListRevisions lists the stored revisions of a note, newest first
+func (r *NoteRepository) ListRevisions(noteID int) ([]*models.NoteRevision, error) {
+	ctx := queryContext(r.ctx)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, note_id, revision, content, author, source, created_at
+		FROM note_revisions
+		WHERE note_id = ?
+		ORDER BY revision DESC
+	`, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("list note revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*models.NoteRevision{}
+	for rows.Next() {
+		rev := &models.NoteRevision{}
+		if err := rows.Scan(&rev.ID, &rev.NoteID, &rev.Revision, &rev.Content, &rev.Author, &rev.Source, &rev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan note revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision retrieves one revision of a note
+func (r *NoteRepository) GetRevision(noteID, revision int) (*models.NoteRevision, error) {
+	ctx := queryContext(r.ctx)
+	rev := &models.NoteRevision{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, note_id, revision, content, author, source, created_at
+		FROM note_revisions
+		WHERE note_id = ? AND revision = ?
+	`, noteID, revision).Scan(&rev.ID, &rev.NoteID, &rev.Revision, &rev.Content, &rev.Author, &rev.Source, &rev.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get note revision: %w", err)
+	}
+
+	return rev, nil
+}
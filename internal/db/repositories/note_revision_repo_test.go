@@ -0,0 +1,111 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func TestNoteRepository_Revisions(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	ctx := repositories.ContextWithActor(context.Background(), "alice@example.com")
+	meeting := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Standup", MeetingDate: "2026-03-01", StartTime: "09:00"}
+	if err := repositories.NewMeetingRepository(database.DB).Create(meeting); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+
+	repo := repositories.NewNoteRepository(database.DB).WithContext(ctx)
+	note := &models.Note{MeetingID: meeting.ID, Content: "draft"}
+	if err := repo.WithRevisionSource(models.RevisionSourceImport).Create(note); err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+	note.Content = "enhanced"
+	if err := repo.WithRevisionSource(models.RevisionSourceLLMEnhance).Update(note); err != nil {
+		t.Fatalf("update note: %v", err)
+	}
+	// Saving unchanged content adds no revision
+	if err := repo.Update(note); err != nil {
+		t.Fatalf("update note: %v", err)
+	}
+
+	revisions, err := repo.ListRevisions(note.ID)
+	if err != nil {
+		t.Fatalf("list revisions: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revisions))
+	}
+	if r := revisions[0]; r.Revision != 2 || r.Content != "enhanced" || r.Source != models.RevisionSourceLLMEnhance || r.Author != "alice@example.com" {
+		t.Errorf("unexpected latest revision %+v", r)
+	}
+	if r := revisions[1]; r.Revision != 1 || r.Content != "draft" || r.Source != models.RevisionSourceImport {
+		t.Errorf("unexpected first revision %+v", r)
+	}
+
+	first, err := repo.GetRevision(note.ID, 1)
+	if err != nil || first == nil || first.Content != "draft" {
+		t.Errorf("GetRevision(1) = %+v, %v", first, err)
+	}
+	missing, err := repo.GetRevision(note.ID, 3)
+	if err != nil || missing != nil {
+		t.Errorf("GetRevision(3) = %+v, %v", missing, err)
+	}
+
+	// Revisions are deleted with their note
+	if err := repo.Delete(note.ID); err != nil {
+		t.Fatalf("delete note: %v", err)
+	}
+	if err := repo.Purge(note.ID); err != nil {
+		t.Fatalf("purge note: %v", err)
+	}
+	if revisions, _ = repo.ListRevisions(note.ID); len(revisions) != 0 {
+		t.Errorf("expected revisions to be deleted, got %d", len(revisions))
+	}
+}
+
+func TestNoteRepository_RevisionRetention(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meeting := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Standup", MeetingDate: "2026-03-01", StartTime: "09:00"}
+	if err := repositories.NewMeetingRepository(database.DB).Create(meeting); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+
+	repo := repositories.NewNoteRepository(database.DB).WithRetention(repositories.RevisionRetention{MaxCount: 3})
+	note := &models.Note{MeetingID: meeting.ID, Content: "v1"}
+	if err := repo.Create(note); err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+	for _, content := range []string{"v2", "v3", "v4", "v5"} {
+		note.Content = content
+		if err := repo.Update(note); err != nil {
+			t.Fatalf("update note: %v", err)
+		}
+	}
+
+	revisions, err := repo.ListRevisions(note.ID)
+	if err != nil {
+		t.Fatalf("list revisions: %v", err)
+	}
+	if len(revisions) != 3 || revisions[0].Revision != 5 || revisions[2].Revision != 3 {
+		t.Fatalf("unexpected revisions after count pruning: %+v", revisions)
+	}
+
+	// Age pruning drops all but the latest of the old revisions
+	if _, err := database.ExecContext(context.Background(), `UPDATE note_revisions SET created_at = '2000-01-01 00:00:00'`); err != nil {
+		t.Fatalf("age revisions: %v", err)
+	}
+	note.Content = "v6"
+	if err := repo.WithRetention(repositories.RevisionRetention{MaxAge: 24 * time.Hour}).Update(note); err != nil {
+		t.Fatalf("update note: %v", err)
+	}
+	if revisions, _ = repo.ListRevisions(note.ID); len(revisions) != 1 || revisions[0].Content != "v6" {
+		t.Errorf("unexpected revisions after age pruning: %+v", revisions)
+	}
+}
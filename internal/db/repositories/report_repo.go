@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// Report groupings
+const (
+	ReportGroupWeek  = "week"
+	ReportGroupMonth = "month"
+)
+
+// reportPeriodExprs maps a grouping to the SQL expression for the first day
+// of the period a meeting falls into (whitelist, used in query text)
+var reportPeriodExprs = map[string]string{
+	// SQLite's %w is 0 for Sunday; weeks start on Monday
+	ReportGroupWeek:  `date(meeting_date, '-' || ((CAST(strftime('%w', meeting_date) AS INTEGER) + 6) % 7) || ' days')`,
+	ReportGroupMonth: `strftime('%Y-%m-01', meeting_date)`,
+}
+
+// meetingMinutesExpr is a meeting's length in minutes from its HH:MM start and
+// end times. An end before the start runs past midnight; no end time counts as 0.
+const meetingMinutesExpr = `CASE WHEN end_time IS NULL OR end_time = '' THEN 0 ELSE
+	((CAST(substr(end_time, 1, 2) AS INTEGER) * 60 + CAST(substr(end_time, 4, 2) AS INTEGER))
+	- (CAST(substr(start_time, 1, 2) AS INTEGER) * 60 + CAST(substr(start_time, 4, 2) AS INTEGER)) + 1440) % 1440 END`
+
+// participantKeyExpr reduces a participants entry such as "Alice <alice@example.com>"
+// to its lower-cased address, and other entries to lower case, so the same
+// person is counted once
+const participantKeyExpr = `CASE WHEN instr(item, '<') > 0 AND instr(item, '>') > instr(item, '<')
+	THEN lower(substr(item, instr(item, '<') + 1, instr(item, '>') - instr(item, '<') - 1)) ELSE lower(item) END`
+
+// ReportRepository aggregates meetings and notes for reports
+type ReportRepository struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// WithContext returns a copy of the repository that runs its queries with ctx
+func (r *ReportRepository) WithContext(ctx context.Context) *ReportRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// IsValidReportGroup reports whether group is a supported grouping
+func IsValidReportGroup(group string) bool {
+	_, ok := reportPeriodExprs[group]
+	return ok
+}
+
+// reportMeetingsCTE selects the meetings of [from, to] outside the trash with
+// their period and length
+func reportMeetingsCTE(periodExpr string) string {
+	return `m AS (
+		SELECT id, ` + periodExpr + ` AS period, ` + meetingMinutesExpr + ` AS minutes,
+			CASE WHEN summary IS NOT NULL AND TRIM(summary) != '' THEN 1 ELSE 0 END AS summarized,
+			keywords, participants
+		FROM meetings
+		WHERE meeting_date BETWEEN ? AND ? AND deleted_at IS NULL
+	)`
+}
+
+// Periods aggregates meetings between from and to (YYYY-MM-DD, inclusive) by week or month
+func (r *ReportRepository) Periods(from, to, group string) ([]*models.ReportPeriod, error) {
+	periodExpr, ok := reportPeriodExprs[group]
+	if !ok {
+		return nil, fmt.Errorf("invalid report group %q", group)
+	}
+	return r.periods(periodExpr, from, to)
+}
+
+// Totals aggregates all meetings between from and to (YYYY-MM-DD, inclusive)
+func (r *ReportRepository) Totals(from, to string) (*models.ReportPeriod, error) {
+	periods, err := r.periods(`''`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return &models.ReportPeriod{}, nil
+	}
+	return periods[0], nil
+}
+
+func (r *ReportRepository) periods(periodExpr, from, to string) ([]*models.ReportPeriod, error) {
+	//nolint:gosec // periodExpr comes from the reportPeriodExprs whitelist
+	query := `
+		WITH ` + reportMeetingsCTE(periodExpr) + `,
+		n AS (
+			SELECT meeting_id, COUNT(*) AS notes, SUM(LENGTH(content)) AS chars
+			FROM notes
+			WHERE deleted_at IS NULL
+			GROUP BY meeting_id
+		)
+		SELECT m.period, COUNT(*), ROUND(SUM(m.minutes) / 60.0, 2),
+			COALESCE(SUM(n.notes), 0), COALESCE(SUM(n.chars), 0),
+			ROUND(CAST(COALESCE(SUM(n.notes), 0) AS REAL) / COUNT(*), 2),
+			SUM(m.summarized), ROUND(CAST(SUM(m.summarized) AS REAL) / COUNT(*), 2)
+		FROM m
+		LEFT JOIN n ON n.meeting_id = m.id
+		GROUP BY m.period
+		ORDER BY m.period
+	`
+
+	ctx := queryContext(r.ctx)
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate meetings: %w", err)
+	}
+	defer rows.Close()
+
+	var periods []*models.ReportPeriod
+	for rows.Next() {
+		p := &models.ReportPeriod{}
+		if err := rows.Scan(&p.Period, &p.Meetings, &p.Hours, &p.Notes, &p.NoteChars, &p.AvgNotesPerMeeting, &p.Summarized, &p.SummaryShare); err != nil {
+			return nil, fmt.Errorf("scan report period: %w", err)
+		}
+		periods = append(periods, p)
+	}
+
+	return periods, rows.Err()
+}
+
+// Keywords breaks meetings down by keyword (case-insensitive) per week or month
+func (r *ReportRepository) Keywords(from, to, group string) ([]*models.ReportBreakdown, error) {
+	return r.breakdown("keywords", "lower(item)", from, to, group)
+}
+
+// Participants breaks meetings down by participant per week or month
+func (r *ReportRepository) Participants(from, to, group string) ([]*models.ReportBreakdown, error) {
+	return r.breakdown("participants", participantKeyExpr, from, to, group)
+}
+
+// breakdown splits the comma-separated column into items with a recursive CTE
+// and aggregates the meetings per period and normalized item. column and
+// keyExpr are constants supplied by the callers above.
+func (r *ReportRepository) breakdown(column, keyExpr, from, to, group string) ([]*models.ReportBreakdown, error) {
+	periodExpr, ok := reportPeriodExprs[group]
+	if !ok {
+		return nil, fmt.Errorf("invalid report group %q", group)
+	}
+
+	//nolint:gosec // column, keyExpr and periodExpr are constants
+	query := `
+		WITH RECURSIVE ` + reportMeetingsCTE(periodExpr) + `,
+		split(id, period, minutes, item, rest) AS (
+			SELECT id, period, minutes, '', COALESCE(` + column + `, '') || ',' FROM m
+			UNION ALL
+			SELECT id, period, minutes, TRIM(substr(rest, 1, instr(rest, ',') - 1)), substr(rest, instr(rest, ',') + 1)
+			FROM split
+			WHERE rest != ''
+		),
+		items AS (
+			SELECT DISTINCT id, period, minutes, ` + keyExpr + ` AS name
+			FROM split
+			WHERE item != ''
+		)
+		SELECT period, name, COUNT(*), ROUND(SUM(minutes) / 60.0, 2)
+		FROM items
+		GROUP BY period, name
+		ORDER BY period, COUNT(*) DESC, name
+	`
+
+	ctx := queryContext(r.ctx)
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate %s: %w", column, err)
+	}
+	defer rows.Close()
+
+	var breakdown []*models.ReportBreakdown
+	for rows.Next() {
+		b := &models.ReportBreakdown{}
+		if err := rows.Scan(&b.Period, &b.Name, &b.Meetings, &b.Hours); err != nil {
+			return nil, fmt.Errorf("scan %s breakdown: %w", column, err)
+		}
+		breakdown = append(breakdown, b)
+	}
+
+	return breakdown, rows.Err()
+}
@@ -0,0 +1,179 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func seedReportMeetings(t *testing.T, meetingRepo *repositories.MeetingRepository, noteRepo *repositories.NoteRepository) {
+	t.Helper()
+
+	str := func(s string) *string { return &s }
+	fixtures := []struct {
+		meeting *models.Meeting
+		notes   []string
+	}{
+		// Week of Monday 2026-03-02
+		{&models.Meeting{Subject: "Planning", MeetingDate: "2026-03-02", StartTime: "09:00", EndTime: str("10:30"),
+			Keywords: str("Planning, Team"), Participants: str("Alice <Alice@example.com>, Bob"), Summary: str("Agreed on scope")}, []string{"a", "bb"}},
+		{&models.Meeting{Subject: "Late deploy", MeetingDate: "2026-03-08", StartTime: "23:30", EndTime: str("00:30"),
+			Keywords: str("ops, planning"), Participants: str("alice@example.com")}, []string{"ccc"}},
+		// Week of Monday 2026-03-09
+		{&models.Meeting{Subject: "Quick sync", MeetingDate: "2026-03-09", StartTime: "10:00",
+			Keywords: str("team,team"), Participants: str("BOB")}, nil},
+		// Outside the range
+		{&models.Meeting{Subject: "April", MeetingDate: "2026-04-01", StartTime: "10:00", EndTime: str("11:00")}, nil},
+	}
+
+	for _, f := range fixtures {
+		f.meeting.CreatedBy = "test@example.com"
+		if err := meetingRepo.Create(f.meeting); err != nil {
+			t.Fatalf("create meeting failed: %v", err)
+		}
+		for _, content := range f.notes {
+			if err := noteRepo.Create(&models.Note{MeetingID: f.meeting.ID, Content: content}); err != nil {
+				t.Fatalf("create note failed: %v", err)
+			}
+		}
+	}
+}
+
+func TestReportRepository_Periods(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	seedReportMeetings(t, repositories.NewMeetingRepository(database.DB), repositories.NewNoteRepository(database.DB))
+	repo := repositories.NewReportRepository(database.DB)
+
+	weeks, err := repo.Periods("2026-03-01", "2026-03-31", repositories.ReportGroupWeek)
+	if err != nil {
+		t.Fatalf("periods failed: %v", err)
+	}
+	if len(weeks) != 2 {
+		t.Fatalf("expected 2 weeks, got %d", len(weeks))
+	}
+
+	first := weeks[0]
+	if first.Period != "2026-03-02" || first.Meetings != 2 || first.Hours != 2.5 {
+		t.Errorf("unexpected first week %+v", first)
+	}
+	if first.Notes != 3 || first.NoteChars != 6 || first.AvgNotesPerMeeting != 1.5 {
+		t.Errorf("unexpected note volume %+v", first)
+	}
+	if first.Summarized != 1 || first.SummaryShare != 0.5 {
+		t.Errorf("unexpected summary share %+v", first)
+	}
+	if weeks[1].Period != "2026-03-09" || weeks[1].Hours != 0 {
+		t.Errorf("expected meeting without end time to count 0 hours, got %+v", weeks[1])
+	}
+
+	months, err := repo.Periods("2026-03-01", "2026-04-30", repositories.ReportGroupMonth)
+	if err != nil {
+		t.Fatalf("periods failed: %v", err)
+	}
+	if len(months) != 2 || months[0].Period != "2026-03-01" || months[0].Meetings != 3 || months[1].Period != "2026-04-01" {
+		t.Errorf("unexpected months %+v %+v", months[0], months[len(months)-1])
+	}
+
+	totals, err := repo.Totals("2026-03-01", "2026-03-31")
+	if err != nil {
+		t.Fatalf("totals failed: %v", err)
+	}
+	if totals.Meetings != 3 || totals.Hours != 2.5 || totals.Notes != 3 {
+		t.Errorf("unexpected totals %+v", totals)
+	}
+
+	if _, err := repo.Periods("2026-03-01", "2026-03-31", "day"); err == nil {
+		t.Error("expected error for unsupported group")
+	}
+}
+
+func TestReportRepository_SkipsTrash(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meetingRepo := repositories.NewMeetingRepository(database.DB)
+	noteRepo := repositories.NewNoteRepository(database.DB)
+	seedReportMeetings(t, meetingRepo, noteRepo)
+	repo := repositories.NewReportRepository(database.DB)
+
+	keywords := "trashed"
+	end := "12:00"
+	trashed := &models.Meeting{CreatedBy: "test@example.com", Subject: "Trashed", MeetingDate: "2026-03-03", StartTime: "10:00", EndTime: &end, Keywords: &keywords}
+	if err := meetingRepo.Create(trashed); err != nil {
+		t.Fatalf("create meeting failed: %v", err)
+	}
+	if err := noteRepo.Create(&models.Note{MeetingID: trashed.ID, Content: "gone"}); err != nil {
+		t.Fatalf("create note failed: %v", err)
+	}
+	if err := meetingRepo.Delete(trashed.ID); err != nil {
+		t.Fatalf("delete meeting failed: %v", err)
+	}
+
+	meetings, err := meetingRepo.Search("Planning")
+	if err != nil || len(meetings) == 0 {
+		t.Fatalf("search failed: %v", err)
+	}
+	notes, err := noteRepo.ListByMeeting(meetings[0].ID)
+	if err != nil || len(notes) == 0 {
+		t.Fatalf("list notes failed: %v", err)
+	}
+	if err := noteRepo.Delete(notes[0].ID); err != nil {
+		t.Fatalf("delete note failed: %v", err)
+	}
+
+	totals, err := repo.Totals("2026-03-01", "2026-03-31")
+	if err != nil {
+		t.Fatalf("totals failed: %v", err)
+	}
+	if totals.Meetings != 3 || totals.Hours != 2.5 || totals.Notes != 2 {
+		t.Errorf("expected the trashed meeting and note to be left out, got %+v", totals)
+	}
+
+	breakdown, err := repo.Keywords("2026-03-01", "2026-03-31", repositories.ReportGroupMonth)
+	if err != nil {
+		t.Fatalf("keywords failed: %v", err)
+	}
+	for _, k := range breakdown {
+		if k.Name == "trashed" {
+			t.Errorf("expected the keywords of the trashed meeting to be left out, got %+v", k)
+		}
+	}
+}
+
+func TestReportRepository_Breakdowns(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	seedReportMeetings(t, repositories.NewMeetingRepository(database.DB), repositories.NewNoteRepository(database.DB))
+	repo := repositories.NewReportRepository(database.DB)
+
+	keywords, err := repo.Keywords("2026-03-01", "2026-03-31", repositories.ReportGroupMonth)
+	if err != nil {
+		t.Fatalf("keywords failed: %v", err)
+	}
+	got := map[string]*models.ReportBreakdown{}
+	for _, k := range keywords {
+		got[k.Name] = k
+	}
+	if got["planning"] == nil || got["planning"].Meetings != 2 || got["planning"].Hours != 2.5 {
+		t.Errorf("expected planning across 2 meetings and 2.5 hours, got %+v", got["planning"])
+	}
+	if got["team"] == nil || got["team"].Meetings != 2 {
+		t.Errorf("expected duplicate keyword counted once per meeting, got %+v", got["team"])
+	}
+	if keywords[0].Name != "planning" {
+		t.Errorf("expected busiest keyword first, got %q", keywords[0].Name)
+	}
+
+	participants, err := repo.Participants("2026-03-01", "2026-03-31", repositories.ReportGroupMonth)
+	if err != nil {
+		t.Fatalf("participants failed: %v", err)
+	}
+	if len(participants) != 2 || participants[0].Name != "alice@example.com" || participants[0].Meetings != 2 ||
+		participants[1].Name != "bob" || participants[1].Meetings != 2 {
+		t.Errorf("unexpected participants %+v", participants)
+	}
+}
@@ -0,0 +1,288 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// shareTokenBytes is the entropy of a share token
+const shareTokenBytes = 32
+
+// shareColumns is the column list shared by all share SELECTs, in scanShare order
+const shareColumns = `id, meeting_id, include_notes, created_by, created_at, expires_at, revoked_at, last_accessed_at, access_count`
+
+// ShareRepository handles public meeting shares and their audit trail
+type ShareRepository struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
+// NewShareRepository creates a new share repository
+func NewShareRepository(db *sql.DB) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+// WithContext returns a copy of the repository that runs its queries with ctx
+func (r *ShareRepository) WithContext(ctx context.Context) *ShareRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// NewShareToken returns a random, URL-safe share token
+func NewShareToken() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the stored form of a share or API token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// scanShare scans a row selected with shareColumns
+func scanShare(row rowScanner) (*models.MeetingShare, error) {
+	s := &models.MeetingShare{}
+	var expiresAt, revokedAt, lastAccessedAt sql.NullString
+	err := row.Scan(&s.ID, &s.MeetingID, &s.IncludeNotes, &s.CreatedBy, &s.CreatedAt, &expiresAt, &revokedAt, &lastAccessedAt, &s.AccessCount)
+	if err != nil {
+		return nil, err
+	}
+
+	expires, err := parseUTCColumn(expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("share %d expires_at: %w", s.ID, err)
+	}
+	if expires == nil {
+		return nil, fmt.Errorf("share %d has no expires_at", s.ID)
+	}
+	s.ExpiresAt = *expires
+	if s.RevokedAt, err = parseUTCColumn(revokedAt); err != nil {
+		return nil, fmt.Errorf("share %d revoked_at: %w", s.ID, err)
+	}
+	if s.LastAccessedAt, err = parseUTCColumn(lastAccessedAt); err != nil {
+		return nil, fmt.Errorf("share %d last_accessed_at: %w", s.ID, err)
+	}
+	return s, nil
+}
+
+// insertShareEvent appends an entry to the share audit trail
+func insertShareEvent(ctx context.Context, tx *sql.Tx, s *models.MeetingShare, event, actor, userAgent string) error {
+	var agent any
+	if userAgent != "" {
+		agent = userAgent
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO share_events (share_id, meeting_id, event, actor, user_agent)
+		VALUES (?, ?, ?, ?, ?)
+	`, s.ID, s.MeetingID, event, actor, agent)
+	if err != nil {
+		return fmt.Errorf("record share event: %w", err)
+	}
+	return nil
+}
+
+// Create stores a share for token and records who created it
+func (r *ShareRepository) Create(s *models.MeetingShare, token string) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	expiresAt := s.ExpiresAt.UTC()
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO meeting_shares (meeting_id, token_hash, include_notes, created_by, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, s.MeetingID, hashToken(token), s.IncludeNotes, s.CreatedBy, utcColumn(&expiresAt))
+	if err != nil {
+		return fmt.Errorf("create share: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	s.ID = int(id)
+
+	if err := insertShareEvent(ctx, tx, s, models.ShareEventCreated, s.CreatedBy, ""); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	created, err := r.GetByID(s.ID)
+	if err != nil {
+		return err
+	}
+	*s = *created
+	return nil
+}
+
+// GetByID retrieves a share by ID
+func (r *ShareRepository) GetByID(id int) (*models.MeetingShare, error) {
+	ctx := queryContext(r.ctx)
+	row := r.db.QueryRowContext(ctx, `SELECT `+shareColumns+` FROM meeting_shares WHERE id = ?`, id)
+	s, err := scanShare(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get share: %w", err)
+	}
+	return s, nil
+}
+
+// GetByToken retrieves the share a token belongs to, whether active or not
+func (r *ShareRepository) GetByToken(token string) (*models.MeetingShare, error) {
+	ctx := queryContext(r.ctx)
+	row := r.db.QueryRowContext(ctx, `SELECT `+shareColumns+` FROM meeting_shares WHERE token_hash = ?`, hashToken(token))
+	s, err := scanShare(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get share: %w", err)
+	}
+	return s, nil
+}
+
+// ListByMeeting lists all shares of a meeting, newest first
+func (r *ShareRepository) ListByMeeting(meetingID int) ([]*models.MeetingShare, error) {
+	ctx := queryContext(r.ctx)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+shareColumns+` FROM meeting_shares
+		WHERE meeting_id = ?
+		ORDER BY id DESC
+	`, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("list shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []*models.MeetingShare{}
+	for rows.Next() {
+		s, err := scanShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan share: %w", err)
+		}
+		shares = append(shares, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return shares, nil
+}
+
+// Revoke disables a share and records who revoked it. Revoking a share
+// twice keeps the first revocation time.
+func (r *ShareRepository) Revoke(s *models.MeetingShare, actor string) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE meeting_shares SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL
+	`, utcColumn(&now), s.ID)
+	if err != nil {
+		return fmt.Errorf("revoke share: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if affected == 0 {
+		// Already revoked
+		return nil
+	}
+
+	if err := insertShareEvent(ctx, tx, s, models.ShareEventRevoked, actor, ""); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	s.RevokedAt = &now
+	return nil
+}
+
+// RecordAccess counts a public access to a share and adds it to the trail
+func (r *ShareRepository) RecordAccess(s *models.MeetingShare, remoteAddr, userAgent string) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE meeting_shares SET access_count = access_count + 1, last_accessed_at = ? WHERE id = ?
+	`, utcColumn(&now), s.ID)
+	if err != nil {
+		return fmt.Errorf("record share access: %w", err)
+	}
+
+	if err := insertShareEvent(ctx, tx, s, models.ShareEventAccessed, remoteAddr, userAgent); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	s.AccessCount++
+	s.LastAccessedAt = &now
+	return nil
+}
+
+// ListEvents lists the audit trail of a share, oldest first
+func (r *ShareRepository) ListEvents(shareID int) ([]*models.ShareEvent, error) {
+	ctx := queryContext(r.ctx)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, share_id, meeting_id, event, actor, user_agent, created_at
+		FROM share_events
+		WHERE share_id = ?
+		ORDER BY id ASC
+	`, shareID)
+	if err != nil {
+		return nil, fmt.Errorf("list share events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.ShareEvent{}
+	for rows.Next() {
+		e := &models.ShareEvent{}
+		if err := rows.Scan(&e.ID, &e.ShareID, &e.MeetingID, &e.Event, &e.Actor, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan share event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return events, nil
+}
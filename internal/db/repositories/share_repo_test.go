@@ -0,0 +1,127 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func TestShareRepository_Lifecycle(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meetingRepo := repositories.NewMeetingRepository(database.DB)
+	meeting := &models.Meeting{
+		CreatedBy:   "test@example.com",
+		Subject:     "Shared Meeting",
+		MeetingDate: "2026-02-14",
+		StartTime:   "10:00",
+	}
+	if err := meetingRepo.Create(meeting); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+
+	token, err := repositories.NewShareToken()
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	repo := repositories.NewShareRepository(database.DB)
+	share := &models.MeetingShare{
+		MeetingID: meeting.ID,
+		CreatedBy: "alice@example.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if err := repo.Create(share, token); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if share.ID == 0 || !share.Active(time.Now()) {
+		t.Fatalf("unexpected share %+v", share)
+	}
+
+	found, err := repo.GetByToken(token)
+	if err != nil || found == nil {
+		t.Fatalf("get by token failed: %v", err)
+	}
+	if found.ID != share.ID {
+		t.Errorf("GetByToken() returned share %d, want %d", found.ID, share.ID)
+	}
+	if missing, err := repo.GetByToken("unknown"); err != nil || missing != nil {
+		t.Errorf("GetByToken(unknown) = %v, %v", missing, err)
+	}
+
+	if err := repo.RecordAccess(found, "203.0.113.7", "curl/8.0"); err != nil {
+		t.Fatalf("record access failed: %v", err)
+	}
+	if err := repo.Revoke(found, "bob@example.com"); err != nil {
+		t.Fatalf("revoke failed: %v", err)
+	}
+	if err := repo.Revoke(found, "bob@example.com"); err != nil {
+		t.Fatalf("second revoke failed: %v", err)
+	}
+
+	shares, err := repo.ListByMeeting(meeting.ID)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(shares) != 1 || shares[0].AccessCount != 1 || shares[0].LastAccessedAt == nil || shares[0].Active(time.Now()) {
+		t.Errorf("unexpected shares %+v", shares)
+	}
+
+	events, err := repo.ListEvents(share.ID)
+	if err != nil {
+		t.Fatalf("list events failed: %v", err)
+	}
+	want := []struct{ event, actor string }{
+		{models.ShareEventCreated, "alice@example.com"},
+		{models.ShareEventAccessed, "203.0.113.7"},
+		{models.ShareEventRevoked, "bob@example.com"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(events))
+	}
+	for i, w := range want {
+		if events[i].Event != w.event || events[i].Actor != w.actor {
+			t.Errorf("event %d = %s by %s, want %s by %s", i, events[i].Event, events[i].Actor, w.event, w.actor)
+		}
+	}
+
+	// The audit trail outlives the meeting
+	if err := meetingRepo.Delete(meeting.ID); err != nil {
+		t.Fatalf("failed to delete meeting: %v", err)
+	}
+	if err := meetingRepo.Purge(meeting.ID); err != nil {
+		t.Fatalf("failed to purge meeting: %v", err)
+	}
+	if gone, err := repo.GetByID(share.ID); err != nil || gone != nil {
+		t.Errorf("expected share to be deleted with its meeting, got %v, %v", gone, err)
+	}
+	if events, err := repo.ListEvents(share.ID); err != nil || len(events) != 3 {
+		t.Errorf("expected events to be kept, got %d, %v", len(events), err)
+	}
+}
+
+func TestMeetingShare_Active(t *testing.T) {
+	now := time.Now()
+	revoked := now.Add(-time.Minute)
+
+	tests := []struct {
+		name  string
+		share models.MeetingShare
+		want  bool
+	}{
+		{name: "active", share: models.MeetingShare{ExpiresAt: now.Add(time.Hour)}, want: true},
+		{name: "expired", share: models.MeetingShare{ExpiresAt: now.Add(-time.Hour)}, want: false},
+		{name: "revoked", share: models.MeetingShare{ExpiresAt: now.Add(time.Hour), RevokedAt: &revoked}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.share.Active(now); got != tt.want {
+				t.Errorf("Active() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+var (
+	// ErrNotInTrash is returned when restoring or purging an item that is
+	// not in the trash
+	ErrNotInTrash = errors.New("not in trash")
+	// ErrRestoreConflict is returned when a restored meeting has the calendar
+	// identity of a meeting created since it was trashed
+	ErrRestoreConflict = errors.New("a meeting for the same calendar event exists")
+)
+
+// deletedAtFormat is the fixed-width UTC format of deleted_at. Notes trashed
+// with their meeting are matched by equal deleted_at, so it is precise
+// enough to tell apart deletions within the same second.
+const deletedAtFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// trashTime returns the deleted_at value for items trashed now
+func trashTime() string {
+	return time.Now().UTC().Format(deletedAtFormat)
+}
+
+// TrashRepository lists and purges trashed meetings and notes
+type TrashRepository struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
+// NewTrashRepository creates a new trash repository
+func NewTrashRepository(db *sql.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// WithContext returns a copy of the repository that runs its queries with ctx
+func (r *TrashRepository) WithContext(ctx context.Context) *TrashRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// getTrashedMeeting retrieves a meeting in the trash by ID within tx
+func getTrashedMeeting(ctx context.Context, tx *sql.Tx, id int) (*models.Meeting, error) {
+	m, err := scanMeeting(tx.QueryRowContext(ctx, `SELECT `+meetingColumns+` FROM meetings WHERE id = ? AND deleted_at IS NOT NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotInTrash
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get meeting: %w", err)
+	}
+	return m, nil
+}
+
+// getTrashedNote retrieves a note in the trash by ID within tx. Notes
+// trashed with their meeting are only reachable through the meeting.
+func getTrashedNote(ctx context.Context, tx *sql.Tx, id int) (*models.Note, error) {
+	n, err := scanNote(tx.QueryRowContext(ctx, `
+		SELECT `+noteColumns+` FROM notes
+		WHERE id = ? AND deleted_at IS NOT NULL
+			AND meeting_id IN (SELECT id FROM meetings WHERE deleted_at IS NULL)
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotInTrash
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get note: %w", err)
+	}
+	return n, nil
+}
+
+// Restore takes a meeting out of the trash together with the notes trashed
+// with it; their note numbers are unchanged
+func (r *MeetingRepository) Restore(id int) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	before, err := getTrashedMeeting(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE meetings SET deleted_at = NULL WHERE id = ?", id); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return ErrRestoreConflict
+		}
+		return fmt.Errorf("restore meeting: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE notes SET deleted_at = NULL WHERE meeting_id = ? AND deleted_at = ?",
+		id, before.DeletedAt.Format(deletedAtFormat))
+	if err != nil {
+		return fmt.Errorf("restore notes: %w", err)
+	}
+
+	if err := r.audit(ctx, tx, models.AuditActionRestore, before, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// Purge permanently deletes a meeting in the trash with all its notes
+func (r *MeetingRepository) Purge(id int) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := r.purge(ctx, tx, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// purge deletes a trashed meeting (and its notes via CASCADE) within tx
+func (r *MeetingRepository) purge(ctx context.Context, tx *sql.Tx, id int) error {
+	before, err := getTrashedMeeting(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM meetings WHERE id = ?", id); err != nil {
+		return fmt.Errorf("purge meeting: %w", err)
+	}
+	return r.audit(ctx, tx, models.AuditActionPurge, before, id)
+}
+
+// Restore takes a note out of the trash. The note keeps its number, so it
+// returns to its old position among the meeting's notes.
+func (r *NoteRepository) Restore(id int) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	before, err := getTrashedNote(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE notes SET deleted_at = NULL WHERE id = ?", id); err != nil {
+		return fmt.Errorf("restore note: %w", err)
+	}
+
+	after, err := getNote(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if err := auditNote(ctx, tx, models.AuditActionRestore, before, after); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// Purge permanently deletes a note in the trash
+func (r *NoteRepository) Purge(id int) error {
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := purgeNote(ctx, tx, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// purgeNote deletes a trashed note within tx
+func purgeNote(ctx context.Context, tx *sql.Tx, id int) error {
+	before, err := getTrashedNote(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM notes WHERE id = ?", id); err != nil {
+		return fmt.Errorf("purge note: %w", err)
+	}
+	return auditNote(ctx, tx, models.AuditActionPurge, before, nil)
+}
+
+// List returns the trashed meetings and the trashed notes of meetings
+// outside the trash, most recently deleted first
+func (r *TrashRepository) List() ([]*models.TrashItem, error) {
+	ctx := queryContext(r.ctx)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT 'meeting', m.id, m.id, m.subject, m.meeting_date, 0, '', m.deleted_at,
+			(SELECT COUNT(*) FROM notes n WHERE n.meeting_id = m.id AND n.deleted_at = m.deleted_at)
+		FROM meetings m
+		WHERE m.deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'note', n.id, n.meeting_id, m.subject, m.meeting_date, n.note_number, n.content, n.deleted_at, 0
+		FROM notes n JOIN meetings m ON m.id = n.meeting_id
+		WHERE n.deleted_at IS NOT NULL AND m.deleted_at IS NULL
+		ORDER BY 8 DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list trash: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*models.TrashItem{}
+	for rows.Next() {
+		item := &models.TrashItem{}
+		var deletedAt string
+		if err := rows.Scan(&item.Type, &item.ID, &item.MeetingID, &item.Subject, &item.MeetingDate,
+			&item.NoteNumber, &item.Content, &deletedAt, &item.Notes); err != nil {
+			return nil, fmt.Errorf("scan trash item: %w", err)
+		}
+		if item.DeletedAt, err = time.Parse(time.RFC3339, deletedAt); err != nil {
+			return nil, fmt.Errorf("%s %d deleted_at: %w", item.Type, item.ID, err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return items, nil
+}
+
+// PurgeDeletedBefore permanently deletes the meetings and notes trashed
+// before cutoff and returns how many items were purged
+func (r *TrashRepository) PurgeDeletedBefore(cutoff time.Time) (int, error) {
+	items, err := r.List()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := queryContext(r.ctx)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	meetings := &MeetingRepository{db: r.db}
+	purged := 0
+	for _, item := range items {
+		if !item.DeletedAt.Before(cutoff) {
+			continue
+		}
+		if item.Type == models.TrashTypeMeeting {
+			err = meetings.purge(ctx, tx, item.ID)
+		} else {
+			err = purgeNote(ctx, tx, item.ID)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("purge %s %d: %w", item.Type, item.ID, err)
+		}
+		purged++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return purged, nil
+}
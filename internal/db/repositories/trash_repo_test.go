@@ -0,0 +1,171 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func TestTrash_DeleteRestorePurge(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meetings := repositories.NewMeetingRepository(database.DB)
+	notes := repositories.NewNoteRepository(database.DB)
+	trash := repositories.NewTrashRepository(database.DB)
+
+	m := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Standup", MeetingDate: "2026-03-01", StartTime: "09:00"}
+	if err := meetings.Create(m); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	var created []*models.Note
+	for _, content := range []string{"one", "two", "three"} {
+		n := &models.Note{MeetingID: m.ID, Content: content}
+		if err := notes.Create(n); err != nil {
+			t.Fatalf("create note: %v", err)
+		}
+		created = append(created, n)
+	}
+
+	// A note trashed on its own stays in the trash when the meeting is restored
+	if err := notes.Delete(created[1].ID); err != nil {
+		t.Fatalf("delete note: %v", err)
+	}
+	if err := meetings.Delete(m.ID); err != nil {
+		t.Fatalf("delete meeting: %v", err)
+	}
+	if got, _ := meetings.GetByID(m.ID); got != nil {
+		t.Error("expected trashed meeting to be hidden")
+	}
+	if list, _ := meetings.List("meeting_date", false); len(list) != 0 {
+		t.Errorf("expected trashed meeting to be excluded from the list, got %d", len(list))
+	}
+	if found, _ := meetings.Search("Standup"); len(found) != 0 {
+		t.Errorf("expected trashed meeting to be excluded from search, got %d", len(found))
+	}
+
+	items, err := trash.List()
+	if err != nil {
+		t.Fatalf("list trash: %v", err)
+	}
+	if len(items) != 1 || items[0].Type != models.TrashTypeMeeting || items[0].Notes != 2 || items[0].Subject != "Standup" {
+		t.Fatalf("unexpected trash %+v", items)
+	}
+	if err := notes.Restore(created[1].ID); !errors.Is(err, repositories.ErrNotInTrash) {
+		t.Errorf("expected note of a trashed meeting not to be restorable, got %v", err)
+	}
+
+	if err := meetings.Restore(m.ID); err != nil {
+		t.Fatalf("restore meeting: %v", err)
+	}
+	live, err := notes.ListByMeeting(m.ID)
+	if err != nil || len(live) != 2 || live[0].NoteNumber != 1 || live[1].NoteNumber != 3 {
+		t.Fatalf("unexpected notes after restoring the meeting: %+v, %v", live, err)
+	}
+	if items, _ = trash.List(); len(items) != 1 || items[0].Type != models.TrashTypeNote || items[0].NoteNumber != 2 || items[0].Content != "two" {
+		t.Fatalf("unexpected trash %+v", items)
+	}
+
+	if err := notes.Restore(created[1].ID); err != nil {
+		t.Fatalf("restore note: %v", err)
+	}
+	live, _ = notes.ListByMeeting(m.ID)
+	if len(live) != 3 || live[1].Content != "two" || live[1].NoteNumber != 2 {
+		t.Errorf("expected restored note back in place, got %+v", live)
+	}
+	next := &models.Note{MeetingID: m.ID, Content: "four"}
+	if err := notes.Create(next); err != nil || next.NoteNumber != 4 {
+		t.Errorf("expected next note number 4, got %d, %v", next.NoteNumber, err)
+	}
+
+	// Only trashed items can be purged
+	if err := notes.Purge(created[0].ID); !errors.Is(err, repositories.ErrNotInTrash) {
+		t.Errorf("expected purging a live note to fail, got %v", err)
+	}
+	if err := meetings.Purge(m.ID); !errors.Is(err, repositories.ErrNotInTrash) {
+		t.Errorf("expected purging a live meeting to fail, got %v", err)
+	}
+	if err := notes.Delete(created[0].ID); err != nil {
+		t.Fatalf("delete note: %v", err)
+	}
+	if err := notes.Purge(created[0].ID); err != nil {
+		t.Fatalf("purge note: %v", err)
+	}
+	if items, _ = trash.List(); len(items) != 0 {
+		t.Errorf("expected empty trash, got %+v", items)
+	}
+}
+
+func TestTrash_RestoreConflict(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meetings := repositories.NewMeetingRepository(database.DB)
+	uid := "event-1@example.com"
+	old := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Old", MeetingDate: "2026-03-01", StartTime: "09:00", ICalUID: &uid}
+	if err := meetings.Create(old); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	if err := meetings.Delete(old.ID); err != nil {
+		t.Fatalf("delete meeting: %v", err)
+	}
+
+	// Re-importing the event creates a new meeting
+	if found, _ := meetings.GetByICalUID(uid, ""); found != nil {
+		t.Fatalf("expected trashed meeting to be ignored, got %+v", found)
+	}
+	reimported := &models.Meeting{CreatedBy: "alice@example.com", Subject: "New", MeetingDate: "2026-03-01", StartTime: "09:00", ICalUID: &uid}
+	if err := meetings.Create(reimported); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	if err := meetings.Restore(old.ID); !errors.Is(err, repositories.ErrRestoreConflict) {
+		t.Errorf("expected restore conflict, got %v", err)
+	}
+}
+
+func TestTrash_PurgeDeletedBefore(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	meetings := repositories.NewMeetingRepository(database.DB)
+	notes := repositories.NewNoteRepository(database.DB)
+	trash := repositories.NewTrashRepository(database.DB)
+
+	m := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Standup", MeetingDate: "2026-03-01", StartTime: "09:00"}
+	if err := meetings.Create(m); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	kept := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Retro", MeetingDate: "2026-03-02", StartTime: "09:00"}
+	if err := meetings.Create(kept); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	n := &models.Note{MeetingID: kept.ID, Content: "draft"}
+	if err := notes.Create(n); err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+	if err := meetings.Delete(m.ID); err != nil {
+		t.Fatalf("delete meeting: %v", err)
+	}
+	if err := notes.Delete(n.ID); err != nil {
+		t.Fatalf("delete note: %v", err)
+	}
+
+	if purged, err := trash.PurgeDeletedBefore(time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+		t.Errorf("expected nothing to purge yet, got %d, %v", purged, err)
+	}
+	purged, err := trash.PurgeDeletedBefore(time.Now().Add(time.Hour))
+	if err != nil || purged != 2 {
+		t.Fatalf("expected 2 purged items, got %d, %v", purged, err)
+	}
+	if items, _ := trash.List(); len(items) != 0 {
+		t.Errorf("expected empty trash, got %+v", items)
+	}
+
+	entries, err := repositories.NewAuditRepository(database.DB).List(repositories.AuditFilter{Action: models.AuditActionPurge})
+	if err != nil || len(entries) != 2 || entries[0].Actor != models.AuditSystemActor {
+		t.Errorf("unexpected purge audit entries %+v, %v", entries, err)
+	}
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import "errors"
+
+// ErrVersionConflict is returned when a conditional update or delete finds
+// the row at a different version than the caller expected
+var ErrVersionConflict = errors.New("version conflict")
+
+// IfVersion returns a copy of the repository whose Update and Delete only
+// apply while the meeting is still at version; 0 applies them unconditionally
+func (r *MeetingRepository) IfVersion(version int) *MeetingRepository {
+	c := *r
+	c.version = version
+	return &c
+}
+
+// IfVersion returns a copy of the repository whose Update and Delete only
+// apply while the note is still at version; 0 applies them unconditionally
+func (r *NoteRepository) IfVersion(version int) *NoteRepository {
+	c := *r
+	c.version = version
+	return &c
+}
+
+// checkVersion compares the version a row was read at within the write
+// transaction with the expected one
+func checkVersion(expected, actual int) error {
+	if expected != 0 && expected != actual {
+		return ErrVersionConflict
+	}
+	return nil
+}
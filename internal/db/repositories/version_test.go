@@ -0,0 +1,77 @@
+package repositories_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func TestIfVersion_Meeting(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	repo := repositories.NewMeetingRepository(database.DB)
+	m := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Standup", MeetingDate: "2026-03-01", StartTime: "09:00", Timezone: "UTC"}
+	if err := repo.Create(m); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	got, err := repo.GetByID(m.ID)
+	if err != nil || got.Version != 1 {
+		t.Fatalf("expected version 1, got %+v (err %v)", got, err)
+	}
+
+	got.Subject = "Daily standup"
+	if err := repo.IfVersion(1).Update(got); err != nil {
+		t.Fatalf("update at current version: %v", err)
+	}
+	if got, _ = repo.GetByID(m.ID); got.Version != 2 {
+		t.Fatalf("expected version 2 after update, got %d", got.Version)
+	}
+
+	got.Subject = "Stale edit"
+	if err := repo.IfVersion(1).Update(got); !errors.Is(err, repositories.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict for a stale update, got %v", err)
+	}
+	if err := repo.IfVersion(1).Delete(m.ID); !errors.Is(err, repositories.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict for a stale delete, got %v", err)
+	}
+	if got, _ = repo.GetByID(m.ID); got == nil || got.Subject != "Daily standup" {
+		t.Fatalf("expected meeting unchanged by stale writes, got %+v", got)
+	}
+
+	if err := repo.IfVersion(2).Delete(m.ID); err != nil {
+		t.Errorf("delete at current version: %v", err)
+	}
+}
+
+func TestIfVersion_Note(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	m := &models.Meeting{CreatedBy: "alice@example.com", Subject: "Standup", MeetingDate: "2026-03-01", StartTime: "09:00", Timezone: "UTC"}
+	if err := repositories.NewMeetingRepository(database.DB).Create(m); err != nil {
+		t.Fatalf("create meeting: %v", err)
+	}
+	repo := repositories.NewNoteRepository(database.DB)
+	n := &models.Note{MeetingID: m.ID, Content: "first"}
+	if err := repo.Create(n); err != nil {
+		t.Fatalf("create note: %v", err)
+	}
+
+	if err := repo.IfVersion(1).Update(&models.Note{ID: n.ID, Content: "second"}); err != nil {
+		t.Fatalf("update at current version: %v", err)
+	}
+	if err := repo.IfVersion(1).Update(&models.Note{ID: n.ID, Content: "stale"}); !errors.Is(err, repositories.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict for a stale update, got %v", err)
+	}
+	if err := repo.Update(&models.Note{ID: n.ID, Content: "third"}); err != nil {
+		t.Errorf("unconditional update: %v", err)
+	}
+
+	got, err := repo.GetByID(n.ID)
+	if err != nil || got.Content != "third" || got.Version != 3 {
+		t.Errorf("expected third content at version 3, got %+v (err %v)", got, err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package events implements the in-process bus the web server publishes
+// domain events on after successful writes. Subscribers receive them in
+// order and can resume after a reconnect from a bounded log of recent events.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types published by the web server
+const (
+	NoteCreated      = "note.created"
+	NoteUpdated      = "note.updated"
+	NoteReordered    = "note.reordered"
+	NoteDeleted      = "note.deleted"
+	MeetingCreated   = "meeting.created"
+	MeetingUpdated   = "meeting.updated"
+	MeetingDeleted   = "meeting.deleted"
+	SummaryGenerated = "summary.generated"
+	PresenceUpdated  = "presence.updated"
+	NoteLocked       = "note.locked"
+	NoteUnlocked     = "note.unlocked"
+)
+
+// DefaultLogSize is the number of recent events kept for resuming
+const DefaultLogSize = 1000
+
+// subscriberBuffer is the number of events a subscriber may lag behind
+// before it is dropped
+const subscriberBuffer = 64
+
+// Event is a change to a meeting or one of its notes
+type Event struct {
+	// ID increases with every event. IDs of a new process start above those
+	// of earlier ones, so a stale Last-Event-ID is never mistaken for a
+	// current one.
+	ID        uint64    `json:"id"`
+	Type      string    `json:"type"`
+	MeetingID int       `json:"meeting_id"`
+	Actor     string    `json:"actor"`
+	Time      time.Time `json:"time"`
+	// Data is the changed entity, or identifiers for deletions
+	Data any `json:"data,omitempty"`
+}
+
+// Bus fans published events out to subscribers
+type Bus struct {
+	mu     sync.Mutex
+	nextID uint64
+	// log is a ring of the most recent events, oldest at start
+	log    []Event
+	start  int
+	size   int
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription receives the events published after it was created
+type Subscription struct {
+	bus *Bus
+	ch  chan Event
+}
+
+// NewBus creates a bus that keeps the last logSize events for resuming.
+// A logSize of 0 or less uses DefaultLogSize.
+func NewBus(logSize int) *Bus {
+	if logSize <= 0 {
+		logSize = DefaultLogSize
+	}
+	return &Bus{
+		nextID: uint64(time.Now().UnixMicro()), //nolint:gosec // Unix time is positive
+		log:    make([]Event, logSize),
+		subs:   map[*Subscription]struct{}{},
+	}
+}
+
+// Publish assigns e its ID and time, records it in the log and delivers it
+// to all subscribers. Subscribers whose buffer is full are dropped; they
+// resume from the log when they reconnect.
+func (b *Bus) Publish(e Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	e.ID = b.nextID
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if b.closed {
+		return e
+	}
+
+	b.log[(b.start+b.size)%len(b.log)] = e
+	if b.size < len(b.log) {
+		b.size++
+	} else {
+		b.start = (b.start + 1) % len(b.log)
+	}
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- e:
+		default:
+			b.drop(sub)
+		}
+	}
+	return e
+}
+
+// Subscribe registers a subscriber. When lastID is not 0, the events
+// published after it are returned as backlog; complete is false if some
+// of them are no longer in the log or lastID is unknown, in which case
+// the subscriber has to reload its state.
+func (b *Bus) Subscribe(lastID uint64) (sub *Subscription, backlog []Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{bus: b, ch: make(chan Event, subscriberBuffer)}
+	if b.closed {
+		close(sub.ch)
+		return sub, nil, lastID == 0
+	}
+	b.subs[sub] = struct{}{}
+
+	if lastID == 0 {
+		return sub, nil, true
+	}
+	if lastID > b.nextID {
+		return sub, nil, false
+	}
+
+	// the log holds the events oldest..nextID without gaps
+	oldest := b.nextID - uint64(b.size) + 1 //nolint:gosec // size is never negative
+	for i := range b.size {
+		if e := b.log[(b.start+i)%len(b.log)]; e.ID > lastID {
+			backlog = append(backlog, e)
+		}
+	}
+	return sub, backlog, lastID+1 >= oldest
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription is closed, the subscriber falls too far behind or the bus
+// shuts down.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Close unregisters the subscription
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.drop(s)
+}
+
+// drop removes sub and closes its channel; b.mu must be held
+func (b *Bus) drop(sub *Subscription) {
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Close ends all subscriptions. Events published afterwards are discarded.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		b.drop(sub)
+	}
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func publishN(b *Bus, n int) []Event {
+	published := make([]Event, n)
+	for i := range n {
+		published[i] = b.Publish(Event{Type: NoteUpdated, MeetingID: i})
+	}
+	return published
+}
+
+func TestBus_PublishDelivers(t *testing.T) {
+	b := NewBus(10)
+	sub, backlog, complete := b.Subscribe(0)
+	defer sub.Close()
+	if len(backlog) != 0 || !complete {
+		t.Fatalf("fresh subscription: backlog %v, complete %v", backlog, complete)
+	}
+
+	published := publishN(b, 3)
+	for i, want := range published {
+		got := <-sub.Events()
+		if got.ID != want.ID || got.MeetingID != i {
+			t.Errorf("event %d = %+v, want %+v", i, got, want)
+		}
+		if got.Time.IsZero() {
+			t.Errorf("event %d has no time", i)
+		}
+	}
+	if published[1].ID != published[0].ID+1 || published[2].ID != published[1].ID+1 {
+		t.Errorf("IDs are not consecutive: %d %d %d", published[0].ID, published[1].ID, published[2].ID)
+	}
+}
+
+func TestBus_Resume(t *testing.T) {
+	b := NewBus(5)
+	published := publishN(b, 8)
+	last := published[len(published)-1].ID
+
+	tests := []struct {
+		name         string
+		lastID       uint64
+		wantBacklog  int
+		wantComplete bool
+	}{
+		{name: "up to date", lastID: last, wantBacklog: 0, wantComplete: true},
+		{name: "within log", lastID: published[5].ID, wantBacklog: 2, wantComplete: true},
+		{name: "oldest logged is next", lastID: published[2].ID, wantBacklog: 5, wantComplete: true},
+		{name: "beyond log", lastID: published[1].ID, wantBacklog: 5, wantComplete: false},
+		{name: "from the future", lastID: last + 100, wantBacklog: 0, wantComplete: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, backlog, complete := b.Subscribe(tt.lastID)
+			defer sub.Close()
+			if len(backlog) != tt.wantBacklog || complete != tt.wantComplete {
+				t.Fatalf("backlog %d, complete %v; want %d, %v", len(backlog), complete, tt.wantBacklog, tt.wantComplete)
+			}
+			for i := 1; i < len(backlog); i++ {
+				if backlog[i].ID != backlog[i-1].ID+1 {
+					t.Errorf("backlog out of order: %d after %d", backlog[i].ID, backlog[i-1].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestBus_StaleIDAfterRestart(t *testing.T) {
+	old := NewBus(5)
+	stale := publishN(old, 1)[0].ID
+
+	time.Sleep(time.Millisecond) // a restart takes longer than a microsecond per event
+	b := NewBus(5)
+	publishN(b, 1)
+	sub, _, complete := b.Subscribe(stale)
+	defer sub.Close()
+	if complete {
+		t.Error("resume from the previous process must be incomplete")
+	}
+}
+
+func TestBus_DropsSlowSubscriber(t *testing.T) {
+	b := NewBus(0)
+	sub, _, _ := b.Subscribe(0)
+
+	publishN(b, subscriberBuffer+1)
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("received %d events before the drop, want %d", received, subscriberBuffer)
+	}
+	sub.Close() // closing a dropped subscription is a no-op
+}
+
+func TestBus_Close(t *testing.T) {
+	b := NewBus(0)
+	sub, _, _ := b.Subscribe(0)
+	b.Close()
+	if _, ok := <-sub.Events(); ok {
+		t.Error("subscription still open after Close")
+	}
+	b.Publish(Event{Type: MeetingDeleted})
+
+	late, _, _ := b.Subscribe(0)
+	if _, ok := <-late.Events(); ok {
+		t.Error("subscription on a closed bus is open")
+	}
+	late.Close()
+}
@@ -0,0 +1,151 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the content line length limit from RFC 5545 section 3.1
+const maxLineOctets = 75
+
+// Encode writes c and all of its sub-components as iCalendar text with
+// CRLF line endings, folding lines longer than 75 octets
+func Encode(w io.Writer, c *Component) error {
+	bw := bufio.NewWriter(w)
+	encodeComponent(bw, c)
+	return bw.Flush()
+}
+
+func encodeComponent(w *bufio.Writer, c *Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		writeLine(w, formatProperty(p))
+	}
+	for _, child := range c.Components {
+		encodeComponent(w, child)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+func formatProperty(p *Property) string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+
+	names := make([]string, 0, len(p.Params))
+	for name := range p.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b.WriteString(";" + name + "=")
+		for i, v := range p.Params[name] {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if strings.ContainsAny(v, ";:,") {
+				v = `"` + strings.ReplaceAll(v, `"`, "'") + `"`
+			}
+			b.WriteString(v)
+		}
+	}
+
+	b.WriteByte(':')
+	b.WriteString(p.Value)
+	return b.String()
+}
+
+// writeLine writes one content line, folding it without splitting UTF-8 sequences
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		_, _ = w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	_, _ = w.WriteString(line + "\r\n")
+}
+
+// EscapeText encodes a TEXT value (RFC 5545 section 3.3.11)
+func EscapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// FormatUTC formats t as a UTC DATE-TIME value, e.g. 20260214T090000Z
+func FormatUTC(t time.Time) string {
+	return t.UTC().Format(utcDateTimeLayout)
+}
+
+// NewProperty creates a property with the given raw (already escaped) value
+func NewProperty(name, value string) *Property {
+	return &Property{Name: strings.ToUpper(name), Params: map[string][]string{}, Value: value}
+}
+
+// NewCalendar creates an empty VCALENDAR with the mandatory properties
+func NewCalendar(prodID string) *Component {
+	return &Component{
+		Name: "VCALENDAR",
+		Properties: []*Property{
+			NewProperty("VERSION", "2.0"),
+			NewProperty("PRODID", prodID),
+			NewProperty("CALSCALE", "GREGORIAN"),
+		},
+	}
+}
+
+// Component builds a VEVENT for the event. stamp is used for DTSTAMP and
+// LAST-MODIFIED; times are written in UTC.
+func (ev *Event) Component(stamp time.Time) *Component {
+	c := &Component{Name: "VEVENT"}
+	add := func(name, value string) {
+		c.Properties = append(c.Properties, NewProperty(name, value))
+	}
+
+	add("UID", ev.UID)
+	add("DTSTAMP", FormatUTC(stamp))
+	add("LAST-MODIFIED", FormatUTC(stamp))
+	add("DTSTART", FormatUTC(ev.Start))
+	if !ev.End.IsZero() {
+		add("DTEND", FormatUTC(ev.End))
+	}
+	add("SUMMARY", EscapeText(ev.Summary))
+	if ev.Description != "" {
+		add("DESCRIPTION", EscapeText(ev.Description))
+	}
+	if ev.URL != "" {
+		add("URL", ev.URL)
+	}
+	if len(ev.Categories) > 0 {
+		escaped := make([]string, len(ev.Categories))
+		for i, cat := range ev.Categories {
+			escaped[i] = EscapeText(cat)
+		}
+		add("CATEGORIES", strings.Join(escaped, ","))
+	}
+	if ev.Organizer != nil {
+		c.Properties = append(c.Properties, attendeeProperty("ORGANIZER", *ev.Organizer))
+	}
+	for _, a := range ev.Attendees {
+		c.Properties = append(c.Properties, attendeeProperty("ATTENDEE", a))
+	}
+
+	return c
+}
+
+func attendeeProperty(name string, a Attendee) *Property {
+	p := NewProperty(name, "mailto:"+a.Email)
+	if a.Name != "" {
+		p.Params["CN"] = []string{a.Name}
+	}
+	return p
+}
@@ -0,0 +1,77 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	cal := NewCalendar("-//test//EN")
+	ev := &Event{
+		UID:         "fold@example.com",
+		Summary:     strings.Repeat("Ü", 60),
+		Description: "Line one\nLine two; with, separators",
+		Start:       time.Date(2026, 2, 14, 9, 0, 0, 0, time.UTC),
+	}
+	cal.Components = append(cal.Components, ev.Component(ev.Start))
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, cal); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line exceeds %d octets: %q", maxLineOctets, line)
+		}
+	}
+	if !strings.Contains(buf.String(), `DESCRIPTION:Line one\nLine two\; with\, separators`) {
+		t.Errorf("expected escaped description, got:\n%s", buf.String())
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	original := &Event{
+		UID:         "roundtrip@example.com",
+		Summary:     "Planning, Q3",
+		Description: strings.Repeat("Long description text. ", 10),
+		URL:         "https://notebook.example.ts.net/meetings/7",
+		Categories:  []string{"planning", "budget, finance"},
+		Start:       time.Date(2026, 7, 1, 8, 30, 0, 0, time.UTC),
+		End:         time.Date(2026, 7, 1, 9, 15, 0, 0, time.UTC),
+		Organizer:   &Attendee{Name: "Doe, Jane", Email: "jane@example.com"},
+		Attendees:   []Attendee{{Email: "bob@example.com"}},
+	}
+	cal := NewCalendar("-//test//EN")
+	cal.Components = append(cal.Components, original.Component(original.Start))
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, cal); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+
+	parsed, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	events, err := Events(parsed, time.UTC)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d (err %v)", len(events), err)
+	}
+
+	got := events[0]
+	if got.UID != original.UID || got.Summary != original.Summary || got.Description != strings.TrimSpace(original.Description) || got.URL != original.URL {
+		t.Errorf("text fields did not round-trip: %+v", got)
+	}
+	if !got.Start.Equal(original.Start) || !got.End.Equal(original.End) {
+		t.Errorf("times did not round-trip: %v - %v", got.Start, got.End)
+	}
+	if len(got.Categories) != 2 || got.Categories[1] != "budget, finance" {
+		t.Errorf("categories did not round-trip: %q", got.Categories)
+	}
+	if got.Organizer == nil || got.Organizer.Name != "Doe, Jane" || len(got.Attendees) != 1 {
+		t.Errorf("attendees did not round-trip: %+v %+v", got.Organizer, got.Attendees)
+	}
+}
@@ -0,0 +1,252 @@
+package ical
+
+import (
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout        = "20060102"
+	dateTimeLayout    = "20060102T150405"
+	utcDateTimeLayout = "20060102T150405Z"
+)
+
+// Attendee is a participant taken from an ATTENDEE or ORGANIZER property
+type Attendee struct {
+	Name  string
+	Email string
+}
+
+// String returns the display form used in the meeting participants field
+func (a Attendee) String() string {
+	switch {
+	case a.Name != "" && a.Email != "":
+		return fmt.Sprintf("%s <%s>", a.Name, a.Email)
+	case a.Name != "":
+		return a.Name
+	default:
+		return a.Email
+	}
+}
+
+// Event is the notebook-relevant view of a VEVENT
+type Event struct {
+	UID string
+	// RecurrenceID identifies an overridden instance of a recurring event,
+	// normalized to UTC (or a plain date for all-day events); empty otherwise
+	RecurrenceID string
+	Summary      string
+	Description  string
+	URL          string
+	Categories   []string
+	Start        time.Time
+	End          time.Time // zero if the event has neither DTEND nor DURATION
+	AllDay       bool
+	Organizer    *Attendee
+	Attendees    []Attendee
+}
+
+// Events extracts all VEVENTs from a parsed calendar. Floating times and
+// all-day dates are interpreted in defaultLoc.
+func Events(cal *Component, defaultLoc *time.Location) ([]*Event, error) {
+	if defaultLoc == nil {
+		defaultLoc = time.UTC
+	}
+	zones := newZoneResolver(cal, defaultLoc)
+
+	var events []*Event
+	for _, vevent := range cal.Children("VEVENT") {
+		ev, err := parseEvent(vevent, zones)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+
+	return events, nil
+}
+
+func parseEvent(c *Component, zones *zoneResolver) (*Event, error) {
+	ev := &Event{}
+
+	uid := c.Prop("UID")
+	if uid == nil || uid.Value == "" {
+		return nil, fmt.Errorf("VEVENT without UID")
+	}
+	ev.UID = uid.Value
+
+	dtstart := c.Prop("DTSTART")
+	if dtstart == nil {
+		return nil, fmt.Errorf("VEVENT %s: missing DTSTART", ev.UID)
+	}
+	start, allDay, err := zones.parseTime(dtstart)
+	if err != nil {
+		return nil, fmt.Errorf("VEVENT %s: DTSTART: %w", ev.UID, err)
+	}
+	ev.Start, ev.AllDay = start, allDay
+
+	if err := ev.parseEnd(c, zones); err != nil {
+		return nil, err
+	}
+
+	if rid := c.Prop("RECURRENCE-ID"); rid != nil {
+		t, ridAllDay, err := zones.parseTime(rid)
+		if err != nil {
+			return nil, fmt.Errorf("VEVENT %s: RECURRENCE-ID: %w", ev.UID, err)
+		}
+		if ridAllDay {
+			ev.RecurrenceID = t.Format(dateLayout)
+		} else {
+			ev.RecurrenceID = t.UTC().Format(utcDateTimeLayout)
+		}
+	}
+
+	if p := c.Prop("SUMMARY"); p != nil {
+		ev.Summary = strings.TrimSpace(UnescapeText(p.Value))
+	}
+	if p := c.Prop("DESCRIPTION"); p != nil {
+		ev.Description = strings.TrimSpace(UnescapeText(p.Value))
+	}
+	if p := c.Prop("URL"); p != nil {
+		ev.URL = p.Value
+	}
+	for _, p := range c.Props("CATEGORIES") {
+		for _, cat := range splitText(p.Value) {
+			if cat = strings.TrimSpace(cat); cat != "" {
+				ev.Categories = append(ev.Categories, cat)
+			}
+		}
+	}
+	if p := c.Prop("ORGANIZER"); p != nil {
+		org := parseAttendee(p)
+		ev.Organizer = &org
+	}
+	for _, p := range c.Props("ATTENDEE") {
+		ev.Attendees = append(ev.Attendees, parseAttendee(p))
+	}
+
+	return ev, nil
+}
+
+// splitText splits a comma-separated list of TEXT values, honoring "\," escapes
+func splitText(s string) []string {
+	var (
+		parts []string
+		cur   strings.Builder
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			cur.WriteByte(s[i])
+			cur.WriteByte(s[i+1])
+			i++
+		case s[i] == ',':
+			parts = append(parts, UnescapeText(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(s[i])
+		}
+	}
+	return append(parts, UnescapeText(cur.String()))
+}
+
+// parseEnd fills End from DTEND, or from DTSTART + DURATION
+func (ev *Event) parseEnd(c *Component, zones *zoneResolver) error {
+	if dtend := c.Prop("DTEND"); dtend != nil {
+		end, _, err := zones.parseTime(dtend)
+		if err != nil {
+			return fmt.Errorf("VEVENT %s: DTEND: %w", ev.UID, err)
+		}
+		ev.End = end
+		return nil
+	}
+
+	if dur := c.Prop("DURATION"); dur != nil {
+		d, err := ParseDuration(dur.Value)
+		if err != nil {
+			return fmt.Errorf("VEVENT %s: DURATION: %w", ev.UID, err)
+		}
+		ev.End = ev.Start.Add(d)
+	}
+
+	return nil
+}
+
+// parseAttendee reads the CN parameter and the mailto: address of a cal-address
+func parseAttendee(p *Property) Attendee {
+	a := Attendee{Name: strings.TrimSpace(p.Param("CN"))}
+
+	value := strings.TrimSpace(p.Value)
+	if strings.HasPrefix(strings.ToLower(value), "mailto:") {
+		value = value[len("mailto:"):]
+	}
+	if addr, err := mail.ParseAddress(value); err == nil {
+		a.Email = addr.Address
+	} else {
+		a.Email = value
+	}
+
+	if a.Name == a.Email {
+		a.Name = ""
+	}
+	return a
+}
+
+// ParseDuration parses an RFC 5545 duration such as "PT1H30M" or "-P1D"
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour,
+		'D': 24 * time.Hour,
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+	}
+
+	var total time.Duration
+	inTime := false
+	num := ""
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == 'T':
+			inTime = true
+		case ch >= '0' && ch <= '9':
+			num += string(ch)
+		default:
+			unit, ok := units[ch]
+			if !ok || num == "" || (ch == 'M' && !inTime) {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			total += time.Duration(n) * unit
+			num = ""
+		}
+	}
+
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	return sign * total, nil
+}
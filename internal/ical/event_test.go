@@ -0,0 +1,248 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustEvents(t *testing.T, input string, loc *time.Location) []*Event {
+	t.Helper()
+
+	cal, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	events, err := Events(cal, loc)
+	if err != nil {
+		t.Fatalf("events failed: %v", err)
+	}
+	return events
+}
+
+func TestEvents_BasicFields(t *testing.T) {
+	input := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:evt-1@example.com
+SUMMARY:Sprint Review
+DESCRIPTION:Agenda:\n- Demo\n- Retro
+DTSTART:20260214T090000Z
+DTEND:20260214T100000Z
+ORGANIZER;CN=Alice:mailto:alice@example.com
+ATTENDEE;CN=Bob:mailto:bob@example.com
+ATTENDEE:mailto:carol@example.com
+END:VEVENT
+END:VCALENDAR
+`
+	events := mustEvents(t, input, time.UTC)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	ev := events[0]
+
+	if ev.UID != "evt-1@example.com" || ev.Summary != "Sprint Review" {
+		t.Errorf("unexpected UID/summary: %q / %q", ev.UID, ev.Summary)
+	}
+	if ev.Description != "Agenda:\n- Demo\n- Retro" {
+		t.Errorf("unexpected description %q", ev.Description)
+	}
+	if !ev.Start.Equal(time.Date(2026, 2, 14, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected start %v", ev.Start)
+	}
+	if ev.End.Sub(ev.Start) != time.Hour {
+		t.Errorf("expected 1h duration, got %v", ev.End.Sub(ev.Start))
+	}
+	if ev.Organizer == nil || ev.Organizer.String() != "Alice <alice@example.com>" {
+		t.Errorf("unexpected organizer %+v", ev.Organizer)
+	}
+	if len(ev.Attendees) != 2 || ev.Attendees[1].String() != "carol@example.com" {
+		t.Errorf("unexpected attendees %+v", ev.Attendees)
+	}
+	if ev.RecurrenceID != "" {
+		t.Errorf("expected empty recurrence ID, got %q", ev.RecurrenceID)
+	}
+}
+
+func TestEvents_TimeZones(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		dtstart  string
+		expected time.Time
+	}{
+		{
+			name:     "UTC",
+			dtstart:  "DTSTART:20260214T090000Z",
+			expected: time.Date(2026, 2, 14, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "IANA TZID",
+			dtstart:  "DTSTART;TZID=America/New_York:20260214T090000",
+			expected: time.Date(2026, 2, 14, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "floating uses default location",
+			dtstart:  "DTSTART:20260214T090000",
+			expected: time.Date(2026, 2, 14, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "IANA TZID during DST",
+			dtstart:  "DTSTART;TZID=Europe/Berlin:20260714T090000",
+			expected: time.Date(2026, 7, 14, 7, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "BEGIN:VEVENT\nUID:tz\n" + tt.dtstart + "\nEND:VEVENT\n"
+			ev := mustEvents(t, input, berlin)[0]
+			if !ev.Start.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ev.Start.UTC())
+			}
+		})
+	}
+}
+
+// outlookTimeZone is the VTIMEZONE Outlook emits for Central European time
+const outlookTimeZone = `BEGIN:VTIMEZONE
+TZID:W. Europe Standard Time
+BEGIN:STANDARD
+DTSTART:16010101T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:16010101T020000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0200
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3
+END:DAYLIGHT
+END:VTIMEZONE
+`
+
+func TestEvents_VTimezoneFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		local    string
+		expected time.Time
+	}{
+		{"winter", "20260214T100000", time.Date(2026, 2, 14, 9, 0, 0, 0, time.UTC)},
+		{"summer", "20260714T100000", time.Date(2026, 7, 14, 8, 0, 0, 0, time.UTC)},
+		{"day after DST start", "20260330T100000", time.Date(2026, 3, 30, 8, 0, 0, 0, time.UTC)},
+		{"day before DST start", "20260328T100000", time.Date(2026, 3, 28, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "BEGIN:VCALENDAR\n" + outlookTimeZone +
+				"BEGIN:VEVENT\nUID:outlook\nDTSTART;TZID=W. Europe Standard Time:" + tt.local + "\nEND:VEVENT\nEND:VCALENDAR\n"
+			ev := mustEvents(t, input, time.UTC)[0]
+			if !ev.Start.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ev.Start.UTC())
+			}
+		})
+	}
+}
+
+func TestEvents_UnknownTimeZone(t *testing.T) {
+	input := "BEGIN:VEVENT\nUID:x\nDTSTART;TZID=Nowhere/Special:20260214T100000\nEND:VEVENT\n"
+	cal, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if _, err := Events(cal, time.UTC); err == nil {
+		t.Error("expected error for unknown time zone")
+	}
+}
+
+func TestEvents_RecurrenceID(t *testing.T) {
+	input := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:weekly@example.com
+SUMMARY:Weekly sync
+DTSTART;TZID=Europe/Berlin:20260210T100000
+DURATION:PT30M
+RRULE:FREQ=WEEKLY
+END:VEVENT
+BEGIN:VEVENT
+UID:weekly@example.com
+SUMMARY:Weekly sync (moved)
+RECURRENCE-ID;TZID=Europe/Berlin:20260217T100000
+DTSTART;TZID=Europe/Berlin:20260218T140000
+DTEND;TZID=Europe/Berlin:20260218T143000
+END:VEVENT
+BEGIN:VEVENT
+UID:allday@example.com
+RECURRENCE-ID;VALUE=DATE:20260301
+DTSTART;VALUE=DATE:20260302
+END:VEVENT
+END:VCALENDAR
+`
+	events := mustEvents(t, input, time.UTC)
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+
+	if events[0].RecurrenceID != "" {
+		t.Errorf("master event should not have a recurrence ID, got %q", events[0].RecurrenceID)
+	}
+	if events[0].End.Sub(events[0].Start) != 30*time.Minute {
+		t.Errorf("expected DURATION to set a 30m end, got %v", events[0].End.Sub(events[0].Start))
+	}
+
+	if events[1].UID != events[0].UID {
+		t.Errorf("override should share the master UID")
+	}
+	if events[1].RecurrenceID != "20260217T090000Z" {
+		t.Errorf("expected recurrence ID normalized to UTC, got %q", events[1].RecurrenceID)
+	}
+
+	if !events[2].AllDay || events[2].RecurrenceID != "20260301" {
+		t.Errorf("expected all-day override with date recurrence ID, got %+v", events[2])
+	}
+}
+
+func TestEvents_MissingUID(t *testing.T) {
+	cal, err := Parse(strings.NewReader("BEGIN:VEVENT\nDTSTART:20260214T100000Z\nEND:VEVENT\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if _, err := Events(cal, time.UTC); err == nil {
+		t.Error("expected error for VEVENT without UID")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"PT1H30M", 90 * time.Minute, false},
+		{"P1D", 24 * time.Hour, false},
+		{"P1W", 7 * 24 * time.Hour, false},
+		{"P1DT2H", 26 * time.Hour, false},
+		{"-PT15M", -15 * time.Minute, false},
+		{"PT", 0, true},
+		{"1H", 0, true},
+		{"P1M", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("ParseDuration(%q) = %v, %v; expected %v", tt.input, got, err, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Package ical implements the subset of RFC 5545 (iCalendar) that notebook
+// needs: reading VEVENT components from .ics files and writing them back out.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Property is a single content line, e.g. DTSTART;TZID=Europe/Berlin:20260214T100000
+type Property struct {
+	Name   string
+	Params map[string][]string
+	Value  string
+}
+
+// Param returns the first value of the named parameter, or "" if absent
+func (p *Property) Param(name string) string {
+	if vals := p.Params[strings.ToUpper(name)]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// Component is a BEGIN/END block such as VCALENDAR, VEVENT or VTIMEZONE
+type Component struct {
+	Name       string
+	Properties []*Property
+	Components []*Component
+}
+
+// Prop returns the first property with the given name, or nil
+func (c *Component) Prop(name string) *Property {
+	name = strings.ToUpper(name)
+	for _, p := range c.Properties {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Props returns all properties with the given name
+func (c *Component) Props(name string) []*Property {
+	name = strings.ToUpper(name)
+	var props []*Property
+	for _, p := range c.Properties {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// Children returns all direct sub-components with the given name
+func (c *Component) Children(name string) []*Component {
+	name = strings.ToUpper(name)
+	var children []*Component
+	for _, child := range c.Components {
+		if child.Name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Parse reads an iCalendar stream and returns its root VCALENDAR component.
+// A bare VEVENT (e.g. pasted from a mail client) is accepted and wrapped in
+// a synthetic VCALENDAR so callers can treat both inputs the same way.
+func Parse(r io.Reader) (*Component, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &Component{Name: "VCALENDAR"}
+	var stack []*Component
+
+	for i, line := range lines {
+		prop, err := parseContentLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			comp := &Component{Name: strings.ToUpper(prop.Value)}
+			if len(stack) == 0 && comp.Name == "VCALENDAR" {
+				comp = root
+			} else {
+				parent := root
+				if len(stack) > 0 {
+					parent = stack[len(stack)-1]
+				}
+				parent.Components = append(parent.Components, comp)
+			}
+			stack = append(stack, comp)
+		case "END":
+			name := strings.ToUpper(prop.Value)
+			if len(stack) == 0 || stack[len(stack)-1].Name != name {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", i+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property %s outside of component", i+1, prop.Name)
+			}
+			cur := stack[len(stack)-1]
+			cur.Properties = append(cur.Properties, prop)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("unterminated component %s", stack[len(stack)-1].Name)
+	}
+
+	return root, nil
+}
+
+// unfoldLines splits the input into logical content lines, joining folded
+// continuation lines (those starting with a space or tab) to their predecessor
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read calendar: %w", err)
+	}
+
+	return lines, nil
+}
+
+// parseContentLine parses "NAME;PARAM=a,b;OTHER=\"x:y\":value"
+func parseContentLine(line string) (*Property, error) {
+	prop := &Property{Params: map[string][]string{}}
+
+	// The name ends at the first ';' or ':'
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return nil, fmt.Errorf("malformed content line %q", line)
+	}
+	prop.Name = strings.ToUpper(line[:end])
+	rest := line[end:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("malformed parameter in %q", line)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var values []string
+		for {
+			var val string
+			if strings.HasPrefix(rest, `"`) {
+				closing := strings.IndexByte(rest[1:], '"')
+				if closing < 0 {
+					return nil, fmt.Errorf("unterminated quoted parameter in %q", line)
+				}
+				val = rest[1 : closing+1]
+				rest = rest[closing+2:]
+			} else {
+				stop := strings.IndexAny(rest, ",;:")
+				if stop < 0 {
+					return nil, fmt.Errorf("malformed parameter in %q", line)
+				}
+				val = rest[:stop]
+				rest = rest[stop:]
+			}
+			values = append(values, val)
+			if !strings.HasPrefix(rest, ",") {
+				break
+			}
+			rest = rest[1:]
+		}
+		prop.Params[name] = values
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return nil, fmt.Errorf("missing value in %q", line)
+	}
+	prop.Value = rest[1:]
+
+	return prop, nil
+}
+
+// UnescapeText decodes a TEXT value (RFC 5545 section 3.3.11)
+func UnescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
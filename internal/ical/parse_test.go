@@ -0,0 +1,98 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_UnfoldsAndParsesParams(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:abc-123\r\n" +
+		"SUMMARY:Quarterly planning with a very long subject that the cl\r\n" +
+		" ient folded\r\n" +
+		"ATTENDEE;CN=\"Doe, Jane\";ROLE=REQ-PARTICIPANT:mailto:jane@example.com\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	events := cal.Children("VEVENT")
+	if len(events) != 1 {
+		t.Fatalf("expected 1 VEVENT, got %d", len(events))
+	}
+
+	summary := events[0].Prop("SUMMARY")
+	if summary == nil || summary.Value != "Quarterly planning with a very long subject that the client folded" {
+		t.Errorf("unexpected unfolded summary: %+v", summary)
+	}
+
+	attendee := events[0].Prop("ATTENDEE")
+	if attendee == nil {
+		t.Fatal("expected ATTENDEE property")
+	}
+	if got := attendee.Param("cn"); got != "Doe, Jane" {
+		t.Errorf("expected CN 'Doe, Jane', got %q", got)
+	}
+	if attendee.Value != "mailto:jane@example.com" {
+		t.Errorf("unexpected attendee value %q", attendee.Value)
+	}
+}
+
+func TestParse_BareVEvent(t *testing.T) {
+	input := "BEGIN:VEVENT\nUID:pasted\nDTSTART:20260214T100000Z\nEND:VEVENT\n"
+
+	cal, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	if cal.Name != "VCALENDAR" {
+		t.Errorf("expected synthetic VCALENDAR root, got %q", cal.Name)
+	}
+	if len(cal.Children("VEVENT")) != 1 {
+		t.Errorf("expected pasted VEVENT to be found")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unterminated component", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\n"},
+		{"mismatched end", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VTODO\nEND:VCALENDAR\n"},
+		{"property outside component", "UID:x\n"},
+		{"missing value", "BEGIN:VCALENDAR\nSUMMARY;LANG=en\nEND:VCALENDAR\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.input)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestUnescapeText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{`Agenda:\n1. Budget\, costs`, "Agenda:\n1. Budget, costs"},
+		{`a\;b\\c`, `a;b\c`},
+		{`upper\Nline`, "upper\nline"},
+	}
+
+	for _, tt := range tests {
+		if got := UnescapeText(tt.input); got != tt.expected {
+			t.Errorf("UnescapeText(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// zoneResolver turns DATE and DATE-TIME property values into instants.
+// TZID parameters are resolved against the IANA database first and fall
+// back to the VTIMEZONE definitions embedded in the calendar, which is what
+// Outlook relies on for its Windows zone names ("W. Europe Standard Time").
+type zoneResolver struct {
+	defaultLoc *time.Location
+	vtimezones map[string]*Component
+}
+
+func newZoneResolver(cal *Component, defaultLoc *time.Location) *zoneResolver {
+	z := &zoneResolver{
+		defaultLoc: defaultLoc,
+		vtimezones: map[string]*Component{},
+	}
+	for _, tz := range cal.Children("VTIMEZONE") {
+		if id := tz.Prop("TZID"); id != nil {
+			z.vtimezones[id.Value] = tz
+		}
+	}
+	return z
+}
+
+// parseTime parses a DTSTART/DTEND/RECURRENCE-ID property. The boolean
+// result reports whether the value was a DATE (all-day) rather than a DATE-TIME.
+func (z *zoneResolver) parseTime(p *Property) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.Value)
+
+	if strings.EqualFold(p.Param("VALUE"), "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, z.defaultLoc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcDateTimeLayout, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid UTC date-time %q", value)
+		}
+		return t, false, nil
+	}
+
+	tzid := p.Param("TZID")
+	if tzid == "" {
+		// Floating time: interpret in the configured local zone
+		t, err := time.ParseInLocation(dateTimeLayout, value, z.defaultLoc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+		}
+		return t, false, nil
+	}
+
+	t, err := z.inZone(value, tzid)
+	return t, false, err
+}
+
+// inZone interprets a local date-time value in the zone named by tzid
+func (z *zoneResolver) inZone(value, tzid string) (time.Time, error) {
+	if loc, err := time.LoadLocation(tzid); err == nil {
+		t, err := time.ParseInLocation(dateTimeLayout, value, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date-time %q", value)
+		}
+		return t, nil
+	}
+
+	vtz, ok := z.vtimezones[tzid]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown time zone %q", tzid)
+	}
+
+	// Parse as a naive wall-clock time and shift by the observance offset
+	naive, err := time.Parse(dateTimeLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date-time %q", value)
+	}
+	offset, err := vtimezoneOffset(vtz, naive)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time zone %q: %w", tzid, err)
+	}
+
+	loc := time.FixedZone(tzid, offset)
+	return time.Date(naive.Year(), naive.Month(), naive.Day(), naive.Hour(), naive.Minute(), naive.Second(), 0, loc), nil
+}
+
+// vtimezoneOffset returns the UTC offset in seconds that a VTIMEZONE assigns
+// to the given wall-clock time: the offset of the observance (STANDARD or
+// DAYLIGHT) with the most recent onset at or before that time.
+func vtimezoneOffset(vtz *Component, local time.Time) (int, error) {
+	var (
+		bestOnset  time.Time
+		bestOffset int
+		found      bool
+	)
+
+	observances := append(vtz.Children("STANDARD"), vtz.Children("DAYLIGHT")...)
+	if len(observances) == 0 {
+		return 0, fmt.Errorf("no STANDARD or DAYLIGHT observance")
+	}
+
+	for _, obs := range observances {
+		offsetProp := obs.Prop("TZOFFSETTO")
+		startProp := obs.Prop("DTSTART")
+		if offsetProp == nil || startProp == nil {
+			return 0, fmt.Errorf("observance without TZOFFSETTO or DTSTART")
+		}
+		offset, err := parseUTCOffset(offsetProp.Value)
+		if err != nil {
+			return 0, err
+		}
+		dtstart, err := time.Parse(dateTimeLayout, startProp.Value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid observance DTSTART %q", startProp.Value)
+		}
+
+		onset, ok := latestOnset(obs, dtstart, local)
+		if ok && (!found || onset.After(bestOnset)) {
+			bestOnset, bestOffset, found = onset, offset, true
+		}
+	}
+
+	if !found {
+		// Before every defined onset: the first observance is the best guess
+		offset, err := parseUTCOffset(observances[0].Prop("TZOFFSETTO").Value)
+		return offset, err
+	}
+
+	return bestOffset, nil
+}
+
+// latestOnset returns the most recent onset of an observance at or before
+// local, evaluating the yearly BYMONTH/BYDAY rules time zone definitions use
+func latestOnset(obs *Component, dtstart, local time.Time) (time.Time, bool) {
+	rule := obs.Prop("RRULE")
+	if rule == nil {
+		return dtstart, !dtstart.After(local)
+	}
+
+	parts := parseRRule(rule.Value)
+	if parts["FREQ"] != "YEARLY" {
+		return dtstart, !dtstart.After(local)
+	}
+
+	for _, year := range []int{local.Year(), local.Year() - 1} {
+		onset, ok := yearlyOnset(parts, dtstart, year)
+		if ok && !onset.Before(dtstart) && !onset.After(local) {
+			return onset, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// yearlyOnset computes the onset in the given year for rules such as
+// BYMONTH=3;BYDAY=-1SU (last Sunday of March) or BYMONTH=10;BYMONTHDAY=1
+func yearlyOnset(parts map[string]string, dtstart time.Time, year int) (time.Time, bool) {
+	month := int(dtstart.Month())
+	if m, err := strconv.Atoi(parts["BYMONTH"]); err == nil {
+		month = m
+	}
+
+	clock := func(day int) time.Time {
+		return time.Date(year, time.Month(month), day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, time.UTC)
+	}
+
+	if md, err := strconv.Atoi(parts["BYMONTHDAY"]); err == nil {
+		return clock(md), true
+	}
+
+	byday := parts["BYDAY"]
+	if len(byday) < 2 {
+		return clock(dtstart.Day()), true
+	}
+
+	weekday, ok := weekdays[byday[len(byday)-2:]]
+	if !ok {
+		return time.Time{}, false
+	}
+	nth := 1
+	if prefix := byday[:len(byday)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 {
+			return time.Time{}, false
+		}
+		nth = n
+	}
+
+	if nth > 0 {
+		first := clock(1)
+		delta := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, delta+(nth-1)*7), true
+	}
+
+	last := clock(1).AddDate(0, 1, -1)
+	delta := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -delta+(nth+1)*7), true
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseRRule splits "FREQ=YEARLY;BYMONTH=3" into its parts
+func parseRRule(s string) map[string]string {
+	parts := map[string]string{}
+	for _, kv := range strings.Split(s, ";") {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			parts[strings.ToUpper(k)] = strings.ToUpper(v)
+		}
+	}
+	return parts
+}
+
+// parseUTCOffset parses "+0100", "-0530" or "+013000" into seconds east of UTC
+func parseUTCOffset(s string) (int, error) {
+	if len(s) != 5 && len(s) != 7 {
+		return 0, fmt.Errorf("invalid UTC offset %q", s)
+	}
+
+	sign := 1
+	switch s[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, fmt.Errorf("invalid UTC offset %q", s)
+	}
+
+	hours, err1 := strconv.Atoi(s[1:3])
+	minutes, err2 := strconv.Atoi(s[3:5])
+	seconds := 0
+	var err3 error
+	if len(s) == 7 {
+		seconds, err3 = strconv.Atoi(s[5:7])
+	}
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, fmt.Errorf("invalid UTC offset %q", s)
+	}
+
+	return sign * (hours*3600 + minutes*60 + seconds), nil
+}
@@ -36,7 +36,7 @@ func NewAnthropicProvider(apiKey, model string) (*AnthropicProvider, error) {
 }
 
 // Complete sends a prompt to the Anthropic API
-func (p *AnthropicProvider) Complete(ctx context.Context, prompt string) (string, error) {
+func (p *AnthropicProvider) Complete(ctx context.Context, prompt string) (*Completion, error) {
 	reqBody := map[string]interface{}{
 		"model": p.model,
 		"messages": []map[string]string{
@@ -47,13 +47,13 @@ func (p *AnthropicProvider) Complete(ctx context.Context, prompt string) (string
 
 	body, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("marshal request: %w", err)
+		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
 	url := "https://api.anthropic.com/v1/messages"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -62,28 +62,37 @@ func (p *AnthropicProvider) Complete(ctx context.Context, prompt string) (string
 
 	resp, err := p.client.Do(req) //nolint:gosec // G704 - URL is hardcoded constant
 	if err != nil {
-		return "", fmt.Errorf("send request: %w", err)
+		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(bodyBytes))
+		return nil, fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	var result struct {
+		Model   string `json:"model"`
 		Content []struct {
 			Text string `json:"text"`
 		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decode response: %w", err)
+		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
 	if len(result.Content) == 0 {
-		return "", fmt.Errorf("no content in response")
+		return nil, fmt.Errorf("no content in response")
 	}
 
-	return result.Content[0].Text, nil
+	return &Completion{
+		Text:  result.Content[0].Text,
+		Model: result.Model,
+		Usage: Usage{InputTokens: result.Usage.InputTokens, OutputTokens: result.Usage.OutputTokens},
+	}, nil
 }
@@ -3,17 +3,71 @@ package llm
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/metrics"
+)
+
+var (
+	requestsTotal = metrics.Default.NewCounterVec(
+		"notebook_llm_requests_total",
+		"Number of LLM completion requests.",
+		"provider", "model",
+	)
+	requestErrors = metrics.Default.NewCounterVec(
+		"notebook_llm_request_errors_total",
+		"Number of failed LLM completion requests.",
+		"provider", "model",
+	)
+	requestDuration = metrics.Default.NewHistogramVec(
+		"notebook_llm_request_duration_seconds",
+		"Duration of LLM completion requests in seconds.",
+		[]float64{.5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+		"provider", "model",
+	)
 )
 
+// Usage is the token usage a provider reports for a completion
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// Completion is the result of a completion request
+type Completion struct {
+	Text string
+	// Model is the model that answered, as reported by the provider
+	Model string
+	Usage Usage
+}
+
 // Provider defines the interface for LLM completion providers
 type Provider interface {
-	Complete(ctx context.Context, prompt string) (string, error)
+	Complete(ctx context.Context, prompt string) (*Completion, error)
 }
 
 // Client wraps an LLM provider for completions
 type Client struct {
 	provider Provider
+	// name and model label the client's metrics
+	name  string
+	model string
+}
+
+// Provider types reported in metrics and diagnostics
+const (
+	ProviderAnthropic = "anthropic"
+	ProviderOpenAI    = "openai"
+)
+
+// ProviderType returns the provider type New picks for providerURL
+func ProviderType(providerURL string) string {
+	if strings.Contains(providerURL, "anthropic.com") {
+		return ProviderAnthropic
+	}
+	return ProviderOpenAI
 }
 
 // New creates a new LLM client based on the provider URL
@@ -22,21 +76,48 @@ func New(providerURL, apiKey, model string) (*Client, error) {
 	var provider Provider
 	var err error
 
-	if strings.Contains(providerURL, "anthropic.com") {
-		provider, err = NewAnthropicProvider(apiKey, model)
+	name := ProviderType(providerURL)
+	if name == ProviderAnthropic {
+		var p *AnthropicProvider
+		if p, err = NewAnthropicProvider(apiKey, model); err == nil {
+			provider, model = p, p.model
+		}
 	} else {
 		// Everything else uses OpenAI-compatible format (OpenAI, Azure, Ollama, LM Studio, vLLM, etc.)
-		provider, err = NewOpenAIProvider(providerURL, apiKey, model)
+		var p *OpenAIProvider
+		if p, err = NewOpenAIProvider(providerURL, apiKey, model); err == nil {
+			provider, model = p, p.model
+		}
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("create provider: %w", err)
 	}
 
-	return &Client{provider: provider}, nil
+	return &Client{provider: provider, name: name, model: model}, nil
 }
 
 // Complete sends a prompt to the LLM and returns the completion
-func (c *Client) Complete(ctx context.Context, prompt string) (string, error) {
-	return c.provider.Complete(ctx, prompt)
+func (c *Client) Complete(ctx context.Context, prompt string) (*Completion, error) {
+	start := time.Now()
+	result, err := c.provider.Complete(ctx, prompt)
+
+	duration := time.Since(start)
+
+	requestsTotal.Inc(c.name, c.model)
+	requestDuration.Observe(duration.Seconds(), c.name, c.model)
+	if err != nil {
+		requestErrors.Inc(c.name, c.model)
+		slog.WarnContext(ctx, "llm completion failed", "provider", c.name, "model", c.model, "duration", duration, "error", err)
+		return nil, err
+	}
+	if result.Model == "" {
+		result.Model = c.model
+	}
+
+	// Only sizes are logged; prompts and completions may contain confidential notes
+	slog.DebugContext(ctx, "llm completion", "provider", c.name, "model", c.model,
+		"prompt_chars", len(prompt), "completion_chars", len(result.Text), "duration", duration,
+		"input_tokens", result.Usage.InputTokens, "output_tokens", result.Usage.OutputTokens)
+	return result, nil
 }
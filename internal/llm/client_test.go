@@ -1,7 +1,14 @@
 package llm
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/zorak1103/notebook/internal/metrics"
 )
 
 func TestNew_ProviderDetection(t *testing.T) {
@@ -164,3 +171,61 @@ func TestNewAnthropicProvider_DefaultModel(t *testing.T) {
 		t.Errorf("expected default model 'claude-sonnet-4-20250514', got %q", provider.model)
 	}
 }
+
+type fakeProvider struct {
+	err error
+}
+
+func (p *fakeProvider) Complete(_ context.Context, _ string) (*Completion, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Completion{Text: "ok", Usage: Usage{InputTokens: 3, OutputTokens: 1}}, nil
+}
+
+func TestClient_CompleteRecordsMetrics(t *testing.T) {
+	client := &Client{provider: &fakeProvider{}, name: "fake", model: "metrics-test"}
+	result, err := client.Complete(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Model != "metrics-test" || result.Usage.InputTokens != 3 {
+		t.Errorf("unexpected completion %+v", result)
+	}
+	client.provider = &fakeProvider{err: errors.New("boom")}
+	if _, err := client.Complete(context.Background(), "prompt"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var out strings.Builder
+	if err := metrics.Default.WriteText(&out); err != nil {
+		t.Fatalf("write metrics: %v", err)
+	}
+	for _, want := range []string{
+		`notebook_llm_requests_total{provider="fake",model="metrics-test"} 2`,
+		`notebook_llm_request_errors_total{provider="fake",model="metrics-test"} 1`,
+		`notebook_llm_request_duration_seconds_count{provider="fake",model="metrics-test"} 2`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	if err := Probe(context.Background(), srv.URL+"/v1/"); err != nil {
+		t.Errorf("expected reachable provider, got %v", err)
+	}
+
+	srv.Close()
+	if err := Probe(context.Background(), srv.URL+"/v1"); err == nil {
+		t.Error("expected error for closed server, got nil")
+	}
+}
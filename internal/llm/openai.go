@@ -39,7 +39,7 @@ func NewOpenAIProvider(baseURL, apiKey, model string) (*OpenAIProvider, error) {
 }
 
 // Complete sends a prompt to the OpenAI-compatible API
-func (p *OpenAIProvider) Complete(ctx context.Context, prompt string) (string, error) {
+func (p *OpenAIProvider) Complete(ctx context.Context, prompt string) (*Completion, error) {
 	reqBody := map[string]interface{}{
 		"model": p.model,
 		"messages": []map[string]string{
@@ -49,13 +49,13 @@ func (p *OpenAIProvider) Complete(ctx context.Context, prompt string) (string, e
 
 	body, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("marshal request: %w", err)
+		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
 	url := p.baseURL + "/chat/completions"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -63,30 +63,39 @@ func (p *OpenAIProvider) Complete(ctx context.Context, prompt string) (string, e
 
 	resp, err := p.client.Do(req) //nolint:gosec // G704 - URL is intentionally user-configurable (LLM provider endpoint)
 	if err != nil {
-		return "", fmt.Errorf("send request: %w", err)
+		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(bodyBytes))
+		return nil, fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	var result struct {
+		Model   string `json:"model"`
 		Choices []struct {
 			Message struct {
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decode response: %w", err)
+		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
 	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
+		return nil, fmt.Errorf("no choices in response")
 	}
 
-	return result.Choices[0].Message.Content, nil
+	return &Completion{
+		Text:  result.Choices[0].Message.Content,
+		Model: result.Model,
+		Usage: Usage{InputTokens: result.Usage.PromptTokens, OutputTokens: result.Usage.CompletionTokens},
+	}, nil
 }
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	anthropicBaseURL = "https://api.anthropic.com/v1"
+	probeTimeout     = 5 * time.Second
+)
+
+// Probe checks whether the provider behind providerURL answers HTTP requests.
+// It lists models without credentials, so any HTTP response, including 401,
+// counts as reachable.
+func Probe(ctx context.Context, providerURL string) error {
+	base := strings.TrimSuffix(providerURL, "/")
+	if ProviderType(providerURL) == ProviderAnthropic {
+		base = anthropicBaseURL
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/models", http.NoBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704 - URL is intentionally user-configurable (LLM provider endpoint)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// Package logging configures log/slog for notebook: text or JSON output,
+// request-scoped attributes taken from the context, and redaction of secrets.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Output formats accepted by New
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Attribute keys set from the request context
+const (
+	KeyRequestID = "request_id"
+	KeyUser      = "user"
+)
+
+// redacted replaces the value of sensitive attributes
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values never reach the log
+var sensitiveKeys = map[string]bool{
+	"api_key":       true,
+	"llm_api_key":   true,
+	"authorization": true,
+	"client_secret": true,
+	"cookie":        true,
+	"prompt":        true,
+}
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	userKey
+)
+
+// New creates a logger writing to w in the given format ("text" or "json")
+// at the given level ("debug", "info", "warn" or "error")
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: redact}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+
+	return slog.New(contextHandler{handler}), nil
+}
+
+// redact hides the values of sensitive attributes
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request ID stored in ctx, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithUser returns a context carrying the Tailscale login name of the caller
+func WithUser(ctx context.Context, login string) context.Context {
+	return context.WithValue(ctx, userKey, login)
+}
+
+// User returns the login name stored in ctx, or ""
+func User(ctx context.Context) string {
+	login, _ := ctx.Value(userKey).(string)
+	return login
+}
+
+// contextHandler adds the request ID and user from the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String(KeyRequestID, id))
+	}
+	if login := User(ctx); login != "" {
+		r.AddAttrs(slog.String(KeyUser, login))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNew_JSONWithContextAndRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, "info")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := WithUser(WithRequestID(context.Background(), "req-1"), "alice@example.com")
+	logger.InfoContext(ctx, "llm call", "prompt", "secret notes", "API_KEY", "sk-123", "model", "gpt-4o")
+	logger.DebugContext(ctx, "hidden")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected exactly one JSON line, got %q: %v", buf.String(), err)
+	}
+
+	want := map[string]any{
+		"msg":        "llm call",
+		KeyRequestID: "req-1",
+		KeyUser:      "alice@example.com",
+		"prompt":     redacted,
+		"API_KEY":    redacted,
+		"model":      "gpt-4o",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+}
+
+func TestNew_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "TEXT", "debug")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	logger.Debug("hello", "count", 2)
+	if !strings.Contains(buf.String(), "level=DEBUG msg=hello count=2") {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "xml", "info"); err == nil {
+		t.Error("expected error for invalid format")
+	}
+	if _, err := New(&bytes.Buffer{}, FormatText, "loud"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+func TestContextValues_Empty(t *testing.T) {
+	ctx := context.Background()
+	if RequestID(ctx) != "" || User(ctx) != "" {
+		t.Error("expected empty values for a bare context")
+	}
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounterVec("test_requests_total", "Requests.\nSecond line", "path")
+	latency := reg.NewHistogramVec("test_latency_seconds", "Latency.", []float64{1, 0.1}, "path")
+	items := reg.NewGaugeFunc("test_items", "Items.")
+	broken := reg.NewGaugeFunc("test_broken", "Broken.")
+	reg.NewGaugeFunc("test_unset", "Unset.")
+
+	requests.Inc(`/a"b`)
+	requests.Add(2, "/c")
+	latency.Observe(0.05, "/c")
+	latency.Observe(0.5, "/c")
+	latency.Observe(3, "/c")
+	items.Set(func() (float64, error) { return 42, nil })
+	broken.Set(func() (float64, error) { return 0, errors.New("boom") })
+
+	var out strings.Builder
+	if err := reg.WriteText(&out); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+
+	want := `# HELP test_items Items.
+# TYPE test_items gauge
+test_items 42
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{path="/c",le="0.1"} 1
+test_latency_seconds_bucket{path="/c",le="1"} 2
+test_latency_seconds_bucket{path="/c",le="+Inf"} 3
+test_latency_seconds_sum{path="/c"} 3.55
+test_latency_seconds_count{path="/c"} 3
+# HELP test_requests_total Requests.\nSecond line
+# TYPE test_requests_total counter
+test_requests_total{path="/a\"b"} 1
+test_requests_total{path="/c"} 2
+`
+	if out.String() != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRegistry_DuplicatePanics(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounterVec("dup_total", "First.")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for duplicate metric name")
+		}
+	}()
+	reg.NewCounterVec("dup_total", "Second.")
+}
+
+func TestCounterVec_WrongLabelCountPanics(t *testing.T) {
+	reg := NewRegistry()
+	c := reg.NewCounterVec("labels_total", "Labels.", "a", "b")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for wrong number of label values")
+		}
+	}()
+	c.Inc("only-one")
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounterVec("handler_total", "Handler.").Inc()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != contentTypeText {
+		t.Errorf("Content-Type = %q, want %q", got, contentTypeText)
+	}
+	if !strings.Contains(rec.Body.String(), "handler_total 1\n") {
+		t.Errorf("body missing counter:\n%s", rec.Body.String())
+	}
+}
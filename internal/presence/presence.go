@@ -0,0 +1,240 @@
+// Package presence tracks who is viewing or editing which meeting and holds
+// advisory edit locks on notes. Both live in memory and expire unless
+// clients renew them with heartbeats.
+package presence
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// States of a user in a meeting
+const (
+	StateViewing = "viewing"
+	StateEditing = "editing"
+)
+
+// Default lifetimes without a heartbeat
+const (
+	DefaultPresenceTTL = time.Minute
+	DefaultLockTTL     = 2 * time.Minute
+)
+
+var (
+	// ErrLocked is returned when another user holds the lock
+	ErrLocked = errors.New("note is locked by another user")
+	// ErrNotPeer is returned when a user not present in the meeting tries
+	// to take over a lock
+	ErrNotPeer = errors.New("only users present in the meeting can take over the lock")
+	// ErrNotHolder is returned when releasing a lock held by another user
+	ErrNotHolder = errors.New("lock is held by another user")
+)
+
+// User identifies a user by login name
+type User struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+// Entry is the presence of a user in a meeting
+type Entry struct {
+	User
+	MeetingID int `json:"meeting_id"`
+	// NoteID is the note being edited, if any
+	NoteID   *int      `json:"note_id,omitempty"`
+	State    string    `json:"state"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Lock is an advisory edit lock on a note
+type Lock struct {
+	NoteID     int       `json:"note_id"`
+	MeetingID  int       `json:"meeting_id"`
+	Holder     User      `json:"holder"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// entryKey identifies the presence of a user in a meeting
+type entryKey struct {
+	login     string
+	meetingID int
+}
+
+// Tracker holds presence entries and note locks
+type Tracker struct {
+	mu          sync.Mutex
+	now         func() time.Time
+	presenceTTL time.Duration
+	lockTTL     time.Duration
+	entries     map[entryKey]Entry
+	locks       map[int]Lock
+}
+
+// NewTracker creates a tracker whose entries and locks expire after the
+// given durations without renewal. Durations of 0 or less use the defaults.
+func NewTracker(presenceTTL, lockTTL time.Duration) *Tracker {
+	if presenceTTL <= 0 {
+		presenceTTL = DefaultPresenceTTL
+	}
+	if lockTTL <= 0 {
+		lockTTL = DefaultLockTTL
+	}
+	return &Tracker{
+		now:         time.Now,
+		presenceTTL: presenceTTL,
+		lockTTL:     lockTTL,
+		entries:     map[entryKey]Entry{},
+		locks:       map[int]Lock{},
+	}
+}
+
+// prune drops expired entries and locks; t.mu must be held
+func (t *Tracker) prune(now time.Time) {
+	for key, e := range t.entries {
+		if now.Sub(e.LastSeen) > t.presenceTTL {
+			delete(t.entries, key)
+		}
+	}
+	for noteID, l := range t.locks {
+		if !now.Before(l.ExpiresAt) {
+			delete(t.locks, noteID)
+		}
+	}
+}
+
+// Touch records or renews the presence of e.User in e.MeetingID and returns
+// the users present in the meeting. changed is false for a renewal that
+// leaves state and note as they were.
+func (t *Tracker) Touch(e Entry) (entries []Entry, changed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.prune(now)
+	key := entryKey{login: e.Login, meetingID: e.MeetingID}
+	prev, ok := t.entries[key]
+	changed = !ok || prev.State != e.State || !sameNote(prev.NoteID, e.NoteID)
+	e.LastSeen = now
+	t.entries[key] = e
+	return t.meetingEntries(e.MeetingID), changed
+}
+
+// Leave removes the presence of a user in a meeting and returns the users
+// still present. changed is false if the user was not present.
+func (t *Tracker) Leave(login string, meetingID int) (entries []Entry, changed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(t.now())
+	key := entryKey{login: login, meetingID: meetingID}
+	_, changed = t.entries[key]
+	delete(t.entries, key)
+	return t.meetingEntries(meetingID), changed
+}
+
+// Meeting returns the users present in a meeting, sorted by name, and the
+// locks on its notes, sorted by note
+func (t *Tracker) Meeting(meetingID int) ([]Entry, []Lock) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(t.now())
+	locks := []Lock{}
+	for _, l := range t.locks {
+		if l.MeetingID == meetingID {
+			locks = append(locks, l)
+		}
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].NoteID < locks[j].NoteID })
+	return t.meetingEntries(meetingID), locks
+}
+
+// meetingEntries returns the entries of a meeting sorted by name; t.mu must be held
+func (t *Tracker) meetingEntries(meetingID int) []Entry {
+	entries := []Entry{}
+	for _, e := range t.entries {
+		if e.MeetingID == meetingID {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Login < entries[j].Login
+	})
+	return entries
+}
+
+// Lock returns the current lock on a note
+func (t *Tracker) Lock(noteID int) (Lock, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(t.now())
+	l, ok := t.locks[noteID]
+	return l, ok
+}
+
+// Acquire locks a note for user, or renews the lock user already holds.
+// A lock held by another user is only taken over with force, and only by
+// a user present in the meeting. previous is the lock replaced: the one
+// taken over, or user's own when renewing. With ErrLocked or ErrNotPeer,
+// the returned lock is the current one.
+func (t *Tracker) Acquire(noteID, meetingID int, user User, force bool) (lock Lock, previous *Lock, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.prune(now)
+	lock = Lock{NoteID: noteID, MeetingID: meetingID, Holder: user, AcquiredAt: now}
+	if current, ok := t.locks[noteID]; ok {
+		switch {
+		case current.Holder.Login == user.Login:
+			lock.AcquiredAt = current.AcquiredAt
+		case !force:
+			return current, nil, ErrLocked
+		case !t.present(user.Login, current.MeetingID):
+			return current, nil, ErrNotPeer
+		}
+		previous = &current
+	}
+	lock.ExpiresAt = now.Add(t.lockTTL)
+	t.locks[noteID] = lock
+	return lock, previous, nil
+}
+
+// Release removes the lock user holds on a note. released is false if the
+// note was not locked.
+func (t *Tracker) Release(noteID int, login string) (released bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(t.now())
+	current, ok := t.locks[noteID]
+	if !ok {
+		return false, nil
+	}
+	if current.Holder.Login != login {
+		return false, ErrNotHolder
+	}
+	delete(t.locks, noteID)
+	return true, nil
+}
+
+// present reports whether a user is present in a meeting; t.mu must be held
+func (t *Tracker) present(login string, meetingID int) bool {
+	_, ok := t.entries[entryKey{login: login, meetingID: meetingID}]
+	return ok
+}
+
+// sameNote compares two optional note IDs
+func sameNote(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
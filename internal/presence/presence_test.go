@@ -0,0 +1,118 @@
+package presence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	alice = User{Login: "alice@example.com", Name: "Alice"}
+	bob   = User{Login: "bob@example.com", Name: "Bob"}
+	carol = User{Login: "carol@example.com", Name: "Carol"}
+)
+
+// newTestTracker returns a tracker with a clock the test advances
+func newTestTracker() (*Tracker, *time.Time) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	t := NewTracker(time.Minute, 2*time.Minute)
+	t.now = func() time.Time { return now }
+	return t, &now
+}
+
+func TestTracker_Presence(t *testing.T) {
+	tracker, now := newTestTracker()
+
+	if _, changed := tracker.Touch(Entry{User: bob, MeetingID: 1, State: StateViewing}); !changed {
+		t.Error("joining must count as a change")
+	}
+	entries, _ := tracker.Touch(Entry{User: alice, MeetingID: 1, State: StateViewing})
+	tracker.Touch(Entry{User: carol, MeetingID: 2, State: StateViewing})
+	if len(entries) != 2 || entries[0].Login != alice.Login || entries[1].Login != bob.Login {
+		t.Fatalf("entries = %+v, want Alice and Bob sorted by name", entries)
+	}
+
+	*now = now.Add(30 * time.Second)
+	if _, changed := tracker.Touch(Entry{User: alice, MeetingID: 1, State: StateViewing}); changed {
+		t.Error("a renewal without changes must not count as a change")
+	}
+	noteID := 7
+	if _, changed := tracker.Touch(Entry{User: alice, MeetingID: 1, State: StateEditing, NoteID: &noteID}); !changed {
+		t.Error("starting to edit must count as a change")
+	}
+
+	// Bob's presence expires without a heartbeat
+	*now = now.Add(45 * time.Second)
+	entries, _ = tracker.Meeting(1)
+	if len(entries) != 1 || entries[0].Login != alice.Login || entries[0].NoteID == nil || *entries[0].NoteID != noteID {
+		t.Fatalf("entries = %+v, want Alice editing note %d", entries, noteID)
+	}
+
+	if entries, changed := tracker.Leave(alice.Login, 1); !changed || len(entries) != 0 {
+		t.Errorf("Leave = %+v, %v; want no entries and a change", entries, changed)
+	}
+	if _, changed := tracker.Leave(alice.Login, 1); changed {
+		t.Error("leaving twice must not count as a change")
+	}
+}
+
+func TestTracker_Locks(t *testing.T) {
+	tracker, now := newTestTracker()
+
+	lock, previous, err := tracker.Acquire(7, 1, alice, false)
+	if err != nil || previous != nil || lock.Holder != alice {
+		t.Fatalf("Acquire = %+v, %v, %v", lock, previous, err)
+	}
+
+	// Renewal keeps the acquisition time and extends the expiry
+	*now = now.Add(time.Minute)
+	renewed, previous, err := tracker.Acquire(7, 1, alice, false)
+	if err != nil || !renewed.AcquiredAt.Equal(lock.AcquiredAt) || !renewed.ExpiresAt.After(lock.ExpiresAt) {
+		t.Fatalf("renewal = %+v, %v", renewed, err)
+	}
+	if previous == nil || previous.Holder != alice {
+		t.Errorf("renewal replaced %+v, want Alice's lock", previous)
+	}
+
+	current, _, err := tracker.Acquire(7, 1, bob, false)
+	if !errors.Is(err, ErrLocked) || current.Holder != alice {
+		t.Fatalf("Acquire by Bob = %+v, %v; want ErrLocked by Alice", current, err)
+	}
+	if _, _, err := tracker.Acquire(7, 1, bob, true); !errors.Is(err, ErrNotPeer) {
+		t.Fatalf("forced Acquire by an absent user = %v, want ErrNotPeer", err)
+	}
+	if _, err := tracker.Release(7, bob.Login); !errors.Is(err, ErrNotHolder) {
+		t.Fatalf("Release by Bob = %v, want ErrNotHolder", err)
+	}
+
+	tracker.Touch(Entry{User: bob, MeetingID: 1, State: StateViewing})
+	taken, previous, err := tracker.Acquire(7, 1, bob, true)
+	if err != nil || taken.Holder != bob || previous == nil || previous.Holder != alice {
+		t.Fatalf("takeover = %+v, %+v, %v", taken, previous, err)
+	}
+
+	if _, locks := tracker.Meeting(1); len(locks) != 1 || locks[0].Holder != bob {
+		t.Fatalf("locks = %+v, want Bob's", locks)
+	}
+	if released, err := tracker.Release(7, bob.Login); !released || err != nil {
+		t.Fatalf("Release = %v, %v", released, err)
+	}
+	if _, ok := tracker.Lock(7); ok {
+		t.Error("lock still held after release")
+	}
+}
+
+func TestTracker_LockExpires(t *testing.T) {
+	tracker, now := newTestTracker()
+
+	if _, _, err := tracker.Acquire(7, 1, alice, false); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(2 * time.Minute)
+	if _, ok := tracker.Lock(7); ok {
+		t.Fatal("lock must expire without renewal")
+	}
+	if lock, _, err := tracker.Acquire(7, 1, bob, false); err != nil || lock.Holder != bob {
+		t.Fatalf("Acquire after expiry = %+v, %v", lock, err)
+	}
+}
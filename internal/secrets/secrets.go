@@ -0,0 +1,124 @@
+// Package secrets encrypts values stored at rest with AES-256-GCM. The key
+// is derived with HKDF-SHA256 from an operator-supplied master key.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Prefix marks an encrypted value and the format version
+const Prefix = "enc:v1:"
+
+// MinMasterKeyLength is the shortest master key accepted, in bytes
+const MinMasterKeyLength = 32
+
+// hkdfInfo binds derived keys to their purpose
+const hkdfInfo = "notebook config secrets v1"
+
+// ErrNoMasterKey is returned when a secret must be encrypted or decrypted
+// but no master key is configured
+var ErrNoMasterKey = errors.New("no master key configured: set NOTEBOOK_MASTER_KEY or --master-key-file")
+
+// ErrDecrypt is returned when a value cannot be decrypted, usually because
+// the master key is not the one it was encrypted with
+var ErrDecrypt = errors.New("decrypt secret: wrong master key or corrupted value")
+
+// Cipher encrypts and decrypts secrets. A nil Cipher stands for a missing
+// master key: it passes through plaintext and empty values but fails with
+// ErrNoMasterKey on everything else.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New derives the encryption key from masterKey
+func New(masterKey []byte) (*Cipher, error) {
+	if len(masterKey) < MinMasterKeyLength {
+		return nil, fmt.Errorf("master key must be at least %d bytes, got %d", MinMasterKeyLength, len(masterKey))
+	}
+
+	key, err := hkdf.Key(sha256.New, masterKey, nil, hkdfInfo, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create GCM: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// ReadKeyFile reads a master key from a file, ignoring surrounding whitespace
+func ReadKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is chosen by the operator
+	if err != nil {
+		return nil, fmt.Errorf("read master key file: %w", err)
+	}
+	return []byte(strings.TrimSpace(string(data))), nil
+}
+
+// GenerateKey returns a new random master key, base64-encoded
+func GenerateKey() (string, error) {
+	key := make([]byte, MinMasterKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generate master key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// IsEncrypted reports whether value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Encrypt encrypts plaintext for the given name. The name is authenticated
+// so a value cannot be moved to another key. Empty values stay empty.
+func (c *Cipher) Encrypt(name, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	if c == nil {
+		return "", ErrNoMasterKey
+	}
+
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), []byte(name))
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt. Values without the Prefix are returned as they
+// are, so plaintext written before encryption was enabled stays readable.
+func (c *Cipher) Decrypt(name, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if c == nil {
+		return "", ErrNoMasterKey
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
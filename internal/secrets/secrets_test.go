@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+func newCipher(t *testing.T, key string) *Cipher {
+	t.Helper()
+	c, err := New([]byte(key))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return c
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c := newCipher(t, testKey)
+
+	encrypted, err := c.Encrypt("llm_api_key", "sk-secret")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "sk-secret") {
+		t.Errorf("unexpected ciphertext %q", encrypted)
+	}
+
+	again, err := c.Encrypt("llm_api_key", "sk-secret")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if again == encrypted {
+		t.Error("expected a fresh nonce for every encryption")
+	}
+
+	decrypted, err := c.Decrypt("llm_api_key", encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if decrypted != "sk-secret" {
+		t.Errorf("Decrypt() = %q, want sk-secret", decrypted)
+	}
+}
+
+func TestCipher_DecryptFailures(t *testing.T) {
+	c := newCipher(t, testKey)
+	encrypted, err := c.Encrypt("llm_api_key", "sk-secret")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	other := newCipher(t, strings.Repeat("x", 40))
+	if _, err := other.Decrypt("llm_api_key", encrypted); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong key: expected ErrDecrypt, got %v", err)
+	}
+	if _, err := c.Decrypt("other_key", encrypted); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong name: expected ErrDecrypt, got %v", err)
+	}
+	if _, err := c.Decrypt("llm_api_key", Prefix+"not base64!"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("corrupted value: expected ErrDecrypt, got %v", err)
+	}
+}
+
+func TestCipher_Nil(t *testing.T) {
+	var c *Cipher
+
+	if _, err := c.Encrypt("llm_api_key", "sk-secret"); !errors.Is(err, ErrNoMasterKey) {
+		t.Errorf("Encrypt: expected ErrNoMasterKey, got %v", err)
+	}
+	if _, err := c.Decrypt("llm_api_key", Prefix+"abc"); !errors.Is(err, ErrNoMasterKey) {
+		t.Errorf("Decrypt: expected ErrNoMasterKey, got %v", err)
+	}
+
+	if v, err := c.Encrypt("llm_api_key", ""); err != nil || v != "" {
+		t.Errorf("Encrypt(\"\") = %q, %v", v, err)
+	}
+	if v, err := c.Decrypt("llm_api_key", "plain"); err != nil || v != "plain" {
+		t.Errorf("Decrypt(plain) = %q, %v", v, err)
+	}
+}
+
+func TestNew_ShortKey(t *testing.T) {
+	if _, err := New([]byte("too short")); err == nil {
+		t.Error("expected error for short master key")
+	}
+}
+
+func TestReadKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "master.key")
+	if err := os.WriteFile(path, []byte(testKey+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+
+	key, err := ReadKeyFile(path)
+	if err != nil {
+		t.Fatalf("ReadKeyFile failed: %v", err)
+	}
+	if string(key) != testKey {
+		t.Errorf("ReadKeyFile() = %q", key)
+	}
+
+	if _, err := ReadKeyFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	if _, err := New([]byte(key)); err != nil {
+		t.Errorf("generated key rejected: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package textdiff computes line-based unified diffs
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxCells bounds the size of the LCS table. Inputs whose differing middle
+// part exceeds it are diffed as a whole replacement of that part.
+const maxCells = 4 << 20
+
+// noNewline marks a last line without a line break, as in diff(1)
+const noNewline = "\\ No newline at end of file\n"
+
+// op is one line of an edit script: ' ' kept, '-' deleted or '+' inserted
+type op struct {
+	kind byte
+	line string
+}
+
+// Unified returns the unified diff from a to b with contextLines lines of
+// context around each change, or "" if both are equal
+func Unified(fromName, toName, a, b string, contextLines int) string {
+	if a == b {
+		return ""
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	writeHunks(&out, ops, max(contextLines, 0))
+	return out.String()
+}
+
+// splitLines splits s after each line break
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns an edit script turning a into b
+func diffLines(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{' ', line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{' ', line})
+	}
+	return ops
+}
+
+// diffMiddle diffs a and b by their longest common subsequence
+func diffMiddle(a, b []string) []op {
+	n, m := len(a), len(b)
+	if (n+1)*(m+1) > maxCells {
+		ops := make([]op, 0, n+m)
+		for _, line := range a {
+			ops = append(ops, op{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, op{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i*(m+1)+j] is the LCS length of a[i:] and b[j:]
+	lcs := make([]int32, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+			case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j]
+			default:
+				lcs[i*(m+1)+j] = lcs[i*(m+1)+j+1]
+			}
+		}
+	}
+
+	ops := make([]op, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+// writeHunks writes the changes of ops with their context as hunks
+func writeHunks(out *strings.Builder, ops []op, contextLines int) {
+	// aPos and bPos are the 0-based line of each op in a and b
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	var changes []int
+	for i, o := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if o.kind != '+' {
+			aPos[i+1]++
+		}
+		if o.kind != '-' {
+			bPos[i+1]++
+		}
+		if o.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+
+	for k := 0; k < len(changes); {
+		start := max(changes[k]-contextLines, 0)
+		last := changes[k]
+		for k++; k < len(changes) && changes[k]-last <= 2*contextLines+1; k++ {
+			last = changes[k]
+		}
+		stop := min(last+contextLines+1, len(ops))
+
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aPos[start], aPos[stop]-aPos[start]), hunkRange(bPos[start], bPos[stop]-bPos[start]))
+		for _, o := range ops[start:stop] {
+			out.WriteByte(o.kind)
+			out.WriteString(o.line)
+			if !strings.HasSuffix(o.line, "\n") {
+				out.WriteString("\n" + noNewline)
+			}
+		}
+	}
+}
+
+// hunkRange formats the 1-based start and length of a hunk side
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
@@ -0,0 +1,64 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "equal", a: "same\n", b: "same\n", want: ""},
+		{
+			name: "changed line",
+			a:    "one\ntwo\nthree\n",
+			b:    "one\n2\nthree\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n",
+		},
+		{
+			name: "insertion into empty",
+			a:    "",
+			b:    "new\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n",
+		},
+		{
+			name: "missing final newline",
+			a:    "one\ntwo",
+			b:    "one\ntwo\n",
+			want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n one\n-two\n\\ No newline at end of file\n+two\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified("a", "b", tt.a, tt.b, 3); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := range 20 {
+		line := strings.Repeat("x", i+1)
+		a = append(a, line)
+		b = append(b, line)
+	}
+	b[1] = "changed"
+	b[18] = "changed"
+
+	got := Unified("a", "b", strings.Join(a, "\n")+"\n", strings.Join(b, "\n")+"\n", 1)
+	if strings.Count(got, "@@ -") != 2 || !strings.Contains(got, "@@ -1,3 +1,3 @@") || !strings.Contains(got, "@@ -18,3 +18,3 @@") {
+		t.Errorf("unexpected hunks:\n%s", got)
+	}
+
+	// Changes closer than twice the context share a hunk
+	b[4] = "changed"
+	if got := Unified("a", "b", strings.Join(a, "\n")+"\n", strings.Join(b, "\n")+"\n", 1); strings.Count(got, "@@ -") != 2 || !strings.Contains(got, "@@ -1,6 +1,6 @@") {
+		t.Errorf("expected merged hunk:\n%s", got)
+	}
+}
@@ -4,6 +4,7 @@ package tsapp
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/http"
 
@@ -33,7 +34,7 @@ func New(hostname, stateDir string) *App {
 		Hostname: hostname,
 		Dir:      stateDir,
 		Logf: func(format string, args ...interface{}) {
-			fmt.Printf("[tsnet] "+format+"\n", args...)
+			slog.Debug(fmt.Sprintf(format, args...), "component", "tsnet")
 		},
 	}
 
@@ -56,16 +57,80 @@ func (a *App) Up(ctx context.Context) error {
 		return fmt.Errorf("failed to get local client: %w", err)
 	}
 
-	fmt.Printf("Tailscale connected. Node: %s\n", status.Self.DNSName)
+	slog.InfoContext(ctx, "tailscale connected", "node", status.Self.DNSName)
 
 	return nil
 }
 
+// NodeStatus summarizes the state of the Tailscale node
+type NodeStatus struct {
+	BackendState string   `json:"backendState"`
+	DNSName      string   `json:"dnsName"`
+	TailscaleIPs []string `json:"tailscaleIPs"`
+	Online       bool     `json:"online"`
+	Tailnet      string   `json:"tailnet,omitempty"`
+	Version      string   `json:"version"`
+}
+
+// Running reports whether the node is logged in and connected
+func (s *NodeStatus) Running() bool {
+	return s.BackendState == "Running"
+}
+
+// Status returns the current state of the Tailscale node
+func (a *App) Status(ctx context.Context) (*NodeStatus, error) {
+	if a.lc == nil {
+		return nil, fmt.Errorf("local client not initialized")
+	}
+
+	st, err := a.lc.StatusWithoutPeers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tailscale status: %w", err)
+	}
+
+	status := &NodeStatus{BackendState: st.BackendState, Version: st.Version}
+	if st.Self != nil {
+		status.DNSName = st.Self.DNSName
+		status.Online = st.Self.Online
+		for _, ip := range st.Self.TailscaleIPs {
+			status.TailscaleIPs = append(status.TailscaleIPs, ip.String())
+		}
+	}
+	if st.CurrentTailnet != nil {
+		status.Tailnet = st.CurrentTailnet.Name
+	}
+	return status, nil
+}
+
 // Listen returns a network listener on the Tailscale network
 func (a *App) Listen(network, addr string) (net.Listener, error) {
 	return a.server.Listen(network, addr)
 }
 
+// ListenTLS returns a TLS listener on the Tailscale network. Certificates
+// for the node's MagicDNS name are obtained from Let's Encrypt through
+// Tailscale, which requires MagicDNS and HTTPS to be enabled for the tailnet.
+func (a *App) ListenTLS(network, addr string) (net.Listener, error) {
+	return a.server.ListenTLS(network, addr)
+}
+
+// ListenFunnel returns a TLS listener that accepts connections from the
+// public internet through Tailscale Funnel, and only those. Funnel must be
+// allowed for the node in the tailnet policy; addr must use port 443, 8443
+// or 10000.
+func (a *App) ListenFunnel(network, addr string) (net.Listener, error) {
+	return a.server.ListenFunnel(network, addr, tsnet.FunnelOnly())
+}
+
+// CertDomain returns the MagicDNS name TLS certificates are issued for
+func (a *App) CertDomain() (string, error) {
+	domains := a.server.CertDomains()
+	if len(domains) == 0 {
+		return "", fmt.Errorf("no certificate domain: enable MagicDNS and HTTPS for the tailnet, see https://tailscale.com/s/https")
+	}
+	return domains[0], nil
+}
+
 // Close shuts down the Tailscale connection
 func (a *App) Close() error {
 	if a.server != nil {
@@ -88,8 +153,6 @@ func (a *App) WhoIs(r *http.Request) (*UserInfo, error) {
 		remoteIP = r.RemoteAddr
 	}
 
-	fmt.Printf("[DEBUG] WhoIs lookup for: %s (original: %s)\n", remoteIP, r.RemoteAddr)
-
 	info, err := a.lc.WhoIs(r.Context(), remoteIP)
 	if err != nil {
 		return nil, fmt.Errorf("whois lookup for %s: %w", remoteIP, err)
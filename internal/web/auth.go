@@ -0,0 +1,55 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+// Authenticator identifies users in standalone mode, where neither
+// Tailscale nor dev mode vouch for them
+type Authenticator interface {
+	// Authenticate returns the user of the request or an error
+	Authenticate(r *http.Request) (*tsapp.UserInfo, error)
+	// LoginURL returns where browsers without a session sign in, or ""
+	LoginURL(r *http.Request) string
+	// Handler serves the authenticator's endpoints below /auth/, or is nil
+	Handler() http.Handler
+}
+
+// publicPaths are reachable without authentication in standalone mode
+var publicPaths = []string{"/healthz", "/readyz", "/auth/"}
+
+// machinePaths are used by API clients and calendar apps, which get 401
+// instead of a redirect to the login page
+var machinePaths = []string{"/api/", "/caldav/", "/.well-known/", "/calendar.ics", "/metrics"}
+
+// hasPathPrefix reports whether path equals or lies below any of prefixes
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == strings.TrimSuffix(prefix, "/") || strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// requireAuthMiddleware rejects requests without an identity. Browsers are
+// sent to the login page if the authenticator has one.
+func (s *Server) requireAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(userContextKey{}).(*tsapp.UserInfo); ok || hasPathPrefix(r.URL.Path, publicPaths) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method == http.MethodGet && !hasPathPrefix(r.URL.Path, machinePaths) {
+			if loginURL := s.auth.LoginURL(r); loginURL != "" {
+				http.Redirect(w, r, loginURL, http.StatusFound)
+				return
+			}
+		}
+		writeError(w, http.StatusUnauthorized, "authentication required")
+	})
+}
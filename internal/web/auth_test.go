@@ -0,0 +1,104 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/auth"
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+// loginAuth is an authenticator with a login page that accepts nobody
+type loginAuth struct{}
+
+func (loginAuth) Authenticate(*http.Request) (*tsapp.UserInfo, error) {
+	return nil, auth.ErrUnauthenticated
+}
+
+func (loginAuth) LoginURL(*http.Request) string { return "/auth/login" }
+
+func (loginAuth) Handler() http.Handler { return nil }
+
+func TestStandaloneMode_HeaderAuth(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	headerAuth, err := auth.NewHeaderAuth("X-Forwarded-User", "", []string{"127.0.0.1/32"})
+	if err != nil {
+		t.Fatalf("NewHeaderAuth() error = %v", err)
+	}
+	srv.auth = headerAuth
+	srv.admins = map[string]bool{"alice@example.com": true}
+	handler := srv.Handler()
+
+	tests := []struct {
+		name       string
+		path       string
+		remoteAddr string
+		user       string
+		status     int
+	}{
+		{name: "trusted proxy", path: "/api/meetings", remoteAddr: "127.0.0.1:5000", user: "alice@example.com", status: http.StatusOK},
+		{name: "admin from header", path: "/api/admin/diagnostics", remoteAddr: "127.0.0.1:5000", user: "alice@example.com", status: http.StatusOK},
+		{name: "non-admin from header", path: "/api/admin/diagnostics", remoteAddr: "127.0.0.1:5000", user: "bob@example.com", status: http.StatusForbidden},
+		{name: "missing header", path: "/api/meetings", remoteAddr: "127.0.0.1:5000", status: http.StatusUnauthorized},
+		{name: "spoofed header", path: "/api/meetings", remoteAddr: "198.51.100.9:5000", user: "alice@example.com", status: http.StatusUnauthorized},
+		{name: "web UI without login page", path: "/", remoteAddr: "198.51.100.9:5000", status: http.StatusUnauthorized},
+		{name: "health probe", path: "/healthz", remoteAddr: "198.51.100.9:5000", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.user != "" {
+				req.Header.Set("X-Forwarded-User", tt.user)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// whoami reports the proxy's identity
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/whoami", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	req.Header.Set("X-Forwarded-User", "alice@example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	var user tsapp.UserInfo
+	if err := json.NewDecoder(w.Body).Decode(&user); err != nil {
+		t.Fatalf("failed to decode whoami: %v", err)
+	}
+	if user.LoginName != "alice@example.com" {
+		t.Errorf("whoami LoginName = %q", user.LoginName)
+	}
+}
+
+func TestStandaloneMode_LoginRedirect(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.auth = loginAuth{}
+	handler := srv.Handler()
+
+	// Browsers are sent to the login page, API clients get 401
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/meetings/1", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/auth/login" {
+		t.Errorf("expected redirect to login, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	for _, path := range []string{"/api/meetings", "/calendar.ics", "/caldav/"} {
+		req = httptest.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("GET %s: expected status 401, got %d", path, w.Code)
+		}
+	}
+}
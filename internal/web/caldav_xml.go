@@ -0,0 +1,207 @@
+package web
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// XML namespaces used by WebDAV, CalDAV and the CalendarServer extensions
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+const maxDAVRequestSize = 1 << 20 // 1 MiB
+
+// Property names notebook knows how to answer
+var (
+	propResourceType       = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName        = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUser        = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL       = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propPrivilegeSet       = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propGetETag            = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType     = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propGetLastModified    = xml.Name{Space: nsDAV, Local: "getlastmodified"}
+	propCalendarHomeSet    = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propCalendarData       = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propSupportedComponent = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propGetCTag            = xml.Name{Space: nsCS, Local: "getctag"}
+)
+
+// davElement captures only the name of an arbitrary XML element
+type davElement struct {
+	XMLName xml.Name
+}
+
+// davPropNames is the <prop> list of a PROPFIND or REPORT request
+type davPropNames struct {
+	Names []davElement `xml:",any"`
+}
+
+// davPropfind is a PROPFIND request body; an empty body means allprop
+type davPropfind struct {
+	XMLName xml.Name      `xml:"DAV: propfind"`
+	AllProp *struct{}     `xml:"DAV: allprop"`
+	Prop    *davPropNames `xml:"DAV: prop"`
+}
+
+// calDAVReport covers the calendar-query and calendar-multiget REPORTs
+type calDAVReport struct {
+	XMLName xml.Name
+	Prop    *davPropNames `xml:"DAV: prop"`
+	Hrefs   []string      `xml:"DAV: href"`
+	Filter  *calDAVFilter `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type calDAVFilter struct {
+	CompFilter calDAVCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type calDAVCompFilter struct {
+	Name        string             `xml:"name,attr"`
+	TimeRange   *calDAVTimeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilters []calDAVCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type calDAVTimeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+// eventTimeRange returns the time-range of the VEVENT comp-filter, if any
+func (f *calDAVFilter) eventTimeRange() *calDAVTimeRange {
+	if f == nil {
+		return nil
+	}
+	for _, cf := range f.CompFilter.CompFilters {
+		if cf.Name == "VEVENT" {
+			return cf.TimeRange
+		}
+	}
+	return nil
+}
+
+// davProperty is one property value in a response. Value is escaped text,
+// Inner is trusted pre-rendered XML for structured values.
+type davProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+	Inner   string `xml:",innerxml"`
+}
+
+type davPropValues struct {
+	Values []davProperty `xml:",any"`
+}
+
+type davPropstat struct {
+	Prop   davPropValues `xml:"prop"`
+	Status string        `xml:"status"`
+}
+
+type davResponse struct {
+	Href      string        `xml:"href"`
+	Status    string        `xml:"status,omitempty"`
+	Propstats []davPropstat `xml:"propstat,omitempty"`
+}
+
+type davMultistatus struct {
+	XMLName   xml.Name      `xml:"DAV: multistatus"`
+	Responses []davResponse `xml:"response"`
+}
+
+// davStatus formats an HTTP status line as used in <status> elements
+func davStatus(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+// newDAVResponse answers the requested properties from the available ones,
+// reporting unknown properties as 404 in a second propstat. A nil request
+// list (allprop) returns everything available.
+func newDAVResponse(href string, available map[xml.Name]davProperty, requested []xml.Name) davResponse {
+	resp := davResponse{Href: href}
+
+	var found, missing []davProperty
+	if requested == nil {
+		for _, name := range sortedPropNames(available) {
+			found = append(found, available[name])
+		}
+	}
+	for _, name := range requested {
+		if p, ok := available[name]; ok {
+			found = append(found, p)
+		} else {
+			missing = append(missing, davProperty{XMLName: name})
+		}
+	}
+
+	if len(found) > 0 {
+		resp.Propstats = append(resp.Propstats, davPropstat{Prop: davPropValues{Values: found}, Status: davStatus(http.StatusOK)})
+	}
+	if len(missing) > 0 {
+		resp.Propstats = append(resp.Propstats, davPropstat{Prop: davPropValues{Values: missing}, Status: davStatus(http.StatusNotFound)})
+	}
+
+	return resp
+}
+
+// sortedPropNames returns the property names in a stable order
+func sortedPropNames(props map[xml.Name]davProperty) []xml.Name {
+	order := []xml.Name{
+		propResourceType, propDisplayName, propCurrentUser, propPrincipalURL, propPrivilegeSet,
+		propCalendarHomeSet, propSupportedComponent, propGetCTag,
+		propGetETag, propGetContentType, propGetLastModified, propCalendarData,
+	}
+	var names []xml.Name
+	for _, name := range order {
+		if _, ok := props[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// requestedProps decodes the <prop> list; nil means all properties
+func requestedProps(p *davPropNames) []xml.Name {
+	if p == nil {
+		return nil
+	}
+	names := make([]xml.Name, 0, len(p.Names))
+	for _, n := range p.Names {
+		names = append(names, n.XMLName)
+	}
+	return names
+}
+
+// decodeDAVBody decodes an optional XML request body into v.
+// It reports false for an empty body.
+func decodeDAVBody(r *http.Request, v any) (bool, error) {
+	dec := xml.NewDecoder(io.LimitReader(r.Body, maxDAVRequestSize))
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// writeMultistatus writes a 207 Multi-Status response
+func writeMultistatus(w http.ResponseWriter, responses []davResponse) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(davMultistatus{Responses: responses})
+}
+
+// hrefProp renders a property whose value is a single DAV:href
+func hrefProp(name xml.Name, href string) davProperty {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(href))
+	return davProperty{XMLName: name, Inner: `<href xmlns="DAV:">` + b.String() + `</href>`}
+}
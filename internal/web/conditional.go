@@ -0,0 +1,81 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// entityTag returns the ETag of a meeting or note at version
+func entityTag(id, version int) string {
+	return fmt.Sprintf(`"%d-%d"`, id, version)
+}
+
+// ifMatchFailed reports whether the If-Match header of a write rules out a
+// resource currently tagged etag. If-Match uses the strong comparison, so
+// weak tags never match; a request without the header always passes.
+func ifMatchFailed(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return false
+		}
+	}
+	return true
+}
+
+// writePreconditionFailed answers a conditional write whose resource has
+// changed with 412 and the current state, so the client can merge
+func writePreconditionFailed(w http.ResponseWriter, etag string, current any) {
+	w.Header().Set("ETag", etag)
+	writeJSON(w, http.StatusPreconditionFailed, current)
+}
+
+// writeTaggedJSON writes data like writeJSON, tagged with etag
+func writeTaggedJSON(w http.ResponseWriter, status int, etag string, data any) {
+	w.Header().Set("ETag", etag)
+	writeJSON(w, status, data)
+}
+
+// writeConditionalJSON answers a GET with data tagged with etag, or with
+// 304 Not Modified when If-None-Match matches. An empty etag is derived
+// from the encoded body, for lists that have no version of their own.
+func writeConditionalJSON(w http.ResponseWriter, r *http.Request, etag string, data any) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	if etag == "" {
+		sum := sha256.Sum256(buf.Bytes())
+		etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// ifMatchVersion returns the version a write must still find when it is
+// applied, or 0 unless the request is conditional on a specific version
+func ifMatchVersion(r *http.Request, version int) int {
+	if header := r.Header.Get("If-Match"); header == "" || header == "*" {
+		return 0
+	}
+	return version
+}
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// serveConditional sends a request with the given headers and returns the recorder
+func serveConditional(t *testing.T, handler http.Handler, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(context.Background(), method, path, strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestConditional_Meeting(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	body := `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`
+	w := serveConditional(t, handler, http.MethodPost, "/api/meetings", body, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var meeting models.Meeting
+	if err := json.NewDecoder(w.Body).Decode(&meeting); err != nil {
+		t.Fatalf("decode meeting: %v", err)
+	}
+	path := "/api/meetings/" + strconv.Itoa(meeting.ID)
+	etag := w.Header().Get("ETag")
+	if etag != `"`+strconv.Itoa(meeting.ID)+`-1"` {
+		t.Fatalf("unexpected ETag %q", etag)
+	}
+
+	if w := serveConditional(t, handler, http.MethodGet, path, "", map[string]string{"If-None-Match": etag}); w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for matching If-None-Match, got %d", w.Code)
+	}
+
+	update := `{"subject": "Daily standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`
+	w = serveConditional(t, handler, http.MethodPut, path, update, map[string]string{"If-Match": etag})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for matching If-Match, got %d: %s", w.Code, w.Body.String())
+	}
+	current := w.Header().Get("ETag")
+	if current == etag {
+		t.Fatal("expected a new ETag after the update")
+	}
+
+	stale := `{"subject": "Stale", "meeting_date": "2026-03-01", "start_time": "09:00"}`
+	w = serveConditional(t, handler, http.MethodPut, path, stale, map[string]string{"If-Match": etag})
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for stale If-Match, got %d", w.Code)
+	}
+	var state models.Meeting
+	if err := json.NewDecoder(w.Body).Decode(&state); err != nil || state.Subject != "Daily standup" || w.Header().Get("ETag") != current {
+		t.Errorf("expected current state with its ETag in the 412 body, got %+v (err %v)", state, err)
+	}
+
+	if w := serveConditional(t, handler, http.MethodGet, path, "", map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("expected 200 for stale If-None-Match, got %d", w.Code)
+	}
+	if w := serveConditional(t, handler, http.MethodDelete, path, "", map[string]string{"If-Match": etag}); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412 for stale delete, got %d", w.Code)
+	}
+	if w := serveConditional(t, handler, http.MethodDelete, path, "", map[string]string{"If-Match": current}); w.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for delete at the current version, got %d", w.Code)
+	}
+}
+
+func TestConditional_Note(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	var note models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(meeting.ID)+`, "content": "first"}`, http.StatusCreated, &note)
+	path := "/api/notes/" + strconv.Itoa(note.ID)
+	etag := entityTag(note.ID, note.Version)
+
+	if w := serveConditional(t, handler, http.MethodPut, path, `{"content": "second"}`, map[string]string{"If-Match": etag}); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for matching If-Match, got %d: %s", w.Code, w.Body.String())
+	}
+	w := serveConditional(t, handler, http.MethodPut, path, `{"content": "stale"}`, map[string]string{"If-Match": etag})
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for stale If-Match, got %d", w.Code)
+	}
+	var state models.Note
+	if err := json.NewDecoder(w.Body).Decode(&state); err != nil || state.Content != "second" {
+		t.Errorf("expected current state in the 412 body, got %+v (err %v)", state, err)
+	}
+	if w := serveConditional(t, handler, http.MethodPut, path, `{"content": "weak"}`, map[string]string{"If-Match": "W/" + entityTag(note.ID, state.Version)}); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412 for a weak If-Match, got %d", w.Code)
+	}
+
+	listPath := "/api/meetings/" + strconv.Itoa(meeting.ID) + "/notes"
+	w = serveConditional(t, handler, http.MethodGet, listPath, "", nil)
+	listTag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || listTag == "" {
+		t.Fatalf("expected tagged list, got %d with ETag %q", w.Code, listTag)
+	}
+	if w := serveConditional(t, handler, http.MethodGet, listPath, "", map[string]string{"If-None-Match": listTag}); w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for unchanged list, got %d", w.Code)
+	}
+	serveJSON(t, handler, http.MethodPut, path, `{"content": "third"}`, http.StatusOK, nil)
+	if w := serveConditional(t, handler, http.MethodGet, listPath, "", map[string]string{"If-None-Match": listTag}); w.Code != http.StatusOK {
+		t.Errorf("expected 200 for changed list, got %d", w.Code)
+	}
+}
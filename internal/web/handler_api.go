@@ -3,11 +3,21 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/zorak1103/notebook/internal/tsapp"
 )
 
+// devUser is the identity of every request in dev mode
+var devUser = tsapp.UserInfo{
+	DisplayName:   "Dev User",
+	LoginName:     devModeCreatedBy,
+	ProfilePicURL: "https://ui-avatars.com/api/?name=Dev+User&size=128",
+	NodeName:      "dev-machine",
+	NodeID:        "dev-node-12345",
+}
+
 // versionResponse holds build-time version information.
 type versionResponse struct {
 	Version string `json:"version"`
@@ -16,43 +26,32 @@ type versionResponse struct {
 }
 
 // handleVersion returns the build-time version information.
-func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
-	resp := versionResponse{
+func (s *Server) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.buildInfo())
+}
+
+// buildInfo returns the build-time version information
+func (s *Server) buildInfo() versionResponse {
+	return versionResponse{
 		Version: s.version,
 		Commit:  s.commit,
 		Date:    s.date,
 	}
-	writeJSON(w, http.StatusOK, resp)
-	s.logRequest(r.Method, r.URL.Path, http.StatusOK)
 }
 
-// handleWhoAmI returns the authenticated user's Tailscale information.
+// handleWhoAmI returns the authenticated user's Tailscale information, or
+// the identity from the reverse proxy or OIDC login in standalone mode.
 // In dev mode, it returns mock data since Tailscale is not available.
 func (s *Server) handleWhoAmI(w http.ResponseWriter, r *http.Request) {
-	var userInfo *tsapp.UserInfo
-	var err error
-
-	if s.devMode {
-		// Mock data for development without Tailscale
-		userInfo = &tsapp.UserInfo{
-			DisplayName:   "Dev User",
-			LoginName:     "dev@example.com",
-			ProfilePicURL: "https://ui-avatars.com/api/?name=Dev+User&size=128",
-			NodeName:      "dev-machine",
-			NodeID:        "dev-node-12345",
-		}
-	} else {
-		// Real Tailscale WhoIs lookup
-		if s.tsapp == nil {
-			http.Error(w, "Tailscale not initialized", http.StatusInternalServerError)
-			return
-		}
+	if !s.devMode && s.tsapp == nil && s.auth == nil {
+		http.Error(w, "Tailscale not initialized", http.StatusInternalServerError)
+		return
+	}
 
-		userInfo, err = s.tsapp.WhoIs(r)
-		if err != nil {
-			http.Error(w, "failed to authenticate user: "+err.Error(), http.StatusUnauthorized)
-			return
-		}
+	userInfo, err := s.currentUser(r)
+	if err != nil {
+		http.Error(w, "failed to authenticate user: "+err.Error(), http.StatusUnauthorized)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -60,6 +59,48 @@ func (s *Server) handleWhoAmI(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 		return
 	}
+}
+
+// userContextKey stores the identity resolved by identityMiddleware
+type userContextKey struct{}
 
-	s.logRequest(r.Method, r.URL.Path, http.StatusOK)
+// currentUser returns the Tailscale identity of the request, or the one
+// established by the authenticator in standalone mode.
+// In dev mode, it returns mock data since Tailscale is not available.
+func (s *Server) currentUser(r *http.Request) (*tsapp.UserInfo, error) {
+	if user, ok := r.Context().Value(userContextKey{}).(*tsapp.UserInfo); ok {
+		return user, nil
+	}
+	if s.devMode {
+		user := devUser
+		return &user, nil
+	}
+	if s.auth != nil {
+		return s.auth.Authenticate(r)
+	}
+	if s.tsapp == nil {
+		return nil, errors.New("tailscale not initialized")
+	}
+	return s.tsapp.WhoIs(r)
+}
+
+// isAdmin reports whether user may use the admin endpoints.
+// The dev mode user is always an admin.
+func (s *Server) isAdmin(user *tsapp.UserInfo) bool {
+	return s.devMode || s.admins[user.LoginName]
+}
+
+// requireAdmin writes 401 or 403 and returns false unless the request
+// comes from an admin
+func (s *Server) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
+		return false
+	}
+	if !s.isAdmin(user) {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return false
+	}
+	return true
 }
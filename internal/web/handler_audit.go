@@ -0,0 +1,98 @@
+package web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+// maxAuditLimit caps the entries of one audit log query
+const maxAuditLimit = 10000
+
+// handleListAudit handles GET /api/admin/audit?actor=&action=&entity_type=&entity_id=&from=&to=&limit=&format=json|csv
+func (s *Server) handleListAudit(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	filter, err := auditFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, http.StatusBadRequest, "invalid format, expected json or csv")
+		return
+	}
+
+	entries, err := repositories.NewAuditRepository(s.database.DB).WithContext(r.Context()).List(filter)
+	if err != nil {
+		s.logError(r, "failed to list audit log", err)
+		writeError(w, http.StatusInternalServerError, "failed to list audit log")
+		return
+	}
+
+	if format == "csv" {
+		writeAuditCSV(w, entries)
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// auditFilter validates the query parameters of an audit log query
+func auditFilter(r *http.Request) (repositories.AuditFilter, error) {
+	q := r.URL.Query()
+	filter := repositories.AuditFilter{
+		Actor:      q.Get("actor"),
+		Action:     q.Get("action"),
+		EntityType: q.Get("entity_type"),
+		EntityID:   q.Get("entity_id"),
+		From:       q.Get("from"),
+		To:         q.Get("to"),
+	}
+
+	if filter.Action != "" && !slices.Contains(models.AuditActions, filter.Action) {
+		return filter, fmt.Errorf("invalid action, expected one of %s", strings.Join(models.AuditActions, ", "))
+	}
+	if filter.EntityType != "" && !slices.Contains(models.AuditEntityTypes, filter.EntityType) {
+		return filter, fmt.Errorf("invalid entity_type, expected one of %s", strings.Join(models.AuditEntityTypes, ", "))
+	}
+	for name, date := range map[string]string{"from": filter.From, "to": filter.To} {
+		if _, err := time.Parse(dateFormat, date); date != "" && err != nil {
+			return filter, fmt.Errorf("invalid %s date, expected YYYY-MM-DD", name)
+		}
+	}
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxAuditLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxAuditLimit)
+		}
+		filter.Limit = n
+	}
+	return filter, nil
+}
+
+// writeAuditCSV writes one row per audit entry with the changes as JSON
+func writeAuditCSV(w http.ResponseWriter, entries []*models.AuditEntry) {
+	w.Header().Set("Content-Type", contentTypeCSV)
+	w.Header().Set("Content-Disposition", `attachment; filename="notebook-audit.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"id", "created_at", "actor", "action", "entity_type", "entity_id", "changes"})
+	for _, e := range entries {
+		changes, _ := json.Marshal(e.Changes)
+		_ = cw.Write([]string{strconv.Itoa(e.ID), e.CreatedAt.UTC().Format(time.RFC3339), e.Actor, e.Action,
+			e.EntityType, e.EntityID, string(changes)})
+	}
+	cw.Flush()
+}
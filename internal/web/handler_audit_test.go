@@ -0,0 +1,102 @@
+package web
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+func TestHandleListAudit(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	for _, req := range []*http.Request{
+		httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings",
+			strings.NewReader(`{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`)),
+		httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/config",
+			strings.NewReader(`{"llm_api_key": "sk-secret-value", "llm_prompt_summary": "Summarize briefly"}`)),
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code >= http.StatusBadRequest {
+			t.Fatalf("%s %s: status %d: %s", req.Method, req.URL.Path, w.Code, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/admin/audit?entity_type=config", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "sk-secret-value") {
+		t.Fatalf("audit log leaks the API key: %s", w.Body.String())
+	}
+	var entries []*models.AuditEntry
+	if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	keys := map[string]bool{}
+	for _, e := range entries {
+		if e.Actor != devModeCreatedBy {
+			t.Errorf("entry %d actor = %q", e.ID, e.Actor)
+		}
+		keys[e.EntityID] = true
+	}
+	if len(entries) != 2 || !keys[configKeyLLMAPIKey] || !keys[configKeyLLMPromptSummary] {
+		t.Errorf("unexpected config entries %+v", entries)
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/admin/audit?format=csv", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != contentTypeCSV {
+		t.Fatalf("expected CSV, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	if len(records) != 4 || records[0][0] != "id" || records[3][3] != models.AuditActionCreate || records[3][4] != models.AuditEntityMeeting {
+		t.Errorf("unexpected CSV %v", records)
+	}
+}
+
+func TestHandleListAudit_Validation(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	for _, query := range []string{"action=drop", "entity_type=share", "from=yesterday", "to=2026-13-01", "limit=0", "format=xml"} {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/admin/audit?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestHandleListAudit_RequiresAdmin(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+
+	user := &tsapp.UserInfo{LoginName: "bob@example.com"}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/admin/audit", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey{}, user))
+	w := httptest.NewRecorder()
+	srv.handleListAudit(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", w.Code)
+	}
+}
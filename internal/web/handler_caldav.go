@@ -0,0 +1,634 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/ical"
+)
+
+// CalDAV layout: /caldav/ is both the principal and the calendar home, and
+// /caldav/meetings/ is the single calendar collection holding one resource
+// per meeting.
+const (
+	calDAVRoot         = "/caldav/"
+	calDAVCalendar     = "/caldav/meetings/"
+	calDAVDisplayName  = "notebook"
+	calDAVTimeLayout   = "20060102T150405Z"
+	calDAVResourceType = "text/calendar; charset=utf-8; component=VEVENT"
+	calDAVMethods      = "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT"
+)
+
+var (
+	reportCalendarQuery    = xml.Name{Space: nsCalDAV, Local: "calendar-query"}
+	reportCalendarMultiget = xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}
+)
+
+// handleCalDAVWellKnown handles /.well-known/caldav (RFC 6764) for client autodiscovery
+func (s *Server) handleCalDAVWellKnown(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, calDAVRoot, http.StatusMovedPermanently)
+}
+
+// handleCalDAVOptions handles OPTIONS /caldav/ and advertises CalDAV support
+func (s *Server) handleCalDAVOptions(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	w.Header().Set("Allow", calDAVMethods)
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleCalDAVPropfind handles PROPFIND on the principal, the calendar
+// collection and individual meeting resources. Depth 0 describes only the
+// target; any other depth includes its direct children.
+func (s *Server) handleCalDAVPropfind(w http.ResponseWriter, r *http.Request) {
+	var req davPropfind
+	if _, err := decodeDAVBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid PROPFIND body")
+		return
+	}
+
+	responses, err := s.propfind(r.Context(), r.URL.Path, requestedProps(req.Prop), r.Header.Get("Depth") != "0")
+	if err != nil {
+		s.logError(r, "failed to answer PROPFIND", err)
+		writeError(w, http.StatusInternalServerError, "failed to read calendar")
+		return
+	}
+	if responses == nil {
+		writeError(w, http.StatusNotFound, "calendar resource not found")
+		return
+	}
+
+	writeMultistatus(w, responses)
+}
+
+// propfind collects the responses for a PROPFIND target; nil means not found
+func (s *Server) propfind(ctx context.Context, target string, requested []xml.Name, withChildren bool) ([]davResponse, error) {
+	switch {
+	case target == calDAVRoot:
+		responses := []davResponse{newDAVResponse(calDAVRoot, principalProps(), requested)}
+		if !withChildren {
+			return responses, nil
+		}
+		collection, err := s.calendarCollectionResponse(ctx, requested)
+		if err != nil {
+			return nil, err
+		}
+		return append(responses, collection), nil
+	case target == calDAVCalendar || target+"/" == calDAVCalendar:
+		return s.calendarPropfind(ctx, requested, withChildren)
+	case strings.HasPrefix(target, calDAVCalendar):
+		m, err := s.findCalDAVMeeting(ctx, path.Base(target))
+		if err != nil || m == nil {
+			return nil, err
+		}
+		return []davResponse{newDAVResponse(calDAVHref(m), resourceProps(m), requested)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// calendarPropfind describes the calendar collection and, optionally, its meetings
+func (s *Server) calendarPropfind(ctx context.Context, requested []xml.Name, withChildren bool) ([]davResponse, error) {
+	collection, err := s.calendarCollectionResponse(ctx, requested)
+	if err != nil {
+		return nil, err
+	}
+	responses := []davResponse{collection}
+	if !withChildren {
+		return responses, nil
+	}
+
+	meetings, err := repositories.NewMeetingRepository(s.database.DB).WithContext(ctx).List("meeting_date", true)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range meetings {
+		responses = append(responses, newDAVResponse(calDAVHref(m), resourceProps(m), requested))
+	}
+	return responses, nil
+}
+
+// calendarCollectionResponse describes /caldav/meetings/
+func (s *Server) calendarCollectionResponse(ctx context.Context, requested []xml.Name) (davResponse, error) {
+	ctag, err := repositories.NewMeetingRepository(s.database.DB).WithContext(ctx).ChangeTag()
+	if err != nil {
+		return davResponse{}, err
+	}
+
+	props := map[xml.Name]davProperty{
+		propResourceType:       {XMLName: propResourceType, Inner: `<collection xmlns="DAV:"/><calendar xmlns="` + nsCalDAV + `"/>`},
+		propDisplayName:        {XMLName: propDisplayName, Value: calDAVDisplayName},
+		propGetCTag:            {XMLName: propGetCTag, Value: ctag},
+		propSupportedComponent: {XMLName: propSupportedComponent, Inner: `<comp xmlns="` + nsCalDAV + `" name="VEVENT"/>`},
+		propPrivilegeSet:       {XMLName: propPrivilegeSet, Inner: privilegeSetXML()},
+	}
+	return newDAVResponse(calDAVCalendar, props, requested), nil
+}
+
+// principalProps describes /caldav/, which doubles as the calendar home
+func principalProps() map[xml.Name]davProperty {
+	return map[xml.Name]davProperty{
+		propResourceType:    {XMLName: propResourceType, Inner: `<collection xmlns="DAV:"/><principal xmlns="DAV:"/>`},
+		propDisplayName:     {XMLName: propDisplayName, Value: calDAVDisplayName},
+		propCurrentUser:     hrefProp(propCurrentUser, calDAVRoot),
+		propPrincipalURL:    hrefProp(propPrincipalURL, calDAVRoot),
+		propCalendarHomeSet: hrefProp(propCalendarHomeSet, calDAVRoot),
+	}
+}
+
+// privilegeSetXML grants full access; authorization happens at the tailnet
+func privilegeSetXML() string {
+	var b strings.Builder
+	for _, p := range []string{"read", "write", "write-content", "write-properties", "bind", "unbind"} {
+		b.WriteString(`<privilege xmlns="DAV:"><` + p + `/></privilege>`)
+	}
+	return b.String()
+}
+
+// resourceProps describes one meeting resource
+func resourceProps(m *models.Meeting) map[xml.Name]davProperty {
+	return map[xml.Name]davProperty{
+		propResourceType:    {XMLName: propResourceType},
+		propGetETag:         {XMLName: propGetETag, Value: calDAVETag(m)},
+		propGetContentType:  {XMLName: propGetContentType, Value: calDAVResourceType},
+		propGetLastModified: {XMLName: propGetLastModified, Value: m.UpdatedAt.UTC().Format(http.TimeFormat)},
+	}
+}
+
+// handleCalDAVReport handles the calendar-query and calendar-multiget REPORTs
+// clients use to sync the calendar collection
+func (s *Server) handleCalDAVReport(w http.ResponseWriter, r *http.Request) {
+	var report calDAVReport
+	ok, err := decodeDAVBody(r, &report)
+	if err != nil || !ok {
+		writeError(w, http.StatusBadRequest, "invalid REPORT body")
+		return
+	}
+
+	var (
+		meetings []*models.Meeting
+		missing  []string
+	)
+	switch report.XMLName {
+	case reportCalendarQuery:
+		rangeStart, rangeEnd, rangeErr := parseTimeRange(report.Filter.eventTimeRange())
+		if rangeErr != nil {
+			writeError(w, http.StatusBadRequest, rangeErr.Error())
+			return
+		}
+		meetings, err = s.queryCalDAVMeetings(r.Context(), rangeStart, rangeEnd)
+	case reportCalendarMultiget:
+		meetings, missing, err = s.multigetCalDAVMeetings(r.Context(), report.Hrefs)
+	default:
+		writeError(w, http.StatusForbidden, "unsupported report "+report.XMLName.Local)
+		return
+	}
+	if err != nil {
+		s.logError(r, "failed to answer REPORT", err)
+		writeError(w, http.StatusInternalServerError, "failed to query calendar")
+		return
+	}
+
+	requested := requestedProps(report.Prop)
+	responses := make([]davResponse, 0, len(meetings)+len(missing))
+	for _, m := range meetings {
+		resp, err := s.reportResponse(m, requested, requestBaseURL(r))
+		if err != nil {
+			// Skip rows with unparseable dates rather than failing the whole sync
+			s.logError(r, fmt.Sprintf("skipping meeting %d in REPORT", m.ID), err)
+			continue
+		}
+		responses = append(responses, resp)
+	}
+	for _, href := range missing {
+		responses = append(responses, davResponse{Href: href, Status: davStatus(http.StatusNotFound)})
+	}
+
+	writeMultistatus(w, responses)
+}
+
+// reportResponse describes a meeting resource, including calendar-data when requested
+func (s *Server) reportResponse(m *models.Meeting, requested []xml.Name, baseURL string) (davResponse, error) {
+	props := resourceProps(m)
+	for _, name := range requested {
+		if name != propCalendarData {
+			continue
+		}
+		data, err := s.meetingICS(m, baseURL)
+		if err != nil {
+			return davResponse{}, err
+		}
+		props[propCalendarData] = davProperty{XMLName: propCalendarData, Value: string(data)}
+	}
+	return newDAVResponse(calDAVHref(m), props, requested), nil
+}
+
+// queryCalDAVMeetings lists the meetings overlapping [rangeStart, rangeEnd);
+// zero bounds are unbounded
+func (s *Server) queryCalDAVMeetings(ctx context.Context, rangeStart, rangeEnd time.Time) ([]*models.Meeting, error) {
+	meetings, err := repositories.NewMeetingRepository(s.database.DB).WithContext(ctx).List("meeting_date", true)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*models.Meeting
+	for _, m := range meetings {
+		start, end, err := meetingInterval(m, s.timeLocation())
+		if err != nil {
+			continue
+		}
+		if (!rangeEnd.IsZero() && !start.Before(rangeEnd)) || (!rangeStart.IsZero() && !end.After(rangeStart)) {
+			continue
+		}
+		matched = append(matched, m)
+	}
+	return matched, nil
+}
+
+// parseTimeRange parses the bounds of a time-range filter; nil matches everything
+func parseTimeRange(tr *calDAVTimeRange) (start, end time.Time, err error) {
+	if tr == nil {
+		return time.Time{}, time.Time{}, nil
+	}
+	if start, err = parseCalDAVTime(tr.Start); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end, err = parseCalDAVTime(tr.End); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, end, nil
+}
+
+// parseCalDAVTime parses a time-range bound; an empty value is unbounded
+func parseCalDAVTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(calDAVTimeLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time-range value %q", value)
+	}
+	return t, nil
+}
+
+// multigetCalDAVMeetings resolves the hrefs of a calendar-multiget,
+// returning the hrefs that do not name an existing resource separately
+func (s *Server) multigetCalDAVMeetings(ctx context.Context, hrefs []string) (found []*models.Meeting, missing []string, err error) {
+	for _, href := range hrefs {
+		u, parseErr := url.Parse(strings.TrimSpace(href))
+		if parseErr != nil || !strings.HasPrefix(u.Path, calDAVCalendar) {
+			missing = append(missing, href)
+			continue
+		}
+
+		m, err := s.findCalDAVMeeting(ctx, path.Base(u.Path))
+		if err != nil {
+			return nil, nil, err
+		}
+		if m == nil {
+			missing = append(missing, href)
+			continue
+		}
+		found = append(found, m)
+	}
+	return found, missing, nil
+}
+
+// handleCalDAVGet handles GET /caldav/meetings/{name}
+func (s *Server) handleCalDAVGet(w http.ResponseWriter, r *http.Request) {
+	m, err := s.findCalDAVMeeting(r.Context(), r.PathValue("name"))
+	if err != nil {
+		s.logError(r, "failed to look up calendar resource", err)
+		writeError(w, http.StatusInternalServerError, "failed to read calendar")
+		return
+	}
+	if m == nil {
+		writeError(w, http.StatusNotFound, "calendar resource not found")
+		return
+	}
+
+	etag := calDAVETag(m)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	data, err := s.meetingICS(m, requestBaseURL(r))
+	if err != nil {
+		s.logError(r, "failed to encode calendar resource", err)
+		writeError(w, http.StatusInternalServerError, "failed to encode calendar")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeCalendar)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// handleCalDAVPut handles PUT /caldav/meetings/{name}. Clients create new
+// meetings under a name of their choosing or replace an existing meeting's
+// scheduling fields, summary (DESCRIPTION) and keywords (CATEGORIES).
+func (s *Server) handleCalDAVPut(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+
+	existing, err := s.findCalDAVMeeting(r.Context(), name)
+	if err != nil {
+		s.logError(r, "failed to look up calendar resource", err)
+		writeError(w, http.StatusInternalServerError, "failed to read calendar")
+		return
+	}
+	if calDAVPreconditionFailed(r, existing) {
+		writeError(w, http.StatusPreconditionFailed, "calendar resource has changed")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxICSUploadSize)
+	ev, err := s.parseCalDAVEvent(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid calendar: "+err.Error())
+		return
+	}
+
+	m := s.meetingFromCalDAVEvent(ev, existing)
+	if err := validateMeeting(m); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
+		return
+	}
+	m.CreatedBy = user.LoginName
+
+	status, err := s.saveCalDAVMeeting(repo, m, existing, name)
+	if err != nil {
+		s.logError(r, "failed to store calendar resource", err)
+		writeError(w, http.StatusInternalServerError, "failed to store calendar resource")
+		return
+	}
+	if status == http.StatusConflict {
+		writeError(w, http.StatusConflict, "an event with this UID already exists")
+		return
+	}
+
+	saved, err := repo.GetByID(m.ID)
+	if err != nil || saved == nil {
+		s.logError(r, "failed to reload calendar resource", err)
+		writeError(w, http.StatusInternalServerError, "failed to store calendar resource")
+		return
+	}
+
+	if status == http.StatusCreated {
+		s.publish(r, events.MeetingCreated, saved.ID, saved)
+	} else {
+		s.publish(r, events.MeetingUpdated, saved.ID, saved)
+	}
+	w.Header().Set("ETag", calDAVETag(saved))
+	w.WriteHeader(status)
+}
+
+// parseCalDAVEvent reads the event of a PUT body. Of a recurring event only
+// the series master is kept, as meetings do not recur.
+func (s *Server) parseCalDAVEvent(r *http.Request) (*ical.Event, error) {
+	cal, err := ical.Parse(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	events, err := ical.Events(cal, s.timeLocation())
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("calendar contains no events")
+	}
+
+	for _, ev := range events {
+		if ev.RecurrenceID == "" {
+			return ev, nil
+		}
+	}
+	return events[0], nil
+}
+
+// meetingFromCalDAVEvent maps a VEVENT onto a meeting. An unchanged set of
+// attendee addresses keeps the existing free-text participants, which may
+// list people without an address that calendars cannot represent. Clients
+// often drop DESCRIPTION and CATEGORIES when they only move an event, so
+// their absence keeps the existing summary and keywords.
+func (s *Server) meetingFromCalDAVEvent(ev *ical.Event, existing *models.Meeting) *models.Meeting {
+	m := meetingFromEvent(ev, s.timeLocation())
+
+	if ev.Description != "" {
+		description := ev.Description
+		m.Summary = &description
+	}
+	if len(ev.Categories) > 0 {
+		keywords := strings.Join(ev.Categories, ", ")
+		m.Keywords = &keywords
+	}
+
+	if existing != nil {
+		// Keep the zone the meeting was planned in
+		if loc, err := meetingLocation(existing.Timezone, s.timeLocation()); err == nil {
+			setLocalTimes(m, loc)
+		}
+
+		attendees := ev.Attendees
+		if ev.Organizer != nil {
+			attendees = append(attendees, *ev.Organizer)
+		}
+		if sameAddresses(attendees, participantAttendees(existing.Participants)) {
+			m.Participants = existing.Participants
+		}
+		if ev.Description == "" {
+			m.Summary = existing.Summary
+		}
+		if len(ev.Categories) == 0 {
+			m.Keywords = existing.Keywords
+		}
+	}
+
+	return m
+}
+
+// saveCalDAVMeeting updates existing or creates a new meeting stored under
+// name, returning the status to answer with
+func (s *Server) saveCalDAVMeeting(repo *repositories.MeetingRepository, m *models.Meeting, existing *models.Meeting, name string) (int, error) {
+	if existing != nil {
+		m.ID = existing.ID
+		if err := repo.Update(m); err != nil {
+			return 0, err
+		}
+		return http.StatusNoContent, nil
+	}
+
+	recurrenceID := ""
+	if m.ICalRecurrenceID != nil {
+		recurrenceID = *m.ICalRecurrenceID
+	}
+	clash, err := repo.GetByICalUID(*m.ICalUID, recurrenceID)
+	if err != nil {
+		return 0, err
+	}
+	if clash != nil {
+		return http.StatusConflict, nil
+	}
+
+	m.CalDAVName = &name
+	if err := repo.Create(m); err != nil {
+		return 0, err
+	}
+	return http.StatusCreated, nil
+}
+
+// handleCalDAVDelete handles DELETE /caldav/meetings/{name}
+func (s *Server) handleCalDAVDelete(w http.ResponseWriter, r *http.Request) {
+	m, err := s.findCalDAVMeeting(r.Context(), r.PathValue("name"))
+	if err != nil {
+		s.logError(r, "failed to look up calendar resource", err)
+		writeError(w, http.StatusInternalServerError, "failed to read calendar")
+		return
+	}
+	if m == nil {
+		writeError(w, http.StatusNotFound, "calendar resource not found")
+		return
+	}
+	if calDAVPreconditionFailed(r, m) {
+		writeError(w, http.StatusPreconditionFailed, "calendar resource has changed")
+		return
+	}
+
+	if err := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context()).Delete(m.ID); err != nil {
+		s.logError(r, "failed to delete calendar resource", err)
+		writeError(w, http.StatusInternalServerError, "failed to delete meeting")
+		return
+	}
+
+	s.publish(r, events.MeetingDeleted, m.ID, deletedEntity{ID: m.ID})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findCalDAVMeeting resolves a resource name to a meeting. Names are either
+// the one a client chose on creation or the published UID plus ".ics".
+func (s *Server) findCalDAVMeeting(ctx context.Context, name string) (*models.Meeting, error) {
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(ctx)
+
+	m, err := repo.GetByCalDAVName(name)
+	if err != nil || m != nil {
+		return m, err
+	}
+
+	uid, ok := strings.CutSuffix(name, ".ics")
+	if !ok {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+
+	if idText, ok := strings.CutPrefix(uid, "meeting-"); ok {
+		if id, err := strconv.Atoi(strings.TrimSuffix(idText, "@notebook")); err == nil {
+			m, err = repo.GetByID(id)
+		}
+	} else {
+		m, err = repo.GetByICalUID(uid, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Only answer under the name the meeting is actually published as
+	if m == nil || calDAVName(m) != name {
+		//nolint:nilnil // Intentional: not found is not an error
+		return nil, nil
+	}
+	return m, nil
+}
+
+// calDAVName returns the resource name a meeting is published under
+func calDAVName(m *models.Meeting) string {
+	if m.CalDAVName != nil && *m.CalDAVName != "" {
+		return *m.CalDAVName
+	}
+	return meetingUID(m) + ".ics"
+}
+
+// calDAVHref returns the path of a meeting resource
+func calDAVHref(m *models.Meeting) string {
+	return calDAVCalendar + url.PathEscape(calDAVName(m))
+}
+
+// calDAVETag returns a resource's entity tag, the same as the meeting's in the API
+func calDAVETag(m *models.Meeting) string {
+	return entityTag(m.ID, m.Version)
+}
+
+// calDAVPreconditionFailed evaluates If-Match and If-None-Match of a write
+// against the resource's current state; existing is nil for a new resource
+func calDAVPreconditionFailed(r *http.Request, existing *models.Meeting) bool {
+	ifMatch := r.Header.Get("If-Match")
+	ifNoneMatch := r.Header.Get("If-None-Match")
+
+	if existing == nil {
+		return ifMatch != ""
+	}
+
+	etag := calDAVETag(existing)
+	if ifMatch != "" && !etagMatches(ifMatch, etag) {
+		return true
+	}
+	return etagMatches(ifNoneMatch, etag)
+}
+
+// sameAddresses reports whether both lists name the same set of e-mail addresses
+func sameAddresses(a, b []ical.Attendee) bool {
+	set := func(attendees []ical.Attendee) map[string]bool {
+		addrs := map[string]bool{}
+		for _, at := range attendees {
+			if at.Email != "" {
+				addrs[strings.ToLower(at.Email)] = true
+			}
+		}
+		return addrs
+	}
+
+	sa, sb := set(a), set(b)
+	if len(sa) != len(sb) {
+		return false
+	}
+	for addr := range sa {
+		if !sb[addr] {
+			return false
+		}
+	}
+	return true
+}
+
+// meetingICS renders a meeting as a single-event calendar object
+func (s *Server) meetingICS(m *models.Meeting, baseURL string) ([]byte, error) {
+	ev, err := s.meetingEvent(m, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := ical.NewCalendar(calendarProdID)
+	cal.Components = append(cal.Components, ev.Component(m.UpdatedAt))
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, cal); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
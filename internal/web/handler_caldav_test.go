@@ -0,0 +1,329 @@
+package web
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+const thunderbirdEventHref = "/caldav/meetings/5f0c3a2e-8b1d-4c6e-9a47-2d1e6b3f9c10.ics"
+
+// loadCalDAVFixture reads a request body recorded from a calendar client
+func loadCalDAVFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "caldav", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return string(data)
+}
+
+// calDAVRequest replays a request through the full handler, as a client would send it
+func calDAVRequest(t *testing.T, srv *Server, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), method, target, strings.NewReader(body))
+	req.Host = "notebook.example.ts.net"
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(w, req)
+	return w
+}
+
+type testMultistatus struct {
+	Responses []struct {
+		Href      string `xml:"href"`
+		Status    string `xml:"status"`
+		Propstats []struct {
+			Prop struct {
+				Inner string `xml:",innerxml"`
+			} `xml:"prop"`
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+func decodeMultistatus(t *testing.T, w *httptest.ResponseRecorder) testMultistatus {
+	t.Helper()
+
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("expected status 207, got %d: %s", w.Code, w.Body.String())
+	}
+	var ms testMultistatus
+	if err := xml.Unmarshal(w.Body.Bytes(), &ms); err != nil {
+		t.Fatalf("invalid multistatus response: %v", err)
+	}
+	return ms
+}
+
+func newCalDAVTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	srv := newTestServer(t)
+	t.Cleanup(func() { _ = srv.database.Close() })
+	srv.location = time.UTC
+	return srv
+}
+
+func TestCalDAV_Discovery(t *testing.T) {
+	srv := newCalDAVTestServer(t)
+
+	w := calDAVRequest(t, srv, "PROPFIND", "/.well-known/caldav", "", nil)
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != calDAVRoot {
+		t.Errorf("expected redirect to %s, got %d %q", calDAVRoot, w.Code, w.Header().Get("Location"))
+	}
+
+	w = calDAVRequest(t, srv, http.MethodOptions, calDAVRoot, "", nil)
+	if !strings.Contains(w.Header().Get("DAV"), "calendar-access") {
+		t.Errorf("expected calendar-access in DAV header, got %q", w.Header().Get("DAV"))
+	}
+
+	w = calDAVRequest(t, srv, "PROPFIND", calDAVRoot, loadCalDAVFixture(t, "apple_propfind_principal.xml"), map[string]string{"Depth": "0"})
+	ms := decodeMultistatus(t, w)
+	if len(ms.Responses) != 1 || ms.Responses[0].Href != calDAVRoot {
+		t.Fatalf("expected a single response for the principal, got %+v", ms.Responses)
+	}
+
+	propstats := ms.Responses[0].Propstats
+	if len(propstats) != 2 {
+		t.Fatalf("expected found and not-found propstats, got %+v", propstats)
+	}
+	if !strings.Contains(propstats[0].Prop.Inner, "calendar-home-set") || !strings.Contains(propstats[0].Prop.Inner, "<href xmlns=\"DAV:\">/caldav/</href>") {
+		t.Errorf("expected calendar-home-set pointing at %s, got %s", calDAVRoot, propstats[0].Prop.Inner)
+	}
+	if !strings.Contains(propstats[1].Status, "404") || !strings.Contains(propstats[1].Prop.Inner, "email-address-set") {
+		t.Errorf("expected unknown property reported as 404, got %+v", propstats[1])
+	}
+}
+
+func TestCalDAV_PropfindCalendar(t *testing.T) {
+	srv := newCalDAVTestServer(t)
+
+	repo := repositories.NewMeetingRepository(srv.database.DB)
+	if err := repo.Create(&models.Meeting{CreatedBy: "a@example.com", Subject: "Standup", MeetingDate: "2026-03-10", StartTime: "09:00"}); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+
+	w := calDAVRequest(t, srv, "PROPFIND", calDAVCalendar, loadCalDAVFixture(t, "thunderbird_propfind_calendar.xml"), map[string]string{"Depth": "0"})
+	ms := decodeMultistatus(t, w)
+	if len(ms.Responses) != 1 {
+		t.Fatalf("expected only the collection for Depth 0, got %d responses", len(ms.Responses))
+	}
+	found := ms.Responses[0].Propstats[0].Prop.Inner
+	for _, want := range []string{"getctag", "<calendar xmlns=\"urn:ietf:params:xml:ns:caldav\"/>", `name="VEVENT"`, "write-content"} {
+		if !strings.Contains(found, want) {
+			t.Errorf("expected %q in collection properties, got %s", want, found)
+		}
+	}
+
+	w = calDAVRequest(t, srv, "PROPFIND", calDAVCalendar, loadCalDAVFixture(t, "thunderbird_propfind_etags.xml"), map[string]string{"Depth": "1"})
+	ms = decodeMultistatus(t, w)
+	if len(ms.Responses) != 2 {
+		t.Fatalf("expected collection and one resource, got %d responses", len(ms.Responses))
+	}
+	resource := ms.Responses[1]
+	if resource.Href != "/caldav/meetings/meeting-1@notebook.ics" {
+		t.Errorf("unexpected resource href %q", resource.Href)
+	}
+	if !strings.Contains(resource.Propstats[0].Prop.Inner, "&#34;1-") {
+		t.Errorf("expected ETag derived from the meeting, got %s", resource.Propstats[0].Prop.Inner)
+	}
+}
+
+func TestCalDAV_PutGetDelete(t *testing.T) {
+	srv := newCalDAVTestServer(t)
+	srv.devMode = true
+	event := loadCalDAVFixture(t, "thunderbird_put.ics")
+
+	w := calDAVRequest(t, srv, http.MethodPut, thunderbirdEventHref, event, map[string]string{"If-None-Match": "*"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag on create")
+	}
+
+	m, err := repositories.NewMeetingRepository(srv.database.DB).GetByCalDAVName("5f0c3a2e-8b1d-4c6e-9a47-2d1e6b3f9c10.ics")
+	if err != nil || m == nil {
+		t.Fatalf("expected meeting stored under the resource name, got %v (err %v)", m, err)
+	}
+	if m.Subject != "Sprint Planning" || m.MeetingDate != "2026-03-12" || m.StartTime != "09:00" || m.EndTime == nil || *m.EndTime != "10:30" {
+		t.Errorf("unexpected scheduling fields %+v", m)
+	}
+	if m.Summary == nil || *m.Summary != "Goals for sprint 14" || m.Keywords == nil || *m.Keywords != "Planning, Team" {
+		t.Errorf("expected summary and keywords from DESCRIPTION and CATEGORIES, got %v / %v", m.Summary, m.Keywords)
+	}
+
+	if w := calDAVRequest(t, srv, http.MethodPut, thunderbirdEventHref, event, map[string]string{"If-None-Match": "*"}); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412 when creating over an existing resource, got %d", w.Code)
+	}
+
+	w = calDAVRequest(t, srv, http.MethodGet, thunderbirdEventHref, "", nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "UID:5f0c3a2e-8b1d-4c6e-9a47-2d1e6b3f9c10") {
+		t.Fatalf("expected calendar object with the client's UID, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "CATEGORIES:Planning,Team") {
+		t.Errorf("expected keywords as categories, got %s", w.Body.String())
+	}
+	if w := calDAVRequest(t, srv, http.MethodGet, thunderbirdEventHref, "", map[string]string{"If-None-Match": etag}); w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for matching ETag, got %d", w.Code)
+	}
+
+	changed := strings.Replace(event, "SUMMARY:Sprint Planning", "SUMMARY:Sprint 14 Planning", 1)
+	if w := calDAVRequest(t, srv, http.MethodPut, thunderbirdEventHref, changed, map[string]string{"If-Match": `"1-0"`}); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412 for stale If-Match, got %d", w.Code)
+	}
+	if w := calDAVRequest(t, srv, http.MethodPut, thunderbirdEventHref, changed, map[string]string{"If-Match": etag}); w.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for update, got %d: %s", w.Code, w.Body.String())
+	}
+	if m, _ := repositories.NewMeetingRepository(srv.database.DB).GetByID(m.ID); m == nil || m.Subject != "Sprint 14 Planning" {
+		t.Errorf("expected updated subject, got %+v", m)
+	}
+
+	if w := calDAVRequest(t, srv, http.MethodDelete, thunderbirdEventHref, "", nil); w.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for delete, got %d", w.Code)
+	}
+	if w := calDAVRequest(t, srv, http.MethodGet, thunderbirdEventHref, "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 after delete, got %d", w.Code)
+	}
+}
+
+func TestCalDAV_PutKeepsFreeTextParticipants(t *testing.T) {
+	srv := newCalDAVTestServer(t)
+	srv.devMode = true
+
+	repo := repositories.NewMeetingRepository(srv.database.DB)
+	participants := "Alice <alice@example.com>, Bob from facilities"
+	meeting := &models.Meeting{CreatedBy: "a@example.com", Subject: "Office move", MeetingDate: "2026-03-10", StartTime: "09:00", Participants: &participants}
+	if err := repo.Create(meeting); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+
+	href := "/caldav/meetings/meeting-1@notebook.ics"
+	w := calDAVRequest(t, srv, http.MethodGet, href, "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	moved := strings.Replace(w.Body.String(), "DTSTART:20260310T090000Z", "DTSTART:20260310T100000Z", 1)
+	if w := calDAVRequest(t, srv, http.MethodPut, href, moved, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	updated, err := repo.GetByID(meeting.ID)
+	if err != nil || updated == nil {
+		t.Fatalf("failed to reload meeting: %v", err)
+	}
+	if updated.StartTime != "10:00" {
+		t.Errorf("expected start moved to 10:00, got %s", updated.StartTime)
+	}
+	if updated.Participants == nil || *updated.Participants != participants {
+		t.Errorf("expected participants kept, got %v", updated.Participants)
+	}
+}
+
+func TestCalDAV_PutKeepsSummaryAndKeywords(t *testing.T) {
+	srv := newCalDAVTestServer(t)
+	srv.devMode = true
+
+	repo := repositories.NewMeetingRepository(srv.database.DB)
+	summary, keywords := "Agreed on the floor plan", "office, facilities"
+	meeting := &models.Meeting{CreatedBy: "a@example.com", Subject: "Office move", MeetingDate: "2026-03-10", StartTime: "09:00", Summary: &summary, Keywords: &keywords}
+	if err := repo.Create(meeting); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+
+	href := "/caldav/meetings/meeting-1@notebook.ics"
+	w := calDAVRequest(t, srv, http.MethodGet, href, "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	// The client moves the event and drops DESCRIPTION and CATEGORIES
+	var lines []string
+	for _, line := range strings.Split(w.Body.String(), "\r\n") {
+		if !strings.HasPrefix(line, "DESCRIPTION") && !strings.HasPrefix(line, "CATEGORIES") {
+			lines = append(lines, line)
+		}
+	}
+	moved := strings.Replace(strings.Join(lines, "\r\n"), "DTSTART:20260310T090000Z", "DTSTART:20260310T100000Z", 1)
+	if w := calDAVRequest(t, srv, http.MethodPut, href, moved, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	updated, err := repo.GetByID(meeting.ID)
+	if err != nil || updated == nil {
+		t.Fatalf("failed to reload meeting: %v", err)
+	}
+	if updated.StartTime != "10:00" {
+		t.Errorf("expected start moved to 10:00, got %s", updated.StartTime)
+	}
+	if updated.Summary == nil || *updated.Summary != summary || updated.Keywords == nil || *updated.Keywords != keywords {
+		t.Errorf("expected summary and keywords kept, got %v and %v", updated.Summary, updated.Keywords)
+	}
+	if versions, err := repo.ListSummaries(meeting.ID); err != nil || len(versions) != 1 {
+		t.Errorf("expected no new summary version, got %d (err %v)", len(versions), err)
+	}
+}
+
+func TestCalDAV_PutRejectsDuplicateUID(t *testing.T) {
+	srv := newCalDAVTestServer(t)
+	srv.devMode = true
+	event := loadCalDAVFixture(t, "thunderbird_put.ics")
+
+	if w := calDAVRequest(t, srv, http.MethodPut, thunderbirdEventHref, event, nil); w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", w.Code)
+	}
+	if w := calDAVRequest(t, srv, http.MethodPut, "/caldav/meetings/copy.ics", event, nil); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 for a second resource with the same UID, got %d", w.Code)
+	}
+}
+
+func TestCalDAV_Report(t *testing.T) {
+	srv := newCalDAVTestServer(t)
+
+	repo := repositories.NewMeetingRepository(srv.database.DB)
+	for _, m := range []*models.Meeting{
+		{CreatedBy: "a@example.com", Subject: "March review", MeetingDate: "2026-03-20", StartTime: "09:00"},
+		{CreatedBy: "a@example.com", Subject: "April review", MeetingDate: "2026-04-20", StartTime: "09:00"},
+	} {
+		if err := repo.Create(m); err != nil {
+			t.Fatalf("failed to create meeting: %v", err)
+		}
+	}
+
+	w := calDAVRequest(t, srv, "REPORT", calDAVCalendar, loadCalDAVFixture(t, "thunderbird_report_query.xml"), map[string]string{"Depth": "1"})
+	ms := decodeMultistatus(t, w)
+	if len(ms.Responses) != 1 {
+		t.Fatalf("expected only the March meeting in range, got %d responses", len(ms.Responses))
+	}
+	if !strings.Contains(ms.Responses[0].Propstats[0].Prop.Inner, "SUMMARY:March review") {
+		t.Errorf("expected calendar-data in report, got %s", ms.Responses[0].Propstats[0].Prop.Inner)
+	}
+
+	w = calDAVRequest(t, srv, "REPORT", calDAVCalendar, loadCalDAVFixture(t, "apple_report_multiget.xml"), map[string]string{"Depth": "1"})
+	ms = decodeMultistatus(t, w)
+	if len(ms.Responses) != 2 {
+		t.Fatalf("expected two responses, got %d", len(ms.Responses))
+	}
+	if ms.Responses[0].Href != "/caldav/meetings/meeting-1@notebook.ics" || len(ms.Responses[0].Propstats) == 0 {
+		t.Errorf("expected the existing meeting first, got %+v", ms.Responses[0])
+	}
+	if !strings.Contains(ms.Responses[1].Status, "404") {
+		t.Errorf("expected 404 for unknown href, got %+v", ms.Responses[1])
+	}
+}
@@ -0,0 +1,192 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/ical"
+)
+
+const (
+	contentTypeCalendar    = "text/calendar; charset=utf-8"
+	calendarProdID         = "-//notebook//Meeting Notes//EN"
+	defaultMeetingDuration = time.Hour
+)
+
+// handleCalendarFeed handles GET /calendar.ics with an optional ?user=<login>
+// filter. It serves meetings as a read-only iCalendar subscription and
+// supports conditional requests so clients only download changed feeds.
+func (s *Server) handleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+
+	user := r.URL.Query().Get("user")
+	var (
+		meetings []*models.Meeting
+		err      error
+	)
+	if user != "" {
+		meetings, err = repo.ListForUser(user)
+	} else {
+		meetings, err = repo.List("meeting_date", true)
+	}
+	if err != nil {
+		s.logError(r, "failed to list meetings for calendar feed", err)
+		writeError(w, http.StatusInternalServerError, "failed to list meetings")
+		return
+	}
+
+	cal := ical.NewCalendar(calendarProdID)
+	cal.Properties = append(cal.Properties, ical.NewProperty("X-WR-CALNAME", ical.EscapeText(calendarName(user))))
+
+	baseURL := requestBaseURL(r)
+	for _, m := range meetings {
+		ev, err := s.meetingEvent(m, baseURL)
+		if err != nil {
+			// Skip rows with unparseable dates rather than failing the whole feed
+			s.logError(r, fmt.Sprintf("skipping meeting %d in calendar feed", m.ID), err)
+			continue
+		}
+		cal.Components = append(cal.Components, ev.Component(m.UpdatedAt))
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, cal); err != nil {
+		s.logError(r, "failed to encode calendar feed", err)
+		writeError(w, http.StatusInternalServerError, "failed to encode calendar")
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeCalendar)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// calendarName returns the display name calendar clients show for the feed
+func calendarName(user string) string {
+	if user == "" {
+		return "notebook"
+	}
+	return "notebook (" + user + ")"
+}
+
+// meetingEvent converts a meeting into a calendar event linking back to the SPA
+func (s *Server) meetingEvent(m *models.Meeting, baseURL string) (*ical.Event, error) {
+	start, end, err := meetingInterval(m, s.timeLocation())
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &ical.Event{
+		UID:       meetingUID(m),
+		Summary:   m.Subject,
+		Start:     start,
+		End:       end,
+		URL:       fmt.Sprintf("%s/meetings/%d", baseURL, m.ID),
+		Attendees: participantAttendees(m.Participants),
+	}
+	if m.Summary != nil {
+		ev.Description = *m.Summary
+	}
+	if m.Keywords != nil {
+		for _, kw := range strings.Split(*m.Keywords, ",") {
+			if kw = strings.TrimSpace(kw); kw != "" {
+				ev.Categories = append(ev.Categories, kw)
+			}
+		}
+	}
+
+	return ev, nil
+}
+
+// meetingInterval returns a meeting's start and end instants. Rows without
+// derived UTC instants are resolved from their local date and times in their
+// own zone, or loc; meetings without an end time are given defaultMeetingDuration.
+func meetingInterval(m *models.Meeting, loc *time.Location) (start, end time.Time, err error) {
+	if m.StartUTC == nil {
+		resolved := *m
+		zone, err := meetingLocation(m.Timezone, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if err := resolved.ResolveUTC(zone); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		m = &resolved
+	}
+
+	start = *m.StartUTC
+	if m.EndUTC == nil {
+		return start, start.Add(defaultMeetingDuration), nil
+	}
+	return start, *m.EndUTC, nil
+}
+
+// meetingUID returns the UID a meeting is published under. Imported meetings
+// keep their original UID unless they override one instance of a series,
+// which would clash with the series master in clients.
+func meetingUID(m *models.Meeting) string {
+	if m.ICalUID != nil && *m.ICalUID != "" && (m.ICalRecurrenceID == nil || *m.ICalRecurrenceID == "") {
+		return *m.ICalUID
+	}
+	return fmt.Sprintf("meeting-%d@notebook", m.ID)
+}
+
+// participantAttendees extracts the e-mail addresses from the free-text
+// participants field; entries without an address are skipped
+func participantAttendees(participants *string) []ical.Attendee {
+	if participants == nil {
+		return nil
+	}
+
+	var attendees []ical.Attendee
+	for _, entry := range strings.Split(*participants, ",") {
+		addr, err := mail.ParseAddress(strings.TrimSpace(entry))
+		if err != nil {
+			continue
+		}
+		attendees = append(attendees, ical.Attendee{Name: addr.Name, Email: addr.Address})
+	}
+	return attendees
+}
+
+// requestBaseURL reconstructs the scheme and host the client used to reach us
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// etagMatches reports whether an If-None-Match header matches etag,
+// using the weak comparison RFC 9110 prescribes for If-None-Match
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
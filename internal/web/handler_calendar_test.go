@@ -0,0 +1,197 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/ical"
+)
+
+func getCalendarFeed(t *testing.T, srv *Server, target, ifNoneMatch string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+	req.Host = "notebook.example.ts.net"
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+
+	srv.handleCalendarFeed(w, req)
+	return w
+}
+
+func TestHandleCalendarFeed_Events(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.location = time.UTC
+
+	repo := repositories.NewMeetingRepository(srv.database.DB)
+	end := "11:30"
+	summary := "Agreed on the rollout plan"
+	participants := "Alice <alice@example.com>, Bob"
+	meeting := &models.Meeting{
+		CreatedBy:    "alice@example.com",
+		Subject:      "Rollout",
+		MeetingDate:  "2026-03-10",
+		StartTime:    "10:00",
+		EndTime:      &end,
+		Summary:      &summary,
+		Participants: &participants,
+	}
+	if err := repo.Create(meeting); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+
+	w := getCalendarFeed(t, srv, "/calendar.ics", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	cal, err := ical.Parse(w.Body)
+	if err != nil {
+		t.Fatalf("feed is not valid iCalendar: %v", err)
+	}
+	events, err := ical.Events(cal, time.UTC)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d (err %v)", len(events), err)
+	}
+
+	ev := events[0]
+	if !ev.Start.Equal(time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)) || !ev.End.Equal(time.Date(2026, 3, 10, 11, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected interval %v - %v", ev.Start, ev.End)
+	}
+	if ev.Summary != "Rollout" || ev.Description != summary {
+		t.Errorf("unexpected summary/description %q / %q", ev.Summary, ev.Description)
+	}
+	if ev.URL != "http://notebook.example.ts.net/meetings/1" {
+		t.Errorf("unexpected URL %q", ev.URL)
+	}
+	if len(ev.Attendees) != 1 || ev.Attendees[0].Email != "alice@example.com" {
+		t.Errorf("expected only participants with addresses as attendees, got %+v", ev.Attendees)
+	}
+}
+
+func TestHandleCalendarFeed_ConditionalGet(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.location = time.UTC
+
+	repo := repositories.NewMeetingRepository(srv.database.DB)
+	meeting := &models.Meeting{CreatedBy: "a@example.com", Subject: "One", MeetingDate: "2026-03-10", StartTime: "10:00"}
+	if err := repo.Create(meeting); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+
+	first := getCalendarFeed(t, srv, "/calendar.ics", "")
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+
+	notModified := getCalendarFeed(t, srv, "/calendar.ics", etag)
+	if notModified.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for matching If-None-Match, got %d", notModified.Code)
+	}
+	if notModified.Body.Len() != 0 {
+		t.Error("expected empty body for 304")
+	}
+
+	if err := repo.Create(&models.Meeting{CreatedBy: "a@example.com", Subject: "Two", MeetingDate: "2026-03-11", StartTime: "10:00"}); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+
+	changed := getCalendarFeed(t, srv, "/calendar.ics", etag)
+	if changed.Code != http.StatusOK {
+		t.Errorf("expected 200 after feed changed, got %d", changed.Code)
+	}
+	if changed.Header().Get("ETag") == etag {
+		t.Error("expected ETag to change with the feed")
+	}
+}
+
+func TestHandleCalendarFeed_UserFilter(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.location = time.UTC
+
+	repo := repositories.NewMeetingRepository(srv.database.DB)
+	bobInvited := "Bob <bob@example.com>"
+	fixtures := []*models.Meeting{
+		{CreatedBy: "alice@example.com", Subject: "Alice solo", MeetingDate: "2026-03-10", StartTime: "09:00"},
+		{CreatedBy: "alice@example.com", Subject: "Alice with Bob", MeetingDate: "2026-03-10", StartTime: "10:00", Participants: &bobInvited},
+		{CreatedBy: "bob@example.com", Subject: "Bob solo", MeetingDate: "2026-03-10", StartTime: "11:00"},
+	}
+	for _, m := range fixtures {
+		if err := repo.Create(m); err != nil {
+			t.Fatalf("failed to create meeting: %v", err)
+		}
+	}
+
+	w := getCalendarFeed(t, srv, "/calendar.ics?user=bob@example.com", "")
+	body := w.Body.String()
+
+	if strings.Contains(body, "Alice solo") {
+		t.Error("feed for bob should not contain alice's own meeting")
+	}
+	if !strings.Contains(body, "Alice with Bob") || !strings.Contains(body, "Bob solo") {
+		t.Errorf("feed for bob is missing meetings:\n%s", body)
+	}
+}
+
+func TestMeetingInterval(t *testing.T) {
+	late := "01:30"
+	empty := ""
+
+	tests := []struct {
+		name        string
+		endTime     *string
+		expectedEnd time.Time
+	}{
+		{"no end time uses default duration", nil, time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC).Add(defaultMeetingDuration)},
+		{"empty end time uses default duration", &empty, time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC).Add(defaultMeetingDuration)},
+		{"end before start crosses midnight", &late, time.Date(2026, 3, 11, 1, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &models.Meeting{MeetingDate: "2026-03-10", StartTime: "23:00", EndTime: tt.endTime}
+			_, end, err := meetingInterval(m, time.UTC)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !end.Equal(tt.expectedEnd) {
+				t.Errorf("expected end %v, got %v", tt.expectedEnd, end)
+			}
+		})
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected bool
+	}{
+		{"", false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`"xyz"`, false},
+		{"*", true},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `"abc"`); got != tt.expected {
+			t.Errorf("etagMatches(%q) = %v, expected %v", tt.header, got, tt.expected)
+		}
+	}
+}
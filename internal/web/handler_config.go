@@ -1,13 +1,18 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/zorak1103/notebook/internal/db/models"
 	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/secrets"
 )
 
 // Config key constants
@@ -28,6 +33,8 @@ type ConfigData struct {
 	Language         string `json:"language"`
 	LLMPromptSummary string `json:"llm_prompt_summary"`
 	LLMPromptEnhance string `json:"llm_prompt_enhance"`
+	// Locked lists keys set by the operator that cannot be changed here
+	Locked []string `json:"locked"`
 }
 
 // ConfigUpdateRequest represents the configuration update request
@@ -40,9 +47,15 @@ type ConfigUpdateRequest struct {
 	LLMPromptEnhance string `json:"llm_prompt_enhance"`
 }
 
+// configRepository returns a config repository that runs with ctx and
+// encrypts secrets with the server's cipher
+func (s *Server) configRepository(ctx context.Context) *repositories.ConfigRepository {
+	return repositories.NewConfigRepository(s.database.DB).WithContext(ctx).WithCipher(s.cipher)
+}
+
 // handleGetConfig returns the current configuration with masked API key
 func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
-	repo := repositories.NewConfigRepository(s.database.DB)
+	repo := s.configRepository(r.Context())
 
 	configs, err := repo.GetAll()
 	if err != nil {
@@ -52,6 +65,7 @@ func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := buildConfigData(configs)
+	data.Locked = s.lockedConfigKeys()
 	writeJSON(w, http.StatusOK, data)
 }
 
@@ -80,10 +94,28 @@ func (s *Server) handleUpdateConfig(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	repo := repositories.NewConfigRepository(s.database.DB)
+	repo := s.configRepository(r.Context())
+
+	// Reject changes to keys locked by the operator
+	lockedKey, err := s.lockedConfigChange(repo, &req)
+	if err != nil {
+		s.logError(r, "failed to get configuration", err)
+		writeError(w, http.StatusInternalServerError, "failed to get configuration")
+		return
+	}
+	if lockedKey != "" {
+		writeError(w, http.StatusConflict, fmt.Sprintf("configuration key %s is locked", lockedKey))
+		return
+	}
 
 	// Save non-empty, non-masked fields
-	if err := saveConfigFields(repo, &req); err != nil {
+	err = saveConfigFields(repo, &req)
+	if errors.Is(err, secrets.ErrNoMasterKey) {
+		s.logError(r, "failed to save configuration", err)
+		writeError(w, http.StatusInternalServerError, "cannot store the API key: the server has no master key configured")
+		return
+	}
+	if err != nil {
 		s.logError(r, "failed to save configuration", err)
 		writeError(w, http.StatusInternalServerError, "failed to save configuration")
 		return
@@ -160,6 +192,45 @@ func saveConfigFields(repo *repositories.ConfigRepository, req *ConfigUpdateRequ
 	return nil
 }
 
+// lockedConfigKeys returns the locked config keys in sorted order
+func (s *Server) lockedConfigKeys() []string {
+	keys := make([]string, 0, len(s.lockedConfig))
+	for key := range s.lockedConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lockedConfigChange returns the first locked key the request would change.
+// Empty and masked values are not saved and therefore never count as changes.
+func (s *Server) lockedConfigChange(repo *repositories.ConfigRepository, req *ConfigUpdateRequest) (string, error) {
+	requested := map[string]string{
+		configKeyLLMProviderURL:   req.LLMProviderURL,
+		configKeyLLMAPIKey:        req.LLMAPIKey,
+		configKeyLLMModel:         req.LLMModel,
+		configKeyLanguage:         req.Language,
+		configKeyLLMPromptSummary: req.LLMPromptSummary,
+		configKeyLLMPromptEnhance: req.LLMPromptEnhance,
+	}
+
+	for _, key := range s.lockedConfigKeys() {
+		value := requested[key]
+		if value == "" || (key == configKeyLLMAPIKey && isMasked(value)) {
+			continue
+		}
+		current, err := repo.Get(key)
+		if err != nil {
+			return "", err
+		}
+		if current == nil || current.Value != value {
+			return key, nil
+		}
+	}
+
+	return "", nil
+}
+
 // maskAPIKey masks an API key, showing first 4 and last 4 characters
 func maskAPIKey(key string) string {
 	if key == "" {
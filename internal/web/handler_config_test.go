@@ -6,9 +6,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/secrets"
 )
 
 func TestHandleGetConfig_Empty(t *testing.T) {
@@ -51,7 +52,7 @@ func TestHandleGetConfig_Empty(t *testing.T) {
 
 func TestHandleGetConfig_WithValues(t *testing.T) {
 	srv := newTestServer(t)
-	repo := repositories.NewConfigRepository(srv.database.DB)
+	repo := srv.configRepository(context.Background())
 
 	// Set config values
 	if err := repo.Set("llm_provider_url", "https://api.openai.com/v1"); err != nil {
@@ -95,7 +96,7 @@ func TestHandleGetConfig_WithValues(t *testing.T) {
 
 func TestHandleGetConfig_MasksShortKey(t *testing.T) {
 	srv := newTestServer(t)
-	repo := repositories.NewConfigRepository(srv.database.DB)
+	repo := srv.configRepository(context.Background())
 
 	// Set short API key (8 chars)
 	if err := repo.Set("llm_api_key", "short123"); err != nil {
@@ -200,7 +201,7 @@ func TestHandleUpdateConfig_InvalidURL(t *testing.T) {
 
 func TestHandleUpdateConfig_SkipsMaskedKey(t *testing.T) {
 	srv := newTestServer(t)
-	repo := repositories.NewConfigRepository(srv.database.DB)
+	repo := srv.configRepository(context.Background())
 
 	// Set initial API key
 	originalKey := "sk-original-key-12345678"
@@ -238,7 +239,7 @@ func TestHandleUpdateConfig_SkipsMaskedKey(t *testing.T) {
 
 func TestHandleUpdateConfig_EmptyFieldsSkipped(t *testing.T) {
 	srv := newTestServer(t)
-	repo := repositories.NewConfigRepository(srv.database.DB)
+	repo := srv.configRepository(context.Background())
 
 	// Set initial values
 	if err := repo.Set("llm_provider_url", "https://api.initial.com"); err != nil {
@@ -296,6 +297,52 @@ func TestHandleUpdateConfig_EmptyFieldsSkipped(t *testing.T) {
 	}
 }
 
+func TestHandleUpdateConfig_APIKeyEncrypted(t *testing.T) {
+	srv := newTestServer(t)
+
+	body, _ := json.Marshal(ConfigUpdateRequest{LLMAPIKey: "sk-1234567890abcdefghijklmnop"})
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/config", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.handleUpdateConfig(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var data ConfigData
+	if err := json.NewDecoder(w.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if data.LLMAPIKey != "sk-1*********************mnop" {
+		t.Errorf("expected masked key, got %q", data.LLMAPIKey)
+	}
+
+	var stored string
+	err := srv.database.QueryRowContext(context.Background(), `SELECT value FROM config WHERE key = 'llm_api_key'`).Scan(&stored)
+	if err != nil {
+		t.Fatalf("failed to read stored key: %v", err)
+	}
+	if !secrets.IsEncrypted(stored) {
+		t.Errorf("expected the key to be stored encrypted, got %q", stored)
+	}
+}
+
+func TestHandleUpdateConfig_NoMasterKey(t *testing.T) {
+	srv := newTestServer(t)
+	srv.cipher = nil
+
+	body, _ := json.Marshal(ConfigUpdateRequest{LLMAPIKey: "sk-1234567890abcdefghijklmnop", LLMModel: "gpt-4o"})
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/config", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.handleUpdateConfig(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "master key") {
+		t.Errorf("expected error to mention the master key, got %s", w.Body.String())
+	}
+}
+
 func TestMaskAPIKey(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -482,3 +529,69 @@ func TestIsMasked(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleUpdateConfig_LockedKeys(t *testing.T) {
+	srv := newTestServer(t)
+	srv.lockedConfig = map[string]bool{"llm_provider_url": true, "llm_api_key": true}
+	repo := srv.configRepository(context.Background())
+
+	locked := map[string]string{"llm_provider_url": "https://api.openai.com/v1", "llm_api_key": "sk-operator-key-12345678"}
+	if err := repo.Seed(locked, true); err != nil {
+		t.Fatalf("failed to seed config: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		req        ConfigUpdateRequest
+		wantStatus int
+	}{
+		{
+			name:       "unchanged locked values and masked key",
+			req:        ConfigUpdateRequest{LLMProviderURL: "https://api.openai.com/v1", LLMAPIKey: "sk-o****************5678", LLMModel: "gpt-4o"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "changed provider URL",
+			req:        ConfigUpdateRequest{LLMProviderURL: "https://api.example.com"},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "new API key",
+			req:        ConfigUpdateRequest{LLMAPIKey: "sk-new"},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.req)
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/config", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			srv.handleUpdateConfig(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	for key, value := range locked {
+		cfg, err := repo.Get(key)
+		if err != nil || cfg == nil || cfg.Value != value {
+			t.Errorf("locked key %s changed: %v, %v", key, cfg, err)
+		}
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/config", nil)
+	w := httptest.NewRecorder()
+	srv.handleGetConfig(w, req)
+
+	var data ConfigData
+	if err := json.NewDecoder(w.Body).Decode(&data); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(data.Locked) != 2 || data.Locked[0] != "llm_api_key" || data.Locked[1] != "llm_provider_url" {
+		t.Errorf("expected sorted locked keys, got %v", data.Locked)
+	}
+}
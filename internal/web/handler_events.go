@@ -0,0 +1,174 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+// contentTypeEventStream is the media type of server-sent events
+const contentTypeEventStream = "text/event-stream"
+
+// defaultHeartbeat is the interval of comment lines that keep idle event
+// streams open through proxies
+const defaultHeartbeat = 30 * time.Second
+
+// eventRetry is the reconnect delay suggested to EventSource clients
+const eventRetry = 3 * time.Second
+
+// eventReset is sent when a stream cannot resume from Last-Event-ID and
+// the client has to reload its state
+const eventReset = "reset"
+
+// deletedEntity is the data of deletion events
+type deletedEntity struct {
+	ID int `json:"id"`
+}
+
+// publish records a change to a meeting or its notes on the event bus.
+// Call it only after the change has been committed.
+func (s *Server) publish(r *http.Request, eventType string, meetingID int, data any) {
+	actor := models.AuditSystemActor
+	if user, ok := r.Context().Value(userContextKey{}).(*tsapp.UserInfo); ok {
+		actor = user.LoginName
+	}
+	s.events.Publish(events.Event{Type: eventType, MeetingID: meetingID, Actor: actor, Data: data})
+}
+
+// Close ends open event streams so that a graceful shutdown does not wait
+// for them
+func (s *Server) Close() {
+	s.events.Close()
+}
+
+// heartbeatInterval returns the interval of stream heartbeats
+func (s *Server) heartbeatInterval() time.Duration {
+	if s.heartbeat <= 0 {
+		return defaultHeartbeat
+	}
+	return s.heartbeat
+}
+
+// eventFilter returns whether an event is delivered on a stream. Every user
+// may see all meetings, so only the optional meeting_id parameter narrows it.
+func eventFilter(r *http.Request) (func(events.Event) bool, error) {
+	param := r.URL.Query().Get("meeting_id")
+	if param == "" {
+		return func(events.Event) bool { return true }, nil
+	}
+	meetingID, err := strconv.Atoi(param)
+	if err != nil {
+		return nil, fmt.Errorf("invalid meeting_id: %w", err)
+	}
+	return func(e events.Event) bool { return e.MeetingID == meetingID }, nil
+}
+
+// lastEventID returns the ID a stream resumes after, taken from the
+// Last-Event-ID header or, for clients that cannot set headers, the
+// last_event_id query parameter
+func lastEventID(r *http.Request) uint64 {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// writeEvent writes e as a server-sent event
+func writeEvent(w io.Writer, e events.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+// handleEvents handles GET /api/events, a server-sent event stream of
+// changes to meetings and notes
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if _, err := s.currentUser(r); err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
+		return
+	}
+	visible, err := eventFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// the stream outlives the server's write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.logError(r, "failed to clear write deadline", err)
+	}
+
+	sub, backlog, complete := s.events.Subscribe(lastEventID(r))
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", contentTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = fmt.Fprintf(w, "retry: %d\n\n", eventRetry.Milliseconds())
+	if !complete {
+		_, _ = fmt.Fprintf(w, "event: %s\ndata: {}\n\n", eventReset)
+	}
+	for _, e := range backlog {
+		if visible(e) {
+			_ = writeEvent(w, e)
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		s.logError(r, "failed to flush event stream", err)
+		return
+	}
+
+	s.streamEvents(w, r, rc, sub, visible)
+}
+
+// streamEvents writes events and heartbeats until the client disconnects
+// or the subscription ends
+func (s *Server) streamEvents(w io.Writer, r *http.Request, rc *http.ResponseController, sub *events.Subscription, visible func(events.Event) bool) {
+	heartbeat := time.NewTicker(s.heartbeatInterval())
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.Events():
+			if !ok {
+				// dropped for lagging behind or shutting down; the client
+				// reconnects and resumes with Last-Event-ID
+				return
+			}
+			if !visible(e) {
+				continue
+			}
+			err = writeEvent(w, e)
+		case <-heartbeat.C:
+			_, err = io.WriteString(w, ": heartbeat\n\n")
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
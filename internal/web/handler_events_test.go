@@ -0,0 +1,174 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/events"
+)
+
+// sseFrame is one block of an event stream
+type sseFrame struct {
+	id, event, data, comment string
+}
+
+// openEventStream connects to path on ts and returns a reader of its frames
+func openEventStream(t *testing.T, ts *httptest.Server, path string, headers map[string]string) *bufio.Reader {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+path, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: expected status 200, got %d", path, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != contentTypeEventStream {
+		t.Fatalf("GET %s: expected content type %s, got %s", path, contentTypeEventStream, ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+// readFrame reads the next frame, skipping the retry hint
+func readFrame(t *testing.T, r *bufio.Reader) sseFrame {
+	t.Helper()
+	var f sseFrame
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if f != (sseFrame{}) {
+				return f
+			}
+		case strings.HasPrefix(line, ":"):
+			f.comment = strings.TrimSpace(strings.TrimPrefix(line, ":"))
+		case strings.HasPrefix(line, "id: "):
+			f.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			f.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			f.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// readEvent reads frames until the next event
+func readEvent(t *testing.T, r *bufio.Reader) (sseFrame, events.Event) {
+	t.Helper()
+	for {
+		f := readFrame(t, r)
+		if f.event == "" {
+			continue
+		}
+		var e events.Event
+		if err := json.Unmarshal([]byte(f.data), &e); err != nil {
+			t.Fatalf("invalid event data %q: %v", f.data, err)
+		}
+		return f, e
+	}
+}
+
+func TestEvents_Stream(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close) // after the cleanups of the streams disconnect them
+
+	var meeting, other models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Retro", "meeting_date": "2026-03-02", "start_time": "09:00"}`, http.StatusCreated, &other)
+
+	stream := openEventStream(t, ts, "/api/events?meeting_id="+strconv.Itoa(meeting.ID), nil)
+
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(other.ID)+`, "content": "elsewhere"}`, http.StatusCreated, nil)
+	var note models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(meeting.ID)+`, "content": "hello"}`, http.StatusCreated, &note)
+	serveJSON(t, handler, http.MethodDelete, "/api/notes/"+strconv.Itoa(note.ID), "", http.StatusNoContent, nil)
+
+	created, e := readEvent(t, stream)
+	if created.event != events.NoteCreated || e.MeetingID != meeting.ID || e.Actor != devUser.LoginName {
+		t.Fatalf("first event = %s %+v, want %s for meeting %d", created.event, e, events.NoteCreated, meeting.ID)
+	}
+	if created.id != strconv.FormatUint(e.ID, 10) {
+		t.Errorf("frame id %s does not match event ID %d", created.id, e.ID)
+	}
+	if data, _ := e.Data.(map[string]any); data["content"] != "hello" {
+		t.Errorf("event data = %v, want the created note", e.Data)
+	}
+
+	deleted, e := readEvent(t, stream)
+	if deleted.event != events.NoteDeleted {
+		t.Fatalf("second event = %s, want %s", deleted.event, events.NoteDeleted)
+	}
+	if data, _ := e.Data.(map[string]any); data["id"] != float64(note.ID) {
+		t.Errorf("event data = %v, want the deleted note ID", e.Data)
+	}
+
+	// Resuming after the first event replays the deletion only
+	resumed := openEventStream(t, ts, "/api/events?meeting_id="+strconv.Itoa(meeting.ID), map[string]string{"Last-Event-ID": created.id})
+	if f, _ := readEvent(t, resumed); f.event != events.NoteDeleted || f.id != deleted.id {
+		t.Errorf("resumed with %s %s, want %s %s", f.event, f.id, events.NoteDeleted, deleted.id)
+	}
+
+	// An unknown ID asks the client to reload
+	reset := openEventStream(t, ts, "/api/events?last_event_id=1", nil)
+	if f := readFrame(t, reset); f.event != eventReset {
+		t.Errorf("stale resume sent %+v, want a %s event", f, eventReset)
+	}
+}
+
+func TestEvents_Heartbeat(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	srv.heartbeat = 10 * time.Millisecond
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	stream := openEventStream(t, ts, "/api/events", nil)
+	for {
+		if f := readFrame(t, stream); f.comment == "heartbeat" {
+			break
+		}
+	}
+
+	// Closing the bus ends the stream
+	srv.Close()
+	for {
+		if _, err := stream.ReadString('\n'); err != nil {
+			break
+		}
+	}
+}
+
+func TestEvents_InvalidMeetingID(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+
+	serveJSON(t, srv.Handler(), http.MethodGet, "/api/events?meeting_id=abc", "", http.StatusBadRequest, nil)
+}
@@ -0,0 +1,201 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/zorak1103/notebook/internal/db"
+	"github.com/zorak1103/notebook/internal/llm"
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+// Tables whose row counts are reported
+const (
+	tableMeetings = "meetings"
+	tableNotes    = "notes"
+	tableConfig   = "config"
+)
+
+const checkOK = "ok"
+
+// readyResponse reports the result of each readiness check
+type readyResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+type diagnosticsResponse struct {
+	Build     versionResponse      `json:"build"`
+	Database  diagnosticsDatabase  `json:"database"`
+	LLM       diagnosticsLLM       `json:"llm"`
+	Tailscale diagnosticsTailscale `json:"tailscale"`
+}
+
+type diagnosticsDatabase struct {
+	FileSize            int64            `json:"file_size"`
+	WALSize             int64            `json:"wal_size"`
+	SchemaVersion       int              `json:"schema_version"`
+	LatestSchemaVersion int              `json:"latest_schema_version"`
+	RowCounts           map[string]int64 `json:"row_counts"`
+	Error               string           `json:"error,omitempty"`
+}
+
+type diagnosticsLLM struct {
+	Configured bool   `json:"configured"`
+	Provider   string `json:"provider,omitempty"`
+	Model      string `json:"model,omitempty"`
+	Reachable  bool   `json:"reachable"`
+	Error      string `json:"error,omitempty"`
+}
+
+type diagnosticsTailscale struct {
+	Enabled bool              `json:"enabled"`
+	Node    *tsapp.NodeStatus `json:"node,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// handleHealthz reports that the process is alive
+func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": checkOK})
+}
+
+// handleReadyz reports whether the database is usable and, in Tailscale
+// mode, Tailscale is up. Responds 503 if any check fails.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resp := readyResponse{Status: "ready", Checks: map[string]string{
+		"database":   checkResult(s.database.PingContext(ctx)),
+		"migrations": checkResult(s.checkMigrations(ctx)),
+	}}
+	if !s.devMode && s.auth == nil {
+		resp.Checks["tailscale"] = checkResult(s.checkTailscale(ctx))
+	}
+
+	status := http.StatusOK
+	for _, result := range resp.Checks {
+		if result != checkOK {
+			resp.Status = "not ready"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, status, resp)
+}
+
+// checkResult renders a readiness check outcome
+func checkResult(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return checkOK
+}
+
+// checkMigrations fails unless all migrations have been applied
+func (s *Server) checkMigrations(ctx context.Context) error {
+	version, err := s.database.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if latest := db.LatestSchemaVersion(); version != latest {
+		return fmt.Errorf("schema version %d, expected %d", version, latest)
+	}
+	return nil
+}
+
+// checkTailscale fails unless the Tailscale node is running
+func (s *Server) checkTailscale(ctx context.Context) error {
+	if s.tsapp == nil {
+		return fmt.Errorf("tailscale not initialized")
+	}
+	status, err := s.tsapp.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if !status.Running() {
+		return fmt.Errorf("tailscale state %s", status.BackendState)
+	}
+	return nil
+}
+
+// handleDiagnostics reports database, LLM, Tailscale and build details to admins
+func (s *Server) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	ctx := r.Context()
+	writeJSON(w, http.StatusOK, diagnosticsResponse{
+		Build:     s.buildInfo(),
+		Database:  s.databaseDiagnostics(ctx),
+		LLM:       s.llmDiagnostics(ctx),
+		Tailscale: s.tailscaleDiagnostics(ctx),
+	})
+}
+
+func (s *Server) databaseDiagnostics(ctx context.Context) diagnosticsDatabase {
+	diag := diagnosticsDatabase{
+		LatestSchemaVersion: db.LatestSchemaVersion(),
+		RowCounts:           map[string]int64{},
+	}
+	diag.FileSize, diag.WALSize = s.database.FileSizes()
+
+	var err error
+	if diag.SchemaVersion, err = s.database.SchemaVersion(ctx); err != nil {
+		diag.Error = err.Error()
+		return diag
+	}
+	for _, table := range []string{tableMeetings, tableNotes, tableConfig} {
+		if diag.RowCounts[table], err = s.rowCount(ctx, table); err != nil {
+			diag.Error = err.Error()
+			return diag
+		}
+	}
+	return diag
+}
+
+func (s *Server) llmDiagnostics(ctx context.Context) diagnosticsLLM {
+	configRepo := s.configRepository(ctx)
+	providerURL, err := configRepo.Get("llm_provider_url")
+	if err != nil {
+		return diagnosticsLLM{Error: err.Error()}
+	}
+	if providerURL == nil || providerURL.Value == "" {
+		return diagnosticsLLM{}
+	}
+
+	diag := diagnosticsLLM{Configured: true, Provider: llm.ProviderType(providerURL.Value)}
+	if model, err := configRepo.Get("llm_model"); err == nil && model != nil {
+		diag.Model = model.Value
+	}
+	if err := llm.Probe(ctx, providerURL.Value); err != nil {
+		diag.Error = err.Error()
+	} else {
+		diag.Reachable = true
+	}
+	return diag
+}
+
+func (s *Server) tailscaleDiagnostics(ctx context.Context) diagnosticsTailscale {
+	if s.tsapp == nil {
+		return diagnosticsTailscale{}
+	}
+	status, err := s.tsapp.Status(ctx)
+	if err != nil {
+		return diagnosticsTailscale{Enabled: true, Error: err.Error()}
+	}
+	return diagnosticsTailscale{Enabled: true, Node: status}
+}
+
+// rowCount counts the rows of one of the table constants; rows of meetings
+// and notes in the trash are left out
+func (s *Server) rowCount(ctx context.Context, table string) (int64, error) {
+	var n int64
+	query := "SELECT COUNT(*) FROM " + table //nolint:gosec // table is one of the table constants
+	if table == tableMeetings || table == tableNotes {
+		query += " WHERE deleted_at IS NULL"
+	}
+	if err := s.database.QueryRowContext(ctx, query).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count %s: %w", table, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,170 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func TestHandleHealthz(t *testing.T) {
+	srv := newTestServer(t)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	srv.handleHealthz(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+}
+
+func TestHandleReadyz(t *testing.T) {
+	tests := []struct {
+		name       string
+		devMode    bool
+		wantStatus int
+		wantChecks map[string]string
+	}{
+		{
+			name:       "dev mode skips tailscale",
+			devMode:    true,
+			wantStatus: http.StatusOK,
+			wantChecks: map[string]string{"database": checkOK, "migrations": checkOK},
+		},
+		{
+			name:       "tailscale not initialized",
+			devMode:    false,
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"database": checkOK, "migrations": checkOK, "tailscale": "tailscale not initialized"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			srv.devMode = tt.devMode
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/readyz", nil)
+			rec := httptest.NewRecorder()
+			srv.handleReadyz(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			var resp readyResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Checks) != len(tt.wantChecks) {
+				t.Errorf("checks = %v, want %v", resp.Checks, tt.wantChecks)
+			}
+			for name, want := range tt.wantChecks {
+				if resp.Checks[name] != want {
+					t.Errorf("check %s = %q, want %q", name, resp.Checks[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleReadyz_PendingMigrations(t *testing.T) {
+	srv := newTestServer(t)
+	srv.devMode = true
+
+	_, err := srv.database.ExecContext(context.Background(), "DELETE FROM schema_version WHERE version = ?", db.LatestSchemaVersion())
+	if err != nil {
+		t.Fatalf("failed to reset schema version: %v", err)
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+	srv.handleReadyz(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rec.Code)
+	}
+}
+
+func TestHandleDiagnostics_RequiresAdmin(t *testing.T) {
+	srv := newTestServer(t)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/admin/diagnostics", nil)
+	rec := httptest.NewRecorder()
+	srv.handleDiagnostics(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestHandleDiagnostics(t *testing.T) {
+	srv := newTestServer(t)
+	srv.devMode = true
+
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer provider.Close()
+
+	configRepo := srv.configRepository(context.Background())
+	if err := configRepo.Set("llm_provider_url", provider.URL+"/v1"); err != nil {
+		t.Fatalf("failed to set config: %v", err)
+	}
+	if err := configRepo.Set("llm_model", "test-model"); err != nil {
+		t.Fatalf("failed to set config: %v", err)
+	}
+	meetingRepo := repositories.NewMeetingRepository(srv.database.DB)
+	createTestMeeting(t, meetingRepo)
+	// Meetings in the trash are not counted
+	if err := meetingRepo.Delete(createTestMeeting(t, meetingRepo)); err != nil {
+		t.Fatalf("failed to delete meeting: %v", err)
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/admin/diagnostics", nil)
+	rec := httptest.NewRecorder()
+	srv.handleDiagnostics(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp diagnosticsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Database.SchemaVersion != db.LatestSchemaVersion() {
+		t.Errorf("schema_version = %d, want %d", resp.Database.SchemaVersion, db.LatestSchemaVersion())
+	}
+	if resp.Database.RowCounts[tableMeetings] != 1 {
+		t.Errorf("meetings row count = %d, want 1", resp.Database.RowCounts[tableMeetings])
+	}
+	if !resp.LLM.Configured || !resp.LLM.Reachable || resp.LLM.Provider != "openai" || resp.LLM.Model != "test-model" {
+		t.Errorf("unexpected llm diagnostics: %+v", resp.LLM)
+	}
+	if resp.Tailscale.Enabled {
+		t.Error("expected tailscale to be reported as disabled")
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	srv := NewServer(nil, nil, Options{Admins: []string{"alice@example.com"}})
+
+	if srv.isAdmin(&devUser) {
+		t.Error("dev user must not be admin outside dev mode")
+	}
+	alice := devUser
+	alice.LoginName = "alice@example.com"
+	if !srv.isAdmin(&alice) {
+		t.Error("expected configured login to be admin")
+	}
+
+	srv.devMode = true
+	if !srv.isAdmin(&devUser) {
+		t.Error("expected dev user to be admin in dev mode")
+	}
+}
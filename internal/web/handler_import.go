@@ -0,0 +1,179 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/ical"
+)
+
+const (
+	maxICSUploadSize    = 1 << 20 // 1 MiB
+	icsFormField        = "file"
+	importActionCreated = "created"
+	importActionUpdated = "updated"
+	untitledSubject     = "Untitled meeting"
+)
+
+// importedMeeting reports what happened to one VEVENT of an import
+type importedMeeting struct {
+	Action  string          `json:"action"`
+	Meeting *models.Meeting `json:"meeting"`
+}
+
+// handleImportICS handles POST /api/meetings/import.
+// The body is either a multipart form with an .ics file in the "file" field
+// or the raw calendar text (a full VCALENDAR or a pasted VEVENT). Events
+// already imported (same UID and RECURRENCE-ID) are updated in place. All
+// events are stored in one transaction, so a failed import stores none.
+func (s *Server) handleImportICS(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxICSUploadSize)
+
+	body, err := readICSUpload(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer func() { _ = body.Close() }()
+
+	cal, err := ical.Parse(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid calendar: "+err.Error())
+		return
+	}
+
+	icsEvents, err := ical.Events(cal, s.timeLocation())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid calendar: "+err.Error())
+		return
+	}
+	if len(icsEvents) == 0 {
+		writeError(w, http.StatusBadRequest, "calendar contains no events")
+		return
+	}
+
+	// Validate everything up front so a bad event does not leave a partial import
+	meetings := make([]*models.Meeting, len(icsEvents))
+	for i, ev := range icsEvents {
+		meetings[i] = meetingFromEvent(ev, s.timeLocation())
+		if err := validateMeeting(meetings[i]); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("event %s: %v", ev.UID, err))
+			return
+		}
+		if ev.Description != "" {
+			if err := validateNoteContent(ev.Description); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("event %s: description: %v", ev.UID, err))
+				return
+			}
+		}
+	}
+
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
+		return
+	}
+
+	imports := make([]*repositories.MeetingImport, len(icsEvents))
+	for i, ev := range icsEvents {
+		meetings[i].CreatedBy = user.LoginName
+		imports[i] = &repositories.MeetingImport{Meeting: meetings[i], Description: ev.Description}
+	}
+	meetingRepo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	notes := s.noteRepository(r.Context()).WithRevisionSource(models.RevisionSourceImport)
+	if err := meetingRepo.Import(imports, notes); err != nil {
+		s.logError(r, "failed to import calendar", err)
+		writeError(w, http.StatusInternalServerError, "failed to import calendar")
+		return
+	}
+
+	results := make([]importedMeeting, 0, len(imports))
+	for _, imp := range imports {
+		if imp.Created {
+			results = append(results, importedMeeting{Action: importActionCreated, Meeting: imp.Meeting})
+			s.publish(r, events.MeetingCreated, imp.Meeting.ID, imp.Meeting)
+		} else {
+			results = append(results, importedMeeting{Action: importActionUpdated, Meeting: imp.Meeting})
+			s.publish(r, events.MeetingUpdated, imp.Meeting.ID, imp.Meeting)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// readICSUpload returns the calendar data from a multipart upload or the raw body
+func readICSUpload(r *http.Request) (io.ReadCloser, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+
+	file, _, err := r.FormFile(icsFormField)
+	if err != nil {
+		return nil, fmt.Errorf("missing %q file in upload", icsFormField)
+	}
+	return file, nil
+}
+
+// meetingFromEvent maps a VEVENT onto a meeting, expressing its times in loc
+func meetingFromEvent(ev *ical.Event, loc *time.Location) *models.Meeting {
+	uid := ev.UID
+	start := ev.Start.UTC()
+
+	m := &models.Meeting{
+		Subject:  ev.Summary,
+		StartUTC: &start,
+		ICalUID:  &uid,
+	}
+	if m.Subject == "" {
+		m.Subject = untitledSubject
+	}
+
+	if !ev.End.IsZero() && !ev.AllDay {
+		end := ev.End.UTC()
+		m.EndUTC = &end
+	}
+	setLocalTimes(m, loc)
+
+	if participants := eventParticipants(ev); participants != "" {
+		m.Participants = &participants
+	}
+
+	if ev.RecurrenceID != "" {
+		rid := ev.RecurrenceID
+		m.ICalRecurrenceID = &rid
+	}
+
+	return m
+}
+
+// eventParticipants lists the organizer followed by all attendees, without duplicates
+func eventParticipants(ev *ical.Event) string {
+	all := ev.Attendees
+	if ev.Organizer != nil {
+		all = append([]ical.Attendee{*ev.Organizer}, all...)
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, a := range all {
+		key := strings.ToLower(a.Email)
+		if key == "" {
+			key = strings.ToLower(a.Name)
+		}
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, a.String())
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,255 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+const testInvite = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//EN
+BEGIN:VEVENT
+UID:invite-42@example.com
+SUMMARY:Architecture Review
+DESCRIPTION:Agenda:\n1. Storage layer\n2. Rollout
+DTSTART:20260310T130000Z
+DTEND:20260310T143000Z
+ORGANIZER;CN=Alice:mailto:alice@example.com
+ATTENDEE;CN=Bob:mailto:bob@example.com
+ATTENDEE;CN=Alice:mailto:alice@example.com
+END:VEVENT
+END:VCALENDAR
+`
+
+func postICS(t *testing.T, srv *Server, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/calendar")
+	w := httptest.NewRecorder()
+
+	srv.handleImportICS(w, req)
+	return w
+}
+
+func decodeImportResults(t *testing.T, w *httptest.ResponseRecorder) []importedMeeting {
+	t.Helper()
+
+	var results []importedMeeting
+	if err := json.NewDecoder(w.Body).Decode(&results); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return results
+}
+
+func TestHandleImportICS_CreatesMeetingAndNote(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	srv.location = berlin
+
+	w := postICS(t, srv, testInvite)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	results := decodeImportResults(t, w)
+	if len(results) != 1 || results[0].Action != importActionCreated {
+		t.Fatalf("expected one created meeting, got %+v", results)
+	}
+
+	m := results[0].Meeting
+	if m.Subject != "Architecture Review" {
+		t.Errorf("unexpected subject %q", m.Subject)
+	}
+	if m.MeetingDate != "2026-03-10" || m.StartTime != "14:00" {
+		t.Errorf("expected 2026-03-10 14:00 Berlin time, got %s %s", m.MeetingDate, m.StartTime)
+	}
+	if m.EndTime == nil || *m.EndTime != "15:30" {
+		t.Errorf("expected end 15:30, got %v", m.EndTime)
+	}
+	if m.Participants == nil || *m.Participants != "Alice <alice@example.com>, Bob <bob@example.com>" {
+		t.Errorf("unexpected participants %v", m.Participants)
+	}
+	if m.ICalUID == nil || *m.ICalUID != "invite-42@example.com" {
+		t.Errorf("expected UID to be stored, got %v", m.ICalUID)
+	}
+
+	notes, err := repositories.NewNoteRepository(srv.database.DB).ListByMeeting(m.ID)
+	if err != nil {
+		t.Fatalf("failed to list notes: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Content != "Agenda:\n1. Storage layer\n2. Rollout" {
+		t.Errorf("expected description as first note, got %+v", notes)
+	}
+}
+
+func TestHandleImportICS_ReimportUpdates(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	srv.location = time.UTC
+
+	if w := postICS(t, srv, testInvite); w.Code != http.StatusOK {
+		t.Fatalf("first import failed: %d %s", w.Code, w.Body.String())
+	}
+
+	// Set a notebook-owned field that the re-import must keep
+	meetingRepo := repositories.NewMeetingRepository(srv.database.DB)
+	meeting, err := meetingRepo.GetByICalUID("invite-42@example.com", "")
+	if err != nil || meeting == nil {
+		t.Fatalf("imported meeting not found: %v", err)
+	}
+	keywords := "architecture"
+	meeting.Keywords = &keywords
+	if err := meetingRepo.Update(meeting); err != nil {
+		t.Fatalf("failed to update meeting: %v", err)
+	}
+
+	moved := strings.Replace(testInvite, "SUMMARY:Architecture Review", "SUMMARY:Architecture Review (moved)", 1)
+	moved = strings.Replace(moved, "DTSTART:20260310T130000Z", "DTSTART:20260311T090000Z", 1)
+	moved = strings.Replace(moved, "DTEND:20260310T143000Z", "DTEND:20260311T100000Z", 1)
+
+	w := postICS(t, srv, moved)
+	if w.Code != http.StatusOK {
+		t.Fatalf("re-import failed: %d %s", w.Code, w.Body.String())
+	}
+
+	results := decodeImportResults(t, w)
+	if len(results) != 1 || results[0].Action != importActionUpdated {
+		t.Fatalf("expected one updated meeting, got %+v", results)
+	}
+	m := results[0].Meeting
+	if m.ID != meeting.ID || m.Subject != "Architecture Review (moved)" || m.MeetingDate != "2026-03-11" || m.StartTime != "09:00" {
+		t.Errorf("unexpected updated meeting %+v", m)
+	}
+	if m.Keywords == nil || *m.Keywords != keywords {
+		t.Errorf("expected keywords to survive re-import, got %v", m.Keywords)
+	}
+
+	all, err := meetingRepo.List("meeting_date", true)
+	if err != nil {
+		t.Fatalf("failed to list meetings: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("expected re-import not to duplicate, got %d meetings", len(all))
+	}
+
+	notes, err := repositories.NewNoteRepository(srv.database.DB).ListByMeeting(m.ID)
+	if err != nil {
+		t.Fatalf("failed to list notes: %v", err)
+	}
+	if len(notes) != 1 {
+		t.Errorf("expected re-import not to duplicate the description note, got %d notes", len(notes))
+	}
+}
+
+func TestHandleImportICS_RecurrenceOverrideIsSeparateMeeting(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	srv.location = time.UTC
+
+	body := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:weekly@example.com
+SUMMARY:Weekly
+DTSTART:20260303T090000Z
+RRULE:FREQ=WEEKLY
+END:VEVENT
+BEGIN:VEVENT
+UID:weekly@example.com
+RECURRENCE-ID:20260310T090000Z
+SUMMARY:Weekly (moved)
+DTSTART:20260311T090000Z
+END:VEVENT
+END:VCALENDAR
+`
+	w := postICS(t, srv, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("import failed: %d %s", w.Code, w.Body.String())
+	}
+
+	results := decodeImportResults(t, w)
+	if len(results) != 2 || results[0].Meeting.ID == results[1].Meeting.ID {
+		t.Fatalf("expected two distinct meetings, got %+v", results)
+	}
+	if rid := results[1].Meeting.ICalRecurrenceID; rid == nil || *rid != "20260310T090000Z" {
+		t.Errorf("expected recurrence ID on override, got %v", rid)
+	}
+
+	// Importing again must update both rather than create new ones
+	w = postICS(t, srv, body)
+	for _, r := range decodeImportResults(t, w) {
+		if r.Action != importActionUpdated {
+			t.Errorf("expected updated on re-import, got %s", r.Action)
+		}
+	}
+}
+
+func TestHandleImportICS_MultipartUpload(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	srv.location = time.UTC
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile(icsFormField, "invite.ics")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = fw.Write([]byte(testInvite))
+	_ = mw.Close()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings/import", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	srv.handleImportICS(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if results := decodeImportResults(t, w); len(results) != 1 {
+		t.Errorf("expected 1 imported meeting, got %d", len(results))
+	}
+}
+
+func TestHandleImportICS_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not a calendar", "hello world"},
+		{"no events", "BEGIN:VCALENDAR\nVERSION:2.0\nEND:VCALENDAR\n"},
+		{"missing DTSTART", "BEGIN:VEVENT\nUID:x\nSUMMARY:Broken\nEND:VEVENT\n"},
+		{"subject too long", "BEGIN:VEVENT\nUID:x\nDTSTART:20260310T130000Z\nSUMMARY:" + strings.Repeat("a", 300) + "\nEND:VEVENT\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			defer srv.database.Close()
+
+			w := postICS(t, srv, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
@@ -3,14 +3,18 @@ package web
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/zorak1103/notebook/internal/db/models"
 	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
 	"github.com/zorak1103/notebook/internal/llm"
 )
 
@@ -18,6 +22,7 @@ const (
 	llmKeyContent      = "content"
 	llmKeySubject      = "subject"
 	llmKeyParticipants = "participants"
+	llmKeyNotes        = "notes"
 )
 
 type enhanceNoteRequest struct {
@@ -37,7 +42,7 @@ func (s *Server) handleSummarizeMeeting(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Load LLM config
-	configRepo := repositories.NewConfigRepository(s.database.DB)
+	configRepo := s.configRepository(r.Context())
 	llmURL, llmAPIKey, llmModel, summaryPrompt, err := loadLLMConfig(configRepo)
 	if err != nil {
 		s.logError(r, "failed to load LLM config", err)
@@ -46,7 +51,7 @@ func (s *Server) handleSummarizeMeeting(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Load meeting and notes
-	meeting, notes, meetingRepo, err := s.loadMeetingWithNotes(int(meetingID))
+	meeting, notes, meetingRepo, err := s.loadMeetingWithNotes(r.Context(), int(meetingID))
 	if err != nil {
 		s.logError(r, "failed to load meeting data", err)
 		writeError(w, http.StatusInternalServerError, err.Error())
@@ -70,13 +75,14 @@ func (s *Server) handleSummarizeMeeting(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Update meeting with summary
-	meeting.Summary = &summary
-	if err := meetingRepo.Update(meeting); err != nil {
+	meeting.Summary = &summary.Text
+	if err := meetingRepo.WithSummaryProvenance(summaryProvenance(summary, summaryPrompt)).Update(meeting); err != nil {
 		s.logError(r, "failed to update meeting", err)
 		writeError(w, http.StatusInternalServerError, "failed to update meeting")
 		return
 	}
 
+	s.publish(r, events.SummaryGenerated, meeting.ID, meeting)
 	writeJSON(w, http.StatusOK, meeting)
 }
 
@@ -99,7 +105,7 @@ func (s *Server) handleEnhanceNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	configRepo := repositories.NewConfigRepository(s.database.DB)
+	configRepo := s.configRepository(r.Context())
 	llmURL, llmAPIKey, llmModel, enhancePrompt, err := loadLLMConfigForEnhance(configRepo)
 	if err != nil {
 		s.logError(r, "failed to load LLM config", err)
@@ -107,7 +113,7 @@ func (s *Server) handleEnhanceNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	noteRepo := repositories.NewNoteRepository(s.database.DB)
+	noteRepo := repositories.NewNoteRepository(s.database.DB).WithContext(r.Context())
 	note, err := noteRepo.GetByID(int(noteID))
 	if err != nil {
 		s.logError(r, "failed to get note", err)
@@ -148,18 +154,18 @@ func (s *Server) enhanceContent(r *http.Request, llmURL, llmAPIKey, llmModel, en
 		return "", fmt.Errorf("LLM completion failed: %w", err)
 	}
 
-	return enhanced, nil
+	return enhanced.Text, nil
 }
 
 // loadMeetingWithNotes loads a meeting and its notes from the database
-func (s *Server) loadMeetingWithNotes(meetingID int) (*models.Meeting, []*models.Note, *repositories.MeetingRepository, error) {
-	meetingRepo := repositories.NewMeetingRepository(s.database.DB)
+func (s *Server) loadMeetingWithNotes(ctx context.Context, meetingID int) (*models.Meeting, []*models.Note, *repositories.MeetingRepository, error) {
+	meetingRepo := repositories.NewMeetingRepository(s.database.DB).WithContext(ctx)
 	meeting, err := meetingRepo.GetByID(meetingID)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to get meeting: %w", err)
 	}
 
-	noteRepo := repositories.NewNoteRepository(s.database.DB)
+	noteRepo := repositories.NewNoteRepository(s.database.DB).WithContext(ctx)
 	notes, err := noteRepo.ListByMeeting(meetingID)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to get notes: %w", err)
@@ -169,35 +175,55 @@ func (s *Server) loadMeetingWithNotes(meetingID int) (*models.Meeting, []*models
 }
 
 // generateSummary creates an LLM summary from meeting and notes
-func (s *Server) generateSummary(r *http.Request, llmURL, llmAPIKey, llmModel, summaryPrompt string, meeting *models.Meeting, notes []*models.Note) (string, error) {
+func (s *Server) generateSummary(r *http.Request, llmURL, llmAPIKey, llmModel, summaryPrompt string, meeting *models.Meeting, notes []*models.Note) (*llm.Completion, error) {
 	client, err := llm.New(llmURL, llmAPIKey, llmModel)
 	if err != nil {
-		return "", fmt.Errorf("failed to create LLM client: %w", err)
+		return nil, fmt.Errorf("failed to create LLM client: %w", err)
 	}
 
-	notesText := formatNotes(notes)
 	participants := ""
 	if meeting.Participants != nil {
 		participants = *meeting.Participants
 	}
-	prompt := llm.RenderPrompt(summaryPrompt, map[string]string{
+	vars := map[string]string{
 		llmKeySubject:      meeting.Subject,
 		"date":             meeting.MeetingDate,
 		llmKeyParticipants: participants,
-		"notes":            notesText,
-	})
+		llmKeyNotes:        formatNotes(notes),
+	}
+	// {{notes.decision}} and so on hold the notes of one type
+	for _, noteType := range models.NoteTypes {
+		vars[llmKeyNotes+"."+noteType] = formatNotesOfType(notes, noteType)
+	}
+	prompt := llm.RenderPrompt(summaryPrompt, vars)
 
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
 	summary, err := client.Complete(ctx, prompt)
 	if err != nil {
-		return "", fmt.Errorf("LLM completion failed: %w", err)
+		return nil, fmt.Errorf("LLM completion failed: %w", err)
 	}
 
 	return summary, nil
 }
 
+// summaryProvenance describes a summary generated by completion from the
+// prompt template. Token counts are left empty if the provider reported none.
+func summaryProvenance(completion *llm.Completion, template string) models.SummaryProvenance {
+	hash := sha256.Sum256([]byte(template))
+	promptHash := hex.EncodeToString(hash[:])
+	p := models.SummaryProvenance{
+		Source:     models.SummarySourceLLM,
+		Model:      &completion.Model,
+		PromptHash: &promptHash,
+	}
+	if usage := completion.Usage; usage.InputTokens > 0 || usage.OutputTokens > 0 {
+		p.InputTokens, p.OutputTokens = &usage.InputTokens, &usage.OutputTokens
+	}
+	return p
+}
+
 // loadLLMConfig loads and validates LLM configuration for summarization
 func loadLLMConfig(repo *repositories.ConfigRepository) (url, apiKey, model, summaryPrompt string, err error) {
 	configs, err := repo.GetAll()
@@ -252,11 +278,67 @@ func loadLLMConfigForEnhance(repo *repositories.ConfigRepository) (url, apiKey,
 	return url, apiKey, model, enhancePrompt, nil
 }
 
-// formatNotes converts a list of notes to a formatted string
+// outlineNote is a note with its place in the outline of a meeting's notes
+type outlineNote struct {
+	note   *models.Note
+	number string
+	depth  int
+}
+
+// outlineNotes orders notes as an outline: every note follows its parent, and
+// notes under the same parent keep their order in the list. Notes whose
+// parent is not in the list are top-level notes. The numbers count positions
+// in the outline, like 1, 1.1, 1.2 and 2.
+func outlineNotes(notes []*models.Note) []outlineNote {
+	listed := map[int]bool{}
+	for _, n := range notes {
+		listed[n.ID] = true
+	}
+	var roots []*models.Note
+	children := map[int][]*models.Note{}
+	for _, n := range notes {
+		if n.ParentID != nil && *n.ParentID != n.ID && listed[*n.ParentID] {
+			children[*n.ParentID] = append(children[*n.ParentID], n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+
+	var outline []outlineNote
+	var walk func(level []*models.Note, prefix string, depth int)
+	walk = func(level []*models.Note, prefix string, depth int) {
+		for i, n := range level {
+			number := prefix + strconv.Itoa(i+1)
+			outline = append(outline, outlineNote{note: n, number: number, depth: depth})
+			walk(children[n.ID], number+".", depth+1)
+		}
+	}
+	walk(roots, "", 0)
+	return outline
+}
+
+// formatNotes renders notes as an outline for the summary prompt, indented by
+// level and tagged with their type unless they are plain notes
 func formatNotes(notes []*models.Note) string {
-	var parts []string
-	for _, note := range notes {
-		parts = append(parts, fmt.Sprintf("%d. %s", note.NoteNumber, note.Content))
+	var lines []string
+	for _, o := range outlineNotes(notes) {
+		tag := ""
+		if o.note.NoteType != "" && o.note.NoteType != models.NoteTypePlain {
+			tag = "[" + o.note.NoteType + "] "
+		}
+		lines = append(lines, fmt.Sprintf("%s%s. %s%s", strings.Repeat("  ", o.depth), o.number, tag, o.note.Content))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatNotesOfType renders the notes of one type as a flat list, numbered
+// as in the outline of formatNotes
+func formatNotesOfType(notes []*models.Note, noteType string) string {
+	var lines []string
+	for _, o := range outlineNotes(notes) {
+		if o.note.NoteType == noteType {
+			lines = append(lines, fmt.Sprintf("%s. %s", o.number, o.note.Content))
+		}
 	}
-	return strings.Join(parts, "\n")
+	return strings.Join(lines, "\n")
 }
@@ -191,6 +191,28 @@ func TestFormatNotes(t *testing.T) {
 	}
 }
 
+func TestFormatNotes_Outline(t *testing.T) {
+	one, two := 1, 2
+	notes := []*models.Note{
+		{ID: 1, NoteNumber: 1, Content: "Budget", NoteType: models.NoteTypePlain},
+		{ID: 2, NoteNumber: 2, Content: "Approved", NoteType: models.NoteTypeDecision, ParentID: &one},
+		{ID: 3, NoteNumber: 3, Content: "Hiring", NoteType: models.NoteTypePlain},
+		{ID: 4, NoteNumber: 4, Content: "Send numbers", NoteType: models.NoteTypeAction, ParentID: &two},
+		{ID: 5, NoteNumber: 5, Content: "Who signs?", NoteType: models.NoteTypeQuestion, ParentID: &one},
+	}
+
+	expected := "1. Budget\n  1.1. [decision] Approved\n    1.1.1. [action] Send numbers\n  1.2. [question] Who signs?\n2. Hiring"
+	if result := formatNotes(notes); result != expected {
+		t.Errorf("formatNotes() = %q, expected %q", result, expected)
+	}
+	if result := formatNotesOfType(notes, models.NoteTypeAction); result != "1.1.1. Send numbers" {
+		t.Errorf("formatNotesOfType(action) = %q", result)
+	}
+	if result := formatNotesOfType(notes, models.NoteTypeInfo); result != "" {
+		t.Errorf("formatNotesOfType(info) = %q, expected empty string", result)
+	}
+}
+
 func TestFormatNotes_Empty(t *testing.T) {
 	notes := []*models.Note{}
 	result := formatNotes(notes)
@@ -204,7 +226,7 @@ func TestFormatNotes_Empty(t *testing.T) {
 func setTestLLMConfig(t *testing.T, srv *Server) {
 	t.Helper()
 
-	repo := repositories.NewConfigRepository(srv.database.DB)
+	repo := srv.configRepository(context.Background())
 
 	configs := map[string]string{
 		"llm_provider_url": "https://api.openai.com/v1",
@@ -222,7 +244,7 @@ func setTestLLMConfig(t *testing.T, srv *Server) {
 // TestLoadLLMConfig tests the config loading helper
 func TestLoadLLMConfig_Success(t *testing.T) {
 	srv := newTestServer(t)
-	repo := repositories.NewConfigRepository(srv.database.DB)
+	repo := srv.configRepository(context.Background())
 
 	// Set all required config
 	configs := map[string]string{
@@ -259,7 +281,7 @@ func TestLoadLLMConfig_Success(t *testing.T) {
 
 func TestLoadLLMConfig_MissingURL(t *testing.T) {
 	srv := newTestServer(t)
-	repo := repositories.NewConfigRepository(srv.database.DB)
+	repo := srv.configRepository(context.Background())
 
 	// Set only API key, missing URL
 	if err := repo.Set("llm_api_key", "sk-test-key"); err != nil {
@@ -274,7 +296,7 @@ func TestLoadLLMConfig_MissingURL(t *testing.T) {
 
 func TestLoadLLMConfig_MissingAPIKey(t *testing.T) {
 	srv := newTestServer(t)
-	repo := repositories.NewConfigRepository(srv.database.DB)
+	repo := srv.configRepository(context.Background())
 
 	// Set only URL, missing API key
 	if err := repo.Set("llm_provider_url", "https://api.openai.com/v1"); err != nil {
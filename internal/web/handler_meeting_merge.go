@@ -0,0 +1,131 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/validation"
+)
+
+// mergeMeetingRequest is the request body for merging a meeting into another
+type mergeMeetingRequest struct {
+	// MeetingID is the meeting merged into the one of the path and then deleted permanently
+	MeetingID int `json:"meeting_id"`
+}
+
+// splitMeetingRequest is the request body for splitting notes off a meeting
+type splitMeetingRequest struct {
+	FromNote int    `json:"from_note"`
+	ToNote   int    `json:"to_note"`
+	Subject  string `json:"subject"`
+}
+
+// handleMergeMeeting handles POST /api/meetings/{id}/merge. It honors
+// If-Match for the meeting that is kept.
+func (s *Server) handleMergeMeeting(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+	var req mergeMeetingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.MeetingID == 0 {
+		writeError(w, http.StatusBadRequest, "missing required field: meeting_id")
+		return
+	}
+
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	existing := s.meetingForWrite(w, r, repo, int(id))
+	if existing == nil || !s.meetingExists(w, r, req.MeetingID) {
+		return
+	}
+
+	err = repo.IfVersion(ifMatchVersion(r, existing.Version)).Merge(int(id), req.MeetingID)
+	switch {
+	case errors.Is(err, repositories.ErrMergeSelf):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, repositories.ErrVersionConflict):
+		s.writeMeetingChanged(w, r, repo, int(id))
+		return
+	case err != nil:
+		s.logError(r, "failed to merge meetings", err)
+		writeError(w, http.StatusInternalServerError, "failed to merge meetings")
+		return
+	}
+
+	merged, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to fetch merged meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch merged meeting")
+		return
+	}
+	s.publish(r, events.MeetingDeleted, req.MeetingID, deletedEntity{ID: req.MeetingID})
+	s.publish(r, events.MeetingUpdated, merged.ID, merged)
+	if _, err := s.publishNoteOrder(r, merged.ID); err != nil {
+		s.logError(r, "failed to list notes", err)
+	}
+	writeTaggedJSON(w, http.StatusOK, entityTag(merged.ID, merged.Version), merged)
+}
+
+// handleSplitMeeting handles POST /api/meetings/{id}/split, which moves the
+// notes numbered from_note to to_note into a new meeting
+func (s *Server) handleSplitMeeting(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+	var req splitMeetingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.FromNote < 1 || req.ToNote < req.FromNote {
+		writeError(w, http.StatusBadRequest, "invalid note range: from_note must be at least 1 and at most to_note")
+		return
+	}
+
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	original := s.meetingForWrite(w, r, repo, int(id))
+	if original == nil {
+		return
+	}
+	if req.Subject == "" {
+		req.Subject = original.Subject
+	}
+	if len(req.Subject) > validation.MaxSubjectLength {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("subject exceeds maximum length of %d characters", validation.MaxSubjectLength))
+		return
+	}
+
+	split, err := repo.IfVersion(ifMatchVersion(r, original.Version)).Split(original.ID, req.FromNote, req.ToNote, req.Subject)
+	switch {
+	case errors.Is(err, repositories.ErrEmptySplit):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, repositories.ErrVersionConflict):
+		s.writeMeetingChanged(w, r, repo, original.ID)
+		return
+	case err != nil:
+		s.logError(r, "failed to split meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to split meeting")
+		return
+	}
+
+	s.publish(r, events.MeetingCreated, split.ID, split)
+	for _, meetingID := range []int{original.ID, split.ID} {
+		if _, err := s.publishNoteOrder(r, meetingID); err != nil {
+			s.logError(r, "failed to list notes", err)
+		}
+	}
+	writeTaggedJSON(w, http.StatusCreated, entityTag(split.ID, split.Version), split)
+}
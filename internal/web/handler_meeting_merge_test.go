@@ -0,0 +1,103 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+func TestMergeMeeting(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	meeting, _ := createOrderTestNotes(t, handler, "Standup", "a", "b")
+	duplicate, _ := createOrderTestNotes(t, handler, "Standup (copy)", "c")
+	path := "/api/meetings/" + strconv.Itoa(meeting.ID) + "/merge"
+
+	var merged models.Meeting
+	serveJSON(t, handler, http.MethodPost, path, fmt.Sprintf(`{"meeting_id": %d}`, duplicate.ID), http.StatusOK, &merged)
+	if merged.ID != meeting.ID || merged.Subject != "Standup" {
+		t.Errorf("merged = %+v, want the meeting of the path", merged)
+	}
+
+	var notes []models.Note
+	serveJSON(t, handler, http.MethodGet, "/api/meetings/"+strconv.Itoa(meeting.ID)+"/notes", "", http.StatusOK, &notes)
+	if got := noteContents(notes); got != "1:a 2:b 3:c " {
+		t.Errorf("notes = %q, want 1:a 2:b 3:c", got)
+	}
+	serveJSON(t, handler, http.MethodGet, "/api/meetings/"+strconv.Itoa(duplicate.ID), "", http.StatusNotFound, nil)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"itself", path, fmt.Sprintf(`{"meeting_id": %d}`, meeting.ID), http.StatusBadRequest},
+		{"merged meeting", path, fmt.Sprintf(`{"meeting_id": %d}`, duplicate.ID), http.StatusNotFound},
+		{"missing meeting", path, `{}`, http.StatusBadRequest},
+		{"unknown meeting", "/api/meetings/999/merge", fmt.Sprintf(`{"meeting_id": %d}`, meeting.ID), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveJSON(t, handler, http.MethodPost, tt.path, tt.body, tt.status, nil)
+		})
+	}
+}
+
+func TestSplitMeeting(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	meeting, _ := createOrderTestNotes(t, handler, "Standup", "a", "b", "c")
+	path := "/api/meetings/" + strconv.Itoa(meeting.ID) + "/split"
+
+	var split models.Meeting
+	serveJSON(t, handler, http.MethodPost, path, `{"from_note": 2, "to_note": 3}`, http.StatusCreated, &split)
+	if split.ID == meeting.ID || split.Subject != "Standup" || split.MeetingDate != meeting.MeetingDate {
+		t.Errorf("split = %+v, want a copy of the meeting", split)
+	}
+
+	var notes []models.Note
+	serveJSON(t, handler, http.MethodGet, "/api/meetings/"+strconv.Itoa(split.ID)+"/notes", "", http.StatusOK, &notes)
+	if got := noteContents(notes); got != "1:b 2:c " {
+		t.Errorf("split notes = %q, want 1:b 2:c", got)
+	}
+	serveJSON(t, handler, http.MethodGet, "/api/meetings/"+strconv.Itoa(meeting.ID)+"/notes", "", http.StatusOK, &notes)
+	if got := noteContents(notes); got != "1:a " {
+		t.Errorf("remaining notes = %q, want 1:a", got)
+	}
+
+	// A stale If-Match is rejected with the current meeting
+	stale := map[string]string{"If-Match": entityTag(meeting.ID, meeting.Version-1)}
+	if w := serveConditional(t, handler, http.MethodPost, path, `{"from_note": 1, "to_note": 1}`, stale); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected 412 for a stale If-Match, got %d: %s", w.Code, w.Body.String())
+	}
+	current := map[string]string{"If-Match": entityTag(meeting.ID, meeting.Version)}
+	if w := serveConditional(t, handler, http.MethodPost, path, `{"from_note": 1, "to_note": 1}`, current); w.Code != http.StatusCreated {
+		t.Errorf("expected 201 for a current If-Match, got %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"empty range", path, `{"from_note": 5, "to_note": 6}`, http.StatusBadRequest},
+		{"reversed range", path, `{"from_note": 2, "to_note": 1}`, http.StatusBadRequest},
+		{"unknown meeting", "/api/meetings/999/split", `{"from_note": 1, "to_note": 1}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveJSON(t, handler, http.MethodPost, tt.path, tt.body, tt.status, nil)
+		})
+	}
+}
@@ -3,12 +3,14 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/zorak1103/notebook/internal/db/models"
 	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
 	"github.com/zorak1103/notebook/internal/validation"
 )
 
@@ -17,6 +19,7 @@ const (
 	dateFormat        = "2006-01-02"
 	timeFormat        = "15:04"
 	defaultSortColumn = "meeting_date"
+	maxMeetingLength  = 24 * time.Hour
 )
 
 // validateMeetingDateTime validates date and time formats
@@ -70,6 +73,71 @@ func validateMeeting(m *models.Meeting) error {
 	return validateMeetingFieldLengths(m)
 }
 
+// meetingLocation loads the named IANA zone, or returns fallback for an empty name
+func meetingLocation(name string, fallback *time.Location) (*time.Location, error) {
+	if name == "" {
+		return fallback, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", name)
+	}
+	return loc, nil
+}
+
+// resolveMeetingTimes completes whichever of the local (meeting_date,
+// start_time, end_time) and UTC (start_utc, end_utc) representations the
+// client left out. When both are given the local fields win.
+func resolveMeetingTimes(m *models.Meeting, fallback *time.Location) error {
+	loc, err := meetingLocation(m.Timezone, fallback)
+	if err != nil {
+		return err
+	}
+
+	if m.MeetingDate == "" && m.StartTime == "" && m.StartUTC != nil {
+		if m.EndUTC != nil && (!m.EndUTC.After(*m.StartUTC) || m.EndUTC.Sub(*m.StartUTC) >= maxMeetingLength) {
+			return fmt.Errorf("end_utc must be after start_utc and less than 24 hours later")
+		}
+		setLocalTimes(m, loc)
+		return nil
+	}
+
+	if err := validateMeetingDateTime(m.MeetingDate, m.StartTime, m.EndTime); err != nil {
+		return err
+	}
+	m.Timezone = loc.String()
+	return m.ResolveUTC(loc)
+}
+
+// setLocalTimes derives the local date and times from StartUTC and EndUTC
+func setLocalTimes(m *models.Meeting, loc *time.Location) {
+	start := m.StartUTC.In(loc)
+	m.Timezone = loc.String()
+	m.MeetingDate = start.Format(dateFormat)
+	m.StartTime = start.Format(timeFormat)
+	m.EndTime = nil
+	if m.EndUTC != nil {
+		end := m.EndUTC.In(loc).Format(timeFormat)
+		m.EndTime = &end
+	}
+}
+
+// prepareMeeting checks the required fields of a create or update request,
+// completes its time representations and validates the result. zone is
+// used when the request names no timezone; empty means the server default.
+func (s *Server) prepareMeeting(m *models.Meeting, zone string) error {
+	if m.Subject == "" || (m.StartUTC == nil && (m.MeetingDate == "" || m.StartTime == "")) {
+		return fmt.Errorf("missing required fields: subject, meeting_date, start_time")
+	}
+	if m.Timezone == "" {
+		m.Timezone = zone
+	}
+	if err := resolveMeetingTimes(m, s.timeLocation()); err != nil {
+		return err
+	}
+	return validateMeeting(m)
+}
+
 // handleListMeetings handles GET /api/meetings with optional sorting
 func (s *Server) handleListMeetings(w http.ResponseWriter, r *http.Request) {
 	sortColumn := r.URL.Query().Get("sort")
@@ -80,7 +148,7 @@ func (s *Server) handleListMeetings(w http.ResponseWriter, r *http.Request) {
 	order := r.URL.Query().Get("order")
 	ascending := order == "asc"
 
-	repo := repositories.NewMeetingRepository(s.database.DB)
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
 	meetings, err := repo.List(sortColumn, ascending)
 	if err != nil {
 		s.logError(r, "failed to list meetings", err)
@@ -93,7 +161,7 @@ func (s *Server) handleListMeetings(w http.ResponseWriter, r *http.Request) {
 		meetings = []*models.Meeting{}
 	}
 
-	writeJSON(w, http.StatusOK, meetings)
+	writeConditionalJSON(w, r, "", meetings)
 }
 
 // handleGetMeeting handles GET /api/meetings/{id}
@@ -104,7 +172,7 @@ func (s *Server) handleGetMeeting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	repo := repositories.NewMeetingRepository(s.database.DB)
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
 	meeting, err := repo.GetByID(int(id))
 	if err != nil {
 		s.logError(r, "failed to get meeting", err)
@@ -117,7 +185,7 @@ func (s *Server) handleGetMeeting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, meeting)
+	writeConditionalJSON(w, r, entityTag(meeting.ID, meeting.Version), meeting)
 }
 
 // handleCreateMeeting handles POST /api/meetings
@@ -128,29 +196,35 @@ func (s *Server) handleCreateMeeting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if meeting.Subject == "" || meeting.MeetingDate == "" || meeting.StartTime == "" {
-		writeError(w, http.StatusBadRequest, "missing required fields: subject, meeting_date, start_time")
+	// Validate required fields, formats and lengths
+	if err := s.prepareMeeting(&meeting, ""); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Validate formats and lengths
-	if err := validateMeeting(&meeting); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
 		return
 	}
+	meeting.CreatedBy = user.LoginName
 
-	// Set created_by (dev mode placeholder for now)
-	meeting.CreatedBy = devModeCreatedBy
-
-	repo := repositories.NewMeetingRepository(s.database.DB)
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
 	if err := repo.Create(&meeting); err != nil {
 		s.logError(r, "failed to create meeting", err)
 		writeError(w, http.StatusInternalServerError, "failed to create meeting")
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, meeting)
+	created, err := repo.GetByID(meeting.ID)
+	if err != nil {
+		s.logError(r, "failed to fetch created meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch created meeting")
+		return
+	}
+
+	s.publish(r, events.MeetingCreated, created.ID, created)
+	writeTaggedJSON(w, http.StatusCreated, entityTag(created.ID, created.Version), created)
 }
 
 // handleUpdateMeeting handles PUT /api/meetings/{id}
@@ -168,43 +242,129 @@ func (s *Server) handleUpdateMeeting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if meeting.Subject == "" || meeting.MeetingDate == "" || meeting.StartTime == "" {
-		writeError(w, http.StatusBadRequest, "missing required fields: subject, meeting_date, start_time")
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	existing := s.meetingForWrite(w, r, repo, int(id))
+	if existing == nil {
 		return
 	}
 
-	// Validate formats and lengths
-	err = validateMeeting(&meeting)
-	if err != nil {
+	// Validate required fields, formats and lengths; the meeting keeps its zone unless the request names one
+	if err := s.prepareMeeting(&meeting, existing.Timezone); err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Check if meeting exists
-	repo := repositories.NewMeetingRepository(s.database.DB)
-	existing, err := repo.GetByID(int(id))
+	// Set ID from path parameter
+	meeting.ID = int(id)
+
+	err = repo.IfVersion(ifMatchVersion(r, existing.Version)).Update(&meeting)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		s.writeMeetingChanged(w, r, repo, int(id))
+		return
+	}
 	if err != nil {
-		s.logError(r, "failed to check meeting existence", err)
-		writeError(w, http.StatusInternalServerError, "failed to check meeting existence")
+		s.logError(r, "failed to update meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to update meeting")
 		return
 	}
+
+	// Fetch updated meeting to return with all fields
+	updated, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to fetch updated meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch updated meeting")
+		return
+	}
+
+	s.publish(r, events.MeetingUpdated, updated.ID, updated)
+	writeTaggedJSON(w, http.StatusOK, entityTag(updated.ID, updated.Version), updated)
+}
+
+// meetingPatchFields are the meeting fields a merge patch may change
+var meetingPatchFields = []string{
+	"subject", "meeting_date", "start_time", "end_time", "participants", "summary", "keywords",
+	"timezone", "start_utc", "end_utc",
+}
+
+// applyMeetingPatch returns existing with a merge patch applied, its time
+// representations completed and its fields validated. Patching local times
+// or the timezone re-derives the UTC instants; patching only the instants
+// re-derives the local times.
+func applyMeetingPatch(existing *models.Meeting, patch mergePatch, fallback *time.Location) (*models.Meeting, error) {
+	if err := patch.allow(meetingPatchFields...); err != nil {
+		return nil, err
+	}
+
+	m := *existing
+	if err := errors.Join(
+		patch.requiredString("subject", &m.Subject),
+		patch.requiredString("meeting_date", &m.MeetingDate),
+		patch.requiredString("start_time", &m.StartTime),
+		patch.optionalString("end_time", &m.EndTime),
+		patch.optionalString("participants", &m.Participants),
+		patch.optionalString("summary", &m.Summary),
+		patch.optionalString("keywords", &m.Keywords),
+		patch.requiredString("timezone", &m.Timezone),
+		patch.optionalTime("start_utc", &m.StartUTC, true),
+		patch.optionalTime("end_utc", &m.EndUTC, false),
+	); err != nil {
+		return nil, err
+	}
+
+	if patch.has("start_utc", "end_utc") && !patch.has("meeting_date", "start_time", "end_time") {
+		m.MeetingDate, m.StartTime = "", ""
+	}
+	if err := resolveMeetingTimes(&m, fallback); err != nil {
+		return nil, err
+	}
+	if err := validateMeetingFieldLengths(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// handlePatchMeeting handles PATCH /api/meetings/{id} with an RFC 7396 merge
+// patch. Only the columns the patch changes are written.
+func (s *Server) handlePatchMeeting(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid meeting ID")
+		return
+	}
+
+	patch, err := decodeMergePatch(r)
+	if errors.Is(err, errUnsupportedPatchType) {
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	existing := s.meetingForWrite(w, r, repo, int(id))
 	if existing == nil {
-		writeError(w, http.StatusNotFound, "meeting not found")
 		return
 	}
 
-	// Set ID from path parameter
-	meeting.ID = int(id)
+	meeting, err := applyMeetingPatch(existing, patch, s.timeLocation())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	err = repo.Update(&meeting)
+	err = repo.IfVersion(ifMatchVersion(r, existing.Version)).Patch(meeting)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		s.writeMeetingChanged(w, r, repo, int(id))
+		return
+	}
 	if err != nil {
-		s.logError(r, "failed to update meeting", err)
+		s.logError(r, "failed to patch meeting", err)
 		writeError(w, http.StatusInternalServerError, "failed to update meeting")
 		return
 	}
 
-	// Fetch updated meeting to return with all fields
 	updated, err := repo.GetByID(int(id))
 	if err != nil {
 		s.logError(r, "failed to fetch updated meeting", err)
@@ -212,10 +372,12 @@ func (s *Server) handleUpdateMeeting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, updated)
+	s.publish(r, events.MeetingUpdated, updated.ID, updated)
+	writeTaggedJSON(w, http.StatusOK, entityTag(updated.ID, updated.Version), updated)
 }
 
-// handleDeleteMeeting handles DELETE /api/meetings/{id}
+// handleDeleteMeeting handles DELETE /api/meetings/{id}. The meeting and its
+// notes move to the trash.
 func (s *Server) handleDeleteMeeting(w http.ResponseWriter, r *http.Request) {
 	id, err := parseIDParam(r)
 	if err != nil {
@@ -223,24 +385,61 @@ func (s *Server) handleDeleteMeeting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if meeting exists
-	repo := repositories.NewMeetingRepository(s.database.DB)
-	existing, err := repo.GetByID(int(id))
-	if err != nil {
-		s.logError(r, "failed to check meeting existence", err)
-		writeError(w, http.StatusInternalServerError, "failed to check meeting existence")
-		return
-	}
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	existing := s.meetingForWrite(w, r, repo, int(id))
 	if existing == nil {
-		writeError(w, http.StatusNotFound, "meeting not found")
 		return
 	}
 
-	if err := repo.Delete(int(id)); err != nil {
+	err = repo.IfVersion(ifMatchVersion(r, existing.Version)).Delete(int(id))
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		s.writeMeetingChanged(w, r, repo, int(id))
+		return
+	}
+	if err != nil {
 		s.logError(r, "failed to delete meeting", err)
 		writeError(w, http.StatusInternalServerError, "failed to delete meeting")
 		return
 	}
 
+	s.publish(r, events.MeetingDeleted, int(id), deletedEntity{ID: int(id)})
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// meetingForWrite loads the meeting a PUT or DELETE targets and checks the
+// request's If-Match against it. When the meeting does not exist or has
+// changed it writes the response and returns nil.
+func (s *Server) meetingForWrite(w http.ResponseWriter, r *http.Request, repo *repositories.MeetingRepository, id int) *models.Meeting {
+	existing, err := repo.GetByID(id)
+	if err != nil {
+		s.logError(r, "failed to check meeting existence", err)
+		writeError(w, http.StatusInternalServerError, "failed to check meeting existence")
+		return nil
+	}
+	if existing == nil {
+		writeError(w, http.StatusNotFound, "meeting not found")
+		return nil
+	}
+
+	if etag := entityTag(existing.ID, existing.Version); ifMatchFailed(r, etag) {
+		writePreconditionFailed(w, etag, existing)
+		return nil
+	}
+	return existing
+}
+
+// writeMeetingChanged answers a write that lost a race with another change
+// of the meeting with 412 and the meeting's current state
+func (s *Server) writeMeetingChanged(w http.ResponseWriter, r *http.Request, repo *repositories.MeetingRepository, id int) {
+	current, err := repo.GetByID(id)
+	if err != nil {
+		s.logError(r, "failed to fetch changed meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch changed meeting")
+		return
+	}
+	if current == nil {
+		writeError(w, http.StatusNotFound, "meeting not found")
+		return
+	}
+	writePreconditionFailed(w, entityTag(current.ID, current.Version), current)
+}
@@ -6,12 +6,17 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/zorak1103/notebook/internal/db"
 	"github.com/zorak1103/notebook/internal/db/models"
 	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/presence"
+	"github.com/zorak1103/notebook/internal/secrets"
+	"github.com/zorak1103/notebook/internal/tsapp"
 	"github.com/zorak1103/notebook/internal/validation"
 )
 
@@ -28,7 +33,17 @@ func newTestServer(t *testing.T) *Server {
 		t.Fatalf("failed to migrate test database: %v", err)
 	}
 
-	return &Server{database: database}
+	cipher, err := secrets.New([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+
+	return &Server{
+		database: database,
+		cipher:   cipher,
+		events:   events.NewBus(0),
+		presence: presence.NewTracker(0, 0),
+	}
 }
 
 func TestHandleListMeetings_Empty(t *testing.T) {
@@ -245,6 +260,7 @@ func TestHandleGetMeeting_InvalidID(t *testing.T) {
 func TestHandleCreateMeeting_Success(t *testing.T) {
 	server := newTestServer(t)
 	defer server.database.Close()
+	server.devMode = true
 
 	payload := map[string]interface{}{
 		"subject":      "New Meeting",
@@ -279,6 +295,26 @@ func TestHandleCreateMeeting_Success(t *testing.T) {
 	}
 }
 
+func TestHandleCreateMeeting_CreatedByUser(t *testing.T) {
+	server := newTestServer(t)
+	defer server.database.Close()
+
+	body := `{"subject": "Planning", "meeting_date": "2026-03-01", "start_time": "09:00"}`
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey{}, &tsapp.UserInfo{LoginName: "ann@example.com"}))
+	w := httptest.NewRecorder()
+
+	server.handleCreateMeeting(w, req)
+
+	var result models.Meeting
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.CreatedBy != "ann@example.com" {
+		t.Errorf("expected created_by of the request user, got %q", result.CreatedBy)
+	}
+}
+
 func TestHandleCreateMeeting_MissingFields(t *testing.T) {
 	server := newTestServer(t)
 	defer server.database.Close()
@@ -737,3 +773,164 @@ func TestHandleUpdateMeeting_SubjectTooLong(t *testing.T) {
 		t.Error("expected error message, got empty string")
 	}
 }
+
+func createMeetingRequest(t *testing.T, server *Server, payload map[string]interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	server.handleCreateMeeting(w, req)
+	return w
+}
+
+func TestHandleCreateMeeting_TimeZones(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Berlin"); err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		payload       map[string]interface{}
+		expectedStart string
+		expectedEnd   string
+		expectedLocal [3]string // date, start, end
+		expectedZone  string
+	}{
+		{
+			name:          "local times in named zone",
+			payload:       map[string]interface{}{"meeting_date": "2026-03-10", "start_time": "10:00", "end_time": "11:30", "timezone": "Europe/Berlin"},
+			expectedStart: "2026-03-10T09:00:00Z",
+			expectedEnd:   "2026-03-10T10:30:00Z",
+			expectedLocal: [3]string{"2026-03-10", "10:00", "11:30"},
+			expectedZone:  "Europe/Berlin",
+		},
+		{
+			name:          "end time crossing midnight",
+			payload:       map[string]interface{}{"meeting_date": "2026-03-10", "start_time": "23:00", "end_time": "01:00", "timezone": "Europe/Berlin"},
+			expectedStart: "2026-03-10T22:00:00Z",
+			expectedEnd:   "2026-03-11T00:00:00Z",
+			expectedLocal: [3]string{"2026-03-10", "23:00", "01:00"},
+			expectedZone:  "Europe/Berlin",
+		},
+		{
+			name:          "UTC instants only",
+			payload:       map[string]interface{}{"start_utc": "2026-07-01T16:00:00Z", "end_utc": "2026-07-01T17:00:00Z", "timezone": "Europe/Berlin"},
+			expectedStart: "2026-07-01T16:00:00Z",
+			expectedEnd:   "2026-07-01T17:00:00Z",
+			expectedLocal: [3]string{"2026-07-01", "18:00", "19:00"},
+			expectedZone:  "Europe/Berlin",
+		},
+		{
+			name:          "server default zone",
+			payload:       map[string]interface{}{"meeting_date": "2026-03-10", "start_time": "10:00"},
+			expectedStart: "2026-03-10T10:00:00Z",
+			expectedLocal: [3]string{"2026-03-10", "10:00", ""},
+			expectedZone:  "UTC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.database.Close()
+			server.location = time.UTC
+			server.devMode = true
+
+			tt.payload["subject"] = "Zoned"
+			w := createMeetingRequest(t, server, tt.payload)
+			if w.Code != http.StatusCreated {
+				t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var m models.Meeting
+			if err := json.NewDecoder(w.Body).Decode(&m); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if m.Timezone != tt.expectedZone {
+				t.Errorf("expected timezone %q, got %q", tt.expectedZone, m.Timezone)
+			}
+			if m.StartUTC == nil || m.StartUTC.Format(time.RFC3339) != tt.expectedStart {
+				t.Errorf("expected start_utc %s, got %v", tt.expectedStart, m.StartUTC)
+			}
+			if tt.expectedEnd == "" && m.EndUTC != nil {
+				t.Errorf("expected no end_utc, got %v", m.EndUTC)
+			}
+			if tt.expectedEnd != "" && (m.EndUTC == nil || m.EndUTC.Format(time.RFC3339) != tt.expectedEnd) {
+				t.Errorf("expected end_utc %s, got %v", tt.expectedEnd, m.EndUTC)
+			}
+
+			end := ""
+			if m.EndTime != nil {
+				end = *m.EndTime
+			}
+			if got := [3]string{m.MeetingDate, m.StartTime, end}; got != tt.expectedLocal {
+				t.Errorf("expected local %v, got %v", tt.expectedLocal, got)
+			}
+		})
+	}
+}
+
+func TestHandleCreateMeeting_InvalidTimeZones(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload map[string]interface{}
+	}{
+		{"unknown zone", map[string]interface{}{"meeting_date": "2026-03-10", "start_time": "10:00", "timezone": "Mars/Olympus_Mons"}},
+		{"end before start", map[string]interface{}{"start_utc": "2026-03-10T10:00:00Z", "end_utc": "2026-03-10T09:00:00Z"}},
+		{"longer than a day", map[string]interface{}{"start_utc": "2026-03-10T10:00:00Z", "end_utc": "2026-03-11T10:00:00Z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.database.Close()
+
+			tt.payload["subject"] = "Zoned"
+			if w := createMeetingRequest(t, server, tt.payload); w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateMeeting_KeepsTimeZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	server := newTestServer(t)
+	defer server.database.Close()
+	server.location = time.UTC
+
+	repo := repositories.NewMeetingRepository(server.database.DB)
+	meeting := &models.Meeting{CreatedBy: "a@example.com", Subject: "Sync", MeetingDate: "2026-03-10", StartTime: "09:00", Timezone: "Asia/Tokyo"}
+	if err := meeting.ResolveUTC(tokyo); err != nil {
+		t.Fatalf("failed to resolve times: %v", err)
+	}
+	if err := repo.Create(meeting); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"subject": "Sync", "meeting_date": "2026-03-10", "start_time": "10:00"})
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPut, "/api/meetings/1", bytes.NewReader(body))
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+
+	server.handleUpdateMeeting(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var updated models.Meeting
+	if err := json.NewDecoder(w.Body).Decode(&updated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if updated.Timezone != "Asia/Tokyo" || updated.StartUTC == nil || updated.StartUTC.Format(time.RFC3339) != "2026-03-10T01:00:00Z" {
+		t.Errorf("expected 10:00 Tokyo time kept in its zone, got %s %v", updated.Timezone, updated.StartUTC)
+	}
+}
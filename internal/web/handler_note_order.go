@@ -0,0 +1,135 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+)
+
+// noteOrderRequest is the request body for setting the order of the notes
+// of a meeting
+type noteOrderRequest struct {
+	NoteIDs []int `json:"note_ids"`
+}
+
+// moveNotesRequest is the request body for moving notes to another meeting
+type moveNotesRequest struct {
+	NoteIDs   []int `json:"note_ids"`
+	MeetingID int   `json:"meeting_id"`
+}
+
+// meetingExists writes 404 and returns false if a meeting is not found or
+// in the trash
+func (s *Server) meetingExists(w http.ResponseWriter, r *http.Request, id int) bool {
+	meeting, err := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context()).GetByID(id)
+	if err != nil {
+		s.logError(r, "failed to get meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to get meeting")
+		return false
+	}
+	if meeting == nil {
+		writeError(w, http.StatusNotFound, "meeting not found")
+		return false
+	}
+	return true
+}
+
+// publishNoteOrder announces the notes of a meeting in their new order and
+// returns them
+func (s *Server) publishNoteOrder(r *http.Request, meetingID int) ([]*models.Note, error) {
+	notes, err := s.noteRepository(r.Context()).ListByMeeting(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if notes == nil {
+		notes = []*models.Note{}
+	}
+	s.publish(r, events.NoteReordered, meetingID, notes)
+	return notes, nil
+}
+
+// handleSetNoteOrder handles PUT /api/meetings/{meetingId}/notes/order,
+// which puts all notes of a meeting in the given order at once
+func (s *Server) handleSetNoteOrder(w http.ResponseWriter, r *http.Request) {
+	meetingID, err := parseMeetingIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+	var req noteOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if !s.meetingExists(w, r, int(meetingID)) {
+		return
+	}
+
+	err = s.noteRepository(r.Context()).ReorderNotes(int(meetingID), req.NoteIDs)
+	if errors.Is(err, repositories.ErrInvalidOrder) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		s.logError(r, "failed to reorder notes", err)
+		writeError(w, http.StatusInternalServerError, "failed to reorder notes")
+		return
+	}
+
+	notes, err := s.publishNoteOrder(r, int(meetingID))
+	if err != nil {
+		s.logError(r, "failed to list updated notes", err)
+		writeError(w, http.StatusInternalServerError, "failed to list updated notes")
+		return
+	}
+	writeJSON(w, http.StatusOK, notes)
+}
+
+// handleMoveNotes handles POST /api/notes/move, which appends notes of other
+// meetings to a meeting and renumbers all meetings involved
+func (s *Server) handleMoveNotes(w http.ResponseWriter, r *http.Request) {
+	var req moveNotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.MeetingID == 0 {
+		writeError(w, http.StatusBadRequest, "missing required field: meeting_id")
+		return
+	}
+	if !s.meetingExists(w, r, req.MeetingID) {
+		return
+	}
+
+	sources, err := s.noteRepository(r.Context()).MoveNotes(req.NoteIDs, req.MeetingID)
+	if errors.Is(err, repositories.ErrMeetingNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, repositories.ErrInvalidMove) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		s.logError(r, "failed to move notes", err)
+		writeError(w, http.StatusInternalServerError, "failed to move notes")
+		return
+	}
+
+	for _, id := range sources {
+		if _, err := s.publishNoteOrder(r, id); err != nil {
+			s.logError(r, "failed to list notes", err)
+		}
+	}
+	notes, err := s.publishNoteOrder(r, req.MeetingID)
+	if err != nil {
+		s.logError(r, "failed to list updated notes", err)
+		writeError(w, http.StatusInternalServerError, "failed to list updated notes")
+		return
+	}
+	writeJSON(w, http.StatusOK, notes)
+}
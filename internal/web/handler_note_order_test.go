@@ -0,0 +1,91 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// createOrderTestNotes creates a meeting with a note per content
+func createOrderTestNotes(t *testing.T, handler http.Handler, subject string, contents ...string) (models.Meeting, []models.Note) {
+	t.Helper()
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "`+subject+`", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	notes := make([]models.Note, len(contents))
+	for i, content := range contents {
+		serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(meeting.ID)+`, "content": "`+content+`"}`, http.StatusCreated, &notes[i])
+	}
+	return meeting, notes
+}
+
+// noteContents returns the contents of notes in order
+func noteContents(notes []models.Note) string {
+	var s string
+	for _, n := range notes {
+		s += fmt.Sprintf("%d:%s ", n.NoteNumber, n.Content)
+	}
+	return s
+}
+
+func TestSetNoteOrder(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	meeting, notes := createOrderTestNotes(t, handler, "Standup", "a", "b", "c")
+	path := "/api/meetings/" + strconv.Itoa(meeting.ID) + "/notes/order"
+
+	var updated []models.Note
+	body := fmt.Sprintf(`{"note_ids": [%d, %d, %d]}`, notes[2].ID, notes[0].ID, notes[1].ID)
+	serveJSON(t, handler, http.MethodPut, path, body, http.StatusOK, &updated)
+	if got := noteContents(updated); got != "1:c 2:a 3:b " {
+		t.Errorf("order = %q, want 1:c 2:a 3:b", got)
+	}
+
+	serveJSON(t, handler, http.MethodPut, path, fmt.Sprintf(`{"note_ids": [%d]}`, notes[0].ID), http.StatusBadRequest, nil)
+	serveJSON(t, handler, http.MethodPut, "/api/meetings/999/notes/order", `{"note_ids": []}`, http.StatusNotFound, nil)
+	serveJSON(t, handler, http.MethodPut, "/api/meetings/abc/notes/order", `{"note_ids": []}`, http.StatusBadRequest, nil)
+}
+
+func TestMoveNotes(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	source, notes := createOrderTestNotes(t, handler, "Standup", "a", "b", "c")
+	target, _ := createOrderTestNotes(t, handler, "Retro", "x")
+
+	var moved []models.Note
+	body := fmt.Sprintf(`{"note_ids": [%d, %d], "meeting_id": %d}`, notes[2].ID, notes[0].ID, target.ID)
+	serveJSON(t, handler, http.MethodPost, "/api/notes/move", body, http.StatusOK, &moved)
+	if got := noteContents(moved); got != "1:x 2:c 3:a " {
+		t.Errorf("target order = %q, want 1:x 2:c 3:a", got)
+	}
+
+	var remaining []models.Note
+	serveJSON(t, handler, http.MethodGet, "/api/meetings/"+strconv.Itoa(source.ID)+"/notes", "", http.StatusOK, &remaining)
+	if got := noteContents(remaining); got != "1:b " {
+		t.Errorf("source order = %q, want 1:b", got)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"missing meeting", fmt.Sprintf(`{"note_ids": [%d]}`, notes[1].ID), http.StatusBadRequest},
+		{"unknown meeting", fmt.Sprintf(`{"note_ids": [%d], "meeting_id": 999}`, notes[1].ID), http.StatusNotFound},
+		{"note of the target", fmt.Sprintf(`{"note_ids": [%d], "meeting_id": %d}`, notes[0].ID, target.ID), http.StatusBadRequest},
+		{"invalid body", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveJSON(t, handler, http.MethodPost, "/api/notes/move", tt.body, tt.status, nil)
+		})
+	}
+}
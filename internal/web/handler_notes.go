@@ -2,14 +2,17 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/zorak1103/notebook/internal/db/models"
 	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
 	"github.com/zorak1103/notebook/internal/validation"
 )
 
@@ -21,6 +24,11 @@ const (
 	directionDown       = "down"
 )
 
+// noteRepository returns a note repository that applies the revision retention
+func (s *Server) noteRepository(ctx context.Context) *repositories.NoteRepository {
+	return repositories.NewNoteRepository(s.database.DB).WithContext(ctx).WithRetention(s.noteRetention)
+}
+
 // parseMeetingIDParam extracts and parses the "meetingId" path parameter.
 func parseMeetingIDParam(r *http.Request) (int64, error) {
 	idStr := r.PathValue("meetingId")
@@ -40,6 +48,43 @@ func validateNoteContent(content string) error {
 	return nil
 }
 
+// validateNoteType validates a note type is one of models.NoteTypes
+func validateNoteType(noteType string) error {
+	if !slices.Contains(models.NoteTypes, noteType) {
+		return fmt.Errorf("invalid note_type: must be one of %v", models.NoteTypes)
+	}
+	return nil
+}
+
+// noteUpdateRequest is the request body for updating a note
+type noteUpdateRequest struct {
+	Content string `json:"content"`
+	// NoteType replaces the type of the note unless empty
+	NoteType string `json:"note_type"`
+	// Source is recorded with the new revision: manual (default) or llm-enhance
+	Source string `json:"source"`
+}
+
+// validate validates the content and type of an update and defaults its
+// source to manual
+func (req *noteUpdateRequest) validate() error {
+	if err := validateNoteContent(req.Content); err != nil {
+		return err
+	}
+	if req.NoteType != "" {
+		if err := validateNoteType(req.NoteType); err != nil {
+			return err
+		}
+	}
+	if req.Source == "" {
+		req.Source = models.RevisionSourceManual
+	}
+	if req.Source != models.RevisionSourceManual && req.Source != models.RevisionSourceLLMEnhance {
+		return errors.New("invalid source: must be 'manual' or 'llm-enhance'")
+	}
+	return nil
+}
+
 // reorderNoteRequest is the request body for reordering a note
 type reorderNoteRequest struct {
 	Direction string `json:"direction"`
@@ -88,7 +133,7 @@ func (s *Server) handleReorderNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	repo := repositories.NewNoteRepository(s.database.DB)
+	repo := s.noteRepository(r.Context())
 	note, err := repo.GetByID(int(id))
 	if err != nil {
 		s.logError(r, "failed to get note", err)
@@ -126,6 +171,7 @@ func (s *Server) handleReorderNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.publish(r, events.NoteReordered, note.MeetingID, updated)
 	writeJSON(w, http.StatusOK, updated)
 }
 
@@ -137,7 +183,7 @@ func (s *Server) handleListNotes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	repo := repositories.NewNoteRepository(s.database.DB)
+	repo := s.noteRepository(r.Context())
 	notes, err := repo.ListByMeeting(int(meetingID))
 	if err != nil {
 		s.logError(r, "failed to list notes", err)
@@ -150,7 +196,7 @@ func (s *Server) handleListNotes(w http.ResponseWriter, r *http.Request) {
 		notes = []*models.Note{}
 	}
 
-	writeJSON(w, http.StatusOK, notes)
+	writeConditionalJSON(w, r, "", notes)
 }
 
 // handleGetNote handles GET /api/notes/{id}
@@ -161,7 +207,7 @@ func (s *Server) handleGetNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	repo := repositories.NewNoteRepository(s.database.DB)
+	repo := s.noteRepository(r.Context())
 	note, err := repo.GetByID(int(id))
 	if err != nil {
 		s.logError(r, "failed to get note", err)
@@ -174,7 +220,7 @@ func (s *Server) handleGetNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, note)
+	writeConditionalJSON(w, r, entityTag(note.ID, note.Version), note)
 }
 
 // handleCreateNote handles POST /api/notes
@@ -191,20 +237,39 @@ func (s *Server) handleCreateNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate content
-	if err := validateNoteContent(note.Content); err != nil {
+	// Validate content and type
+	if note.NoteType == "" {
+		note.NoteType = models.NoteTypePlain
+	}
+	if err := errors.Join(validateNoteContent(note.Content), validateNoteType(note.NoteType)); err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	repo := repositories.NewNoteRepository(s.database.DB)
-	if err := repo.Create(&note); err != nil {
+	repo := s.noteRepository(r.Context())
+	err := repo.Create(&note)
+	switch {
+	case errors.Is(err, repositories.ErrMeetingNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, repositories.ErrInvalidParent):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
 		s.logError(r, "failed to create note", err)
 		writeError(w, http.StatusInternalServerError, "failed to create note")
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, note)
+	created, err := repo.GetByID(note.ID)
+	if err != nil {
+		s.logError(r, "failed to fetch created note", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch created note")
+		return
+	}
+
+	s.publish(r, events.NoteCreated, created.MeetingID, created)
+	writeTaggedJSON(w, http.StatusCreated, entityTag(created.ID, created.Version), created)
 }
 
 // handleUpdateNote handles PUT /api/notes/{id}
@@ -215,37 +280,34 @@ func (s *Server) handleUpdateNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var note models.Note
-	err = json.NewDecoder(r.Body).Decode(&note)
+	var req noteUpdateRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	// Validate content
-	err = validateNoteContent(note.Content)
-	if err != nil {
+	if err := req.validate(); err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Check if note exists
-	repo := repositories.NewNoteRepository(s.database.DB)
-	existing, err := repo.GetByID(int(id))
-	if err != nil {
-		s.logError(r, "failed to check note existence", err)
-		writeError(w, http.StatusInternalServerError, "failed to check note existence")
+	repo := s.noteRepository(r.Context())
+	existing := s.noteForWrite(w, r, repo, int(id))
+	if existing == nil || !s.checkNoteLock(w, r, existing) {
 		return
 	}
-	if existing == nil {
-		writeError(w, http.StatusNotFound, errNoteNotFound)
+
+	note := *existing
+	note.Content = req.Content
+	if req.NoteType != "" {
+		note.NoteType = req.NoteType
+	}
+	err = repo.WithRevisionSource(req.Source).IfVersion(ifMatchVersion(r, existing.Version)).Update(&note)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		s.writeNoteChanged(w, r, repo, int(id))
 		return
 	}
-
-	// Set ID from path parameter
-	note.ID = int(id)
-
-	err = repo.Update(&note)
 	if err != nil {
 		s.logError(r, "failed to update note", err)
 		writeError(w, http.StatusInternalServerError, "failed to update note")
@@ -260,35 +322,158 @@ func (s *Server) handleUpdateNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, updated)
+	s.publish(r, events.NoteUpdated, updated.MeetingID, updated)
+	writeTaggedJSON(w, http.StatusOK, entityTag(updated.ID, updated.Version), updated)
 }
 
-// handleDeleteNote handles DELETE /api/notes/{id}
-func (s *Server) handleDeleteNote(w http.ResponseWriter, r *http.Request) {
+// notePatchFields are the note fields a merge patch may change
+var notePatchFields = []string{"content", "note_type", "parent_id"}
+
+// applyNotePatch returns existing with a merge patch applied and its fields
+// validated. A null parent_id makes the note a top-level note.
+func applyNotePatch(existing *models.Note, patch mergePatch) (*models.Note, error) {
+	if err := patch.allow(notePatchFields...); err != nil {
+		return nil, err
+	}
+
+	n := *existing
+	if err := errors.Join(
+		patch.requiredString("content", &n.Content),
+		patch.requiredString("note_type", &n.NoteType),
+		patch.optionalInt("parent_id", &n.ParentID),
+	); err != nil {
+		return nil, err
+	}
+	if err := errors.Join(validateNoteContent(n.Content), validateNoteType(n.NoteType)); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// handlePatchNote handles PATCH /api/notes/{id} with an RFC 7396 merge
+// patch of the content, type and parent of a note. A patch that changes
+// nothing writes nothing.
+func (s *Server) handlePatchNote(w http.ResponseWriter, r *http.Request) {
 	id, err := parseIDParam(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, errInvalidNoteID)
 		return
 	}
 
-	// Check if note exists
-	repo := repositories.NewNoteRepository(s.database.DB)
-	existing, err := repo.GetByID(int(id))
+	patch, err := decodeMergePatch(r)
+	if errors.Is(err, errUnsupportedPatchType) {
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
 	if err != nil {
-		s.logError(r, "failed to check note existence", err)
-		writeError(w, http.StatusInternalServerError, "failed to check note existence")
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	repo := s.noteRepository(r.Context())
+	existing := s.noteForWrite(w, r, repo, int(id))
+	if existing == nil || !s.checkNoteLock(w, r, existing) {
+		return
+	}
+
+	note, err := applyNotePatch(existing, patch)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if note.Content == existing.Content && note.NoteType == existing.NoteType && note.SameParent(existing) {
+		writeTaggedJSON(w, http.StatusOK, entityTag(existing.ID, existing.Version), existing)
+		return
+	}
+
+	err = repo.IfVersion(ifMatchVersion(r, existing.Version)).Update(note)
+	switch {
+	case errors.Is(err, repositories.ErrInvalidParent):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, repositories.ErrVersionConflict):
+		s.writeNoteChanged(w, r, repo, int(id))
+		return
+	case err != nil:
+		s.logError(r, "failed to patch note", err)
+		writeError(w, http.StatusInternalServerError, "failed to update note")
 		return
 	}
+
+	updated, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to fetch updated note", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch updated note")
+		return
+	}
+
+	s.publish(r, events.NoteUpdated, updated.MeetingID, updated)
+	writeTaggedJSON(w, http.StatusOK, entityTag(updated.ID, updated.Version), updated)
+}
+
+// handleDeleteNote handles DELETE /api/notes/{id}. The note moves to the trash.
+func (s *Server) handleDeleteNote(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidNoteID)
+		return
+	}
+
+	repo := s.noteRepository(r.Context())
+	existing := s.noteForWrite(w, r, repo, int(id))
 	if existing == nil {
-		writeError(w, http.StatusNotFound, errNoteNotFound)
 		return
 	}
 
-	if err := repo.Delete(int(id)); err != nil {
+	err = repo.IfVersion(ifMatchVersion(r, existing.Version)).Delete(int(id))
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		s.writeNoteChanged(w, r, repo, int(id))
+		return
+	}
+	if err != nil {
 		s.logError(r, "failed to delete note", err)
 		writeError(w, http.StatusInternalServerError, "failed to delete note")
 		return
 	}
 
+	s.publish(r, events.NoteDeleted, existing.MeetingID, deletedEntity{ID: int(id)})
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// noteForWrite loads the note a PUT or DELETE targets and checks the
+// request's If-Match against it. When the note does not exist or has
+// changed it writes the response and returns nil.
+func (s *Server) noteForWrite(w http.ResponseWriter, r *http.Request, repo *repositories.NoteRepository, id int) *models.Note {
+	existing, err := repo.GetByID(id)
+	if err != nil {
+		s.logError(r, "failed to check note existence", err)
+		writeError(w, http.StatusInternalServerError, "failed to check note existence")
+		return nil
+	}
+	if existing == nil {
+		writeError(w, http.StatusNotFound, errNoteNotFound)
+		return nil
+	}
+
+	if etag := entityTag(existing.ID, existing.Version); ifMatchFailed(r, etag) {
+		writePreconditionFailed(w, etag, existing)
+		return nil
+	}
+	return existing
+}
+
+// writeNoteChanged answers a write that lost a race with another change of
+// the note with 412 and the note's current state
+func (s *Server) writeNoteChanged(w http.ResponseWriter, r *http.Request, repo *repositories.NoteRepository, id int) {
+	current, err := repo.GetByID(id)
+	if err != nil {
+		s.logError(r, "failed to fetch changed note", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch changed note")
+		return
+	}
+	if current == nil {
+		writeError(w, http.StatusNotFound, errNoteNotFound)
+		return
+	}
+	writePreconditionFailed(w, entityTag(current.ID, current.Version), current)
+}
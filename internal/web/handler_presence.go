@@ -0,0 +1,223 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/presence"
+)
+
+// presenceResponse lists who is in a meeting and which of its notes are locked
+type presenceResponse struct {
+	Users []presence.Entry `json:"users"`
+	Locks []presence.Lock  `json:"locks"`
+}
+
+// presenceRequest is the body of a presence heartbeat
+type presenceRequest struct {
+	State  string `json:"state"`
+	NoteID *int   `json:"note_id"`
+}
+
+// lockConflictResponse is returned when another user holds a note's lock
+type lockConflictResponse struct {
+	Error string        `json:"error"`
+	Lock  presence.Lock `json:"lock"`
+}
+
+// presenceUser returns the identity presence and locks are keyed on
+func (s *Server) presenceUser(w http.ResponseWriter, r *http.Request) (presence.User, bool) {
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
+		return presence.User{}, false
+	}
+	name := user.DisplayName
+	if name == "" {
+		name = user.LoginName
+	}
+	return presence.User{Login: user.LoginName, Name: name}, true
+}
+
+// meetingPresence returns the presence of a meeting
+func (s *Server) meetingPresence(meetingID int) presenceResponse {
+	users, locks := s.presence.Meeting(meetingID)
+	return presenceResponse{Users: users, Locks: locks}
+}
+
+// presenceMeetingID parses the meeting of a presence request and checks
+// that it exists. Otherwise it writes the response and returns false.
+func (s *Server) presenceMeetingID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return 0, false
+	}
+	meeting, err := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context()).GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to get meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to get meeting")
+		return 0, false
+	}
+	if meeting == nil {
+		writeError(w, http.StatusNotFound, "meeting not found")
+		return 0, false
+	}
+	return meeting.ID, true
+}
+
+// handleGetPresence handles GET /api/meetings/{id}/presence
+func (s *Server) handleGetPresence(w http.ResponseWriter, r *http.Request) {
+	meetingID, ok := s.presenceMeetingID(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.meetingPresence(meetingID))
+}
+
+// handleTouchPresence handles PUT /api/meetings/{id}/presence, the
+// heartbeat that marks the caller as viewing the meeting or editing one
+// of its notes
+func (s *Server) handleTouchPresence(w http.ResponseWriter, r *http.Request) {
+	meetingID, ok := s.presenceMeetingID(w, r)
+	if !ok {
+		return
+	}
+	user, ok := s.presenceUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req presenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.State == "" {
+		req.State = presence.StateViewing
+	}
+	if req.State != presence.StateViewing && req.State != presence.StateEditing {
+		writeError(w, http.StatusBadRequest, "invalid state: must be 'viewing' or 'editing'")
+		return
+	}
+	if req.State == presence.StateViewing {
+		req.NoteID = nil
+	}
+
+	_, changed := s.presence.Touch(presence.Entry{User: user, MeetingID: meetingID, NoteID: req.NoteID, State: req.State})
+	resp := s.meetingPresence(meetingID)
+	if changed {
+		s.publish(r, events.PresenceUpdated, meetingID, resp)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleLeavePresence handles DELETE /api/meetings/{id}/presence
+func (s *Server) handleLeavePresence(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+	user, ok := s.presenceUser(w, r)
+	if !ok {
+		return
+	}
+
+	if _, changed := s.presence.Leave(user.Login, int(id)); changed {
+		s.publish(r, events.PresenceUpdated, int(id), s.meetingPresence(int(id)))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleLockNote handles POST /api/notes/{id}/lock, which acquires or
+// renews the caller's lock. With ?force=true, a user present in the meeting
+// takes over the lock of another user.
+func (s *Server) handleLockNote(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidNoteID)
+		return
+	}
+	user, ok := s.presenceUser(w, r)
+	if !ok {
+		return
+	}
+	note, err := s.noteRepository(r.Context()).GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to get note", err)
+		writeError(w, http.StatusInternalServerError, "failed to get note")
+		return
+	}
+	if note == nil {
+		writeError(w, http.StatusNotFound, errNoteNotFound)
+		return
+	}
+
+	force := r.URL.Query().Get("force") == "true"
+	lock, previous, err := s.presence.Acquire(note.ID, note.MeetingID, user, force)
+	switch {
+	case errors.Is(err, presence.ErrLocked):
+		writeJSON(w, http.StatusConflict, lockConflictResponse{Error: err.Error(), Lock: lock})
+		return
+	case errors.Is(err, presence.ErrNotPeer):
+		writeJSON(w, http.StatusForbidden, lockConflictResponse{Error: err.Error(), Lock: lock})
+		return
+	}
+
+	// renewals are not announced
+	if previous == nil || previous.Holder.Login != user.Login {
+		s.publish(r, events.NoteLocked, note.MeetingID, lock)
+	}
+	writeJSON(w, http.StatusOK, lock)
+}
+
+// handleUnlockNote handles DELETE /api/notes/{id}/lock
+func (s *Server) handleUnlockNote(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidNoteID)
+		return
+	}
+	user, ok := s.presenceUser(w, r)
+	if !ok {
+		return
+	}
+
+	lock, _ := s.presence.Lock(int(id))
+	released, err := s.presence.Release(int(id), user.Login)
+	if errors.Is(err, presence.ErrNotHolder) {
+		writeJSON(w, http.StatusConflict, lockConflictResponse{Error: err.Error(), Lock: lock})
+		return
+	}
+	if released {
+		s.publish(r, events.NoteUnlocked, lock.MeetingID, deletedEntity{ID: int(id)})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkNoteLock enforces the lock on a note about to be written. A lock
+// held by another user fails the request with 423 in strict mode, and
+// otherwise adds a Warning header. It returns false if it wrote the response.
+func (s *Server) checkNoteLock(w http.ResponseWriter, r *http.Request, note *models.Note) bool {
+	lock, ok := s.presence.Lock(note.ID)
+	if !ok {
+		return true
+	}
+	if user, err := s.currentUser(r); err == nil && user.LoginName == lock.Holder.Login {
+		return true
+	}
+
+	msg := "note is locked by " + lock.Holder.Name
+	if s.strictNoteLocks {
+		writeJSON(w, http.StatusLocked, lockConflictResponse{Error: msg, Lock: lock})
+		return false
+	}
+	w.Header().Set("Warning", fmt.Sprintf("299 notebook %q", msg))
+	return true
+}
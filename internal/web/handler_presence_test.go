@@ -0,0 +1,140 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/presence"
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+var (
+	presenceAlice = &tsapp.UserInfo{LoginName: "alice@example.com", DisplayName: "Alice"}
+	presenceBob   = &tsapp.UserInfo{LoginName: "bob@example.com", DisplayName: "Bob"}
+)
+
+// serveAs serves a request made by user
+func serveAs(t *testing.T, handler http.Handler, user *tsapp.UserInfo, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(context.Background(), method, path, strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey{}, user))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestPresence_JoinAndLeave(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	path := "/api/meetings/" + strconv.Itoa(meeting.ID) + "/presence"
+
+	if w := serveAs(t, handler, presenceBob, http.MethodPut, path, `{"state": "viewing"}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT presence: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w := serveAs(t, handler, presenceAlice, http.MethodPut, path, `{"state": "editing", "note_id": 3}`)
+	var resp presenceResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode presence: %v", err)
+	}
+	if len(resp.Users) != 2 || resp.Users[0].Login != presenceAlice.LoginName || resp.Users[0].State != presence.StateEditing ||
+		resp.Users[0].NoteID == nil || *resp.Users[0].NoteID != 3 {
+		t.Fatalf("unexpected presence %+v", resp.Users)
+	}
+
+	if w := serveAs(t, handler, presenceBob, http.MethodDelete, path, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE presence: expected 204, got %d", w.Code)
+	}
+	serveJSON(t, handler, http.MethodGet, path, "", http.StatusOK, &resp)
+	if len(resp.Users) != 1 || resp.Users[0].Login != presenceAlice.LoginName {
+		t.Errorf("expected only Alice after Bob left, got %+v", resp.Users)
+	}
+
+	if w := serveAs(t, handler, presenceBob, http.MethodPut, path, `{"state": "typing"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid state: expected 400, got %d", w.Code)
+	}
+	if w := serveAs(t, handler, presenceBob, http.MethodPut, "/api/meetings/999/presence", `{}`); w.Code != http.StatusNotFound {
+		t.Errorf("unknown meeting: expected 404, got %d", w.Code)
+	}
+}
+
+func TestNoteLocks(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	var note models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(meeting.ID)+`, "content": "draft"}`, http.StatusCreated, &note)
+	notePath := "/api/notes/" + strconv.Itoa(note.ID)
+	lockPath := notePath + "/lock"
+	presencePath := "/api/meetings/" + strconv.Itoa(meeting.ID) + "/presence"
+
+	w := serveAs(t, handler, presenceAlice, http.MethodPost, lockPath, "")
+	var lock presence.Lock
+	if err := json.NewDecoder(w.Body).Decode(&lock); err != nil || w.Code != http.StatusOK || lock.Holder.Login != presenceAlice.LoginName {
+		t.Fatalf("lock by Alice: status %d, lock %+v, err %v", w.Code, lock, err)
+	}
+	if w := serveAs(t, handler, presenceBob, http.MethodPost, lockPath, ""); w.Code != http.StatusConflict {
+		t.Fatalf("lock by Bob: expected 409, got %d", w.Code)
+	}
+
+	// Advisory by default: the write goes through with a warning
+	w = serveAs(t, handler, presenceBob, http.MethodPut, notePath, `{"content": "bob"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Warning"), "locked by Alice") {
+		t.Fatalf("PUT by Bob: status %d, Warning %q", w.Code, w.Header().Get("Warning"))
+	}
+
+	srv.strictNoteLocks = true
+	if w := serveAs(t, handler, presenceBob, http.MethodPut, notePath, `{"content": "bob again"}`); w.Code != http.StatusLocked {
+		t.Errorf("strict PUT by Bob: expected 423, got %d", w.Code)
+	}
+	if w := serveAs(t, handler, presenceBob, http.MethodPatch, notePath, `{"content": "bob again"}`); w.Code != http.StatusLocked {
+		t.Errorf("strict PATCH by Bob: expected 423, got %d", w.Code)
+	}
+	if w := serveAs(t, handler, presenceBob, http.MethodPost, notePath+"/revisions/1/restore", ""); w.Code != http.StatusLocked {
+		t.Errorf("strict restore by Bob: expected 423, got %d", w.Code)
+	}
+	w = serveAs(t, handler, presenceAlice, http.MethodPut, notePath, `{"content": "alice"}`)
+	if w.Code != http.StatusOK || w.Header().Get("Warning") != "" {
+		t.Errorf("PUT by the holder: status %d, Warning %q", w.Code, w.Header().Get("Warning"))
+	}
+
+	// Only peers present in the meeting may take over
+	if w := serveAs(t, handler, presenceBob, http.MethodPost, lockPath+"?force=true", ""); w.Code != http.StatusForbidden {
+		t.Fatalf("takeover by an absent user: expected 403, got %d", w.Code)
+	}
+	serveAs(t, handler, presenceBob, http.MethodPut, presencePath, `{"state": "editing", "note_id": `+strconv.Itoa(note.ID)+`}`)
+	w = serveAs(t, handler, presenceBob, http.MethodPost, lockPath+"?force=true", "")
+	if err := json.NewDecoder(w.Body).Decode(&lock); err != nil || w.Code != http.StatusOK || lock.Holder.Login != presenceBob.LoginName {
+		t.Fatalf("takeover by Bob: status %d, lock %+v, err %v", w.Code, lock, err)
+	}
+
+	if w := serveAs(t, handler, presenceAlice, http.MethodDelete, lockPath, ""); w.Code != http.StatusConflict {
+		t.Errorf("unlock by Alice: expected 409, got %d", w.Code)
+	}
+	if w := serveAs(t, handler, presenceBob, http.MethodDelete, lockPath, ""); w.Code != http.StatusNoContent {
+		t.Errorf("unlock by Bob: expected 204, got %d", w.Code)
+	}
+	var resp presenceResponse
+	serveJSON(t, handler, http.MethodGet, presencePath, "", http.StatusOK, &resp)
+	if len(resp.Locks) != 0 {
+		t.Errorf("expected no locks after release, got %+v", resp.Locks)
+	}
+
+	if w := serveAs(t, handler, presenceBob, http.MethodPost, "/api/notes/999/lock", ""); w.Code != http.StatusNotFound {
+		t.Errorf("lock of unknown note: expected 404, got %d", w.Code)
+	}
+}
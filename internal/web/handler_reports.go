@@ -0,0 +1,150 @@
+package web
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+const (
+	contentTypeCSV      = "text/csv; charset=utf-8"
+	defaultReportMonths = 3
+)
+
+// reportResponse is the JSON form of GET /api/reports
+type reportResponse struct {
+	From         string                    `json:"from"`
+	To           string                    `json:"to"`
+	Group        string                    `json:"group"`
+	Totals       *models.ReportPeriod      `json:"totals"`
+	Periods      []*models.ReportPeriod    `json:"periods"`
+	Keywords     []*models.ReportBreakdown `json:"keywords"`
+	Participants []*models.ReportBreakdown `json:"participants"`
+}
+
+// handleReports handles GET /api/reports?from=&to=&group=week|month&format=json|csv.
+// The range defaults to the last three months up to today.
+func (s *Server) handleReports(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	from, to, err := reportRange(q.Get("from"), q.Get("to"), time.Now().In(s.timeLocation()))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	group := q.Get("group")
+	if group == "" {
+		group = repositories.ReportGroupWeek
+	}
+	if !repositories.IsValidReportGroup(group) {
+		writeError(w, http.StatusBadRequest, "invalid group, expected week or month")
+		return
+	}
+
+	format := q.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, http.StatusBadRequest, "invalid format, expected json or csv")
+		return
+	}
+
+	report, err := s.buildReport(r.Context(), from, to, group)
+	if err != nil {
+		s.logError(r, "failed to build report", err)
+		writeError(w, http.StatusInternalServerError, "failed to build report")
+		return
+	}
+
+	if format == "csv" {
+		writeReportCSV(w, report)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// reportRange validates the from/to dates, defaulting to the months before now
+func reportRange(from, to string, now time.Time) (string, string, error) {
+	if to == "" {
+		to = now.Format(dateFormat)
+	}
+	toDate, err := time.Parse(dateFormat, to)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+	}
+
+	if from == "" {
+		from = toDate.AddDate(0, -defaultReportMonths, 0).Format(dateFormat)
+	}
+	fromDate, err := time.Parse(dateFormat, from)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+	}
+
+	if fromDate.After(toDate) {
+		return "", "", fmt.Errorf("from must not be after to")
+	}
+	return from, to, nil
+}
+
+// buildReport runs the report aggregations for the range
+func (s *Server) buildReport(ctx context.Context, from, to, group string) (*reportResponse, error) {
+	repo := repositories.NewReportRepository(s.database.DB).WithContext(ctx)
+	report := &reportResponse{From: from, To: to, Group: group}
+
+	var err error
+	if report.Totals, err = repo.Totals(from, to); err != nil {
+		return nil, err
+	}
+	if report.Periods, err = repo.Periods(from, to, group); err != nil {
+		return nil, err
+	}
+	if report.Keywords, err = repo.Keywords(from, to, group); err != nil {
+		return nil, err
+	}
+	if report.Participants, err = repo.Participants(from, to, group); err != nil {
+		return nil, err
+	}
+
+	// Coerce nil to empty slices for JSON response
+	if report.Periods == nil {
+		report.Periods = []*models.ReportPeriod{}
+	}
+	if report.Keywords == nil {
+		report.Keywords = []*models.ReportBreakdown{}
+	}
+	if report.Participants == nil {
+		report.Participants = []*models.ReportBreakdown{}
+	}
+
+	return report, nil
+}
+
+// writeReportCSV writes the report as one flat table: a row per period
+// ("period"), then per period and keyword ("keyword") or participant ("participant")
+func writeReportCSV(w http.ResponseWriter, report *reportResponse) {
+	w.Header().Set("Content-Type", contentTypeCSV)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="notebook-report-%s-%s.csv"`, report.From, report.To))
+	w.WriteHeader(http.StatusOK)
+
+	decimal := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"dimension", "period", "name", "meetings", "hours", "notes", "note_chars", "avg_notes_per_meeting", "summary_share"})
+	for _, p := range report.Periods {
+		_ = cw.Write([]string{"period", p.Period, "", strconv.Itoa(p.Meetings), decimal(p.Hours), strconv.Itoa(p.Notes),
+			strconv.Itoa(p.NoteChars), decimal(p.AvgNotesPerMeeting), decimal(p.SummaryShare)})
+	}
+	for _, b := range report.Keywords {
+		_ = cw.Write([]string{"keyword", b.Period, b.Name, strconv.Itoa(b.Meetings), decimal(b.Hours), "", "", "", ""})
+	}
+	for _, b := range report.Participants {
+		_ = cw.Write([]string{"participant", b.Period, b.Name, strconv.Itoa(b.Meetings), decimal(b.Hours), "", "", "", ""})
+	}
+	cw.Flush()
+}
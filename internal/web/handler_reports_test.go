@@ -0,0 +1,122 @@
+package web
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+func getReport(t *testing.T, srv *Server, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+	w := httptest.NewRecorder()
+
+	srv.handleReports(w, req)
+	return w
+}
+
+func seedReportServer(t *testing.T) *Server {
+	t.Helper()
+
+	srv := newTestServer(t)
+	t.Cleanup(func() { _ = srv.database.Close() })
+
+	end := "10:00"
+	keywords := "Planning"
+	repo := repositories.NewMeetingRepository(srv.database.DB)
+	for _, date := range []string{"2026-03-02", "2026-03-10"} {
+		m := &models.Meeting{CreatedBy: "a@example.com", Subject: "Weekly", MeetingDate: date, StartTime: "09:00", EndTime: &end, Keywords: &keywords}
+		if err := repo.Create(m); err != nil {
+			t.Fatalf("failed to create meeting: %v", err)
+		}
+	}
+	return srv
+}
+
+func TestHandleReports_JSON(t *testing.T) {
+	srv := seedReportServer(t)
+
+	w := getReport(t, srv, "/api/reports?from=2026-03-01&to=2026-03-31&group=week")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var report reportResponse
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.Totals.Meetings != 2 || report.Totals.Hours != 2 {
+		t.Errorf("unexpected totals %+v", report.Totals)
+	}
+	if len(report.Periods) != 2 || report.Periods[1].Period != "2026-03-09" {
+		t.Errorf("expected two weekly periods, got %+v", report.Periods)
+	}
+	if len(report.Keywords) != 2 || report.Keywords[0].Name != "planning" {
+		t.Errorf("unexpected keyword breakdown %+v", report.Keywords)
+	}
+	if report.Participants == nil {
+		t.Error("expected empty participants slice, got nil")
+	}
+}
+
+func TestHandleReports_CSV(t *testing.T) {
+	srv := seedReportServer(t)
+
+	w := getReport(t, srv, "/api/reports?from=2026-03-01&to=2026-03-31&group=month&format=csv")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != contentTypeCSV {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header, one period and one keyword row, got %v", records)
+	}
+	if records[1][0] != "period" || records[1][1] != "2026-03-01" || records[1][4] != "2.00" {
+		t.Errorf("unexpected period row %v", records[1])
+	}
+	if records[2][0] != "keyword" || records[2][2] != "planning" {
+		t.Errorf("unexpected keyword row %v", records[2])
+	}
+}
+
+func TestHandleReports_InvalidParams(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+
+	for _, target := range []string{
+		"/api/reports?from=03/01/2026",
+		"/api/reports?from=2026-04-01&to=2026-03-01",
+		"/api/reports?group=day",
+		"/api/reports?format=xml",
+	} {
+		if w := getReport(t, srv, target); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", target, w.Code)
+		}
+	}
+}
+
+func TestReportRange_Defaults(t *testing.T) {
+	now := time.Date(2026, 5, 15, 12, 0, 0, 0, time.UTC)
+
+	from, to, err := reportRange("", "", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from != "2026-02-15" || to != "2026-05-15" {
+		t.Errorf("expected last three months, got %s..%s", from, to)
+	}
+}
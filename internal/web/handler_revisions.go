@@ -0,0 +1,166 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/textdiff"
+)
+
+// diffContextLines is the context around each change of a revision diff
+const diffContextLines = 3
+
+// revisionDiff is the response of the note revision and summary version diffs
+type revisionDiff struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+	// Diff is a unified diff, empty if both revisions are equal
+	Diff string `json:"diff"`
+}
+
+// handleListNoteRevisions handles GET /api/notes/{id}/revisions
+func (s *Server) handleListNoteRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidNoteID)
+		return
+	}
+
+	repo := s.noteRepository(r.Context())
+	note, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to get note", err)
+		writeError(w, http.StatusInternalServerError, "failed to get note")
+		return
+	}
+	if note == nil {
+		writeError(w, http.StatusNotFound, errNoteNotFound)
+		return
+	}
+
+	revisions, err := repo.ListRevisions(note.ID)
+	if err != nil {
+		s.logError(r, "failed to list note revisions", err)
+		writeError(w, http.StatusInternalServerError, "failed to list note revisions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, revisions)
+}
+
+// handleDiffNoteRevisions handles GET /api/notes/{id}/revisions/diff?from=&to=.
+// to defaults to the latest revision.
+func (s *Server) handleDiffNoteRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidNoteID)
+		return
+	}
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid from revision")
+		return
+	}
+
+	repo := s.noteRepository(r.Context())
+	revisions, err := repo.ListRevisions(int(id))
+	if err != nil {
+		s.logError(r, "failed to list note revisions", err)
+		writeError(w, http.StatusInternalServerError, "failed to list note revisions")
+		return
+	}
+	if len(revisions) == 0 {
+		writeError(w, http.StatusNotFound, errNoteNotFound)
+		return
+	}
+
+	to := revisions[0].Revision
+	if q := r.URL.Query().Get("to"); q != "" {
+		if to, err = strconv.Atoi(q); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid to revision")
+			return
+		}
+	}
+	old, updated := findRevision(revisions, from), findRevision(revisions, to)
+	if old == nil || updated == nil {
+		writeError(w, http.StatusNotFound, "revision not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, revisionDiff{
+		From: from,
+		To:   to,
+		Diff: textdiff.Unified(fmt.Sprintf("revision %d", from), fmt.Sprintf("revision %d", to), old.Content, updated.Content, diffContextLines),
+	})
+}
+
+// findRevision returns the revision numbered n, or nil
+func findRevision(revisions []*models.NoteRevision, n int) *models.NoteRevision {
+	for _, rev := range revisions {
+		if rev.Revision == n {
+			return rev
+		}
+	}
+	return nil
+}
+
+// handleRestoreNoteRevision handles POST /api/notes/{id}/revisions/{revision}/restore.
+// The old content becomes a new revision; later revisions are kept. Like an
+// update, it honors If-Match and the edit lock of the note.
+func (s *Server) handleRestoreNoteRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidNoteID)
+		return
+	}
+	revision, err := strconv.Atoi(r.PathValue("revision"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid revision")
+		return
+	}
+
+	repo := s.noteRepository(r.Context())
+	existing := s.noteForWrite(w, r, repo, int(id))
+	if existing == nil || !s.checkNoteLock(w, r, existing) {
+		return
+	}
+
+	rev, err := repo.GetRevision(int(id), revision)
+	if err != nil {
+		s.logError(r, "failed to get note revision", err)
+		writeError(w, http.StatusInternalServerError, "failed to get note revision")
+		return
+	}
+	if rev == nil {
+		writeError(w, http.StatusNotFound, "revision not found")
+		return
+	}
+
+	note := *existing
+	note.Content = rev.Content
+	err = repo.IfVersion(ifMatchVersion(r, existing.Version)).Update(&note)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		s.writeNoteChanged(w, r, repo, int(id))
+		return
+	}
+	if err != nil {
+		s.logError(r, "failed to restore note revision", err)
+		writeError(w, http.StatusInternalServerError, "failed to restore note revision")
+		return
+	}
+
+	restored, err := repo.GetByID(rev.NoteID)
+	if err != nil {
+		s.logError(r, "failed to fetch restored note", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch restored note")
+		return
+	}
+
+	s.publish(r, events.NoteUpdated, restored.MeetingID, restored)
+	writeTaggedJSON(w, http.StatusOK, entityTag(restored.ID, restored.Version), restored)
+}
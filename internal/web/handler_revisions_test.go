@@ -0,0 +1,105 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+// serveJSON sends a request to handler and decodes a JSON response into v
+func serveJSON(t *testing.T, handler http.Handler, method, path, body string, status int, v any) {
+	t.Helper()
+	req := httptest.NewRequestWithContext(context.Background(), method, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != status {
+		t.Fatalf("%s %s: expected status %d, got %d: %s", method, path, status, w.Code, w.Body.String())
+	}
+	if v != nil {
+		if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: failed to decode response: %v", method, path, err)
+		}
+	}
+}
+
+func TestNoteRevisions_ListDiffRestore(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	var note models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(meeting.ID)+`, "content": "one\ntwo\n"}`, http.StatusCreated, &note)
+	notePath := "/api/notes/" + strconv.Itoa(note.ID)
+	serveJSON(t, handler, http.MethodPut, notePath, `{"content": "one\n2\n", "source": "llm-enhance"}`, http.StatusOK, nil)
+
+	var revisions []*models.NoteRevision
+	serveJSON(t, handler, http.MethodGet, notePath+"/revisions", "", http.StatusOK, &revisions)
+	if len(revisions) != 2 || revisions[0].Source != models.RevisionSourceLLMEnhance || revisions[1].Source != models.RevisionSourceManual ||
+		revisions[0].Author != devModeCreatedBy {
+		t.Fatalf("unexpected revisions %+v", revisions)
+	}
+
+	var diff revisionDiff
+	serveJSON(t, handler, http.MethodGet, notePath+"/revisions/diff?from=1", "", http.StatusOK, &diff)
+	if diff.To != 2 || !strings.Contains(diff.Diff, "-two\n+2\n") {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+
+	var restored models.Note
+	serveJSON(t, handler, http.MethodPost, notePath+"/revisions/1/restore", "", http.StatusOK, &restored)
+	if restored.Content != "one\ntwo\n" {
+		t.Errorf("restored content = %q", restored.Content)
+	}
+	serveJSON(t, handler, http.MethodGet, notePath+"/revisions", "", http.StatusOK, &revisions)
+	if len(revisions) != 3 || revisions[0].Revision != 3 || revisions[0].Content != "one\ntwo\n" {
+		t.Errorf("expected restore to add revision 3, got %+v", revisions)
+	}
+
+	w := serveConditional(t, handler, http.MethodPost, notePath+"/revisions/2/restore", "", map[string]string{"If-Match": entityTag(note.ID, note.Version)})
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("restore with a stale If-Match: expected 412, got %d", w.Code)
+	}
+}
+
+func TestNoteRevisions_Errors(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	var note models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(meeting.ID)+`, "content": "text"}`, http.StatusCreated, &note)
+	notePath := "/api/notes/" + strconv.Itoa(note.ID)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "unknown source", method: http.MethodPut, path: notePath, body: `{"content": "x", "source": "import"}`, status: http.StatusBadRequest},
+		{name: "list unknown note", method: http.MethodGet, path: "/api/notes/999/revisions", status: http.StatusNotFound},
+		{name: "diff without from", method: http.MethodGet, path: notePath + "/revisions/diff", status: http.StatusBadRequest},
+		{name: "diff unknown revision", method: http.MethodGet, path: notePath + "/revisions/diff?from=1&to=9", status: http.StatusNotFound},
+		{name: "restore unknown revision", method: http.MethodPost, path: notePath + "/revisions/9/restore", status: http.StatusNotFound},
+		{name: "restore invalid revision", method: http.MethodPost, path: notePath + "/revisions/x/restore", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveJSON(t, handler, tt.method, tt.path, tt.body, tt.status, nil)
+		})
+	}
+}
@@ -18,7 +18,7 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	repo := repositories.NewMeetingRepository(s.database.DB)
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
 	meetings, err := repo.Search(query)
 	if err != nil {
 		s.logError(r, "failed to search meetings", err)
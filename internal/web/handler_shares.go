@@ -0,0 +1,332 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+const (
+	defaultShareTTL = 7 * 24 * time.Hour
+	maxShareTTL     = 30 * 24 * time.Hour
+	// sharePathPrefix is the public path of share pages, followed by the token
+	sharePathPrefix = "/s/"
+)
+
+// ShareCreateRequest represents the request to create a public share
+type ShareCreateRequest struct {
+	// ExpiresInHours defaults to 168 (7 days) and may not exceed 720 (30 days)
+	ExpiresInHours int  `json:"expires_in_hours"`
+	IncludeNotes   bool `json:"include_notes"`
+}
+
+// ShareCreateResponse carries the token, which is only shown once
+type ShareCreateResponse struct {
+	*models.MeetingShare
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// shareTTL validates the requested lifetime of a share
+func shareTTL(hours int) (time.Duration, error) {
+	if hours == 0 {
+		return defaultShareTTL, nil
+	}
+	ttl := time.Duration(hours) * time.Hour
+	if hours < 0 || ttl > maxShareTTL {
+		return 0, fmt.Errorf("expires_in_hours must be between 1 and %d", int(maxShareTTL.Hours()))
+	}
+	return ttl, nil
+}
+
+// actorName returns the login name recorded in audit trails
+func (s *Server) actorName(r *http.Request) string {
+	user, err := s.currentUser(r)
+	if err != nil {
+		return "unknown"
+	}
+	return user.LoginName
+}
+
+// handleCreateShare handles POST /api/meetings/{id}/shares
+func (s *Server) handleCreateShare(w http.ResponseWriter, r *http.Request) {
+	if s.shareBaseURL == "" {
+		writeError(w, http.StatusForbidden, "public sharing is not enabled")
+		return
+	}
+
+	meetingID, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid meeting ID")
+		return
+	}
+
+	var req ShareCreateRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	ttl, err := shareTTL(req.ExpiresInHours)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	meeting, err := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context()).GetByID(int(meetingID))
+	if err != nil {
+		s.logError(r, "failed to get meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to get meeting")
+		return
+	}
+	if meeting == nil {
+		writeError(w, http.StatusNotFound, "meeting not found")
+		return
+	}
+
+	token, err := repositories.NewShareToken()
+	if err != nil {
+		s.logError(r, "failed to generate share token", err)
+		writeError(w, http.StatusInternalServerError, "failed to create share")
+		return
+	}
+
+	share := &models.MeetingShare{
+		MeetingID:    meeting.ID,
+		IncludeNotes: req.IncludeNotes,
+		CreatedBy:    s.actorName(r),
+		ExpiresAt:    time.Now().Add(ttl),
+	}
+	repo := repositories.NewShareRepository(s.database.DB).WithContext(r.Context())
+	if err := repo.Create(share, token); err != nil {
+		s.logError(r, "failed to create share", err)
+		writeError(w, http.StatusInternalServerError, "failed to create share")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, ShareCreateResponse{
+		MeetingShare: share,
+		Token:        token,
+		URL:          strings.TrimSuffix(s.shareBaseURL, "/") + sharePathPrefix + token,
+	})
+}
+
+// handleListShares handles GET /api/meetings/{id}/shares
+func (s *Server) handleListShares(w http.ResponseWriter, r *http.Request) {
+	meetingID, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid meeting ID")
+		return
+	}
+
+	repo := repositories.NewShareRepository(s.database.DB).WithContext(r.Context())
+	shares, err := repo.ListByMeeting(int(meetingID))
+	if err != nil {
+		s.logError(r, "failed to list shares", err)
+		writeError(w, http.StatusInternalServerError, "failed to list shares")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, shares)
+}
+
+// loadShare loads the share named by the id path parameter, writing an
+// error response and returning nil if that fails
+func (s *Server) loadShare(w http.ResponseWriter, r *http.Request, repo *repositories.ShareRepository) *models.MeetingShare {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid share ID")
+		return nil
+	}
+
+	share, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to get share", err)
+		writeError(w, http.StatusInternalServerError, "failed to get share")
+		return nil
+	}
+	if share == nil {
+		writeError(w, http.StatusNotFound, "share not found")
+		return nil
+	}
+	return share
+}
+
+// handleRevokeShare handles DELETE /api/shares/{id}
+func (s *Server) handleRevokeShare(w http.ResponseWriter, r *http.Request) {
+	repo := repositories.NewShareRepository(s.database.DB).WithContext(r.Context())
+	share := s.loadShare(w, r, repo)
+	if share == nil {
+		return
+	}
+
+	if err := repo.Revoke(share, s.actorName(r)); err != nil {
+		s.logError(r, "failed to revoke share", err)
+		writeError(w, http.StatusInternalServerError, "failed to revoke share")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListShareEvents handles GET /api/shares/{id}/events
+func (s *Server) handleListShareEvents(w http.ResponseWriter, r *http.Request) {
+	repo := repositories.NewShareRepository(s.database.DB).WithContext(r.Context())
+	share := s.loadShare(w, r, repo)
+	if share == nil {
+		return
+	}
+
+	events, err := repo.ListEvents(share.ID)
+	if err != nil {
+		s.logError(r, "failed to list share events", err)
+		writeError(w, http.StatusInternalServerError, "failed to list share events")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, events)
+}
+
+// PublicHandler returns the handler for the public listener. It serves
+// share pages only; the API and the web UI are never reachable through it.
+func (s *Server) PublicHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+sharePathPrefix+"{token}", s.handlePublicShare)
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+
+	// No loggingMiddleware: the request path contains the share token
+	var handler http.Handler = mux
+	handler = s.metricsMiddleware(handler)
+	handler = publicHeadersMiddleware(handler)
+	return s.requestIDMiddleware(handler)
+}
+
+// publicHeadersMiddleware keeps share pages out of caches, search engines
+// and Referer headers, and forbids scripts
+func publicHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Cache-Control", "no-store")
+		h.Set("Referrer-Policy", "no-referrer")
+		h.Set("X-Robots-Tag", "noindex, nofollow")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sharePage is the data rendered by shareTemplate
+type sharePage struct {
+	Meeting *models.Meeting
+	Notes   []*models.Note
+}
+
+var shareTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>{{.Meeting.Subject}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #1f2937; }
+dt { font-weight: 600; margin-top: .5rem; }
+dd { margin: 0; white-space: pre-wrap; }
+li { white-space: pre-wrap; margin-bottom: .5rem; }
+footer { margin-top: 2rem; font-size: .875rem; color: #6b7280; }
+</style>
+</head>
+<body>
+<h1>{{.Meeting.Subject}}</h1>
+<dl>
+<dt>Date</dt>
+<dd>{{.Meeting.MeetingDate}} {{.Meeting.StartTime}}{{with .Meeting.EndTime}}–{{.}}{{end}} ({{.Meeting.Timezone}})</dd>
+{{with .Meeting.Participants}}<dt>Participants</dt>
+<dd>{{.}}</dd>
+{{end}}{{with .Meeting.Summary}}<dt>Summary</dt>
+<dd>{{.}}</dd>
+{{end}}</dl>
+{{if .Notes}}<h2>Notes</h2>
+<ol>
+{{range .Notes}}<li>{{.Content}}</li>
+{{end}}</ol>
+{{end}}<footer>Shared read-only from notebook.</footer>
+</body>
+</html>
+`))
+
+// handlePublicShare handles GET /s/{token} on the public listener. Unknown,
+// expired and revoked tokens, and shares of a meeting in the trash, all get
+// the same 404.
+func (s *Server) handlePublicShare(w http.ResponseWriter, r *http.Request) {
+	repo := repositories.NewShareRepository(s.database.DB).WithContext(r.Context())
+	share, err := repo.GetByToken(r.PathValue("token"))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get share", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if share == nil || !share.Active(time.Now()) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	page, err := s.loadSharePage(r, share)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to load shared meeting", "share_id", share.ID, "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if page == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	remoteAddr, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteAddr = r.RemoteAddr
+	}
+	if err := repo.RecordAccess(share, remoteAddr, r.UserAgent()); err != nil {
+		slog.ErrorContext(r.Context(), "failed to record share access", "share_id", share.ID, "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	slog.InfoContext(r.Context(), "share accessed", "share_id", share.ID, "meeting_id", share.MeetingID, "remote_addr", remoteAddr)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := shareTemplate.Execute(w, page); err != nil {
+		slog.ErrorContext(r.Context(), "failed to render share page", "share_id", share.ID, "error", err)
+	}
+}
+
+// loadSharePage loads the meeting of a share and, if included, its notes.
+// The page is nil while the meeting is in the trash.
+func (s *Server) loadSharePage(r *http.Request, share *models.MeetingShare) (*sharePage, error) {
+	meeting, err := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context()).GetByID(share.MeetingID)
+	if err != nil {
+		return nil, err
+	}
+	if meeting == nil {
+		//nolint:nilnil // Intentional: a trashed meeting is not an error
+		return nil, nil
+	}
+
+	page := &sharePage{Meeting: meeting}
+	if share.IncludeNotes {
+		page.Notes, err = repositories.NewNoteRepository(s.database.DB).WithContext(r.Context()).ListByMeeting(meeting.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
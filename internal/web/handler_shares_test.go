@@ -0,0 +1,222 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+)
+
+// createTestShare creates a share for meetingID through the API
+func createTestShare(t *testing.T, srv *Server, meetingID int, body string) ShareCreateResponse {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings/"+strconv.Itoa(meetingID)+"/shares", strings.NewReader(body))
+	req.SetPathValue("id", strconv.Itoa(meetingID))
+	w := httptest.NewRecorder()
+	srv.handleCreateShare(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ShareCreateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+// getPublic requests path from the public handler
+func getPublic(srv *Server, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	w := httptest.NewRecorder()
+	srv.PublicHandler().ServeHTTP(w, req)
+	return w
+}
+
+func TestHandleCreateShare_Disabled(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings/1/shares", strings.NewReader("{}"))
+	req.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	srv.handleCreateShare(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", w.Code)
+	}
+}
+
+func TestHandleCreateShare_Validation(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.shareBaseURL = "https://notebook.example.ts.net:8443"
+	meetingID := createTestMeeting(t, repositories.NewMeetingRepository(srv.database.DB))
+
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		status int
+	}{
+		{name: "invalid body", id: strconv.Itoa(meetingID), body: "{", status: http.StatusBadRequest},
+		{name: "negative expiry", id: strconv.Itoa(meetingID), body: `{"expires_in_hours": -1}`, status: http.StatusBadRequest},
+		{name: "expiry too long", id: strconv.Itoa(meetingID), body: `{"expires_in_hours": 721}`, status: http.StatusBadRequest},
+		{name: "unknown meeting", id: "9999", body: `{}`, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings/"+tt.id+"/shares", strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+			srv.handleCreateShare(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestShares_Lifecycle(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	srv.shareBaseURL = "https://notebook.example.ts.net:8443"
+
+	meetingRepo := repositories.NewMeetingRepository(srv.database.DB)
+	summary := "Agreed on <b>Q3</b> roadmap"
+	meeting := &models.Meeting{CreatedBy: "user@example.com", Subject: "Partner sync", MeetingDate: "2026-02-14", StartTime: "10:00", Summary: &summary}
+	if err := meetingRepo.Create(meeting); err != nil {
+		t.Fatalf("failed to create meeting: %v", err)
+	}
+	if err := repositories.NewNoteRepository(srv.database.DB).Create(&models.Note{MeetingID: meeting.ID, Content: "Internal note"}); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+
+	share := createTestShare(t, srv, meeting.ID, `{"expires_in_hours": 24}`)
+	if share.Token == "" || share.URL != srv.shareBaseURL+"/s/"+share.Token {
+		t.Fatalf("unexpected share %+v", share)
+	}
+	if share.CreatedBy != devModeCreatedBy {
+		t.Errorf("CreatedBy = %q, want the current user", share.CreatedBy)
+	}
+
+	// The public page shows the summary, escaped, but no notes
+	w := getPublic(srv, "/s/"+share.Token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	page := w.Body.String()
+	if !strings.Contains(page, "Partner sync") || !strings.Contains(page, "&lt;b&gt;Q3&lt;/b&gt;") {
+		t.Errorf("unexpected page %s", page)
+	}
+	if strings.Contains(page, "Internal note") {
+		t.Error("expected notes to be left out")
+	}
+	if w.Header().Get("Referrer-Policy") != "no-referrer" || w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("missing privacy headers: %v", w.Header())
+	}
+
+	// Revoke through the API
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodDelete, "/api/shares/"+strconv.Itoa(share.ID), nil)
+	req.SetPathValue("id", strconv.Itoa(share.ID))
+	w = httptest.NewRecorder()
+	srv.handleRevokeShare(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+
+	if w := getPublic(srv, "/s/"+share.Token); w.Code != http.StatusNotFound {
+		t.Errorf("expected revoked share to return 404, got %d", w.Code)
+	}
+
+	// Creation, access and revocation are audited
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/shares/"+strconv.Itoa(share.ID)+"/events", nil)
+	req.SetPathValue("id", strconv.Itoa(share.ID))
+	w = httptest.NewRecorder()
+	srv.handleListShareEvents(w, req)
+	var events []models.ShareEvent
+	if err := json.NewDecoder(w.Body).Decode(&events); err != nil {
+		t.Fatalf("failed to decode events: %v", err)
+	}
+	var got []string
+	for _, e := range events {
+		got = append(got, e.Event+":"+e.Actor)
+	}
+	want := "created:" + devModeCreatedBy + ",accessed:203.0.113.7,revoked:" + devModeCreatedBy
+	if strings.Join(got, ",") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+}
+
+func TestPublicHandler_IncludeNotes(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.shareBaseURL = "http://127.0.0.1:8443"
+
+	meetingID := createTestMeeting(t, repositories.NewMeetingRepository(srv.database.DB))
+	if err := repositories.NewNoteRepository(srv.database.DB).Create(&models.Note{MeetingID: meetingID, Content: "Shared note"}); err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+
+	share := createTestShare(t, srv, meetingID, `{"include_notes": true}`)
+	w := getPublic(srv, "/s/"+share.Token)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Shared note") {
+		t.Errorf("expected notes on the page, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestPublicHandler_TrashedMeeting(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.shareBaseURL = "http://127.0.0.1:8443"
+
+	meetingRepo := repositories.NewMeetingRepository(srv.database.DB)
+	meetingID := createTestMeeting(t, meetingRepo)
+	share := createTestShare(t, srv, meetingID, `{}`)
+
+	if err := meetingRepo.Delete(meetingID); err != nil {
+		t.Fatalf("failed to trash meeting: %v", err)
+	}
+	if w := getPublic(srv, "/s/"+share.Token); w.Code != http.StatusNotFound {
+		t.Errorf("trashed meeting: expected status 404, got %d", w.Code)
+	}
+
+	// Restoring the meeting brings the share back
+	if err := meetingRepo.Restore(meetingID); err != nil {
+		t.Fatalf("failed to restore meeting: %v", err)
+	}
+	if w := getPublic(srv, "/s/"+share.Token); w.Code != http.StatusOK {
+		t.Errorf("restored meeting: expected status 200, got %d", w.Code)
+	}
+}
+
+func TestPublicHandler_OnlyRoutesShares(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+
+	for _, path := range []string{"/", "/api/meetings", "/api/config", "/calendar.ics", "/caldav/", "/s/unknown-token", "/metrics"} {
+		if w := getPublic(srv, path); w.Code != http.StatusNotFound {
+			t.Errorf("GET %s: expected status 404, got %d", path, w.Code)
+		}
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/meetings", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+	srv.PublicHandler().ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("POST /api/meetings: expected status 404, got %d", w.Code)
+	}
+}
@@ -28,7 +28,6 @@ func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
 		_ = file.Close()
 		// File exists, serve it
 		http.FileServer(http.FS(distFS)).ServeHTTP(w, r)
-		s.logRequest(r.Method, r.URL.Path, http.StatusOK)
 		return
 	}
 
@@ -36,5 +35,4 @@ func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
 	// This allows React Router to handle the route
 	r.URL.Path = "/index.html"
 	http.FileServer(http.FS(distFS)).ServeHTTP(w, r)
-	s.logRequest(r.Method, path, http.StatusOK)
 }
@@ -0,0 +1,143 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/textdiff"
+)
+
+// handleListSummaries handles GET /api/meetings/{id}/summaries
+func (s *Server) handleListSummaries(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	meeting, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to get meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to get meeting")
+		return
+	}
+	if meeting == nil {
+		writeError(w, http.StatusNotFound, "meeting not found")
+		return
+	}
+
+	summaries, err := repo.ListSummaries(meeting.ID)
+	if err != nil {
+		s.logError(r, "failed to list summary versions", err)
+		writeError(w, http.StatusInternalServerError, "failed to list summary versions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// handleDiffSummaries handles GET /api/meetings/{id}/summaries/diff?from=&to=.
+// to defaults to the latest version.
+func (s *Server) handleDiffSummaries(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid from version")
+		return
+	}
+
+	summaries, err := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context()).ListSummaries(int(id))
+	if err != nil {
+		s.logError(r, "failed to list summary versions", err)
+		writeError(w, http.StatusInternalServerError, "failed to list summary versions")
+		return
+	}
+	if len(summaries) == 0 {
+		writeError(w, http.StatusNotFound, "summary version not found")
+		return
+	}
+
+	to := summaries[0].Version
+	if q := r.URL.Query().Get("to"); q != "" {
+		if to, err = strconv.Atoi(q); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid to version")
+			return
+		}
+	}
+	old, updated := findSummary(summaries, from), findSummary(summaries, to)
+	if old == nil || updated == nil {
+		writeError(w, http.StatusNotFound, "summary version not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, revisionDiff{
+		From: from,
+		To:   to,
+		Diff: textdiff.Unified(fmt.Sprintf("version %d", from), fmt.Sprintf("version %d", to), old.Summary, updated.Summary, diffContextLines),
+	})
+}
+
+// findSummary returns the summary version numbered n, or nil
+func findSummary(summaries []*models.MeetingSummary, n int) *models.MeetingSummary {
+	for _, summary := range summaries {
+		if summary.Version == n {
+			return summary
+		}
+	}
+	return nil
+}
+
+// handlePromoteSummary handles POST /api/meetings/{id}/summaries/{version}/promote.
+// The old summary becomes the meeting's summary again and is recorded as a
+// new version that keeps the provenance of the old one.
+func (s *Server) handlePromoteSummary(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	summary, err := repo.GetSummary(int(id), version)
+	if err != nil {
+		s.logError(r, "failed to get summary version", err)
+		writeError(w, http.StatusInternalServerError, "failed to get summary version")
+		return
+	}
+	if summary == nil {
+		writeError(w, http.StatusNotFound, "summary version not found")
+		return
+	}
+	meeting, err := repo.GetByID(summary.MeetingID)
+	if err != nil || meeting == nil {
+		s.logError(r, "failed to get meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to get meeting")
+		return
+	}
+
+	provenance := summary.SummaryProvenance
+	provenance.PromotedFrom = &summary.Version
+	meeting.Summary = &summary.Summary
+	if err := repo.WithSummaryProvenance(provenance).Update(meeting); err != nil {
+		s.logError(r, "failed to promote summary version", err)
+		writeError(w, http.StatusInternalServerError, "failed to promote summary version")
+		return
+	}
+
+	s.publish(r, events.MeetingUpdated, meeting.ID, meeting)
+	writeJSON(w, http.StatusOK, meeting)
+}
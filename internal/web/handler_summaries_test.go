@@ -0,0 +1,100 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+func TestMeetingSummaries_SummarizeDiffPromote(t *testing.T) {
+	llmServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model": "gpt-4o-2024-08-06", "choices": [{"message": {"content": "Agreed on the roadmap"}}],
+			"usage": {"prompt_tokens": 42, "completion_tokens": 7}}`))
+	}))
+	defer llmServer.Close()
+
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	configRepo := srv.configRepository(context.Background())
+	for key, value := range map[string]string{
+		configKeyLLMProviderURL:   llmServer.URL,
+		configKeyLLMAPIKey:        "sk-test-key",
+		configKeyLLMModel:         "gpt-4o",
+		configKeyLLMPromptSummary: "Summarize {{notes}}",
+	} {
+		if err := configRepo.Set(key, value); err != nil {
+			t.Fatalf("failed to set config %s: %v", key, err)
+		}
+	}
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings",
+		`{"subject": "Planning", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	serveJSON(t, handler, http.MethodPost, "/api/notes", fmt.Sprintf(`{"meeting_id": %d, "content": "Roadmap"}`, meeting.ID), http.StatusCreated, nil)
+	base := fmt.Sprintf("/api/meetings/%d", meeting.ID)
+
+	serveJSON(t, handler, http.MethodPost, base+"/summarize", "", http.StatusOK, nil)
+	serveJSON(t, handler, http.MethodPut, base,
+		`{"subject": "Planning", "meeting_date": "2026-03-01", "start_time": "09:00", "summary": "Roadmap postponed"}`, http.StatusOK, nil)
+
+	var summaries []*models.MeetingSummary
+	serveJSON(t, handler, http.MethodGet, base+"/summaries", "", http.StatusOK, &summaries)
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summary versions, got %d", len(summaries))
+	}
+	manual, generated := summaries[0], summaries[1]
+	if manual.Version != 2 || manual.Source != models.SummarySourceManual || manual.Model != nil || manual.Author != devModeCreatedBy {
+		t.Errorf("unexpected manual version %+v", manual)
+	}
+	if generated.Source != models.SummarySourceLLM || generated.Summary != "Agreed on the roadmap" ||
+		generated.Model == nil || *generated.Model != "gpt-4o-2024-08-06" ||
+		generated.InputTokens == nil || *generated.InputTokens != 42 || generated.OutputTokens == nil || *generated.OutputTokens != 7 ||
+		generated.PromptHash == nil || len(*generated.PromptHash) != 64 {
+		t.Errorf("unexpected generated version %+v", generated)
+	}
+
+	var diff revisionDiff
+	serveJSON(t, handler, http.MethodGet, base+"/summaries/diff?from=1", "", http.StatusOK, &diff)
+	if diff.To != 2 || !strings.Contains(diff.Diff, "-Agreed on the roadmap") || !strings.Contains(diff.Diff, "+Roadmap postponed") {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+
+	var promoted models.Meeting
+	serveJSON(t, handler, http.MethodPost, base+"/summaries/1/promote", "", http.StatusOK, &promoted)
+	if promoted.Summary == nil || *promoted.Summary != "Agreed on the roadmap" {
+		t.Errorf("unexpected promoted summary %v", promoted.Summary)
+	}
+	serveJSON(t, handler, http.MethodGet, base+"/summaries", "", http.StatusOK, &summaries)
+	latest := summaries[0]
+	if latest.Version != 3 || latest.Source != models.SummarySourceLLM || latest.PromotedFrom == nil || *latest.PromotedFrom != 1 ||
+		latest.Model == nil || *latest.Model != "gpt-4o-2024-08-06" {
+		t.Errorf("unexpected promoted version %+v", latest)
+	}
+}
+
+func TestMeetingSummaries_Errors(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings",
+		`{"subject": "Planning", "meeting_date": "2026-03-01", "start_time": "09:00", "summary": "First"}`, http.StatusCreated, &meeting)
+	base := fmt.Sprintf("/api/meetings/%d", meeting.ID)
+
+	serveJSON(t, handler, http.MethodGet, "/api/meetings/999/summaries", "", http.StatusNotFound, nil)
+	serveJSON(t, handler, http.MethodGet, base+"/summaries/diff", "", http.StatusBadRequest, nil)
+	serveJSON(t, handler, http.MethodGet, base+"/summaries/diff?from=1&to=5", "", http.StatusNotFound, nil)
+	serveJSON(t, handler, http.MethodPost, base+"/summaries/7/promote", "", http.StatusNotFound, nil)
+	serveJSON(t, handler, http.MethodPost, base+"/summaries/x/promote", "", http.StatusBadRequest, nil)
+}
@@ -0,0 +1,265 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+const (
+	defaultTokenTTLDays = 90
+	maxTokenTTLDays     = 365
+	maxTokenNameLength  = 100
+)
+
+// readMethods are the request methods a read-only token may use
+var readMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND", "REPORT"}
+
+// adminScopePaths are only reachable with admin tokens
+var adminScopePaths = []string{"/api/admin/", "/api/tokens/"}
+
+// notesWriteRoutes are the changes notes-write tokens may make: the note
+// endpoints and creating and updating meetings. Deleting, merging, splitting
+// and sharing meetings and purging the trash need an admin token.
+var notesWriteRoutes = newRouteSet(
+	"POST /api/meetings",
+	"POST /api/meetings/import",
+	"PUT /api/meetings/{id}",
+	"PATCH /api/meetings/{id}",
+	"POST /api/meetings/{id}/summarize",
+	"POST /api/meetings/{id}/summaries/{version}/promote",
+	"PUT /api/meetings/{id}/presence",
+	"DELETE /api/meetings/{id}/presence",
+	"PUT /api/meetings/{meetingId}/notes/order",
+	"POST /api/notes",
+	"/api/notes/",
+	"POST /api/trash/notes/{id}/restore",
+	"PUT /caldav/meetings/{name}",
+)
+
+// routeSet matches requests against http.ServeMux patterns
+type routeSet struct {
+	mux *http.ServeMux
+}
+
+// newRouteSet returns a routeSet of patterns
+func newRouteSet(patterns ...string) routeSet {
+	mux := http.NewServeMux()
+	for _, pattern := range patterns {
+		mux.Handle(pattern, http.NotFoundHandler())
+	}
+	return routeSet{mux: mux}
+}
+
+// matches reports whether one of the patterns matches r
+func (rs routeSet) matches(r *http.Request) bool {
+	_, pattern := rs.mux.Handler(r)
+	return pattern != ""
+}
+
+// APITokenCreateRequest represents the request to create an API token
+type APITokenCreateRequest struct {
+	Name string `json:"name"`
+	// Scope is read, notes-write or admin; defaults to read
+	Scope string `json:"scope"`
+	// ExpiresInDays defaults to 90 and may not exceed 365
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+// APITokenCreateResponse carries the token, which is only shown once
+type APITokenCreateResponse struct {
+	*models.APIToken
+	Token string `json:"token"`
+}
+
+// apiTokenContextKey stores the API token a request authenticated with
+type apiTokenContextKey struct{}
+
+// bearerToken returns the credentials of an "Authorization: Bearer" header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, credentials, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(credentials), true
+}
+
+// tokenAllows reports whether a token with scope may make the request
+func tokenAllows(scope string, r *http.Request) bool {
+	if scope == models.TokenScopeAdmin {
+		return true
+	}
+	if hasPathPrefix(r.URL.Path, adminScopePaths) {
+		return false
+	}
+	if slices.Contains(readMethods, r.Method) {
+		return true
+	}
+	return scope == models.TokenScopeNotesWrite && notesWriteRoutes.matches(r)
+}
+
+// authenticateToken resolves the owner of an API token. It writes 401 for
+// unknown, expired or revoked tokens and 403 if the scope does not cover
+// the request, and returns nil then.
+func (s *Server) authenticateToken(w http.ResponseWriter, r *http.Request, secret string) (*tsapp.UserInfo, *models.APIToken) {
+	repo := repositories.NewAPITokenRepository(s.database.DB).WithContext(r.Context())
+	token, err := repo.GetBySecret(secret)
+	if err != nil {
+		s.logError(r, "failed to get api token", err)
+		writeError(w, http.StatusInternalServerError, "failed to authenticate")
+		return nil, nil
+	}
+	now := time.Now()
+	if token == nil || !token.Active(now) {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		writeError(w, http.StatusUnauthorized, "invalid, expired or revoked API token")
+		return nil, nil
+	}
+	if !tokenAllows(token.Scope, r) {
+		slog.WarnContext(r.Context(), "api token scope denied request", "token_id", token.ID, "scope", token.Scope, "method", r.Method, "path", r.URL.Path)
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		writeError(w, http.StatusForbidden, "API token scope "+token.Scope+" does not allow this request")
+		return nil, nil
+	}
+
+	if err := repo.Touch(token, now); err != nil {
+		s.logError(r, "failed to record api token use", err)
+	}
+	return &tsapp.UserInfo{
+		DisplayName: token.Owner,
+		LoginName:   token.Owner,
+		NodeName:    token.Name,
+		NodeID:      "api-token-" + strconv.Itoa(token.ID),
+	}, token
+}
+
+// tokenTTL validates the requested lifetime of a token
+func tokenTTL(days int) (time.Duration, error) {
+	if days == 0 {
+		days = defaultTokenTTLDays
+	}
+	if days < 0 || days > maxTokenTTLDays {
+		return 0, fmt.Errorf("expires_in_days must be between 1 and %d", maxTokenTTLDays)
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// handleListTokens handles GET /api/tokens
+func (s *Server) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
+		return
+	}
+
+	tokens, err := repositories.NewAPITokenRepository(s.database.DB).WithContext(r.Context()).ListByOwner(user.LoginName)
+	if err != nil {
+		s.logError(r, "failed to list api tokens", err)
+		writeError(w, http.StatusInternalServerError, "failed to list API tokens")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+// handleCreateToken handles POST /api/tokens
+func (s *Server) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
+		return
+	}
+
+	var req APITokenCreateRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxTokenNameLength {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("name is required and may have at most %d characters", maxTokenNameLength))
+		return
+	}
+	if req.Scope == "" {
+		req.Scope = models.TokenScopeRead
+	}
+	if !slices.Contains(models.TokenScopes, req.Scope) {
+		writeError(w, http.StatusBadRequest, "scope must be one of "+strings.Join(models.TokenScopes, ", "))
+		return
+	}
+	if req.Scope == models.TokenScopeAdmin && !s.isAdmin(user) {
+		writeError(w, http.StatusForbidden, "only admins can create admin tokens")
+		return
+	}
+	ttl, err := tokenTTL(req.ExpiresInDays)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	secret, err := repositories.NewAPIToken()
+	if err != nil {
+		s.logError(r, "failed to generate api token", err)
+		writeError(w, http.StatusInternalServerError, "failed to create API token")
+		return
+	}
+	token := &models.APIToken{
+		Name:      req.Name,
+		Owner:     user.LoginName,
+		Scope:     req.Scope,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := repositories.NewAPITokenRepository(s.database.DB).WithContext(r.Context()).Create(token, secret); err != nil {
+		s.logError(r, "failed to create api token", err)
+		writeError(w, http.StatusInternalServerError, "failed to create API token")
+		return
+	}
+	slog.InfoContext(r.Context(), "api token created", "token_id", token.ID, "scope", token.Scope)
+
+	writeJSON(w, http.StatusCreated, APITokenCreateResponse{APIToken: token, Token: secret})
+}
+
+// handleRevokeToken handles DELETE /api/tokens/{id}. Users revoke their own
+// tokens; admins may revoke anyone's.
+func (s *Server) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	user, err := s.currentUser(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "failed to authenticate user")
+		return
+	}
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid token ID")
+		return
+	}
+
+	repo := repositories.NewAPITokenRepository(s.database.DB).WithContext(r.Context())
+	token, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to get api token", err)
+		writeError(w, http.StatusInternalServerError, "failed to get API token")
+		return
+	}
+	if token == nil || (token.Owner != user.LoginName && !s.isAdmin(user)) {
+		writeError(w, http.StatusNotFound, "API token not found")
+		return
+	}
+
+	if err := repo.Revoke(token); err != nil {
+		s.logError(r, "failed to revoke api token", err)
+		writeError(w, http.StatusInternalServerError, "failed to revoke API token")
+		return
+	}
+	slog.InfoContext(r.Context(), "api token revoked", "token_id", token.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,226 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/tsapp"
+)
+
+// createTestToken creates an API token as the dev user through the API
+func createTestToken(t *testing.T, handler http.Handler, body string) APITokenCreateResponse {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/tokens", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp APITokenCreateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+// bearerRequest sends a request with an API token
+func bearerRequest(handler http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(context.Background(), method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandleCreateToken_Validation(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "invalid body", body: "{", status: http.StatusBadRequest},
+		{name: "missing name", body: `{"scope": "read"}`, status: http.StatusBadRequest},
+		{name: "long name", body: `{"name": "` + strings.Repeat("x", 101) + `"}`, status: http.StatusBadRequest},
+		{name: "unknown scope", body: `{"name": "x", "scope": "root"}`, status: http.StatusBadRequest},
+		{name: "expiry too long", body: `{"name": "x", "expires_in_days": 366}`, status: http.StatusBadRequest},
+		{name: "defaults", body: `{"name": "x"}`, status: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/tokens", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleCreateToken_AdminScopeRequiresAdmin(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+
+	user := &tsapp.UserInfo{LoginName: "bob@example.com"}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/tokens", strings.NewReader(`{"name": "x", "scope": "admin"}`))
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey{}, user))
+	w := httptest.NewRecorder()
+	srv.handleCreateToken(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", w.Code)
+	}
+}
+
+func TestAPITokens_Scopes(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	read := createTestToken(t, handler, `{"name": "dashboard", "scope": "read"}`)
+	write := createTestToken(t, handler, `{"name": "CI", "scope": "notes-write", "expires_in_days": 30}`)
+	admin := createTestToken(t, handler, `{"name": "ops", "scope": "admin"}`)
+	if !strings.HasPrefix(read.Token, "nbt_") || read.Scope != models.TokenScopeRead || read.Owner != devModeCreatedBy {
+		t.Fatalf("unexpected token %+v", read)
+	}
+
+	meeting := `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "read lists meetings", token: read.Token, method: http.MethodGet, path: "/api/meetings", status: http.StatusOK},
+		{name: "read cannot create meetings", token: read.Token, method: http.MethodPost, path: "/api/meetings", body: meeting, status: http.StatusForbidden},
+		{name: "read cannot use admin endpoints", token: read.Token, method: http.MethodGet, path: "/api/admin/diagnostics", status: http.StatusForbidden},
+		{name: "read cannot list tokens", token: read.Token, method: http.MethodGet, path: "/api/tokens", status: http.StatusForbidden},
+		{name: "notes-write creates meetings", token: write.Token, method: http.MethodPost, path: "/api/meetings", body: meeting, status: http.StatusCreated},
+		{name: "notes-write cannot change config", token: write.Token, method: http.MethodPost, path: "/api/config", body: `{"language": "de"}`, status: http.StatusForbidden},
+		{name: "notes-write cannot share", token: write.Token, method: http.MethodPost, path: "/api/meetings/1/shares", body: `{}`, status: http.StatusForbidden},
+		{name: "notes-write cannot purge the trash", token: write.Token, method: http.MethodDelete, path: "/api/trash/meetings/1", status: http.StatusForbidden},
+		{name: "notes-write cannot merge meetings", token: write.Token, method: http.MethodPost, path: "/api/meetings/1/merge", body: `{"source_id": 2}`, status: http.StatusForbidden},
+		{name: "notes-write cannot split meetings", token: write.Token, method: http.MethodPost, path: "/api/meetings/1/split", body: `{}`, status: http.StatusForbidden},
+		{name: "notes-write cannot delete meetings", token: write.Token, method: http.MethodDelete, path: "/api/meetings/1", status: http.StatusForbidden},
+		{name: "notes-write updates meetings", token: write.Token, method: http.MethodPut, path: "/api/meetings/1", body: meeting, status: http.StatusOK},
+		{name: "notes-write creates notes", token: write.Token, method: http.MethodPost, path: "/api/notes", body: `{"meeting_id": 1, "content": "x"}`, status: http.StatusCreated},
+		{name: "notes-write cannot mint tokens", token: write.Token, method: http.MethodPost, path: "/api/tokens", body: `{"name": "x"}`, status: http.StatusForbidden},
+		{name: "admin changes config", token: admin.Token, method: http.MethodPost, path: "/api/config", body: `{"language": "de"}`, status: http.StatusOK},
+		{name: "unknown token", token: "nbt_unknown", method: http.MethodGet, path: "/api/meetings", status: http.StatusUnauthorized},
+		{name: "token without prefix", token: "abc", method: http.MethodGet, path: "/api/meetings", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := bearerRequest(handler, tt.method, tt.path, tt.token, tt.body); w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// whoami reports the token's owner and name
+	w := bearerRequest(handler, http.MethodGet, "/api/whoami", write.Token, "")
+	var user tsapp.UserInfo
+	if err := json.NewDecoder(w.Body).Decode(&user); err != nil {
+		t.Fatalf("failed to decode whoami: %v", err)
+	}
+	if user.LoginName != devModeCreatedBy || user.NodeName != "CI" || user.NodeID != "api-token-"+strconv.Itoa(write.ID) {
+		t.Errorf("unexpected whoami %+v", user)
+	}
+}
+
+func TestTokenAllows(t *testing.T) {
+	tests := []struct {
+		scope  string
+		method string
+		path   string
+		want   bool
+	}{
+		{models.TokenScopeRead, http.MethodGet, "/api/trash", true},
+		{models.TokenScopeRead, http.MethodPost, "/api/notes", false},
+		{models.TokenScopeNotesWrite, http.MethodPost, "/api/notes", true},
+		{models.TokenScopeNotesWrite, http.MethodPut, "/api/notes/3", true},
+		{models.TokenScopeNotesWrite, http.MethodPost, "/api/notes/move", true},
+		{models.TokenScopeNotesWrite, http.MethodPatch, "/api/meetings/1", true},
+		{models.TokenScopeNotesWrite, http.MethodPut, "/api/meetings/1/notes/order", true},
+		{models.TokenScopeNotesWrite, http.MethodPost, "/api/trash/notes/3/restore", true},
+		{models.TokenScopeNotesWrite, http.MethodPut, "/caldav/meetings/a.ics", true},
+		{models.TokenScopeNotesWrite, http.MethodDelete, "/api/trash/meetings/1", false},
+		{models.TokenScopeNotesWrite, http.MethodDelete, "/api/trash/notes/3", false},
+		{models.TokenScopeNotesWrite, http.MethodPost, "/api/trash/meetings/1/restore", false},
+		{models.TokenScopeNotesWrite, http.MethodPost, "/api/meetings/1/merge", false},
+		{models.TokenScopeNotesWrite, http.MethodPost, "/api/meetings/1/split", false},
+		{models.TokenScopeNotesWrite, http.MethodDelete, "/api/meetings/1", false},
+		{models.TokenScopeNotesWrite, http.MethodDelete, "/caldav/meetings/a.ics", false},
+		{models.TokenScopeNotesWrite, http.MethodPost, "/api/meetings/1/shares", false},
+		{models.TokenScopeAdmin, http.MethodDelete, "/api/trash/meetings/1", true},
+		{models.TokenScopeAdmin, http.MethodPost, "/api/meetings/1/merge", true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequestWithContext(context.Background(), tt.method, tt.path, nil)
+		if got := tokenAllows(tt.scope, req); got != tt.want {
+			t.Errorf("tokenAllows(%s, %s %s) = %v, want %v", tt.scope, tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAPITokens_ListAndRevoke(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	token := createTestToken(t, handler, `{"name": "script"}`)
+	if w := bearerRequest(handler, http.MethodGet, "/api/meetings", token.Token, ""); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/tokens", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	var tokens []models.APIToken
+	if err := json.NewDecoder(w.Body).Decode(&tokens); err != nil {
+		t.Fatalf("failed to decode tokens: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].LastUsedAt == nil || strings.Contains(w.Body.String(), token.Token) {
+		t.Fatalf("unexpected token list %s", w.Body.String())
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodDelete, "/api/tokens/"+strconv.Itoa(token.ID), nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+
+	if w := bearerRequest(handler, http.MethodGet, "/api/meetings", token.Token, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected revoked token to get 401, got %d", w.Code)
+	}
+
+	// Someone else's token is not found
+	other := &tsapp.UserInfo{LoginName: "mallory@example.com"}
+	srv.devMode = false
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodDelete, "/api/tokens/"+strconv.Itoa(token.ID), nil)
+	req = req.WithContext(context.WithValue(req.Context(), userContextKey{}, other))
+	req.SetPathValue("id", strconv.Itoa(token.ID))
+	w = httptest.NewRecorder()
+	srv.handleRevokeToken(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
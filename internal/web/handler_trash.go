@@ -0,0 +1,117 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+)
+
+// handleListTrash handles GET /api/trash
+func (s *Server) handleListTrash(w http.ResponseWriter, r *http.Request) {
+	items, err := repositories.NewTrashRepository(s.database.DB).WithContext(r.Context()).List()
+	if err != nil {
+		s.logError(r, "failed to list trash", err)
+		writeError(w, http.StatusInternalServerError, "failed to list trash")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, items)
+}
+
+// handleRestoreMeeting handles POST /api/trash/meetings/{id}/restore
+func (s *Server) handleRestoreMeeting(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+
+	repo := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context())
+	if err := repo.Restore(int(id)); err != nil {
+		s.writeTrashError(w, r, "failed to restore meeting", err)
+		return
+	}
+
+	meeting, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to fetch restored meeting", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch restored meeting")
+		return
+	}
+
+	// a restored meeting reappears like a new one
+	s.publish(r, events.MeetingCreated, meeting.ID, meeting)
+	writeJSON(w, http.StatusOK, meeting)
+}
+
+// handleRestoreNote handles POST /api/trash/notes/{id}/restore
+func (s *Server) handleRestoreNote(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidNoteID)
+		return
+	}
+
+	repo := s.noteRepository(r.Context())
+	if err := repo.Restore(int(id)); err != nil {
+		s.writeTrashError(w, r, "failed to restore note", err)
+		return
+	}
+
+	note, err := repo.GetByID(int(id))
+	if err != nil {
+		s.logError(r, "failed to fetch restored note", err)
+		writeError(w, http.StatusInternalServerError, "failed to fetch restored note")
+		return
+	}
+
+	s.publish(r, events.NoteCreated, note.MeetingID, note)
+	writeJSON(w, http.StatusOK, note)
+}
+
+// handlePurgeMeeting handles DELETE /api/trash/meetings/{id}
+func (s *Server) handlePurgeMeeting(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidMeetingID)
+		return
+	}
+
+	if err := repositories.NewMeetingRepository(s.database.DB).WithContext(r.Context()).Purge(int(id)); err != nil {
+		s.writeTrashError(w, r, "failed to purge meeting", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePurgeNote handles DELETE /api/trash/notes/{id}
+func (s *Server) handlePurgeNote(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIDParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errInvalidNoteID)
+		return
+	}
+
+	if err := s.noteRepository(r.Context()).Purge(int(id)); err != nil {
+		s.writeTrashError(w, r, "failed to purge note", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTrashError maps errors of restoring and purging to responses
+func (s *Server) writeTrashError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotInTrash):
+		writeError(w, http.StatusNotFound, "not found in trash")
+	case errors.Is(err, repositories.ErrRestoreConflict):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		s.logError(r, msg, err)
+		writeError(w, http.StatusInternalServerError, msg)
+	}
+}
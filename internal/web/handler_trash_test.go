@@ -0,0 +1,69 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+)
+
+func TestTrash_DeleteRestorePurge(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	meetingID := strconv.Itoa(meeting.ID)
+	var note models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+meetingID+`, "content": "first"}`, http.StatusCreated, &note)
+	noteID := strconv.Itoa(note.ID)
+
+	serveJSON(t, handler, http.MethodDelete, "/api/notes/"+noteID, "", http.StatusNoContent, nil)
+	serveJSON(t, handler, http.MethodGet, "/api/notes/"+noteID, "", http.StatusNotFound, nil)
+
+	var items []*models.TrashItem
+	serveJSON(t, handler, http.MethodGet, "/api/trash", "", http.StatusOK, &items)
+	if len(items) != 1 || items[0].Type != models.TrashTypeNote || items[0].ID != note.ID || items[0].Subject != "Standup" {
+		t.Fatalf("unexpected trash %+v", items)
+	}
+
+	var restored models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/trash/notes/"+noteID+"/restore", "", http.StatusOK, &restored)
+	if restored.Content != "first" || restored.DeletedAt != nil {
+		t.Errorf("unexpected restored note %+v", restored)
+	}
+	serveJSON(t, handler, http.MethodPost, "/api/trash/notes/"+noteID+"/restore", "", http.StatusNotFound, nil)
+
+	serveJSON(t, handler, http.MethodDelete, "/api/meetings/"+meetingID, "", http.StatusNoContent, nil)
+	serveJSON(t, handler, http.MethodGet, "/api/meetings/"+meetingID, "", http.StatusNotFound, nil)
+	serveJSON(t, handler, http.MethodGet, "/api/trash", "", http.StatusOK, &items)
+	if len(items) != 1 || items[0].Type != models.TrashTypeMeeting || items[0].Notes != 1 {
+		t.Fatalf("unexpected trash %+v", items)
+	}
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+meetingID+`, "content": "late"}`, http.StatusNotFound, nil)
+
+	serveJSON(t, handler, http.MethodPost, "/api/trash/meetings/"+meetingID+"/restore", "", http.StatusOK, nil)
+	serveJSON(t, handler, http.MethodGet, "/api/notes/"+noteID, "", http.StatusOK, nil)
+
+	serveJSON(t, handler, http.MethodDelete, "/api/trash/meetings/"+meetingID, "", http.StatusNotFound, nil)
+	serveJSON(t, handler, http.MethodDelete, "/api/meetings/"+meetingID, "", http.StatusNoContent, nil)
+	serveJSON(t, handler, http.MethodDelete, "/api/trash/meetings/"+meetingID, "", http.StatusNoContent, nil)
+	serveJSON(t, handler, http.MethodGet, "/api/trash", "", http.StatusOK, &items)
+	if len(items) != 0 {
+		t.Errorf("expected empty trash, got %+v", items)
+	}
+	serveJSON(t, handler, http.MethodGet, "/api/notes/"+noteID, "", http.StatusNotFound, nil)
+}
+
+func TestTrash_InvalidID(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	serveJSON(t, handler, http.MethodPost, "/api/trash/meetings/abc/restore", "", http.StatusBadRequest, nil)
+	serveJSON(t, handler, http.MethodDelete, "/api/trash/notes/abc", "", http.StatusBadRequest, nil)
+}
@@ -0,0 +1,28 @@
+package web
+
+import (
+	"net/http"
+)
+
+// hstsValue tells browsers to use HTTPS for a year
+const hstsValue = "max-age=31536000"
+
+// hstsMiddleware adds the Strict-Transport-Security header to responses
+// sent over TLS. Browsers ignore it on plain HTTP anyway.
+func hstsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			w.Header().Set("Strict-Transport-Security", hstsValue)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RedirectToHTTPS returns a handler that redirects every request to the
+// same path and query on https://host. The host is the certificate's name
+// rather than the one requested, which may be a short MagicDNS name.
+func RedirectToHTTPS(host string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
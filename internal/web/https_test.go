@@ -0,0 +1,43 @@
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectToHTTPS(t *testing.T) {
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "http://notebook/api/meetings?search=x", nil)
+	w := httptest.NewRecorder()
+
+	RedirectToHTTPS("notebook.example.ts.net").ServeHTTP(w, req)
+
+	if w.Code != http.StatusPermanentRedirect {
+		t.Errorf("expected status 308, got %d", w.Code)
+	}
+	if got, want := w.Header().Get("Location"), "https://notebook.example.ts.net/api/meetings?search=x"; got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+}
+
+func TestHSTSMiddleware(t *testing.T) {
+	handler := hstsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("expected no HSTS header over plain HTTP, got %q", got)
+	}
+
+	req.TLS = &tls.ConnectionState{}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Strict-Transport-Security"); got != hstsValue {
+		t.Errorf("Strict-Transport-Security = %q, want %q", got, hstsValue)
+	}
+}
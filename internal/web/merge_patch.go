@@ -0,0 +1,148 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+)
+
+// contentTypeMergePatch is the media type of RFC 7396 merge patches
+const contentTypeMergePatch = "application/merge-patch+json"
+
+// errUnsupportedPatchType is returned for PATCH bodies that are not merge patches
+var errUnsupportedPatchType = errors.New("unsupported content type: use " + contentTypeMergePatch)
+
+// mergePatch is an RFC 7396 merge patch of a flat JSON object. Members that
+// are absent leave a field unchanged; members set to null remove it.
+type mergePatch map[string]json.RawMessage
+
+// decodeMergePatch reads a merge patch from the request body. Besides
+// application/merge-patch+json, plain application/json is accepted.
+func decodeMergePatch(r *http.Request) (mergePatch, error) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != contentTypeMergePatch && mediaType != contentTypeJSON) {
+			return nil, errUnsupportedPatchType
+		}
+	}
+
+	var patch mergePatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		return nil, errors.New("invalid request body: expected a JSON object")
+	}
+	return patch, nil
+}
+
+// has reports whether the patch contains any of the fields
+func (p mergePatch) has(fields ...string) bool {
+	for _, field := range fields {
+		if _, ok := p[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// allow rejects members other than the given fields
+func (p mergePatch) allow(fields ...string) error {
+	var unknown []string
+	for field := range p {
+		if !slices.Contains(fields, field) {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("fields cannot be patched: %v", unknown)
+	}
+	return nil
+}
+
+// isNull reports whether a member removes its field
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// requiredString applies a member to a field that cannot be removed or empty
+func (p mergePatch) requiredString(field string, dst *string) error {
+	raw, ok := p[field]
+	if !ok {
+		return nil
+	}
+	if isNull(raw) {
+		return fmt.Errorf("%s cannot be removed", field)
+	}
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return fmt.Errorf("%s must be a string", field)
+	}
+	if v == "" {
+		return fmt.Errorf("%s must not be empty", field)
+	}
+	*dst = v
+	return nil
+}
+
+// optionalString applies a member to a field that null clears
+func (p mergePatch) optionalString(field string, dst **string) error {
+	raw, ok := p[field]
+	if !ok {
+		return nil
+	}
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return fmt.Errorf("%s must be a string", field)
+	}
+	*dst = &v
+	return nil
+}
+
+// optionalInt applies a member to a field that null clears
+func (p mergePatch) optionalInt(field string, dst **int) error {
+	raw, ok := p[field]
+	if !ok {
+		return nil
+	}
+	if isNull(raw) {
+		*dst = nil
+		return nil
+	}
+	var v int
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return fmt.Errorf("%s must be an integer", field)
+	}
+	*dst = &v
+	return nil
+}
+
+// optionalTime applies a member holding an RFC 3339 instant; null clears
+// the field unless it is required
+func (p mergePatch) optionalTime(field string, dst **time.Time, required bool) error {
+	raw, ok := p[field]
+	if !ok {
+		return nil
+	}
+	if isNull(raw) {
+		if required {
+			return fmt.Errorf("%s cannot be removed", field)
+		}
+		*dst = nil
+		return nil
+	}
+	var v time.Time
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return fmt.Errorf("%s must be an RFC 3339 time", field)
+	}
+	*dst = &v
+	return nil
+}
@@ -0,0 +1,108 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/validation"
+)
+
+func TestPatchMeeting(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings",
+		`{"subject": "Planning", "meeting_date": "2026-03-10", "start_time": "10:00", "end_time": "11:00", "participants": "Alice", "timezone": "Europe/Berlin"}`,
+		http.StatusCreated, &meeting)
+	path := "/api/meetings/" + strconv.Itoa(meeting.ID)
+	mergePatch := map[string]string{"Content-Type": contentTypeMergePatch}
+
+	w := serveConditional(t, handler, http.MethodPatch, path, `{"keywords": "roadmap", "end_time": null}`, mergePatch)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var patched models.Meeting
+	if err := json.NewDecoder(w.Body).Decode(&patched); err != nil {
+		t.Fatalf("decode meeting: %v", err)
+	}
+	if patched.Keywords == nil || *patched.Keywords != "roadmap" || patched.EndTime != nil || patched.EndUTC != nil ||
+		patched.Subject != "Planning" || patched.Participants == nil || *patched.Participants != "Alice" {
+		t.Errorf("unexpected patched meeting %+v", patched)
+	}
+
+	// Patching the instant re-derives the local time in the meeting's zone
+	serveJSON(t, handler, http.MethodPatch, path, `{"start_utc": "2026-03-10T12:00:00Z"}`, http.StatusOK, &patched)
+	if patched.MeetingDate != "2026-03-10" || patched.StartTime != "13:00" {
+		t.Errorf("expected local start 13:00, got %s %s", patched.MeetingDate, patched.StartTime)
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		headers map[string]string
+		status  int
+	}{
+		{"required field removed", `{"subject": null}`, mergePatch, http.StatusBadRequest},
+		{"required field emptied", `{"meeting_date": ""}`, mergePatch, http.StatusBadRequest},
+		{"read-only field", `{"created_by": "mallory@example.com"}`, mergePatch, http.StatusBadRequest},
+		{"invalid time", `{"start_time": "25:00"}`, mergePatch, http.StatusBadRequest},
+		{"too long", `{"keywords": "` + strings.Repeat("k", validation.MaxKeywordsLength+1) + `"}`, mergePatch, http.StatusBadRequest},
+		{"not an object", `["subject"]`, mergePatch, http.StatusBadRequest},
+		{"wrong content type", `{"subject": "x"}`, map[string]string{"Content-Type": "text/plain"}, http.StatusUnsupportedMediaType},
+		{"stale If-Match", `{"subject": "x"}`, map[string]string{"If-Match": `"` + strconv.Itoa(meeting.ID) + `-1"`}, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serveConditional(t, handler, http.MethodPatch, path, tt.body, tt.headers); w.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPatchNote(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.database.Close()
+	srv.devMode = true
+	handler := srv.Handler()
+
+	var meeting models.Meeting
+	serveJSON(t, handler, http.MethodPost, "/api/meetings", `{"subject": "Standup", "meeting_date": "2026-03-01", "start_time": "09:00"}`, http.StatusCreated, &meeting)
+	var note models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(meeting.ID)+`, "content": "first"}`, http.StatusCreated, &note)
+	path := "/api/notes/" + strconv.Itoa(note.ID)
+
+	var patched models.Note
+	serveJSON(t, handler, http.MethodPatch, path, `{"content": "first"}`, http.StatusOK, &patched)
+	if patched.Version != note.Version {
+		t.Errorf("expected an unchanged patch to keep version %d, got %d", note.Version, patched.Version)
+	}
+	serveJSON(t, handler, http.MethodPatch, path, `{"content": "second"}`, http.StatusOK, &patched)
+	if patched.Content != "second" || patched.Version != note.Version+1 {
+		t.Errorf("unexpected patched note %+v", patched)
+	}
+
+	serveJSON(t, handler, http.MethodPatch, path, `{"content": null}`, http.StatusBadRequest, nil)
+	serveJSON(t, handler, http.MethodPatch, path, `{"note_number": 5}`, http.StatusBadRequest, nil)
+
+	var child models.Note
+	serveJSON(t, handler, http.MethodPost, "/api/notes", `{"meeting_id": `+strconv.Itoa(meeting.ID)+`, "content": "nested"}`, http.StatusCreated, &child)
+	childPath := "/api/notes/" + strconv.Itoa(child.ID)
+	serveJSON(t, handler, http.MethodPatch, childPath, `{"parent_id": `+strconv.Itoa(note.ID)+`, "note_type": "action"}`, http.StatusOK, &patched)
+	if patched.ParentID == nil || *patched.ParentID != note.ID || patched.NoteType != models.NoteTypeAction {
+		t.Errorf("unexpected nested note %+v", patched)
+	}
+	serveJSON(t, handler, http.MethodPatch, path, `{"parent_id": `+strconv.Itoa(child.ID)+`}`, http.StatusBadRequest, nil)
+	serveJSON(t, handler, http.MethodPatch, childPath, `{"note_type": "todo"}`, http.StatusBadRequest, nil)
+	serveJSON(t, handler, http.MethodPatch, childPath, `{"parent_id": null}`, http.StatusOK, &patched)
+	if patched.ParentID != nil {
+		t.Errorf("expected a top-level note, got parent %d", *patched.ParentID)
+	}
+}
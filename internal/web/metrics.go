@@ -0,0 +1,64 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zorak1103/notebook/internal/metrics"
+)
+
+// unmatchedPattern labels requests no route pattern matched (404/405 from the mux)
+const unmatchedPattern = "unmatched"
+
+var (
+	httpRequests = metrics.Default.NewCounterVec(
+		"notebook_http_requests_total",
+		"Number of HTTP requests by route pattern and status code.",
+		"pattern", "code",
+	)
+	httpDuration = metrics.Default.NewHistogramVec(
+		"notebook_http_request_duration_seconds",
+		"Duration of HTTP requests by route pattern in seconds.",
+		metrics.DefaultBuckets,
+		"pattern",
+	)
+	meetingsGauge = metrics.Default.NewGaugeFunc("notebook_meetings", "Number of meetings.")
+	notesGauge    = metrics.Default.NewGaugeFunc("notebook_notes", "Number of notes.")
+)
+
+// metricsMiddleware counts requests and their latency per ServeMux pattern.
+// The mux sets r.Pattern on the request it is given, so it is read after next
+// has served the request.
+func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(wrapped, r)
+
+		pattern := r.Pattern
+		if pattern == "" {
+			pattern = unmatchedPattern
+		}
+		httpRequests.Inc(pattern, strconv.Itoa(wrapped.statusCode))
+		httpDuration.Observe(time.Since(start).Seconds(), pattern)
+	})
+}
+
+// MetricsHandler returns the Prometheus scrape endpoint, including gauges
+// for the number of meetings and notes outside the trash
+func (s *Server) MetricsHandler() http.Handler {
+	meetingsGauge.Set(s.rowCountGauge(tableMeetings))
+	notesGauge.Set(s.rowCountGauge(tableNotes))
+	return metrics.Default.Handler()
+}
+
+// rowCountGauge returns a gauge callback counting the rows of table
+func (s *Server) rowCountGauge(table string) func() (float64, error) {
+	return func() (float64, error) {
+		n, err := s.rowCount(context.Background(), table)
+		return float64(n), err
+	}
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	srv := newTestServer(t)
+	srv.metrics = true
+	handler := srv.Handler()
+
+	for _, path := range []string{"/api/meetings", "/api/meetings/999999"} {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, path, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`notebook_http_requests_total{pattern="GET /api/meetings",code="200"}`,
+		`notebook_http_requests_total{pattern="GET /api/meetings/{id}",code="404"}`,
+		`notebook_http_request_duration_seconds_count{pattern="GET /api/meetings"}`,
+		`notebook_db_query_duration_seconds_count{operation="query"}`,
+		"notebook_meetings 0\n",
+		"notebook_notes 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+}
+
+func TestMetricsEndpoint_Disabled(t *testing.T) {
+	srv := newTestServer(t)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if strings.Contains(rec.Body.String(), "notebook_http_requests_total") {
+		t.Error("metrics served although disabled")
+	}
+}
@@ -2,8 +2,17 @@
 package web
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/zorak1103/notebook/internal/db/models"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/logging"
+	"github.com/zorak1103/notebook/internal/tsapp"
 )
 
 // corsMiddleware adds CORS headers for development mode to allow
@@ -11,8 +20,9 @@ import (
 func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, If-Match, If-None-Match, Last-Event-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag")
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
@@ -24,24 +34,104 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// loggingMiddleware logs HTTP requests with timing information
+// slowRequest is the duration above which requests are logged as warnings
+const slowRequest = 100 * time.Millisecond
+
+// headerRequestID carries the request ID in requests and responses
+const headerRequestID = "X-Request-ID"
+
+// maxRequestIDLength limits request IDs accepted from clients
+const maxRequestIDLength = 64
+
+// requestIDMiddleware stores a request ID in the context and echoes it in the
+// response. A well-formed X-Request-ID from the client is reused.
+func (s *Server) requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(headerRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(headerRequestID, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID accepts short IDs of letters, digits, '-', '_' and '.'
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 16 random hex digits
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// identityMiddleware resolves the caller once per request for handlers, logs
+// and the audit log. A valid Bearer API token takes precedence over Tailscale.
+func (s *Server) identityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var user *tsapp.UserInfo
+		if secret, ok := bearerToken(r); ok {
+			var token *models.APIToken
+			if user, token = s.authenticateToken(w, r, secret); user == nil {
+				return
+			}
+			ctx = context.WithValue(ctx, apiTokenContextKey{}, token)
+		} else {
+			var err error
+			if user, err = s.currentUser(r); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		ctx = context.WithValue(ctx, userContextKey{}, user)
+		ctx = logging.WithUser(ctx, user.LoginName)
+		ctx = repositories.ContextWithActor(ctx, user.LoginName)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// loggingMiddleware logs HTTP requests with timing information: server
+// errors at error level, slow requests at warn level, all others at debug level.
+// Event streams are long-lived by design and never count as slow.
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		path := r.URL.Path // handlers may rewrite it, e.g. the SPA fallback
 
 		// Wrap response writer to capture status code
 		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
 		next.ServeHTTP(wrapped, r)
 
-		if s.verbose {
-			duration := time.Since(start)
-			s.logRequest(r.Method, r.URL.Path, wrapped.statusCode)
-			if duration > 100*time.Millisecond {
-				// Log slow requests
-				println("  [SLOW]", duration.String())
-			}
+		duration := time.Since(start)
+		level := slog.LevelDebug
+		switch {
+		case wrapped.statusCode >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case duration > slowRequest && wrapped.Header().Get("Content-Type") != contentTypeEventStream:
+			level = slog.LevelWarn
 		}
+		slog.Log(r.Context(), level, "request",
+			"method", r.Method,
+			"path", path,
+			"pattern", r.Pattern,
+			"status", wrapped.statusCode,
+			"duration", duration,
+		)
 	})
 }
 
@@ -55,3 +145,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer to flush
+// event streams
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,87 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zorak1103/notebook/internal/logging"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{name: "client ID reused", header: "abc-123_x.y", wantSame: true},
+		{name: "missing ID generated", header: ""},
+		{name: "malformed ID replaced", header: "bad id\n"},
+		{name: "overlong ID replaced", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	srv := newTestServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := srv.requestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				seen = logging.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/meetings", nil)
+			if tt.header != "" {
+				req.Header.Set(headerRequestID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get(headerRequestID); got != seen || seen == "" {
+				t.Errorf("response ID %q, context ID %q", got, seen)
+			}
+			if (seen == tt.header) != tt.wantSame {
+				t.Errorf("ID %q, client sent %q", seen, tt.header)
+			}
+		})
+	}
+}
+
+func TestMiddleware_LogsRequestAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatJSON, "debug")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(previous)
+
+	srv := newTestServer(t)
+	srv.devMode = true
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/meetings", nil)
+	req.Header.Set(headerRequestID, "req-42")
+	srv.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+	var requestLine string
+	var queryLogged bool
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.Contains(line, `"msg":"request"`) {
+			requestLine = line
+		}
+		if strings.Contains(line, `"msg":"db query"`) && strings.Contains(line, `"request_id":"req-42"`) {
+			queryLogged = true
+		}
+	}
+	for _, want := range []string{`"request_id":"req-42"`, `"user":"` + devModeCreatedBy + `"`, `"pattern":"GET /api/meetings"`, `"status":200`} {
+		if !strings.Contains(requestLine, want) {
+			t.Errorf("request log %q missing %s", requestLine, want)
+		}
+	}
+	if !queryLogged {
+		t.Error("expected database queries to be logged with the request ID")
+	}
+}
@@ -2,10 +2,15 @@
 package web
 
 import (
-	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/zorak1103/notebook/internal/db"
+	"github.com/zorak1103/notebook/internal/db/repositories"
+	"github.com/zorak1103/notebook/internal/events"
+	"github.com/zorak1103/notebook/internal/presence"
+	"github.com/zorak1103/notebook/internal/secrets"
 	"github.com/zorak1103/notebook/internal/tsapp"
 )
 
@@ -14,22 +19,103 @@ type Server struct {
 	tsapp    *tsapp.App
 	database *db.DB
 	devMode  bool
-	verbose  bool
 	version  string
 	commit   string
 	date     string
+	location *time.Location
+	metrics  bool
+	https    bool
+	admins   map[string]bool
+	// lockedConfig holds config keys set by the operator that the UI may not change
+	lockedConfig map[string]bool
+	// shareBaseURL is the public origin of share links; empty disables sharing
+	shareBaseURL string
+	// cipher encrypts secrets in the config table; nil without a master key
+	cipher *secrets.Cipher
+	// auth identifies users in standalone mode; nil with Tailscale or in dev mode
+	auth Authenticator
+	// noteRetention limits the stored revisions of each note
+	noteRetention repositories.RevisionRetention
+	// events fans out changes to GET /api/events streams
+	events *events.Bus
+	// heartbeat is the interval of event stream heartbeats; 0 uses defaultHeartbeat
+	heartbeat time.Duration
+	// presence tracks who is in which meeting and the edit locks on notes
+	presence *presence.Tracker
+	// strictNoteLocks rejects writes to notes locked by another user
+	// instead of only warning
+	strictNoteLocks bool
+}
+
+// Options holds the settings NewServer takes beyond its dependencies
+type Options struct {
+	DevMode bool
+	Version string
+	Commit  string
+	Date    string
+	// Location is the zone naive meeting dates and times are expressed in.
+	// Defaults to time.Local.
+	Location *time.Location
+	// Metrics serves GET /metrics on the main handler. Use MetricsHandler
+	// instead to expose it on a separate listener.
+	Metrics bool
+	// HTTPS marks the handler as served over TLS, adding HSTS headers
+	HTTPS bool
+	// Admins lists the Tailscale login names allowed to use /api/admin/
+	Admins []string
+	// LockedConfig lists config keys that POST /api/config may not change
+	LockedConfig []string
+	// ShareBaseURL is the origin PublicHandler is reachable at, e.g. the
+	// Funnel URL. Creating share links is disabled while it is empty.
+	ShareBaseURL string
+	// Cipher encrypts secrets in the config table. Without one, storing
+	// an API key fails.
+	Cipher *secrets.Cipher
+	// Auth authenticates every request when serving without Tailscale.
+	// Requests it rejects get 401 or a redirect to its login page.
+	Auth Authenticator
+	// NoteRetention limits the stored revisions of each note. The zero
+	// value keeps all revisions.
+	NoteRetention repositories.RevisionRetention
+	// StrictNoteLocks fails note updates with 423 Locked while another
+	// user holds the note's edit lock. Otherwise they only get a warning.
+	StrictNoteLocks bool
 }
 
 // NewServer creates a new web server instance
-func NewServer(app *tsapp.App, database *db.DB, devMode, verbose bool, version, commit, date string) *Server {
+func NewServer(app *tsapp.App, database *db.DB, opts Options) *Server {
+	admins := make(map[string]bool, len(opts.Admins))
+	for _, login := range opts.Admins {
+		admins[login] = true
+	}
+
+	lockedConfig := make(map[string]bool, len(opts.LockedConfig))
+	for _, key := range opts.LockedConfig {
+		lockedConfig[key] = true
+	}
+
 	return &Server{
 		tsapp:    app,
 		database: database,
-		devMode:  devMode,
-		verbose:  verbose,
-		version:  version,
-		commit:   commit,
-		date:     date,
+		devMode:  opts.DevMode,
+		version:  opts.Version,
+		commit:   opts.Commit,
+		date:     opts.Date,
+		location: opts.Location,
+		metrics:  opts.Metrics,
+		https:    opts.HTTPS,
+		admins:   admins,
+
+		lockedConfig: lockedConfig,
+		shareBaseURL: opts.ShareBaseURL,
+		cipher:       opts.Cipher,
+		auth:         opts.Auth,
+
+		noteRetention: opts.NoteRetention,
+		events:        events.NewBus(events.DefaultLogSize),
+
+		presence:        presence.NewTracker(presence.DefaultPresenceTTL, presence.DefaultLockTTL),
+		strictNoteLocks: opts.StrictNoteLocks,
 	}
 }
 
@@ -37,6 +123,10 @@ func NewServer(app *tsapp.App, database *db.DB, devMode, verbose bool, version,
 func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
+	// Probes
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+
 	// API routes
 	mux.HandleFunc("GET /api/whoami", s.handleWhoAmI)
 	mux.HandleFunc("GET /api/version", s.handleVersion)
@@ -44,51 +134,131 @@ func (s *Server) Handler() http.Handler {
 	// Meeting CRUD
 	mux.HandleFunc("GET /api/meetings", s.handleListMeetings)
 	mux.HandleFunc("POST /api/meetings", s.handleCreateMeeting)
+	mux.HandleFunc("POST /api/meetings/import", s.handleImportICS)
 	mux.HandleFunc("GET /api/meetings/{id}", s.handleGetMeeting)
 	mux.HandleFunc("PUT /api/meetings/{id}", s.handleUpdateMeeting)
+	mux.HandleFunc("PATCH /api/meetings/{id}", s.handlePatchMeeting)
 	mux.HandleFunc("DELETE /api/meetings/{id}", s.handleDeleteMeeting)
+	mux.HandleFunc("GET /api/meetings/{id}/summaries", s.handleListSummaries)
+	mux.HandleFunc("GET /api/meetings/{id}/summaries/diff", s.handleDiffSummaries)
+	mux.HandleFunc("POST /api/meetings/{id}/summaries/{version}/promote", s.handlePromoteSummary)
+	mux.HandleFunc("POST /api/meetings/{id}/merge", s.handleMergeMeeting)
+	mux.HandleFunc("POST /api/meetings/{id}/split", s.handleSplitMeeting)
+	mux.HandleFunc("GET /api/meetings/{id}/presence", s.handleGetPresence)
+	mux.HandleFunc("PUT /api/meetings/{id}/presence", s.handleTouchPresence)
+	mux.HandleFunc("DELETE /api/meetings/{id}/presence", s.handleLeavePresence)
 
 	// Note CRUD
 	mux.HandleFunc("GET /api/meetings/{meetingId}/notes", s.handleListNotes)
 	mux.HandleFunc("GET /api/notes/{id}", s.handleGetNote)
 	mux.HandleFunc("POST /api/notes", s.handleCreateNote)
 	mux.HandleFunc("PUT /api/notes/{id}", s.handleUpdateNote)
+	mux.HandleFunc("PATCH /api/notes/{id}", s.handlePatchNote)
 	mux.HandleFunc("PUT /api/notes/{id}/reorder", s.handleReorderNote)
+	mux.HandleFunc("PUT /api/meetings/{meetingId}/notes/order", s.handleSetNoteOrder)
+	mux.HandleFunc("POST /api/notes/move", s.handleMoveNotes)
 	mux.HandleFunc("DELETE /api/notes/{id}", s.handleDeleteNote)
+	mux.HandleFunc("GET /api/notes/{id}/revisions", s.handleListNoteRevisions)
+	mux.HandleFunc("GET /api/notes/{id}/revisions/diff", s.handleDiffNoteRevisions)
+	mux.HandleFunc("POST /api/notes/{id}/revisions/{revision}/restore", s.handleRestoreNoteRevision)
+	mux.HandleFunc("POST /api/notes/{id}/lock", s.handleLockNote)
+	mux.HandleFunc("DELETE /api/notes/{id}/lock", s.handleUnlockNote)
+
+	// Trash
+	mux.HandleFunc("GET /api/trash", s.handleListTrash)
+	mux.HandleFunc("POST /api/trash/meetings/{id}/restore", s.handleRestoreMeeting)
+	mux.HandleFunc("POST /api/trash/notes/{id}/restore", s.handleRestoreNote)
+	mux.HandleFunc("DELETE /api/trash/meetings/{id}", s.handlePurgeMeeting)
+	mux.HandleFunc("DELETE /api/trash/notes/{id}", s.handlePurgeNote)
 
 	// Search
 	mux.HandleFunc("GET /api/search", s.handleSearch)
 
+	// Change events
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+
+	// Reports
+	mux.HandleFunc("GET /api/reports", s.handleReports)
+
 	// Config
 	mux.HandleFunc("GET /api/config", s.handleGetConfig)
 	mux.HandleFunc("POST /api/config", s.handleUpdateConfig)
 
+	// Admin
+	mux.HandleFunc("GET /api/admin/diagnostics", s.handleDiagnostics)
+	mux.HandleFunc("GET /api/admin/audit", s.handleListAudit)
+
 	// LLM operations
 	mux.HandleFunc("POST /api/meetings/{id}/summarize", s.handleSummarizeMeeting)
 	mux.HandleFunc("POST /api/notes/{id}/enhance", s.handleEnhanceNote)
 
+	// Public share links
+	mux.HandleFunc("GET /api/meetings/{id}/shares", s.handleListShares)
+	mux.HandleFunc("POST /api/meetings/{id}/shares", s.handleCreateShare)
+	mux.HandleFunc("DELETE /api/shares/{id}", s.handleRevokeShare)
+	mux.HandleFunc("GET /api/shares/{id}/events", s.handleListShareEvents)
+
+	// Personal API tokens
+	mux.HandleFunc("GET /api/tokens", s.handleListTokens)
+	mux.HandleFunc("POST /api/tokens", s.handleCreateToken)
+	mux.HandleFunc("DELETE /api/tokens/{id}", s.handleRevokeToken)
+
+	// Calendar subscription feed
+	mux.HandleFunc("GET /calendar.ics", s.handleCalendarFeed)
+
+	// CalDAV access to meetings
+	mux.HandleFunc("/.well-known/caldav", s.handleCalDAVWellKnown)
+	mux.HandleFunc("OPTIONS /caldav/", s.handleCalDAVOptions)
+	mux.HandleFunc("PROPFIND /caldav/", s.handleCalDAVPropfind)
+	mux.HandleFunc("REPORT /caldav/meetings/", s.handleCalDAVReport)
+	mux.HandleFunc("GET /caldav/meetings/{name}", s.handleCalDAVGet)
+	mux.HandleFunc("PUT /caldav/meetings/{name}", s.handleCalDAVPut)
+	mux.HandleFunc("DELETE /caldav/meetings/{name}", s.handleCalDAVDelete)
+
+	// Prometheus metrics
+	if s.metrics {
+		mux.Handle("GET /metrics", s.MetricsHandler())
+	}
+
+	// Login endpoints of the standalone authenticator
+	if s.auth != nil {
+		if authHandler := s.auth.Handler(); authHandler != nil {
+			mux.Handle("/auth/", authHandler)
+		}
+	}
+
 	// Static files and SPA fallback
 	mux.HandleFunc("/", s.handleStatic)
 
 	// Apply middleware
 	var handler http.Handler = mux
+	if s.auth != nil {
+		handler = s.requireAuthMiddleware(handler)
+	}
+	handler = s.metricsMiddleware(handler)
 	handler = s.loggingMiddleware(handler)
+	handler = s.identityMiddleware(handler)
+	handler = s.requestIDMiddleware(handler)
 
 	if s.devMode {
 		handler = s.corsMiddleware(handler)
 	}
+	if s.https {
+		handler = hstsMiddleware(handler)
+	}
 
 	return handler
 }
 
-// logRequest logs an HTTP request if verbose mode is enabled
-func (s *Server) logRequest(method, path string, status int) {
-	if s.verbose {
-		fmt.Printf("[%s] %s - %d\n", method, path, status)
+// timeLocation returns the zone naive meeting dates and times are expressed in
+func (s *Server) timeLocation() *time.Location {
+	if s.location == nil {
+		return time.Local
 	}
+	return s.location
 }
 
 // logError logs an error with request context
 func (s *Server) logError(r *http.Request, msg string, err error) {
-	fmt.Printf("[ERROR] %s %s: %s: %v\n", r.Method, r.URL.Path, msg, err)
+	slog.ErrorContext(r.Context(), msg, "method", r.Method, "path", r.URL.Path, "error", err)
 }